@@ -315,10 +315,22 @@ impl WasmEnv {
         check!(socket::write_bytes32(writer, &self.large_globals[0]));
         check!(socket::write_bytes32(writer, &self.large_globals[1]));
         check!(socket::write_u64(writer, memory_used.bytes().0 as u64));
+        check!(socket::write_u64(writer, cpu_time_micros()));
         check!(writer.flush());
     }
 }
 
+/// Returns the CPU time used by this process in microseconds, user and system.
+/// Since each validation runs in a forked child, whose usage starts at zero, this is the validation's.
+fn cpu_time_micros() -> u64 {
+    let mut usage: libc::rusage = unsafe { std::mem::zeroed() };
+    if unsafe { libc::getrusage(libc::RUSAGE_SELF, &mut usage) } != 0 {
+        return 0;
+    }
+    let micros = |time: libc::timeval| time.tv_sec as u64 * 1_000_000 + time.tv_usec as u64;
+    micros(usage.ru_utime) + micros(usage.ru_stime)
+}
+
 pub struct ProcessEnv {
     /// Whether to create child processes to handle execution
     pub forks: bool,
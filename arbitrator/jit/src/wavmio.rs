@@ -10,7 +10,7 @@ use arbutil::{Color, PreimageType};
 use caller_env::{GuestPtr, MemAccess};
 use std::{
     io,
-    io::{BufReader, BufWriter, ErrorKind},
+    io::{BufReader, BufWriter, ErrorKind, Write},
     net::TcpStream,
     time::Instant,
 };
@@ -300,7 +300,11 @@ fn ready_hostio(env: &mut WasmEnv) -> MaybeEscape {
         return Escape::hostio("failed to parse global state");
     }
 
-    let writer = BufWriter::new(socket);
+    // tell the validator which process is running the validation, so that it can watch its memory
+    let mut writer = BufWriter::new(socket);
+    socket::write_u64(&mut writer, std::process::id().into())?;
+    writer.flush()?;
+
     env.process.socket = Some((writer, reader));
     env.process.forks = false;
     Ok(())
@@ -12,6 +12,7 @@ import (
 
 	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/staker"
+	multiprotocolstaker "github.com/offchainlabs/nitro/staker/multi_protocol"
 	"github.com/offchainlabs/nitro/validator"
 	"github.com/offchainlabs/nitro/validator/server_api"
 )
@@ -39,11 +40,10 @@ func (a *BlockValidatorDebugAPI) ValidateMessageNumber(
 ) (ValidateBlockResult, error) {
 	result := ValidateBlockResult{}
 
+	// a zero module root validates with the one in effect for the message
 	var moduleRoot common.Hash
 	if moduleRootOptional != nil {
 		moduleRoot = *moduleRootOptional
-	} else {
-		moduleRoot = a.val.GetLatestWasmModuleRoot()
 	}
 	start_time := time.Now()
 	valid, gs, err := a.val.ValidateResult(ctx, arbutil.MessageIndex(msgNum), full, moduleRoot)
@@ -55,6 +55,93 @@ func (a *BlockValidatorDebugAPI) ValidateMessageNumber(
 	return result, err
 }
 
+type ValidateBatchBlockResult struct {
+	MessageNumber hexutil.Uint64          `json:"messageNumber"`
+	Valid         bool                    `json:"valid"`
+	GlobalState   validator.GoGlobalState `json:"globalstate"`
+	GasUsed       *hexutil.Uint64         `json:"gasUsed,omitempty"`
+}
+
+func (a *BlockValidatorDebugAPI) ValidateBatch(ctx context.Context, batchNum hexutil.Uint64, stopOnFirstMismatchOptional *bool) ([]ValidateBatchBlockResult, error) {
+	stopOnFirstMismatch := stopOnFirstMismatchOptional != nil && *stopOnFirstMismatchOptional
+	results, err := a.val.ValidateBatch(ctx, uint64(batchNum), stopOnFirstMismatch)
+	if err != nil {
+		return nil, err
+	}
+	return toValidateBatchBlockResults(results), nil
+}
+
+// ValidateRange validates messages in [start, end). If resumable is set, progress is
+// checkpointed to the database, and an interrupted validation of the same range resumes
+// from its checkpoint, returning only the newly validated messages.
+func (a *BlockValidatorDebugAPI) ValidateRange(ctx context.Context, start, end hexutil.Uint64, stopOnFirstMismatchOptional *bool, resumableOptional *bool) ([]ValidateBatchBlockResult, error) {
+	stopOnFirstMismatch := stopOnFirstMismatchOptional != nil && *stopOnFirstMismatchOptional
+	var results []staker.BlockValidationResult
+	var err error
+	if resumableOptional != nil && *resumableOptional {
+		results, err = a.val.ValidateRangeWithCheckpoint(ctx, arbutil.MessageIndex(start), arbutil.MessageIndex(end), stopOnFirstMismatch)
+	} else {
+		results, err = a.val.ValidateRange(ctx, arbutil.MessageIndex(start), arbutil.MessageIndex(end), stopOnFirstMismatch)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toValidateBatchBlockResults(results), nil
+}
+
+// RevalidateReorged revalidates the previously validated messages reorged since, returning their results.
+func (a *BlockValidatorDebugAPI) RevalidateReorged(ctx context.Context, stopOnFirstMismatchOptional *bool) ([]ValidateBatchBlockResult, error) {
+	stopOnFirstMismatch := stopOnFirstMismatchOptional != nil && *stopOnFirstMismatchOptional
+	results, err := a.val.RevalidateReorged(ctx, stopOnFirstMismatch)
+	if err != nil {
+		return nil, err
+	}
+	return toValidateBatchBlockResults(results), nil
+}
+
+type ModuleRootActivation struct {
+	MessageNumber hexutil.Uint64 `json:"messageNumber"`
+	ModuleRoot    common.Hash    `json:"moduleRoot"`
+}
+
+// Backfill validates messages in [start, end) against the module root active for each message,
+// as given by activations ordered by message number, validating the messages of different module
+// roots concurrently if parallel backfill is enabled.
+func (a *BlockValidatorDebugAPI) Backfill(ctx context.Context, start, end hexutil.Uint64, activations []ModuleRootActivation, stopOnFirstMismatchOptional *bool) ([]ValidateBatchBlockResult, error) {
+	stopOnFirstMismatch := stopOnFirstMismatchOptional != nil && *stopOnFirstMismatchOptional
+	rootActivations := make([]staker.ModuleRootActivation, 0, len(activations))
+	for _, activation := range activations {
+		rootActivations = append(rootActivations, staker.ModuleRootActivation{
+			Pos:        arbutil.MessageIndex(activation.MessageNumber),
+			ModuleRoot: activation.ModuleRoot,
+		})
+	}
+	results, err := a.val.Backfill(ctx, arbutil.MessageIndex(start), arbutil.MessageIndex(end), rootActivations, stopOnFirstMismatch)
+	if err != nil {
+		return nil, err
+	}
+	return toValidateBatchBlockResults(results), nil
+}
+
+func toValidateBatchBlockResults(results []staker.BlockValidationResult) []ValidateBatchBlockResult {
+	apiResults := make([]ValidateBatchBlockResult, 0, len(results))
+	for _, res := range results {
+		apiResult := ValidateBatchBlockResult{
+			MessageNumber: hexutil.Uint64(res.Pos),
+			Valid:         res.Valid,
+		}
+		if res.GlobalState != nil {
+			apiResult.GlobalState = *res.GlobalState
+		}
+		if res.GasUsed != nil {
+			gasUsed := hexutil.Uint64(*res.GasUsed)
+			apiResult.GasUsed = &gasUsed
+		}
+		apiResults = append(apiResults, apiResult)
+	}
+	return apiResults
+}
+
 func (a *BlockValidatorDebugAPI) ValidationInputsAt(ctx context.Context, msgNum hexutil.Uint64, target rawdb.WasmTarget,
 ) (server_api.InputJSON, error) {
 	return a.val.ValidationInputsAt(ctx, arbutil.MessageIndex(msgNum), target)
@@ -75,3 +162,36 @@ func (a *MaintenanceAPI) SecondsSinceLastMaintenance(ctx context.Context) (int64
 func (a *MaintenanceAPI) Trigger(ctx context.Context) error {
 	return a.runner.Trigger()
 }
+
+type StakerAPI struct {
+	staker *multiprotocolstaker.MultiProtocolStaker
+}
+
+type PokeStakerResult struct {
+	Tx     *common.Hash `json:"tx,omitempty"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+// Poke makes the staker act immediately rather than waiting for its next poll, returning the
+// transaction it posted or the reason it didn't post one.
+func (a *StakerAPI) Poke(ctx context.Context) (PokeStakerResult, error) {
+	legacyStaker := a.staker.LegacyStaker()
+	if legacyStaker == nil {
+		return PokeStakerResult{}, errors.New("poking isn't supported by the BoLD staker")
+	}
+	result, err := legacyStaker.Poke(ctx)
+	if result.Tx == nil {
+		return PokeStakerResult{Reason: result.Reason}, err
+	}
+	hash := result.Tx.Hash()
+	return PokeStakerResult{Tx: &hash}, err
+}
+
+// State returns what the staker did in its latest act cycle, e.g. "staking" or "paused".
+func (a *StakerAPI) State(ctx context.Context) (string, error) {
+	legacyStaker := a.staker.LegacyStaker()
+	if legacyStaker == nil {
+		return "", errors.New("the BoLD staker doesn't report its state")
+	}
+	return legacyStaker.State().String(), nil
+}
@@ -488,12 +488,8 @@ func AccessList(opts *AccessListOpts) types.AccessList {
 				common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000000"), // totalDelayedMessagesRead
 				common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"), // bridge
 				common.HexToHash("0x000000000000000000000000000000000000000000000000000000000000000a"), // maxTimeVariation
-				// ADMIN_SLOT from OpenZeppelin, keccak-256 hash of
-				// "eip1967.proxy.admin" subtracted by 1.
-				common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"),
-				// IMPLEMENTATION_SLOT from OpenZeppelin,  keccak-256 hash
-				// of "eip1967.proxy.implementation" subtracted by 1.
-				common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"),
+				arbutil.ProxyAdminSlot,
+				arbutil.ProxyImplementationSlot,
 				// isBatchPoster[batchPosterAddr]; for mainnnet it's: "0xa10aa54071443520884ed767b0684edf43acec528b7da83ab38ce60126562660".
 				common.Hash(arbutil.PaddedKeccak256(opts.DataPosterAddr.Bytes(), []byte{3})),
 			},
@@ -505,12 +501,8 @@ func AccessList(opts *AccessListOpts) types.AccessList {
 				common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000007"), // sequencerInboxAccs.length
 				common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000009"), // sequencerInbox
 				common.HexToHash("0x000000000000000000000000000000000000000000000000000000000000000a"), // sequencerReportedSubMessageCount
-				// ADMIN_SLOT from OpenZeppelin, keccak-256 hash of
-				// "eip1967.proxy.admin" subtracted by 1.
-				common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"),
-				// IMPLEMENTATION_SLOT from OpenZeppelin,  keccak-256 hash
-				// of "eip1967.proxy.implementation" subtracted by 1.
-				common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"),
+				arbutil.ProxyAdminSlot,
+				arbutil.ProxyImplementationSlot,
 				// These below may change when transaction is actually executed:
 				// - delayedInboxAccs[delayedInboxAccs.length - 1]
 				// - delayedInboxAccs.push(...);
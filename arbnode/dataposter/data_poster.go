@@ -61,6 +61,9 @@ var (
 	latestUnconfirmedNonceGauge   = metrics.NewRegisteredGauge("arb/dataposter/nonce/unconfirmed", nil)
 	totalQueueLengthGauge         = metrics.NewRegisteredGauge("arb/dataposter/queue/length", nil)
 	totalQueueWeightGauge         = metrics.NewRegisteredGauge("arb/dataposter/queue/weight", nil)
+	cumulativeFeesGweiCounter     = metrics.NewRegisteredCounter("arb/dataposter/fees/cumulative_gwei", nil)
+	feeCeilingReachedCounter      = metrics.NewRegisteredCounter("arb/dataposter/fees/ceiling_reached", nil)
+	replacementsHistogram         = metrics.NewRegisteredHistogram("arb/dataposter/replacements", nil, metrics.NewBoundedHistogramSample())
 )
 
 // Dataposter implements functionality to post transactions on the chain. It
@@ -83,6 +86,9 @@ type DataPoster struct {
 	parentChainID     *big.Int
 	parentChainID256  *uint256.Int
 	parentChain       *parent.ParentChain
+	logger            log.Logger
+	// serializes nonce allocation with other data posters of the same sender, if sharing nonces
+	nonceCoordinator *NonceCoordinator
 
 	// These fields are protected by the mutex.
 	// TODO: factor out these fields into separate structure, since now one
@@ -95,6 +101,9 @@ type DataPoster struct {
 	nonce      uint64
 	queue      QueueStorage
 	errorCount map[uint64]int // number of consecutive intermittent errors rbf-ing or sending, per nonce
+	// number of times each transaction posted since starting was replaced-by-fee, per nonce
+	replacements map[uint64]uint64
+	feeBudget    *feeBudget
 
 	maxFeeCapExpression *govaluate.EvaluableExpression
 }
@@ -114,6 +123,7 @@ type DataPosterOpts struct {
 	ExtraBacklog      func() uint64
 	RedisKey          string // Redis storage key
 	ParentChainID     *big.Int
+	Logger            log.Logger // Optional, defaults to the root logger
 }
 
 func NewDataPoster(ctx context.Context, opts *DataPosterOpts) (*DataPoster, error) {
@@ -148,6 +158,10 @@ func NewDataPoster(ctx context.Context, opts *DataPosterOpts) (*DataPoster, erro
 		}
 		queue = storage
 	default:
+		if cfg.ShareNonces {
+			// The in-memory queue requires consecutive nonces, which sharing them breaks.
+			return nil, errors.New("data poster share-nonces requires use-db-storage or redis storage")
+		}
 		queue = slice.NewStorage(func() storage.EncoderDecoderInterface { return &storage.EncoderDecoder{} })
 	}
 	expression, err := govaluate.NewEvaluableExpression(cfg.MaxFeeCapFormula)
@@ -166,10 +180,13 @@ func NewDataPoster(ctx context.Context, opts *DataPosterOpts) (*DataPoster, erro
 		metadataRetriever:   opts.MetadataRetriever,
 		queue:               queue,
 		errorCount:          make(map[uint64]int),
+		replacements:        make(map[uint64]uint64),
+		feeBudget:           newFeeBudget(),
 		maxFeeCapExpression: expression,
 		extraBacklog:        opts.ExtraBacklog,
 		parentChainID:       opts.ParentChainID,
 		parentChain:         &parent.ParentChain{ChainID: opts.ParentChainID, L1Reader: opts.HeaderReader},
+		logger:              opts.Logger,
 	}
 	var overflow bool
 	dp.parentChainID256, overflow = uint256.FromBig(opts.ParentChainID)
@@ -179,6 +196,9 @@ func NewDataPoster(ctx context.Context, opts *DataPosterOpts) (*DataPoster, erro
 	if dp.extraBacklog == nil {
 		dp.extraBacklog = func() uint64 { return 0 }
 	}
+	if dp.logger == nil {
+		dp.logger = log.Root()
+	}
 	if cfg.ExternalSigner.URL != "" {
 		signer, sender, err := externalSigner(ctx, &cfg.ExternalSigner)
 		if err != nil {
@@ -192,6 +212,9 @@ func NewDataPoster(ctx context.Context, opts *DataPosterOpts) (*DataPoster, erro
 			},
 		}
 	}
+	if cfg.ShareNonces {
+		dp.nonceCoordinator = SharedNonceCoordinator(opts.ParentChainID, dp.Sender())
+	}
 
 	return dp, nil
 }
@@ -438,7 +461,7 @@ func (p *DataPoster) getNextNonceAndMaybeMeta(ctx context.Context, thisWeight ui
 		return 0, nil, false, 0, fmt.Errorf("fetching last element from queue: %w", err)
 	}
 	if lastQueueItem != nil {
-		nextNonce := lastQueueItem.FullTx.Nonce() + 1
+		nextNonce := p.coordinatedNonce(lastQueueItem.FullTx.Nonce() + 1)
 		if err := p.canPostWithNonce(ctx, nextNonce, thisWeight); err != nil {
 			return 0, nil, false, 0, err
 		}
@@ -451,7 +474,7 @@ func (p *DataPoster) getNextNonceAndMaybeMeta(ctx context.Context, thisWeight ui
 		}
 		// Fall back to using a recent block to get the nonce. This is safe because there's nothing in the queue.
 		nonceQueryBlock := arbmath.UintToBig(arbmath.SaturatingUSub(blockNum, 1))
-		log.Warn("failed to update nonce with queue empty; falling back to using a recent block", "recentBlock", nonceQueryBlock, "err", err)
+		p.logger.Warn("failed to update nonce with queue empty; falling back to using a recent block", "recentBlock", nonceQueryBlock, "err", err)
 		nonce, err := p.client.NonceAt(ctx, p.Sender(), nonceQueryBlock)
 		if err != nil {
 			return 0, nil, false, 0, fmt.Errorf("failed to get nonce at block %v: %w", nonceQueryBlock, err)
@@ -459,7 +482,16 @@ func (p *DataPoster) getNextNonceAndMaybeMeta(ctx context.Context, thisWeight ui
 		p.lastBlock = nonceQueryBlock
 		p.nonce = nonce
 	}
-	return p.nonce, nil, false, p.nonce, nil
+	return p.coordinatedNonce(p.nonce), nil, false, p.nonce, nil
+}
+
+// coordinatedNonce returns the next nonce to use given the data poster's own next nonce,
+// skipping nonces used by other data posters of the same sender if sharing nonces.
+func (p *DataPoster) coordinatedNonce(nonce uint64) uint64 {
+	if p.nonceCoordinator == nil {
+		return nonce
+	}
+	return p.nonceCoordinator.NextNonce(nonce)
 }
 
 // GetNextNonceAndMeta retrieves generates next nonce, validates that a
@@ -613,7 +645,7 @@ func (p *DataPoster) feeAndTipCaps(ctx context.Context, nonce uint64, gasLimit u
 	}
 
 	if arbmath.BigGreaterThan(targetMaxCost, balanceForTx) {
-		log.Warn(
+		p.logger.Warn(
 			"lack of L1 balance prevents posting transaction with desired fee cap",
 			"balance", latestBalance,
 			"weight", weight,
@@ -670,7 +702,7 @@ func (p *DataPoster) feeAndTipCaps(ctx context.Context, nonce uint64, gasLimit u
 	}
 
 	if arbmath.BigGreaterThan(newTipCap, newBaseFeeCap) {
-		log.Info(
+		p.logger.Info(
 			"reducing new tip cap to new basefee cap",
 			"proposedTipCap", newTipCap,
 			"newBasefeeCap", newBaseFeeCap,
@@ -693,11 +725,11 @@ func (p *DataPoster) feeAndTipCaps(ctx context.Context, nonce uint64, gasLimit u
 		"newBlobFeeCap", newBlobFeeCap,
 	}
 
-	log.Debug("calculated data poster fee and tip caps", logFields...)
+	p.logger.Debug("calculated data poster fee and tip caps", logFields...)
 
 	if newBaseFeeCap.Sign() < 0 || newTipCap.Sign() < 0 || newBlobFeeCap.Sign() < 0 {
 		msg := "can't meet data poster fee cap obligations with current target max cost"
-		log.Info(msg, logFields...)
+		p.logger.Info(msg, logFields...)
 		if lastTx != nil {
 			// wait until we have a higher target max cost to replace by fee
 			return lastTx.GasFeeCap(), lastTx.GasTipCap(), lastTx.BlobGasFeeCap(), nil
@@ -713,7 +745,7 @@ func (p *DataPoster) feeAndTipCaps(ctx context.Context, nonce uint64, gasLimit u
 		// E.g. instead of 2x basefee and 2x blobfee, we might actually want to 4x basefee and 2x blobfee.
 		// This check lets us hold off on the rbf until we are actually meet the current fee requirements,
 		// which lets us move in a particular direction (biasing towards either basefee or blobfee).
-		log.Info("can't meet current parent chain fees with current target max cost", logFields...)
+		p.logger.Info("can't meet current parent chain fees with current target max cost", logFields...)
 		// wait until we have a higher target max cost to replace by fee
 		return lastTx.GasFeeCap(), lastTx.GasTipCap(), lastTx.BlobGasFeeCap(), nil
 	}
@@ -732,6 +764,7 @@ func (p *DataPoster) feeAndTipCaps(ctx context.Context, nonce uint64, gasLimit u
 func (p *DataPoster) PostSimpleTransaction(ctx context.Context, to common.Address, calldata []byte, gasLimit uint64, value *big.Int) (*types.Transaction, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
+	defer p.lockNonceCoordinator()()
 	nonce, _, _, _, err := p.getNextNonceAndMaybeMeta(ctx, 1)
 	if err != nil {
 		return nil, err
@@ -742,9 +775,22 @@ func (p *DataPoster) PostSimpleTransaction(ctx context.Context, to common.Addres
 func (p *DataPoster) PostTransaction(ctx context.Context, dataCreatedAt time.Time, nonce uint64, meta []byte, to common.Address, calldata []byte, gasLimit uint64, value *big.Int, kzgBlobs []kzg4844.Blob, accessList types.AccessList) (*types.Transaction, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
+	defer p.lockNonceCoordinator()()
 	return p.postTransactionWithMutex(ctx, dataCreatedAt, nonce, meta, to, calldata, gasLimit, value, kzgBlobs, accessList)
 }
 
+// lockNonceCoordinator stops the other data posters of the sender posting, if sharing nonces,
+// until the returned function is called. A nonce obtained from GetNextNonceAndMeta before
+// locking it may have been used by another data poster since, failing with storage.ErrStorageRace.
+// The mutex must be held by the caller.
+func (p *DataPoster) lockNonceCoordinator() func() {
+	if p.nonceCoordinator == nil {
+		return func() {}
+	}
+	p.nonceCoordinator.postMutex.Lock()
+	return p.nonceCoordinator.postMutex.Unlock
+}
+
 func (p *DataPoster) postTransactionWithMutex(ctx context.Context, dataCreatedAt time.Time, nonce uint64, meta []byte, to common.Address, calldata []byte, gasLimit uint64, value *big.Int, kzgBlobs []kzg4844.Blob, accessList types.AccessList) (*types.Transaction, error) {
 
 	if p.config().DisableNewTx {
@@ -846,7 +892,16 @@ func (p *DataPoster) postTransactionWithMutex(ctx context.Context, dataCreatedAt
 		NextReplacement:        time.Now().Add(replacementTimes[0]),
 		StoredCumulativeWeight: &cumulativeWeight,
 	}
-	return fullTx, p.sendTx(ctx, nil, &queuedTx)
+	p.feeBudget.record(time.Now(), maxTxFee(fullTx))
+	p.replacements[nonce] = 0
+	err = p.sendTx(ctx, nil, &queuedTx)
+	if p.nonceCoordinator != nil {
+		// The transaction is queued, and so its nonce used, unless sendTx failed before saving it
+		if queued, getErr := p.queue.Get(ctx, nonce); err == nil || (getErr == nil && queued != nil) {
+			p.nonceCoordinator.Posted(nonce)
+		}
+	}
+	return fullTx, err
 }
 
 // the mutex must be held by the caller
@@ -890,7 +945,7 @@ func (p *DataPoster) sendTx(ctx context.Context, prevTx *storage.QueuedTransacti
 	}
 
 	if arbmath.BigLessThan(newTx.FullTx.GasFeeCap(), latestHeader.BaseFee) {
-		log.Info(
+		p.logger.Info(
 			"submitting transaction with GasFeeCap less than latest basefee",
 			"txBasefeeCap", newTx.FullTx.GasFeeCap(),
 			"latestBasefee", latestHeader.BaseFee,
@@ -899,7 +954,7 @@ func (p *DataPoster) sendTx(ctx context.Context, prevTx *storage.QueuedTransacti
 	}
 
 	if newTx.FullTx.BlobGasFeeCap() != nil && currentBlobFee != nil && arbmath.BigLessThan(newTx.FullTx.BlobGasFeeCap(), currentBlobFee) {
-		log.Info(
+		p.logger.Info(
 			"submitting transaction with BlobGasFeeCap less than latest blobfee",
 			"txBlobGasFeeCap", newTx.FullTx.BlobGasFeeCap(),
 			"latestBlobFee", currentBlobFee,
@@ -937,11 +992,11 @@ func (p *DataPoster) sendTx(ctx context.Context, prevTx *storage.QueuedTransacti
 				}
 
 				if newTx.FullTx.Nonce() > reorgResistantTxCount {
-					log.Info("DataPoster is avoiding creating a mempool nonce gap (the tx remains queued and will be retried)", "nonce", newTx.FullTx.Nonce(), "prevType", precedingTx.FullTx.Type(), "type", newTx.FullTx.Type(), "prevSent", precedingTx.Sent, "latestBlockNumber", latestBlockNumber, "prevBlockNumber", prevBlockNumber, "reorgResistantTxCount", reorgResistantTxCount)
+					p.logger.Info("DataPoster is avoiding creating a mempool nonce gap (the tx remains queued and will be retried)", "nonce", newTx.FullTx.Nonce(), "prevType", precedingTx.FullTx.Type(), "type", newTx.FullTx.Type(), "prevSent", precedingTx.Sent, "latestBlockNumber", latestBlockNumber, "prevBlockNumber", prevBlockNumber, "reorgResistantTxCount", reorgResistantTxCount)
 					return nil
 				}
 			}
-			log.Debug("DataPoster will send previously unsent batch tx", "nonce", newTx.FullTx.Nonce(), "prevType", precedingTx.FullTx.Type(), "type", newTx.FullTx.Type(), "prevSent", precedingTx.Sent, "latestBlockNumber", latestBlockNumber, "prevBlockNumber", prevBlockNumber, "reorgResistantTxCount", reorgResistantTxCount)
+			p.logger.Debug("DataPoster will send previously unsent batch tx", "nonce", newTx.FullTx.Nonce(), "prevType", precedingTx.FullTx.Type(), "type", newTx.FullTx.Type(), "prevSent", precedingTx.Sent, "latestBlockNumber", latestBlockNumber, "prevBlockNumber", prevBlockNumber, "reorgResistantTxCount", reorgResistantTxCount)
 		}
 	}
 
@@ -953,12 +1008,12 @@ func (p *DataPoster) sendTx(ctx context.Context, prevTx *storage.QueuedTransacti
 		_, _, errTxByHash := p.client.TransactionByHash(ctx, newTx.FullTx.Hash())
 		isAlreadyKnown = isAlreadyKnown || (strings.Contains(err.Error(), "ReplacementNotAllowed") && errTxByHash == nil)
 		if !isAlreadyKnown {
-			log.Warn("DataPoster failed to send transaction", "err", err, "nonce", newTx.FullTx.Nonce(), "feeCap", newTx.FullTx.GasFeeCap(), "tipCap", newTx.FullTx.GasTipCap(), "blobFeeCap", newTx.FullTx.BlobGasFeeCap(), "gas", newTx.FullTx.Gas())
+			p.logger.Warn("DataPoster failed to send transaction", "err", err, "nonce", newTx.FullTx.Nonce(), "feeCap", newTx.FullTx.GasFeeCap(), "tipCap", newTx.FullTx.GasTipCap(), "blobFeeCap", newTx.FullTx.BlobGasFeeCap(), "gas", newTx.FullTx.Gas())
 			return err
 		}
-		log.Info("DataPoster transaction already known", "err", err, "nonce", newTx.FullTx.Nonce(), "hash", newTx.FullTx.Hash())
+		p.logger.Info("DataPoster transaction already known", "err", err, "nonce", newTx.FullTx.Nonce(), "hash", newTx.FullTx.Hash())
 	} else {
-		log.Info("DataPoster sent transaction", "nonce", newTx.FullTx.Nonce(), "hash", newTx.FullTx.Hash(), "feeCap", newTx.FullTx.GasFeeCap(), "tipCap", newTx.FullTx.GasTipCap(), "blobFeeCap", newTx.FullTx.BlobGasFeeCap(), "gas", newTx.FullTx.Gas())
+		p.logger.Info("DataPoster sent transaction", "nonce", newTx.FullTx.Nonce(), "hash", newTx.FullTx.Hash(), "feeCap", newTx.FullTx.GasFeeCap(), "tipCap", newTx.FullTx.GasTipCap(), "blobFeeCap", newTx.FullTx.BlobGasFeeCap(), "gas", newTx.FullTx.Gas())
 	}
 	newerTx := *newTx
 	newerTx.Sent = true
@@ -1020,7 +1075,7 @@ func (p *DataPoster) replaceTx(ctx context.Context, prevTx *storage.QueuedTransa
 	newTx := *prevTx
 	if (prevTx.FullTx.GasFeeCap().Sign() > 0 && arbmath.BigDivToBips(newFeeCap, prevTx.FullTx.GasFeeCap()) < minRbfIncrease) ||
 		(prevTx.FullTx.BlobGasFeeCap() != nil && prevTx.FullTx.BlobGasFeeCap().Sign() > 0 && arbmath.BigDivToBips(newBlobFeeCap, prevTx.FullTx.BlobGasFeeCap()) < minRbfIncrease) {
-		log.Debug(
+		p.logger.Debug(
 			"no need to replace by fee transaction",
 			"nonce", prevTx.FullTx.Nonce(),
 			"lastFeeCap", prevTx.FullTx.GasFeeCap(),
@@ -1034,30 +1089,62 @@ func (p *DataPoster) replaceTx(ctx context.Context, prevTx *storage.QueuedTransa
 		return p.sendTx(ctx, prevTx, &newTx)
 	}
 
-	replacementTimes := p.config().ReplacementTimes
+	unsignedTx, err := updateGasCaps(newTx.FullTx, newFeeCap, newTipCap, newBlobFeeCap)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	feeIncrease := maxTxFeeIncrease(prevTx.FullTx, unsignedTx)
+	config := p.config()
+	replacementTimes := config.ReplacementTimes
 	if len(prevTx.FullTx.BlobHashes()) > 0 {
-		replacementTimes = p.config().BlobTxReplacementTimes
+		replacementTimes = config.BlobTxReplacementTimes
 	}
-
+	// the next replacement is at the first replacement time that hasn't elapsed yet, if any
+	var nextReplacement time.Time
 	elapsed := time.Since(prevTx.Created)
 	for _, replacement := range replacementTimes {
 		if elapsed >= replacement {
 			continue
 		}
-		newTx.NextReplacement = prevTx.Created.Add(replacement)
+		nextReplacement = prevTx.Created.Add(replacement)
 		break
 	}
+	if !p.feeBudget.allows(now, config.CumulativeFeeWindow, config.maxCumulativeFee(), feeIncrease) {
+		p.logger.Warn(
+			"DataPoster reached its cumulative fee ceiling, not replacing transaction by fee",
+			"nonce", prevTx.FullTx.Nonce(),
+			"lastFeeCap", prevTx.FullTx.GasFeeCap(),
+			"recommendedFeeCap", newFeeCap,
+			"lastBlobFeeCap", prevTx.FullTx.BlobGasFeeCap(),
+			"recommendedBlobFeeCap", newBlobFeeCap,
+			"feesInWindow", p.feeBudget.spent(now, config.CumulativeFeeWindow),
+			"maxCumulativeFeeGwei", config.MaxCumulativeFeeGwei,
+			"window", config.CumulativeFeeWindow,
+		)
+		feeCeilingReachedCounter.Inc(1)
+		// retry at the next replacement time, or after the last one's interval once past them all
+		if nextReplacement.IsZero() {
+			nextReplacement = now.Add(replacementTimes[len(replacementTimes)-1])
+		}
+		newTx.NextReplacement = nextReplacement
+		return p.sendTx(ctx, prevTx, &newTx)
+	}
+
+	if !nextReplacement.IsZero() {
+		newTx.NextReplacement = nextReplacement
+	}
 	newTx.Sent = false
 	newTx.DeprecatedData.GasFeeCap = newFeeCap
 	newTx.DeprecatedData.GasTipCap = newTipCap
-	unsignedTx, err := updateGasCaps(newTx.FullTx, newFeeCap, newTipCap, newBlobFeeCap)
-	if err != nil {
-		return err
-	}
 	newTx.FullTx, err = p.signer(ctx, p.Sender(), unsignedTx)
 	if err != nil {
 		return err
 	}
+	p.feeBudget.record(now, feeIncrease)
+	if replacements, ok := p.replacements[prevTx.FullTx.Nonce()]; ok {
+		p.replacements[prevTx.FullTx.Nonce()] = replacements + 1
+	}
 
 	return p.sendTx(ctx, prevTx, &newTx)
 }
@@ -1080,7 +1167,7 @@ func (p *DataPoster) updateNonce(ctx context.Context) error {
 	nonce, err := p.client.NonceAt(ctx, p.Sender(), header.Number)
 	if err != nil {
 		if p.lastBlock != nil {
-			log.Warn("Failed to get current nonce", "lastBlock", p.lastBlock, "newBlock", header.Number, "err", err)
+			p.logger.Warn("Failed to get current nonce", "lastBlock", p.lastBlock, "newBlock", header.Number, "err", err)
 			return nil
 		}
 		return err
@@ -1095,12 +1182,22 @@ func (p *DataPoster) updateNonce(ctx context.Context) error {
 	}
 	// #nosec G115
 	latestFinalizedNonceGauge.Update(int64(nonce))
-	log.Info("Data poster transactions confirmed", "previousNonce", p.nonce, "newNonce", nonce, "previousL1Block", p.lastBlock, "newL1Block", header.Number)
+	p.logger.Info("Data poster transactions confirmed", "previousNonce", p.nonce, "newNonce", nonce, "previousL1Block", p.lastBlock, "newL1Block", header.Number)
 	if len(p.errorCount) > 0 {
 		for x := p.nonce; x < nonce; x++ {
 			delete(p.errorCount, x)
 		}
 	}
+	p.recordConfirmedFees(ctx, nonce)
+	if len(p.replacements) > 0 {
+		for x := p.nonce; x < nonce; x++ {
+			if replacements, ok := p.replacements[x]; ok {
+				// #nosec G115
+				replacementsHistogram.Update(int64(replacements))
+				delete(p.replacements, x)
+			}
+		}
+	}
 	// We don't prune the most recent transaction in order to ensure that the data poster
 	// always has a reference point in its queue of the latest transaction nonce and metadata.
 	// nonce > 0 is implied by nonce > p.nonce, so this won't underflow.
@@ -1113,6 +1210,50 @@ func (p *DataPoster) updateNonce(ctx context.Context) error {
 	return nil
 }
 
+// recordConfirmedFees adds the fees paid by the queued transactions confirmed up to nonce to the cumulative fees metric,
+// as per their receipts, fetched in a single batch. Transactions whose receipt isn't found, e.g. as an earlier
+// replacement was included instead, aren't counted. The mutex must be held by the caller.
+func (p *DataPoster) recordConfirmedFees(ctx context.Context, nonce uint64) {
+	if p.lastBlock == nil {
+		// the first nonce update only finds where the queue starts, confirming nothing new
+		return
+	}
+	confirmed, err := p.queue.FetchContents(ctx, p.nonce, nonce-p.nonce)
+	if err != nil {
+		p.logger.Warn("Failed to fetch confirmed transactions", "previousNonce", p.nonce, "newNonce", nonce, "err", err)
+		return
+	}
+	var batch []rpc.BatchElem
+	receipts := make([]*types.Receipt, len(confirmed))
+	for i, tx := range confirmed {
+		if tx == nil || tx.FullTx == nil || tx.FullTx.Nonce() >= nonce {
+			continue
+		}
+		batch = append(batch, rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []any{tx.FullTx.Hash()},
+			Result: &receipts[i],
+		})
+	}
+	if len(batch) == 0 {
+		return
+	}
+	if err := p.client.Client().BatchCallContext(ctx, batch); err != nil {
+		p.logger.Warn("Failed to get receipts of confirmed transactions", "previousNonce", p.nonce, "newNonce", nonce, "err", err)
+		return
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			p.logger.Debug("Failed to get receipt of confirmed transaction", "hash", elem.Args[0], "err", elem.Error)
+		}
+	}
+	for _, receipt := range receipts {
+		if receipt != nil {
+			cumulativeFeesGweiCounter.Inc(arbmath.BigDivByUint(receiptFee(receipt), params.GWei).Int64())
+		}
+	}
+}
+
 // Updates dataposter balance to balance at pending block.
 func (p *DataPoster) updateBalance(ctx context.Context) error {
 	// Use the pending (representated as -1) balance because we're looking at batches we'd post,
@@ -1164,13 +1305,13 @@ func (p *DataPoster) Start(ctxIn context.Context) {
 		defer p.mutex.Unlock()
 		err := p.updateBalance(ctx)
 		if err != nil {
-			log.Warn("failed to update tx poster balance", "err", err)
+			p.logger.Warn("failed to update tx poster balance", "err", err)
 			return minWait
 		}
 		err = p.updateNonce(ctx)
 		if err != nil {
 			// This is non-fatal because it's only needed for clearing out old queue items.
-			log.Warn("failed to update tx poster nonce", "err", err)
+			p.logger.Warn("failed to update tx poster nonce", "err", err)
 		}
 		now := time.Now()
 		nextCheck := now.Add(arbmath.MinInt(p.config().ReplacementTimes[0], p.config().BlobTxReplacementTimes[0]))
@@ -1180,7 +1321,7 @@ func (p *DataPoster) Start(ctxIn context.Context) {
 		}
 		unconfirmedNonce, err := p.client.NonceAt(ctx, p.Sender(), nil)
 		if err != nil {
-			log.Warn("Failed to get latest nonce", "err", err)
+			p.logger.Warn("Failed to get latest nonce", "err", err)
 			return minWait
 		}
 		// #nosec G115
@@ -1190,12 +1331,12 @@ func (p *DataPoster) Start(ctxIn context.Context) {
 		// replacing them by fee.
 		queueContents, err := p.queue.FetchContents(ctx, unconfirmedNonce, maxTxsToRbf)
 		if err != nil {
-			log.Error("Failed to fetch tx queue contents", "err", err)
+			p.logger.Error("Failed to fetch tx queue contents", "err", err)
 			return minWait
 		}
 		latestQueued, err := p.queue.FetchLast(ctx)
 		if err != nil {
-			log.Error("Failed to fetch last queued tx", "err", err)
+			p.logger.Error("Failed to fetch last queued tx", "err", err)
 			return minWait
 		}
 		var latestCumulativeWeight, latestNonce uint64
@@ -1204,14 +1345,18 @@ func (p *DataPoster) Start(ctxIn context.Context) {
 			latestNonce = latestQueued.FullTx.Nonce()
 
 			confirmedNonce := unconfirmedNonce - 1
-			confirmedMeta, err := p.queue.Get(ctx, confirmedNonce)
-			if err == nil && confirmedMeta != nil {
+			if p.nonceCoordinator != nil {
+				// Other data posters own some of the nonces, so only this poster's queued
+				// transactions are counted. Their weight can't be told apart from the confirmed ones.
+				// #nosec G115
+				totalQueueLengthGauge.Update(int64(len(queueContents)))
+			} else if confirmedMeta, err := p.queue.Get(ctx, confirmedNonce); err == nil && confirmedMeta != nil {
 				// #nosec G115
 				totalQueueWeightGauge.Update(int64(arbmath.SaturatingUSub(latestCumulativeWeight, confirmedMeta.CumulativeWeight())))
 				// #nosec G115
 				totalQueueLengthGauge.Update(int64(arbmath.SaturatingUSub(latestNonce, confirmedNonce)))
 			} else {
-				log.Error("Failed to fetch latest confirmed tx from queue", "confirmedNonce", confirmedNonce, "err", err, "confirmedMeta", confirmedMeta)
+				p.logger.Error("Failed to fetch latest confirmed tx from queue", "confirmedNonce", confirmedNonce, "err", err, "confirmedMeta", confirmedMeta)
 			}
 
 		}
@@ -1228,7 +1373,7 @@ func (p *DataPoster) Start(ctxIn context.Context) {
 			}
 			tx, err = p.queue.Get(ctx, tx.FullTx.Nonce())
 			if err != nil {
-				log.Error("Failed to fetch tx from queue to check updated status", "nonce", tx.FullTx.Nonce(), "err", err)
+				p.logger.Error("Failed to fetch tx from queue to check updated status", "nonce", tx.FullTx.Nonce(), "err", err)
 				return minWait
 			}
 			if nextCheck.After(tx.NextReplacement) {
@@ -1300,6 +1445,9 @@ type DataPosterConfig struct {
 	MaxFeeCapFormula       string            `koanf:"max-fee-cap-formula" reload:"hot"`
 	ElapsedTimeBase        time.Duration     `koanf:"elapsed-time-base" reload:"hot"`
 	ElapsedTimeImportance  float64           `koanf:"elapsed-time-importance" reload:"hot"`
+	ShareNonces            bool              `koanf:"share-nonces"`
+	MaxCumulativeFeeGwei   float64           `koanf:"max-cumulative-fee-gwei" reload:"hot"`
+	CumulativeFeeWindow    time.Duration     `koanf:"cumulative-fee-window" reload:"hot"`
 	// When set, dataposter will not post new batches, but will keep running to
 	// get existing batches confirmed.
 	DisableNewTx bool `koanf:"disable-new-tx" reload:"hot"`
@@ -1376,6 +1524,9 @@ func DataPosterConfigAddOptions(prefix string, f *pflag.FlagSet, defaultDataPost
 		"Currently available variables to construct the formula are BacklogOfBatches, UrgencyGWei, ElapsedTime, ElapsedTimeBase, ElapsedTimeImportance, and TargetPriceGWei")
 	f.Duration(prefix+".elapsed-time-base", defaultDataPosterConfig.ElapsedTimeBase, "unit to measure the time elapsed since creation of transaction used for maximum fee cap calculation")
 	f.Float64(prefix+".elapsed-time-importance", defaultDataPosterConfig.ElapsedTimeImportance, "weight given to the units of time elapsed used for maximum fee cap calculation")
+	f.Bool(prefix+".share-nonces", defaultDataPosterConfig.ShareNonces, "coordinate nonces with the node's other data posters sending from the same address, e.g. a staker sharing the batch poster's key (must be enabled on each of them)")
+	f.Float64(prefix+".max-cumulative-fee-gwei", defaultDataPosterConfig.MaxCumulativeFeeGwei, "the maximum fees in gwei the transactions posted and replaced-by-fee within the cumulative fee window may pay, after which replacing transactions by fee waits (0 = unlimited)")
+	f.Duration(prefix+".cumulative-fee-window", defaultDataPosterConfig.CumulativeFeeWindow, "the rolling window max-cumulative-fee-gwei applies to")
 
 	signature.SimpleHmacConfigAddOptions(prefix+".redis-signer", f)
 	addDangerousOptions(prefix+".dangerous", f)
@@ -1421,6 +1572,9 @@ var DefaultDataPosterConfig = DataPosterConfig{
 	MaxFeeCapFormula:       "((BacklogOfBatches * UrgencyGWei) ** 2) + ((ElapsedTime/ElapsedTimeBase) ** 2) * ElapsedTimeImportance + TargetPriceGWei",
 	ElapsedTimeBase:        10 * time.Minute,
 	ElapsedTimeImportance:  10,
+	ShareNonces:            false,
+	MaxCumulativeFeeGwei:   0,
+	CumulativeFeeWindow:    24 * time.Hour,
 	DisableNewTx:           false,
 }
 
@@ -1456,6 +1610,9 @@ var TestDataPosterConfig = DataPosterConfig{
 	MaxFeeCapFormula:       "((BacklogOfBatches * UrgencyGWei) ** 2) + ((ElapsedTime/ElapsedTimeBase) ** 2) * ElapsedTimeImportance + TargetPriceGWei",
 	ElapsedTimeBase:        10 * time.Minute,
 	ElapsedTimeImportance:  10,
+	ShareNonces:            false,
+	MaxCumulativeFeeGwei:   0,
+	CumulativeFeeWindow:    24 * time.Hour,
 	DisableNewTx:           false,
 }
 
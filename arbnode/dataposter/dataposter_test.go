@@ -21,6 +21,8 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/offchainlabs/nitro/arbnode/dataposter/externalsignertest"
+	"github.com/offchainlabs/nitro/arbnode/dataposter/slice"
+	"github.com/offchainlabs/nitro/arbnode/dataposter/storage"
 	"github.com/offchainlabs/nitro/arbnode/parent"
 	"github.com/offchainlabs/nitro/util/arbmath"
 )
@@ -143,6 +145,8 @@ func TestMaxFeeCapFormulaCalculation(t *testing.T) {
 type stubL1ClientInner struct {
 	senderNonce        uint64
 	suggestedGasTipCap *big.Int
+	receipts           map[common.Hash]*types.Receipt
+	batchSizes         []int
 }
 
 func (c *stubL1ClientInner) CallContext(ctx_in context.Context, result interface{}, method string, args ...interface{}) error {
@@ -167,6 +171,16 @@ func (c *stubL1ClientInner) EthSubscribe(ctx context.Context, channel interface{
 	return nil, nil
 }
 func (c *stubL1ClientInner) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	c.batchSizes = append(c.batchSizes, len(b))
+	for _, elem := range b {
+		if elem.Method == "eth_getTransactionReceipt" {
+			hash, ok := elem.Args[0].(common.Hash)
+			if !ok {
+				return errors.New("argument is not a common.Hash")
+			}
+			*elem.Result.(**types.Receipt) = c.receipts[hash]
+		}
+	}
 	return nil
 }
 func (c *stubL1ClientInner) Close() {}
@@ -433,3 +447,108 @@ func TestFeeAndTipCaps_RBF_RisingBlobFee_FallingBaseFee(t *testing.T) {
 	}
 
 }
+
+func TestCumulativeFeeCeiling(t *testing.T) {
+	config := TestDataPosterConfig
+	config.MaxCumulativeFeeGwei = 4_000_000
+	config.CumulativeFeeWindow = time.Hour
+	limit := config.maxCumulativeFee()
+	txWithFeeCap := func(feeCapGwei int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{
+			Nonce:     1,
+			GasFeeCap: arbmath.BigMulByUint(big.NewInt(feeCapGwei), params.GWei),
+			Gas:       21_000,
+			Value:     big.NewInt(params.Ether),
+		})
+	}
+	start := time.Now()
+	budget := newFeeBudget()
+
+	// posting at 100 gwei commits to 2.1M gwei of fees, and replacing at 150 gwei to another 1.05M gwei
+	posted := txWithFeeCap(100)
+	if got, want := maxTxFee(posted), arbmath.BigMulByUint(big.NewInt(2_100_000), params.GWei); got.Cmp(want) != 0 {
+		t.Fatalf("Unexpected max fee of posted transaction. Got: %v, want: %v", got, want)
+	}
+	budget.record(start, maxTxFee(posted))
+	replaced := txWithFeeCap(150)
+	increase := maxTxFeeIncrease(posted, replaced)
+	if !budget.allows(start, config.CumulativeFeeWindow, limit, increase) {
+		t.Fatal("Replacement within the fee ceiling wasn't allowed")
+	}
+	budget.record(start, increase)
+
+	// replacing at 225 gwei would commit to 4.725M gwei, above the 4M gwei ceiling
+	bumped := txWithFeeCap(225)
+	increase = maxTxFeeIncrease(replaced, bumped)
+	if budget.allows(start.Add(time.Minute), config.CumulativeFeeWindow, limit, increase) {
+		t.Fatal("Replacement above the fee ceiling was allowed")
+	}
+	if !budget.allows(start.Add(time.Minute), config.CumulativeFeeWindow, nil, increase) {
+		t.Fatal("Replacement wasn't allowed without a fee ceiling")
+	}
+
+	// once the earlier fees fall out of the window, bumping resumes
+	if !budget.allows(start.Add(config.CumulativeFeeWindow), config.CumulativeFeeWindow, limit, increase) {
+		t.Fatal("Replacement wasn't allowed after the earlier fees left the window")
+	}
+	if spent := budget.spent(start.Add(config.CumulativeFeeWindow), config.CumulativeFeeWindow); spent.Sign() != 0 {
+		t.Fatalf("Unexpected fees in window after it passed. Got: %v, want: 0", spent)
+	}
+
+	// lowering the fee caps doesn't refund the budget
+	if increase := maxTxFeeIncrease(bumped, posted); increase.Sign() != 0 {
+		t.Fatalf("Unexpected fee increase lowering the fee cap. Got: %v, want: 0", increase)
+	}
+
+	config.MaxCumulativeFeeGwei = 0
+	if config.maxCumulativeFee() != nil {
+		t.Fatal("Expected no fee ceiling when max-cumulative-fee-gwei is 0")
+	}
+}
+
+func TestRecordConfirmedFees(t *testing.T) {
+	ctx := context.Background()
+	queue := slice.NewStorage(func() storage.EncoderDecoderInterface { return &storage.EncoderDecoder{} })
+	client := &stubL1ClientInner{receipts: make(map[common.Hash]*types.Receipt)}
+	for nonce := uint64(5); nonce < 8; nonce++ {
+		tx := types.NewTx(&types.DynamicFeeTx{Nonce: nonce})
+		if err := queue.Put(ctx, nonce, nil, &storage.QueuedTransaction{FullTx: tx}); err != nil {
+			t.Fatal(err)
+		}
+		// the transaction at nonce 6 was replaced, so its receipt isn't found
+		if nonce != 6 {
+			client.receipts[tx.Hash()] = &types.Receipt{GasUsed: 1_000_000, EffectiveGasPrice: big.NewInt(params.GWei)}
+		}
+	}
+	p := &DataPoster{queue: queue, client: ethclient.NewClient(client), logger: log.Root(), nonce: 5}
+
+	// the first nonce update confirms nothing new
+	p.recordConfirmedFees(ctx, 8)
+	if len(client.batchSizes) != 0 {
+		t.Fatalf("Unexpected receipt fetches on the first nonce update. Got: %v, want: none", client.batchSizes)
+	}
+
+	// the receipts of the confirmed transactions are fetched in a single batch
+	p.lastBlock = big.NewInt(1)
+	p.recordConfirmedFees(ctx, 8)
+	if len(client.batchSizes) != 1 || client.batchSizes[0] != 3 {
+		t.Fatalf("Unexpected receipt fetches. Got batch sizes: %v, want: [3]", client.batchSizes)
+	}
+}
+
+func TestReceiptFee(t *testing.T) {
+	receipt := &types.Receipt{
+		GasUsed:           50_000,
+		EffectiveGasPrice: arbmath.BigMulByUint(big.NewInt(30), params.GWei),
+	}
+	// the fee paid is the gas used at the effective gas price, not the fee cap committed to
+	if got, want := receiptFee(receipt), arbmath.BigMulByUint(big.NewInt(1_500_000), params.GWei); got.Cmp(want) != 0 {
+		t.Fatalf("Unexpected fee of receipt. Got: %v, want: %v", got, want)
+	}
+	receipt.BlobGasUsed = params.BlobTxBlobGasPerBlob
+	receipt.BlobGasPrice = big.NewInt(params.GWei)
+	want := arbmath.BigMulByUint(big.NewInt(1_500_000+params.BlobTxBlobGasPerBlob), params.GWei)
+	if got := receiptFee(receipt); got.Cmp(want) != 0 {
+		t.Fatalf("Unexpected fee of blob receipt. Got: %v, want: %v", got, want)
+	}
+}
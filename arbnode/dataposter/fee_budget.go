@@ -0,0 +1,96 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package dataposter
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+type feeSpend struct {
+	at     time.Time
+	amount *big.Int
+}
+
+// feeBudget tracks the fees committed to by the data poster over a rolling window.
+// A transaction commits to the maximum fee it could pay, and a replacement to how much
+// it raised that maximum, so the total is an upper bound on the fees paid in the window.
+// The data poster's mutex must be held when using it.
+type feeBudget struct {
+	spends []feeSpend
+	total  *big.Int
+}
+
+func newFeeBudget() *feeBudget {
+	return &feeBudget{total: new(big.Int)}
+}
+
+// maxTxFee returns the most a transaction could pay in fees, not counting its value.
+func maxTxFee(tx *types.Transaction) *big.Int {
+	return arbmath.BigSub(tx.Cost(), tx.Value())
+}
+
+// maxTxFeeIncrease returns how much a replacement raised the most its transaction could pay in fees.
+func maxTxFeeIncrease(prevTx, newTx *types.Transaction) *big.Int {
+	increase := arbmath.BigSub(maxTxFee(newTx), maxTxFee(prevTx))
+	if increase.Sign() < 0 {
+		return new(big.Int)
+	}
+	return increase
+}
+
+// spent returns the fees committed to in the window ending now.
+func (b *feeBudget) spent(now time.Time, window time.Duration) *big.Int {
+	start := now.Add(-window)
+	pruned := 0
+	for pruned < len(b.spends) && !b.spends[pruned].at.After(start) {
+		b.total.Sub(b.total, b.spends[pruned].amount)
+		pruned++
+	}
+	b.spends = b.spends[pruned:]
+	return new(big.Int).Set(b.total)
+}
+
+// allows returns true if committing to amount now keeps the fees committed to in the window within limit.
+// A nil or zero limit is unlimited.
+func (b *feeBudget) allows(now time.Time, window time.Duration, limit *big.Int, amount *big.Int) bool {
+	if limit == nil || limit.Sign() <= 0 {
+		return true
+	}
+	return arbmath.BigAdd(b.spent(now, window), amount).Cmp(limit) <= 0
+}
+
+func (b *feeBudget) record(now time.Time, amount *big.Int) {
+	if amount.Sign() <= 0 {
+		return
+	}
+	b.spends = append(b.spends, feeSpend{at: now, amount: new(big.Int).Set(amount)})
+	b.total.Add(b.total, amount)
+}
+
+// receiptFee returns the fees a transaction actually paid: its gas used at its effective gas price,
+// plus its blob gas used at its blob gas price.
+func receiptFee(receipt *types.Receipt) *big.Int {
+	fee := new(big.Int)
+	if receipt.EffectiveGasPrice != nil {
+		fee = arbmath.BigMulByUint(receipt.EffectiveGasPrice, receipt.GasUsed)
+	}
+	if receipt.BlobGasPrice != nil {
+		fee.Add(fee, arbmath.BigMulByUint(receipt.BlobGasPrice, receipt.BlobGasUsed))
+	}
+	return fee
+}
+
+// maxCumulativeFee returns the configured ceiling on the fees committed to in a window, in wei, or nil if unlimited.
+func (c *DataPosterConfig) maxCumulativeFee() *big.Int {
+	if c.MaxCumulativeFeeGwei <= 0 || c.CumulativeFeeWindow <= 0 {
+		return nil
+	}
+	return arbmath.FloatToBig(c.MaxCumulativeFeeGwei * params.GWei)
+}
@@ -0,0 +1,63 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package dataposter
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+// NonceCoordinator serializes the nonce allocation of data posters sending transactions from the same
+// address on the same parent chain, e.g. a staker and a batch poster sharing an EOA, so that they don't
+// allocate the same nonce. Each data poster still tracks and replaces-by-fee only its own transactions.
+type NonceCoordinator struct {
+	// held while a data poster posts a new transaction
+	postMutex sync.Mutex
+	// the nonce following the highest nonce posted by any of the data posters
+	next atomic.Uint64
+}
+
+type nonceCoordinatorKey struct {
+	chainID string
+	from    common.Address
+}
+
+var (
+	nonceCoordinatorsMutex sync.Mutex
+	nonceCoordinators      = make(map[nonceCoordinatorKey]*NonceCoordinator)
+)
+
+// SharedNonceCoordinator returns the nonce coordinator of the given sender on the given parent chain,
+// shared by every data poster of the process configured to share nonces.
+func SharedNonceCoordinator(chainID *big.Int, from common.Address) *NonceCoordinator {
+	key := nonceCoordinatorKey{chainID: chainID.String(), from: from}
+	nonceCoordinatorsMutex.Lock()
+	defer nonceCoordinatorsMutex.Unlock()
+	coordinator, ok := nonceCoordinators[key]
+	if !ok {
+		coordinator = &NonceCoordinator{}
+		nonceCoordinators[key] = coordinator
+	}
+	return coordinator
+}
+
+// NextNonce returns the next nonce a data poster may use, given the next nonce by its own view of the chain and its queue.
+func (c *NonceCoordinator) NextNonce(nonce uint64) uint64 {
+	return arbmath.MaxInt(nonce, c.next.Load())
+}
+
+// Posted records that a data poster queued a transaction with the given nonce.
+func (c *NonceCoordinator) Posted(nonce uint64) {
+	for {
+		next := c.next.Load()
+		if nonce < next || c.next.CompareAndSwap(next, nonce+1) {
+			return
+		}
+	}
+}
@@ -370,6 +370,10 @@ func DataposterOnlyUsedToCreateValidatorWalletContract(
 	)
 }
 
+// StakerDataposter returns the data poster of the staker's transactions, or nil if the staker has no signer.
+// Staker transactions are always posted as EIP-1559 calldata transactions, even with post-4844-blobs enabled:
+// the rollup contracts read assertions from calldata, while a blob's contents can't be read on-chain,
+// so carrying a staker transaction's data in blobs would only add to its cost.
 func StakerDataposter(
 	ctx context.Context, db ethdb.Database, l1Reader *headerreader.HeaderReader,
 	transactOpts *bind.TransactOpts, cfgFetcher ConfigFetcher, syncMonitor *SyncMonitor,
@@ -405,6 +409,9 @@ func StakerDataposter(
 			MetadataRetriever: mdRetriever,
 			RedisKey:          sender + ".staker-data-poster.queue",
 			ParentChainID:     parentChainID,
+			Logger: legacystaker.NewSubsystemLogger(legacystaker.LogSubsystemDataPoster, func() *legacystaker.L1ValidatorConfig {
+				return &cfgFetcher.Get().Staker
+			}),
 		})
 }
 
@@ -768,8 +775,30 @@ func getStaker(
 					tmpAddress := common.HexToAddress(config.Staker.ContractWalletAddress)
 					existingWalletAddress = &tmpAddress
 				}
+				stakeToken := validatorwallet.WithStakeToken(common.HexToAddress(config.Staker.StakeTokenAddress), deployInfo.Rollup)
+				creationGas := validatorwallet.WithCreationGas(func() uint64 { return configFetcher.Get().Staker.WalletCreationGas() })
+				walletOpts := []validatorwallet.ContractOption{stakeToken, creationGas}
+				if backupKey := config.Staker.BackupEOAKey(); backupKey != nil {
+					backupTxOpts, err := bind.NewKeyedTransactorWithChainID(backupKey, parentChainID)
+					if err != nil {
+						return nil, nil, common.Address{}, err
+					}
+					backupDataPoster, err := StakerDataposter(
+						ctx,
+						rawdb.NewTable(arbDb, storage.StakerBackupEOAPrefix),
+						l1Reader,
+						backupTxOpts,
+						configFetcher,
+						syncMonitor,
+						parentChainID,
+					)
+					if err != nil {
+						return nil, nil, common.Address{}, err
+					}
+					walletOpts = append(walletOpts, validatorwallet.WithBackupEOA(backupDataPoster))
+				}
 				// #nosec G115
-				wallet, err = validatorwallet.NewContract(dp, existingWalletAddress, deployInfo.ValidatorWalletCreator, l1Reader, txOptsValidator, int64(deployInfo.DeployedAt), func(common.Address) {}, getExtraGas)
+				wallet, err = validatorwallet.NewContract(dp, existingWalletAddress, deployInfo.ValidatorWalletCreator, l1Reader, txOptsValidator, int64(deployInfo.DeployedAt), func(common.Address) {}, getExtraGas, walletOpts...)
 				if err != nil {
 					return nil, nil, common.Address{}, err
 				}
@@ -866,17 +895,36 @@ func getStatelessBlockValidator(
 			return nil, errors.New("stateless block validator requires an execution recorder")
 		}
 
-		statelessBlockValidator, err = staker.NewStatelessBlockValidator(
-			inboxReader,
-			inboxTracker,
-			txStreamer,
-			exec,
-			rawdb.NewTable(arbDb, storage.BlockValidatorPrefix),
-			dapReaders,
-			func() *staker.BlockValidatorConfig { return &configFetcher.Get().BlockValidator },
-			stack,
-			latestWasmModuleRoot,
-		)
+		var moduleRootOracle staker.WasmModuleRootOracle
+		moduleRootOracle, err = config.BlockValidator.ModuleRootOracle()
+		if err != nil {
+			return nil, err
+		}
+		if moduleRootOracle != nil {
+			statelessBlockValidator, err = staker.NewStatelessBlockValidatorWithRootOracle(
+				inboxReader,
+				inboxTracker,
+				txStreamer,
+				exec,
+				rawdb.NewTable(arbDb, storage.BlockValidatorPrefix),
+				dapReaders,
+				func() *staker.BlockValidatorConfig { return &configFetcher.Get().BlockValidator },
+				stack,
+				moduleRootOracle,
+			)
+		} else {
+			statelessBlockValidator, err = staker.NewStatelessBlockValidator(
+				inboxReader,
+				inboxTracker,
+				txStreamer,
+				exec,
+				rawdb.NewTable(arbDb, storage.BlockValidatorPrefix),
+				dapReaders,
+				func() *staker.BlockValidatorConfig { return &configFetcher.Get().BlockValidator },
+				stack,
+				latestWasmModuleRoot,
+			)
+		}
 	} else {
 		err = errors.New("no validator url specified")
 	}
@@ -1234,6 +1282,16 @@ func registerAPIs(currentNode *Node, stack *node.Node) {
 			Public: false,
 		})
 	}
+	if currentNode.Staker != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "staker",
+			Version:   "1.0",
+			Service: &StakerAPI{
+				staker: currentNode.Staker,
+			},
+			Public: false,
+		})
+	}
 	if currentNode.MaintenanceRunner != nil {
 		apis = append(apis, rpc.API{
 			Namespace: "maintenance",
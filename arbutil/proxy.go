@@ -0,0 +1,14 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package arbutil
+
+import "github.com/ethereum/go-ethereum/common"
+
+var (
+	// ProxyAdminSlot is ADMIN_SLOT from OpenZeppelin, keccak-256 hash of "eip1967.proxy.admin" subtracted by 1.
+	ProxyAdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	// ProxyImplementationSlot is IMPLEMENTATION_SLOT from OpenZeppelin, keccak-256 hash of
+	// "eip1967.proxy.implementation" subtracted by 1.
+	ProxyImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+)
@@ -11,6 +11,8 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/util"
 )
 
 var globalFileLoggerFactory = fileLoggerFactory{}
@@ -120,5 +122,7 @@ func InitLog(logType string, logLevel string, fileLoggingConfig *FileLoggingConf
 	glogger = log.NewGlogHandler(handler)
 	glogger.Verbosity(slogLevel)
 	log.SetDefault(log.NewLogger(glogger))
+	// subsystems with their own log level write to the handler directly
+	util.SetLogOutputHandler(handler)
 	return nil
 }
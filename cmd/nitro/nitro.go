@@ -357,9 +357,10 @@ func mainImpl() int {
 			log.Crit("error creating data poster to create validator wallet contract", "err", err)
 		}
 		getExtraGas := func() uint64 { return nodeConfig.Node.Staker.ExtraGas }
+		getCreationGas := func() uint64 { return nodeConfig.Node.Staker.WalletCreationGas() }
 
 		// #nosec G115
-		addr, err := validatorwallet.GetValidatorWalletContract(ctx, deployInfo.ValidatorWalletCreator, int64(deployInfo.DeployedAt), l1Reader, true, dataPoster, getExtraGas)
+		addr, err := validatorwallet.GetValidatorWalletContract(ctx, deployInfo.ValidatorWalletCreator, int64(deployInfo.DeployedAt), l1Reader, true, dataPoster, getExtraGas, getCreationGas)
 		if err != nil {
 			log.Crit("error creating validator wallet contract", "error", err, "address", l1TransactionOptsValidator.From.Hex())
 		}
@@ -880,6 +881,9 @@ func (c *NodeConfig) Validate() error {
 	if err := c.BlocksReExecutor.Validate(); err != nil {
 		return err
 	}
+	if err := c.Validation.Validate(); err != nil {
+		return err
+	}
 	if c.Node.ValidatorRequired() && (c.Execution.Caching.StateScheme == rawdb.PathScheme) {
 		return errors.New("path cannot be used as execution.caching.state-scheme when validator is required")
 	}
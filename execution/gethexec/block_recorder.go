@@ -150,6 +150,7 @@ func (r *BlockRecorder) RecordBlockCreation(
 	}
 
 	var blockHash common.Hash
+	var gasUsed uint64
 	if msg != nil {
 		block, _, err := arbos.ProduceBlock(
 			msg.Message,
@@ -164,6 +165,7 @@ func (r *BlockRecorder) RecordBlockCreation(
 			return nil, err
 		}
 		blockHash = block.Hash()
+		gasUsed = block.GasUsed()
 	}
 
 	preimages, err := r.recordingDatabase.PreimagesFromRecording(chaincontext, recordingKV)
@@ -184,6 +186,7 @@ func (r *BlockRecorder) RecordBlockCreation(
 	return &execution.RecordResult{
 		Pos:       pos,
 		BlockHash: blockHash,
+		GasUsed:   gasUsed,
 		Preimages: preimages,
 		UserWasms: recordingdb.UserWasms(),
 	}, err
@@ -20,6 +20,7 @@ type MessageResult struct {
 type RecordResult struct {
 	Pos       arbutil.MessageIndex
 	BlockHash common.Hash
+	GasUsed   uint64
 	Preimages map[common.Hash][]byte
 	UserWasms state.UserWasms
 }
@@ -0,0 +1,75 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/util/rpcclient"
+	"github.com/offchainlabs/nitro/validator/server_api"
+)
+
+var DefaultArchiveNodeConfig = rpcclient.ClientConfig{
+	URL:                       "",
+	Retries:                   3,
+	RetryErrors:               "websocket: close.*|dial tcp .*|.*i/o timeout|.*connection reset by peer|.*connection refused",
+	ArgLogLimit:               2048,
+	WebsocketMessageSizeLimit: 256 * 1024 * 1024,
+}
+
+// archiveInputsReader reads the validation inputs of a message from a node holding its historical state
+type archiveInputsReader interface {
+	ValidationInputsAt(ctx context.Context, pos arbutil.MessageIndex, target rawdb.WasmTarget) (*server_api.InputJSON, error)
+}
+
+// archiveNodeClient reads validation inputs from an archive node's arbdebug API
+type archiveNodeClient struct {
+	client *rpcclient.RpcClient
+}
+
+func (c *archiveNodeClient) ValidationInputsAt(ctx context.Context, pos arbutil.MessageIndex, target rawdb.WasmTarget) (*server_api.InputJSON, error) {
+	var input server_api.InputJSON
+	if err := c.client.CallContext(ctx, &input, "arbdebug_validationInputsAt", hexutil.Uint64(pos), target); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// recordFromArchive fills a validation entry with the preimages and user wasms recorded by the archive node,
+// for when the local node lacks the state needed to record the message itself (e.g. it was pruned).
+// User wasms are read once per target, as the archive node's inputs hold a single target each.
+func recordFromArchive(ctx context.Context, archive archiveInputsReader, e *validationEntry, targets []rawdb.WasmTarget) error {
+	if len(targets) == 0 {
+		targets = []rawdb.WasmTarget{rawdb.TargetWavm}
+	}
+	userWasms := make(state.UserWasms)
+	for _, target := range targets {
+		inputJson, err := archive.ValidationInputsAt(ctx, e.Pos, target)
+		if err != nil {
+			return fmt.Errorf("reading validation inputs of pos %d for target %v from archive node: %w", e.Pos, target, err)
+		}
+		input, err := server_api.ValidationInputFromJson(inputJson)
+		if err != nil {
+			return fmt.Errorf("decoding validation inputs of pos %d from archive node: %w", e.Pos, err)
+		}
+		if input.StartState != e.Start {
+			return fmt.Errorf("archive node start state for pos %d doesn't match: expected %v, got %v", e.Pos, e.Start, input.StartState)
+		}
+		copyPreimagesInto(e.Preimages, input.Preimages)
+		for moduleHash, asm := range input.UserWasms[target] {
+			if userWasms[moduleHash] == nil {
+				userWasms[moduleHash] = make(map[rawdb.WasmTarget][]byte)
+			}
+			userWasms[moduleHash][target] = asm
+		}
+	}
+	e.UserWasms = userWasms
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// ParallelBackfillConfig makes backfills spanning module root upgrades validate the blocks of each
+// module root concurrently, e.g. on validation servers dedicated to each root, instead of sequentially.
+type ParallelBackfillConfig struct {
+	Enable  bool   `koanf:"enable"`
+	Workers uint64 `koanf:"workers"`
+}
+
+var DefaultParallelBackfillConfig = ParallelBackfillConfig{
+	Enable:  false,
+	Workers: 2,
+}
+
+func ParallelBackfillConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultParallelBackfillConfig.Enable, "when backfilling a range spanning module root upgrades, validate the blocks of each module root concurrently")
+	f.Uint64(prefix+".workers", DefaultParallelBackfillConfig.Workers, "maximum number of module roots whose blocks are validated concurrently when backfilling")
+}
+
+func (c *ParallelBackfillConfig) Validate() error {
+	if c.Enable && c.Workers == 0 {
+		return errors.New("parallel backfill workers must be positive")
+	}
+	return nil
+}
+
+// ModuleRootActivation is a wasm module root active from the message at Pos until the next activation.
+type ModuleRootActivation struct {
+	Pos        arbutil.MessageIndex
+	ModuleRoot common.Hash
+}
+
+type messageSpan struct {
+	start arbutil.MessageIndex
+	end   arbutil.MessageIndex
+}
+
+// moduleRootGroup holds the messages of a backfill validated under the same module root.
+type moduleRootGroup struct {
+	moduleRoot common.Hash
+	spans      []messageSpan
+}
+
+// groupByModuleRoot splits [start, end) into groups of messages under the same active module root,
+// in order of their first message. The first activation must be at or before start.
+func groupByModuleRoot(start, end arbutil.MessageIndex, activations []ModuleRootActivation) ([]*moduleRootGroup, error) {
+	if len(activations) == 0 || activations[0].Pos > start {
+		return nil, fmt.Errorf("no module root active at the start %d of the backfill", start)
+	}
+	var groups []*moduleRootGroup
+	groupsByRoot := make(map[common.Hash]*moduleRootGroup)
+	for i, activation := range activations {
+		if i > 0 && activation.Pos <= activations[i-1].Pos {
+			return nil, fmt.Errorf("module root activations not strictly ordered at position %d", activation.Pos)
+		}
+		spanStart := max(activation.Pos, start)
+		spanEnd := end
+		if i+1 < len(activations) {
+			spanEnd = min(activations[i+1].Pos, end)
+		}
+		if spanStart >= spanEnd {
+			continue
+		}
+		group, ok := groupsByRoot[activation.ModuleRoot]
+		if !ok {
+			group = &moduleRootGroup{moduleRoot: activation.ModuleRoot}
+			groupsByRoot[activation.ModuleRoot] = group
+			groups = append(groups, group)
+		}
+		group.spans = append(group.spans, messageSpan{spanStart, spanEnd})
+	}
+	return groups, nil
+}
+
+// validateModuleRootGroup validates the group's messages in order, returning one result per validated message.
+// If stopOnFirstMismatch is set, validation stops after the first invalid message of the group.
+func (v *StatelessBlockValidator) validateModuleRootGroup(ctx context.Context, group *moduleRootGroup, stopOnFirstMismatch bool) ([]BlockValidationResult, error) {
+	var results []BlockValidationResult
+	for _, span := range group.spans {
+		for pos := span.start; pos < span.end; pos++ {
+			result, err := v.validateAndReport(ctx, pos, group.moduleRoot)
+			if err != nil {
+				return results, fmt.Errorf("failed validating message %d with module root %v: %w", pos, group.moduleRoot, err)
+			}
+			results = append(results, result)
+			if !result.Valid && stopOnFirstMismatch {
+				log.Warn("stopping backfill of module root at first mismatch", "moduleRoot", group.moduleRoot, "pos", pos)
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
+
+// Backfill validates messages in [start, end) against the module root active for each message,
+// as given by activations, e.g. to validate history across module root upgrades. It returns one
+// result per validated message, in message order. If parallel backfill is enabled, the messages of
+// each module root are validated concurrently with those of other module roots, up to the configured
+// number of workers, and otherwise the module roots are validated one after another.
+// If stopOnFirstMismatch is set, the validation of a module root's messages stops after its first invalid message.
+func (v *StatelessBlockValidator) Backfill(
+	ctx context.Context, start, end arbutil.MessageIndex, activations []ModuleRootActivation, stopOnFirstMismatch bool,
+) ([]BlockValidationResult, error) {
+	if end < start {
+		return nil, fmt.Errorf("invalid backfill range [%d, %d)", start, end)
+	}
+	groups, err := groupByModuleRoot(start, end, activations)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if len(v.validationSpawners(group.moduleRoot, false)) == 0 {
+			return nil, fmt.Errorf("validation with WasmModuleRoot %v not supported by node", group.moduleRoot)
+		}
+	}
+	if err := v.CheckDelayedSequencing(ctx, start, end); err != nil {
+		return nil, err
+	}
+	workers := uint64(1)
+	if v.config.ParallelBackfill.Enable {
+		workers = v.config.ParallelBackfill.Workers
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	var resultsMutex sync.Mutex
+	results := make([]BlockValidationResult, 0, end-start)
+	var firstErr error
+	workerSlots := make(chan struct{}, workers)
+	for _, group := range groups {
+		select {
+		case workerSlots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(group *moduleRootGroup) {
+			defer wg.Done()
+			defer func() { <-workerSlots }()
+			groupResults, err := v.validateModuleRootGroup(ctx, group, stopOnFirstMismatch)
+			resultsMutex.Lock()
+			defer resultsMutex.Unlock()
+			results = append(results, groupResults...)
+			if err != nil && firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		}(group)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].Pos < results[j].Pos })
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, ctx.Err()
+}
@@ -0,0 +1,87 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+// ErrBlockNotReproduced is returned when a block validated on demand doesn't reproduce its global state.
+var ErrBlockNotReproduced = errors.New("block validation didn't reproduce its global state")
+
+// blockMessageIndex returns the index of the message producing the given L2 block.
+func (v *StatelessBlockValidator) blockMessageIndex(block uint64) (arbutil.MessageIndex, error) {
+	genesis := v.streamer.ChainConfig().ArbitrumChainParams.GenesisBlockNum
+	if block < genesis {
+		return 0, fmt.Errorf("block %d is before the genesis block %d", block, genesis)
+	}
+	return arbutil.BlockNumberToMessageCount(block, genesis) - 1, nil
+}
+
+// StreamBlockRangeValidation validates the L2 blocks in [start, end) on demand against moduleRoot, or if it's
+// zero, the wasm module root in effect at each block's parent chain block, calling onResult with the result
+// of each block as soon as it's validated. Validation stops at the first error returned by onResult, or when ctx is cancelled.
+func (v *StatelessBlockValidator) StreamBlockRangeValidation(
+	ctx context.Context, start, end uint64, moduleRoot common.Hash, onResult func(block uint64, result BlockValidationResult) error,
+) error {
+	if end < start {
+		return fmt.Errorf("invalid block range [%d, %d)", start, end)
+	}
+	if moduleRoot != (common.Hash{}) && len(v.validationSpawners(moduleRoot, false)) == 0 {
+		return fmt.Errorf("validation with WasmModuleRoot %v not supported by node", moduleRoot)
+	}
+	startPos, err := v.blockMessageIndex(start)
+	if err != nil {
+		return err
+	}
+	if err := v.CheckDelayedSequencing(ctx, startPos, startPos+arbutil.MessageIndex(end-start)); err != nil {
+		return err
+	}
+	for block := start; block < end; block++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pos := startPos + arbutil.MessageIndex(block-start)
+		blockModuleRoot := moduleRoot
+		if blockModuleRoot == (common.Hash{}) {
+			blockModuleRoot, err = v.wasmModuleRootAt(pos)
+			if err != nil {
+				return err
+			}
+		}
+		result, err := v.validateAndReport(ctx, pos, blockModuleRoot)
+		if err != nil {
+			return fmt.Errorf("failed validating block %d: %w", block, err)
+		}
+		if err := onResult(block, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateBlockRange revalidates the L2 blocks in [start, end) on demand against moduleRoot, or the latest
+// wasm module root if it's zero, e.g. to check that a suspect range still reproduces after an upgrade.
+// It returns the global state produced by each block, failing with an error wrapping ErrBlockNotReproduced
+// at the first block which doesn't reproduce its global state.
+func (v *StatelessBlockValidator) ValidateBlockRange(ctx context.Context, start, end uint64, moduleRoot common.Hash) ([]validator.GoGlobalState, error) {
+	var states []validator.GoGlobalState
+	err := v.StreamBlockRangeValidation(ctx, start, end, moduleRoot, func(block uint64, result BlockValidationResult) error {
+		if result.GlobalState != nil {
+			states = append(states, *result.GlobalState)
+		}
+		if !result.Valid {
+			return fmt.Errorf("%w: block %d produced %v", ErrBlockNotReproduced, block, result.GlobalState)
+		}
+		return nil
+	})
+	return states, err
+}
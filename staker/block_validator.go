@@ -40,6 +40,7 @@ var (
 	validatorPendingValidationsGauge         = metrics.NewRegisteredGauge("arb/validator/validations/pending", nil)
 	validatorValidValidationsCounter         = metrics.NewRegisteredCounter("arb/validator/validations/valid", nil)
 	validatorFailedValidationsCounter        = metrics.NewRegisteredCounter("arb/validator/validations/failed", nil)
+	validatorSkippedValidationsCounter       = metrics.NewRegisteredCounter("arb/validator/validations/skipped", nil)
 	validatorProfileWaitToRecordHist         = metrics.NewRegisteredHistogram("arb/validator/profile/wait_to_record", nil, metrics.NewBoundedHistogramSample())
 	validatorProfileRecordingHist            = metrics.NewRegisteredHistogram("arb/validator/profile/recording", nil, metrics.NewBoundedHistogramSample())
 	validatorProfileWaitToLaunchHist         = metrics.NewRegisteredHistogram("arb/validator/profile/wait_to_launch", nil, metrics.NewBoundedHistogramSample())
@@ -50,6 +51,7 @@ var (
 	validatorMsgCountRecordSentGauge         = metrics.NewRegisteredGauge("arb/validator/msg_count_record_sent", nil)
 	validatorMsgCountValidatedGauge          = metrics.NewRegisteredGauge("arb/validator/msg_count_validated", nil)
 	validatorMsgCountLastValidationSentGauge = metrics.NewRegisteredGauge("arb/validator/msg_count_last_validation_sent", nil)
+	validatorPostReorgFailuresCounter        = metrics.NewRegisteredCounter("arb/validator/reorg/revalidations/failed", nil)
 )
 
 type BlockValidator struct {
@@ -107,6 +109,8 @@ type BlockValidator struct {
 	fatalErr chan<- error
 
 	MemoryFreeLimitChecker resourcemanager.LimitChecker
+
+	sampler *ValidationSampler
 }
 
 type BlockValidatorConfig struct {
@@ -127,11 +131,21 @@ type BlockValidatorConfig struct {
 	MemoryFreeLimit                   string                        `koanf:"memory-free-limit" reload:"hot"`
 	ValidationServerConfigsList       string                        `koanf:"validation-server-configs-list"`
 	ValidationSpawningAllowedAttempts uint64                        `koanf:"validation-spawning-allowed-attempts" reload:"hot"`
+	ValidationQuorum                  uint64                        `koanf:"validation-quorum"`
+	ValidationRetries                 uint64                        `koanf:"validation-retries"`
+	ValidationReportFile              string                        `koanf:"validation-report-file"`
+	ValidationCacheSize               int                           `koanf:"validation-cache-size"`
+	Sampling                          ValidationSamplingConfig      `koanf:"sampling"`
+	ArchiveNode                       rpcclient.ClientConfig        `koanf:"archive-node"`
+	InputSizeDispatch                 InputSizeDispatchConfig       `koanf:"input-size-dispatch"`
+	ParallelBackfill                  ParallelBackfillConfig        `koanf:"parallel-backfill"`
+	ModuleRootHistory                 []string                      `koanf:"module-root-history"`
 	// The directory to which the BlockValidator will write the
 	// block_inputs_<id>.json files when WriteToFile() is called.
 	BlockInputsFilePath string `koanf:"block-inputs-file-path"`
 
-	memoryFreeLimit int
+	memoryFreeLimit    int
+	moduleRootUpgrades []WasmModuleRootUpgrade
 }
 
 func (c *BlockValidatorConfig) Validate() error {
@@ -173,12 +187,38 @@ func (c *BlockValidatorConfig) Validate() error {
 			}
 		}
 	}
+	if c.ValidationQuorum > uint64(len(c.ValidationServerConfigs)) {
+		return fmt.Errorf("validation quorum %d is larger than the number of validation servers %d", c.ValidationQuorum, len(c.ValidationServerConfigs))
+	}
+	if err := c.Sampling.Validate(); err != nil {
+		return err
+	}
+	if err := c.ParallelBackfill.Validate(); err != nil {
+		return err
+	}
+	if err := c.ArchiveNode.Validate(); err != nil {
+		return fmt.Errorf("failed to validate block-validator archive-node config: %w", err)
+	}
+	upgrades, err := ParseWasmModuleRootHistory(c.ModuleRootHistory)
+	if err != nil {
+		return fmt.Errorf("failed to parse block-validator module-root-history: %w", err)
+	}
+	c.moduleRootUpgrades = upgrades
 	if c.Dangerous.Revalidation.EndBlock > 0 && c.Dangerous.Revalidation.EndBlock < c.Dangerous.Revalidation.StartBlock {
 		return fmt.Errorf("revalidation end block %d is before start block %d", c.Dangerous.Revalidation.EndBlock, c.Dangerous.Revalidation.StartBlock)
 	}
 	return nil
 }
 
+// ModuleRootOracle returns the module root oracle following the configured module root history,
+// or nil if there's none. The config must have been validated.
+func (c *BlockValidatorConfig) ModuleRootOracle() (WasmModuleRootOracle, error) {
+	if len(c.moduleRootUpgrades) == 0 {
+		return nil, nil
+	}
+	return WasmModuleRootHistory(c.moduleRootUpgrades)
+}
+
 type BlockValidatorDangerousConfig struct {
 	ResetBlockValidation bool               `koanf:"reset-block-validation"`
 	Revalidation         RevalidationConfig `koanf:"revalidation"`
@@ -210,6 +250,15 @@ func BlockValidatorConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	f.String(prefix+".memory-free-limit", DefaultBlockValidatorConfig.MemoryFreeLimit, "minimum free-memory limit after reaching which the blockvalidator pauses validation. Enabled by default as 1GB, to disable provide empty string")
 	f.String(prefix+".block-inputs-file-path", DefaultBlockValidatorConfig.BlockInputsFilePath, "directory to write block validation inputs files")
 	f.Uint64(prefix+".validation-spawning-allowed-attempts", DefaultBlockValidatorConfig.ValidationSpawningAllowedAttempts, "number of attempts allowed when trying to spawn a validation before erroring out")
+	f.Uint64(prefix+".validation-quorum", DefaultBlockValidatorConfig.ValidationQuorum, "if non-zero, the stateless validator dispatches each validation to all validation servers and requires this many of them to agree on the resulting global state (0 uses a single server)")
+	f.Uint64(prefix+".validation-retries", DefaultBlockValidatorConfig.ValidationRetries, "number of times a validation which failed with an error, rather than a mismatching result, is retried before being reported as a failure (retries are spread across the validation servers supporting the module root)")
+	f.String(prefix+".validation-report-file", DefaultBlockValidatorConfig.ValidationReportFile, "if set, range and batch validation results are appended to this file as JSON lines (see staker.ValidationReportEntry)")
+	f.Int(prefix+".validation-cache-size", DefaultBlockValidatorConfig.ValidationCacheSize, "number of successful validation results to cache per validation server by module root and input content, returning them again without revalidating (0 = disabled)")
+	ValidationSamplingConfigAddOptions(prefix+".sampling", f)
+	rpcclient.RPCClientAddOptions(prefix+".archive-node", f, &DefaultBlockValidatorConfig.ArchiveNode)
+	InputSizeDispatchConfigAddOptions(prefix+".input-size-dispatch", f)
+	ParallelBackfillConfigAddOptions(prefix+".parallel-backfill", f)
+	f.StringSlice(prefix+".module-root-history", DefaultBlockValidatorConfig.ModuleRootHistory, "wasm module roots by the parent chain block they took effect at, as <block>:<module root> in block order, to validate the messages of each block with the module root then in effect, e.g. across a scheduled upgrade (empty validates every message with the latest module root)")
 }
 
 func BlockValidatorDangerousConfigAddOptions(prefix string, f *pflag.FlagSet) {
@@ -241,6 +290,15 @@ var DefaultBlockValidatorConfig = BlockValidatorConfig{
 	RecordingIterLimit:                20,
 	ValidationSentLimit:               1024,
 	ValidationSpawningAllowedAttempts: 1,
+	ValidationQuorum:                  0,
+	ValidationRetries:                 0,
+	ValidationReportFile:              "",
+	ValidationCacheSize:               0,
+	Sampling:                          DefaultValidationSamplingConfig,
+	ArchiveNode:                       DefaultArchiveNodeConfig,
+	InputSizeDispatch:                 DefaultInputSizeDispatchConfig,
+	ParallelBackfill:                  DefaultParallelBackfillConfig,
+	ModuleRootHistory:                 nil,
 }
 
 var TestBlockValidatorConfig = BlockValidatorConfig{
@@ -261,6 +319,15 @@ var TestBlockValidatorConfig = BlockValidatorConfig{
 	BlockInputsFilePath:               "./target/validation_inputs",
 	MemoryFreeLimit:                   "default",
 	ValidationSpawningAllowedAttempts: 1,
+	ValidationQuorum:                  0,
+	ValidationRetries:                 0,
+	ValidationReportFile:              "",
+	ValidationCacheSize:               0,
+	Sampling:                          DefaultValidationSamplingConfig,
+	ArchiveNode:                       DefaultArchiveNodeConfig,
+	InputSizeDispatch:                 DefaultInputSizeDispatchConfig,
+	ParallelBackfill:                  DefaultParallelBackfillConfig,
+	ModuleRootHistory:                 nil,
 }
 
 var DefaultBlockValidatorDangerousConfig = BlockValidatorDangerousConfig{
@@ -294,7 +361,9 @@ type validationStatus struct {
 }
 
 type validationDoneEntry struct {
-	Success         bool
+	Success bool
+	// Skipped entries were left out of the validation sample, and aren't recorded as validated
+	Skipped         bool
 	Start           validator.GoGlobalState
 	End             validator.GoGlobalState
 	WasmModuleRoots []common.Hash
@@ -332,6 +401,7 @@ func NewBlockValidator(
 		config:                  config,
 		fatalErr:                fatalErr,
 		prevBatchCache:          make(map[uint64][]byte),
+		sampler:                 NewValidationSampler(),
 	}
 	valInputsWriter, err := inputs.NewWriter(
 		inputs.WithBaseDir(ret.stack.InstanceDir()),
@@ -895,15 +965,19 @@ func (v *BlockValidator) advanceValidations(ctx context.Context) (*arbutil.Messa
 			validationStatus.Cancel()
 			return &pos, nil
 		}
-		if !validationStatus.DoneEntry.Success {
+		if validationStatus.DoneEntry.Skipped {
+			// carry on from the skipped message's end state without persisting it as validated
+			v.lastValidGS = validationStatus.DoneEntry.End
+		} else if !validationStatus.DoneEntry.Success {
 			v.possiblyFatal(fmt.Errorf("validation: failed entry pos %d, start %v", pos, validationStatus.DoneEntry.Start))
 			return &pos, nil // if not fatal - retry
+		} else {
+			err := v.writeLastValidated(validationStatus.DoneEntry.End, validationStatus.DoneEntry.WasmModuleRoots)
+			if err != nil {
+				log.Error("failed writing new validated to database", "pos", pos, "err", err)
+			}
+			go v.recorder.MarkValid(pos, v.lastValidGS.BlockHash)
 		}
-		err := v.writeLastValidated(validationStatus.DoneEntry.End, validationStatus.DoneEntry.WasmModuleRoots)
-		if err != nil {
-			log.Error("failed writing new validated to database", "pos", pos, "err", err)
-		}
-		go v.recorder.MarkValid(pos, v.lastValidGS.BlockHash)
 		atomicStorePos(&v.validatedA, pos+1, validatorMsgCountValidatedGauge)
 		v.validations.Delete(pos)
 		nonBlockingTrigger(v.createNodesChan)
@@ -953,8 +1027,30 @@ func (v *BlockValidator) sendValidations(ctx context.Context) (*arbutil.MessageI
 			log.Trace("sendValidations: validation not prepared", "pos", pos, "status", currentStatus)
 			return nil, nil
 		}
-		for _, moduleRoot := range wasmRoots {
-			spawner := v.chosenValidator[moduleRoot]
+		msgRoots, err := v.moduleRootsToValidateAt(pos, wasmRoots)
+		if err != nil {
+			return nil, err
+		}
+		if !v.sampler.ShouldValidate(pos, v.config().Sampling.Rate) {
+			// trust local execution for messages left out of the sample
+			validationStatus.DoneEntry = &validationDoneEntry{
+				Skipped:         true,
+				Start:           validationStatus.Entry.Start,
+				End:             validationStatus.Entry.End,
+				WasmModuleRoots: msgRoots,
+			}
+			validationStatus.Entry = nil
+			if !validationStatus.replaceStatus(Prepared, ValidationDone) {
+				v.possiblyFatal(errors.New("failed to set ValidationDone status"))
+			}
+			validatorSkippedValidationsCounter.Inc(1)
+			nonBlockingTrigger(v.progressValidationsChan)
+			pos += 1
+			atomicStorePos(&v.lastValidationSentA, pos, validatorMsgCountLastValidationSentGauge)
+			continue
+		}
+		for _, moduleRoot := range msgRoots {
+			spawner := v.chosenSpawner(validationStatus.Entry, moduleRoot)
 			if spawner == nil {
 				notFoundErr := fmt.Errorf("did not find spawner for moduleRoot :%v", moduleRoot)
 				v.possiblyFatal(notFoundErr)
@@ -976,8 +1072,8 @@ func (v *BlockValidator) sendValidations(ctx context.Context) (*arbutil.MessageI
 		validatorProfileWaitToLaunchHist.Update(validationStatus.profileStep())
 		validatorPendingValidationsGauge.Inc(1)
 		var runs []validator.ValidationRun
-		for _, moduleRoot := range wasmRoots {
-			spawner := retry_wrapper.NewValidationSpawnerRetryWrapper(v.chosenValidator[moduleRoot])
+		for _, moduleRoot := range msgRoots {
+			spawner := retry_wrapper.NewValidationSpawnerRetryWrapper(v.chosenSpawner(validationStatus.Entry, moduleRoot))
 			spawner.StopWaiter.Start(ctx, v)
 			input, err := validationStatus.Entry.ToInput(spawner.StylusArchs())
 			if err != nil && ctx.Err() == nil {
@@ -995,12 +1091,13 @@ func (v *BlockValidator) sendValidations(ctx context.Context) (*arbutil.MessageI
 			Success:         false,
 			Start:           validationStatus.Entry.Start,
 			End:             validationStatus.Entry.End,
-			WasmModuleRoots: wasmRoots,
+			WasmModuleRoots: msgRoots,
 		}
 		validationStatus.Entry = nil // no longer needed
 		validatorProfileLaunchingHist.Update(validationStatus.profileStep())
 		validationCtx, cancel := context.WithCancel(ctx)
 		validationStatus.Cancel = cancel
+		validationPos := pos
 		v.LaunchUntrackedThread(func() {
 			defer validatorPendingValidationsGauge.Dec(1)
 			defer cancel()
@@ -1017,6 +1114,10 @@ func (v *BlockValidator) sendValidations(ctx context.Context) (*arbutil.MessageI
 					validatorFailedValidationsCounter.Inc(1)
 					markSuccess = false
 					log.Error("error while validating", "err", err, "start", validationStatus.DoneEntry.Start, "end", validationStatus.DoneEntry.End)
+					// when sampling, fully validate the blocks following the failure
+					if v.config().Sampling.Rate < 1 {
+						v.sampler.ForceFullValidation(validationPos, validationPos+arbutil.MessageIndex(v.config().Sampling.AnomalyWindow))
+					}
 					break
 				}
 				validatorValidValidationsCounter.Inc(1)
@@ -1035,6 +1136,45 @@ func (v *BlockValidator) sendValidations(ctx context.Context) (*arbutil.MessageI
 	}
 }
 
+// ForceFullValidation makes the block validator validate every message in [start, end) even when
+// sampling, e.g. for the range of an incorrect assertion. Messages of the range which were already
+// sent on are revalidated in the background, each at most once however often the range is flagged,
+// and a mismatch is treated as a failed validation.
+func (v *BlockValidator) ForceFullValidation(start, end arbutil.MessageIndex) {
+	if v.config().Sampling.Rate >= 1 || end <= start {
+		return
+	}
+	sent := v.lastValidationSent()
+	for _, r := range v.sampler.force(start, end) {
+		if r.start >= sent {
+			continue
+		}
+		v.revalidateRange(r.start, min(r.end, sent))
+	}
+}
+
+func (v *BlockValidator) revalidateRange(start, end arbutil.MessageIndex) {
+	log.Info("fully revalidating range of flagged messages", "start", start, "end", end)
+	err := v.LaunchThreadSafe(func(ctx context.Context) {
+		results, err := v.ValidateRange(ctx, start, end, true)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Error("error revalidating flagged messages", "start", start, "end", end, "err", err)
+			}
+			return
+		}
+		for _, res := range results {
+			if !res.Valid {
+				validatorFailedValidationsCounter.Inc(1)
+				v.possiblyFatal(fmt.Errorf("validation: revalidating flagged message %d failed, got %v", res.Pos, res.GlobalState))
+			}
+		}
+	})
+	if err != nil {
+		log.Warn("not revalidating flagged messages as block validator isn't running", "start", start, "end", end, "err", err)
+	}
+}
+
 func (v *BlockValidator) iterativeValidationProgress(ctx context.Context, ignored struct{}) time.Duration {
 	reorg, err := v.advanceValidations(ctx)
 	if err != nil {
@@ -1311,7 +1451,7 @@ func (v *BlockValidator) Initialize(ctx context.Context) error {
 			v.chosenValidator[root] = v.redisValidator
 			log.Info("validator chosen", "WasmModuleRoot", root, "chosen", "redis")
 		} else {
-			for _, spawner := range v.execSpawners {
+			for _, spawner := range v.allSpawners() {
 				if validator.SpawnerSupportsModule(spawner, root) {
 					v.chosenValidator[root] = spawner
 					log.Info("validator chosen", "WasmModuleRoot", root, "chosen", spawner.Name())
@@ -1326,6 +1466,19 @@ func (v *BlockValidator) Initialize(ctx context.Context) error {
 	return nil
 }
 
+// chosenSpawner returns the spawner selected for the entry's message, or else the first spawner supporting
+// moduleRoot preferred for the entry's input size, or else the spawner chosen for moduleRoot.
+func (v *BlockValidator) chosenSpawner(entry *validationEntry, moduleRoot common.Hash) validator.ValidationSpawner {
+	if spawner := v.selectedSpawner(entry.Pos, moduleRoot); spawner != nil {
+		return spawner
+	}
+	dispatch := v.config().InputSizeDispatch
+	if preferred := dispatch.preferredSpawners(entry, v.validationSpawners(moduleRoot, false)); len(preferred) > 0 {
+		return preferred[0]
+	}
+	return v.chosenValidator[moduleRoot]
+}
+
 func (v *BlockValidator) checkLegacyValid() error {
 	v.reorgMutex.Lock()
 	defer v.reorgMutex.Unlock()
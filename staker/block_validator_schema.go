@@ -20,7 +20,17 @@ type GlobalStateValidatedInfo struct {
 	WasmRoots   []common.Hash
 }
 
+// RangeValidationProgress is the checkpoint of a range validation, allowing it to resume after a restart
+type RangeValidationProgress struct {
+	Start      uint64
+	End        uint64
+	ModuleRoot common.Hash
+	// NextPos is the first message not yet validated
+	NextPos uint64
+}
+
 var (
 	lastGlobalStateValidatedInfoKey = []byte("_lastGlobalStateValidatedInfo") // contains a rlp encoded lastBlockValidatedDbInfo
 	legacyLastBlockValidatedInfoKey = []byte("_lastBlockValidatedInfo")       // LEGACY - contains a rlp encoded lastBlockValidatedDbInfo
+	rangeValidationProgressKey      = []byte("_rangeValidationProgress")      // contains a rlp encoded RangeValidationProgress
 )
@@ -88,10 +88,16 @@ type BoldConfig struct {
 }
 
 func (c *BoldConfig) Validate() error {
+	if legacystaker.IsChallengeOnlyStrategy(c.Strategy) {
+		return errors.New("the challengeOnly strategy isn't supported by BoLD")
+	}
 	strategy, err := legacystaker.ParseStrategy(c.Strategy)
 	if err != nil {
 		return err
 	}
+	if strategy == legacystaker.MakeNodesAggressiveStrategy {
+		return errors.New("the makeNodesAggressive strategy isn't supported by BoLD")
+	}
 	c.strategy = strategy
 	var blockNum rpc.BlockNumber
 	switch strings.ToLower(c.RPCBlockNumber) {
@@ -0,0 +1,58 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// ErrDelayedSequencing is returned when the delayed messages sequenced in a range don't follow the parent chain's delayed inbox.
+var ErrDelayedSequencing = errors.New("delayed messages sequenced out of parent chain order")
+
+// CheckDelayedSequencing checks that the messages in [start, end) read the delayed inbox in order, one delayed
+// message at a time, and that each delayed message sequenced is the one at its position in the parent chain's
+// delayed inbox. It returns an error wrapping ErrDelayedSequencing on a reordered, skipped or altered delayed message.
+func (v *StatelessBlockValidator) CheckDelayedSequencing(ctx context.Context, start, end arbutil.MessageIndex) error {
+	if end < start {
+		return fmt.Errorf("invalid delayed sequencing range [%d, %d)", start, end)
+	}
+	var prevDelayed uint64
+	if start > 0 {
+		prev, err := v.streamer.GetMessage(start - 1)
+		if err != nil {
+			return fmt.Errorf("failed getting message %d: %w", start-1, err)
+		}
+		prevDelayed = prev.DelayedMessagesRead
+	}
+	for pos := start; pos < end; pos++ {
+		msg, err := v.streamer.GetMessage(pos)
+		if err != nil {
+			return fmt.Errorf("failed getting message %d: %w", pos, err)
+		}
+		if msg.DelayedMessagesRead == prevDelayed {
+			continue
+		}
+		if msg.DelayedMessagesRead != prevDelayed+1 {
+			return fmt.Errorf("%w: message %d reads %d delayed messages after %d", ErrDelayedSequencing, pos, msg.DelayedMessagesRead, prevDelayed)
+		}
+		delayedMsg, err := v.inboxTracker.GetDelayedMessageBytes(ctx, prevDelayed)
+		if err != nil {
+			return fmt.Errorf("failed getting delayed message %d read by message %d: %w", prevDelayed, pos, err)
+		}
+		sequencedMsg, err := msg.Message.Serialize()
+		if err != nil {
+			return fmt.Errorf("failed serializing message %d: %w", pos, err)
+		}
+		if !bytes.Equal(sequencedMsg, delayedMsg) {
+			return fmt.Errorf("%w: message %d doesn't match delayed message %d", ErrDelayedSequencing, pos, prevDelayed)
+		}
+		prevDelayed = msg.DelayedMessagesRead
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/offchainlabs/nitro/validator"
+)
+
+// InputSizeDispatchConfig routes validations to the validation servers best suited to their input size,
+// e.g. small inputs straight to the arbitrator, and large ones to the JIT.
+type InputSizeDispatchConfig struct {
+	SmallInputLimit   uint64 `koanf:"small-input-limit"`
+	SmallInputSpawner string `koanf:"small-input-spawner"`
+	LargeInputSpawner string `koanf:"large-input-spawner"`
+}
+
+var DefaultInputSizeDispatchConfig = InputSizeDispatchConfig{
+	SmallInputLimit:   0,
+	SmallInputSpawner: "",
+	LargeInputSpawner: "",
+}
+
+func InputSizeDispatchConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Uint64(prefix+".small-input-limit", DefaultInputSizeDispatchConfig.SmallInputLimit, "validation inputs of at most this many bytes are small, and the others large")
+	f.String(prefix+".small-input-spawner", DefaultInputSizeDispatchConfig.SmallInputSpawner, "name (or name prefix) of the validation servers to validate small inputs with, e.g. arbitrator (empty for any)")
+	f.String(prefix+".large-input-spawner", DefaultInputSizeDispatchConfig.LargeInputSpawner, "name (or name prefix) of the validation servers to validate large inputs with, e.g. jit (empty for any)")
+}
+
+// preferredSpawner returns the name of the validation servers preferred for an input of the given size,
+// or "" if there's no preference.
+func (c *InputSizeDispatchConfig) preferredSpawner(inputSize uint64) string {
+	if inputSize <= c.SmallInputLimit {
+		return c.SmallInputSpawner
+	}
+	return c.LargeInputSpawner
+}
+
+// preferredSpawners returns the spawners preferred for the entry's input size, or nil if there's no preference
+// or none of them is preferred.
+func (c *InputSizeDispatchConfig) preferredSpawners(entry *validationEntry, spawners []validator.ValidationSpawner) []validator.ValidationSpawner {
+	if c.SmallInputSpawner == "" && c.LargeInputSpawner == "" {
+		return nil
+	}
+	preferred := c.preferredSpawner(entry.inputSize())
+	if preferred == "" {
+		return nil
+	}
+	var dispatched []validator.ValidationSpawner
+	for _, spawner := range spawners {
+		if strings.HasPrefix(spawner.Name(), preferred) {
+			dispatched = append(dispatched, spawner)
+		}
+	}
+	return dispatched
+}
+
+// dispatchBySize narrows spawners down to the ones preferred for the entry's input size.
+// If none of them is preferred, every spawner is kept.
+func (c *InputSizeDispatchConfig) dispatchBySize(entry *validationEntry, spawners []validator.ValidationSpawner) []validator.ValidationSpawner {
+	if dispatched := c.preferredSpawners(entry, spawners); len(dispatched) > 0 {
+		return dispatched
+	}
+	return spawners
+}
+
+// inputSize returns the size in bytes of the entry's validation input: its batches, delayed message,
+// preimages and user wasms. User wasms are counted for a single target, as they're compiled per target.
+func (e *validationEntry) inputSize() uint64 {
+	var size uint64
+	for _, batch := range e.BatchInfo {
+		size += uint64(len(batch.Data))
+	}
+	size += uint64(len(e.DelayedMsg))
+	for _, preimages := range e.Preimages {
+		for _, preimage := range preimages {
+			size += uint64(len(preimage))
+		}
+	}
+	for _, asmMap := range e.UserWasms {
+		var largest int
+		for _, asm := range asmMap {
+			largest = max(largest, len(asm))
+		}
+		size += uint64(largest)
+	}
+	return size
+}
@@ -0,0 +1,71 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+
+	"github.com/offchainlabs/nitro/util/containers"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+var testModuleRoot = common.HexToHash("0xa5a5a5")
+
+// namedSpawner is an execution spawner supporting testModuleRoot, only identified by its name
+type namedSpawner struct {
+	name string
+}
+
+func (s *namedSpawner) Launch(*validator.ValidationInput, common.Hash) validator.ValidationRun {
+	return nil
+}
+func (s *namedSpawner) WasmModuleRoots() ([]common.Hash, error) {
+	return []common.Hash{testModuleRoot}, nil
+}
+func (s *namedSpawner) Start(context.Context) error     { return nil }
+func (s *namedSpawner) Stop()                           {}
+func (s *namedSpawner) Name() string                    { return s.name }
+func (s *namedSpawner) StylusArchs() []rawdb.WasmTarget { return nil }
+func (s *namedSpawner) Room() int                       { return 1 }
+func (s *namedSpawner) CreateExecutionRun(common.Hash, *validator.ValidationInput, bool) containers.PromiseInterface[validator.ExecutionRun] {
+	return nil
+}
+
+func TestBlockValidatorChosenSpawnerDispatchesByInputSize(t *testing.T) {
+	arbitrator := &namedSpawner{name: "arbitrator"}
+	jit := &namedSpawner{name: "jit-cranelift"}
+	config := DefaultBlockValidatorConfig
+	v := &BlockValidator{
+		StatelessBlockValidator: &StatelessBlockValidator{
+			execSpawners: []validator.ExecutionSpawner{arbitrator, jit},
+		},
+		config:          func() *BlockValidatorConfig { return &config },
+		chosenValidator: map[common.Hash]validator.ValidationSpawner{testModuleRoot: arbitrator},
+	}
+	small := &validationEntry{Pos: 1, BatchInfo: []validator.BatchInfo{{Number: 1, Data: make([]byte, 10)}}}
+	large := &validationEntry{Pos: 2, BatchInfo: []validator.BatchInfo{{Number: 1, Data: make([]byte, 1000)}}}
+
+	for _, tc := range []struct {
+		name     string
+		dispatch InputSizeDispatchConfig
+		entry    *validationEntry
+		expected validator.ValidationSpawner
+	}{
+		// without dispatching, the spawner chosen for the module root validates every input
+		{"undispatched", DefaultInputSizeDispatchConfig, large, arbitrator},
+		{"small", InputSizeDispatchConfig{SmallInputLimit: 100, SmallInputSpawner: "arbitrator", LargeInputSpawner: "jit"}, small, arbitrator},
+		{"large", InputSizeDispatchConfig{SmallInputLimit: 100, SmallInputSpawner: "arbitrator", LargeInputSpawner: "jit"}, large, jit},
+		// no spawner has the preferred name
+		{"unmatched", InputSizeDispatchConfig{SmallInputLimit: 100, LargeInputSpawner: "remote"}, large, arbitrator},
+	} {
+		config.InputSizeDispatch = tc.dispatch
+		if spawner := v.chosenSpawner(tc.entry, testModuleRoot); spawner != tc.expected {
+			t.Fatal(tc.name, "input validated by", spawner.Name(), "expected", tc.expected.Name())
+		}
+	}
+}
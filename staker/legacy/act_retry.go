@@ -0,0 +1,85 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbnode/dataposter"
+)
+
+// ErrDataPosterNotReady is returned when the data poster is ahead of the on-chain nonce, waiting for a
+// pending transaction to be included before the staker can post another.
+var ErrDataPosterNotReady = errors.New("data poster is waiting for a pending transaction")
+
+// ErrBlockValidationPending is returned when the staker needs a block validation which hasn't completed yet.
+var ErrBlockValidationPending = errors.New("block validation is still pending")
+
+// ErrNotCaughtUp is returned when the node hasn't caught up to the chain state the staker acts on yet.
+var ErrNotCaughtUp = errors.New("not caught up")
+
+// ErrNodeNotFound is returned when a rollup node isn't found on the parent chain yet, e.g. as the parent
+// chain node serving the staker is behind the rollup contract state.
+var ErrNodeNotFound = errors.New("couldn't find requested node")
+
+// IsTransientActError returns whether err is an act error expected to go away by itself,
+// so the act can simply be retried.
+func IsTransientActError(err error) bool {
+	return errors.Is(err, ErrDataPosterNotReady) ||
+		errors.Is(err, ErrBlockValidationPending) ||
+		errors.Is(err, ErrNotCaughtUp) ||
+		errors.Is(err, ErrNodeNotFound) ||
+		errors.Is(err, dataposter.ErrExceedsMaxMempoolSize)
+}
+
+// ActRetryPolicy determines how ActWithRetry retries transient act errors.
+type ActRetryPolicy struct {
+	// MaxAttempts is the maximum number of acts to try (0 = retry until the context is done)
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled on every retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries (0 = uncapped)
+	MaxBackoff time.Duration
+}
+
+var DefaultActRetryPolicy = ActRetryPolicy{
+	MaxAttempts:    10,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+// ActWithRetry runs act cycles, serialized with the staker loop's, until one succeeds or fails with an error
+// which isn't transient, backing off between them as given by policy. If the attempts run out, the last error
+// is returned.
+func (s *Staker) ActWithRetry(ctx context.Context, policy ActRetryPolicy) (*types.Transaction, error) {
+	return retryTransientActErrors(ctx, policy, s.actOnce)
+}
+
+func retryTransientActErrors(ctx context.Context, policy ActRetryPolicy, act func(context.Context) (*types.Transaction, error)) (*types.Transaction, error) {
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		tx, err := act(ctx)
+		if err == nil || !IsTransientActError(err) || (policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts) {
+			return tx, err
+		}
+		log.Debug("retrying staker act after transient error", "attempt", attempt, "backoff", backoff, "err", err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w while retrying act: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+		backoff *= 2
+		if policy.MaxBackoff > 0 {
+			backoff = min(backoff, policy.MaxBackoff)
+		}
+	}
+}
@@ -4,8 +4,11 @@
 package legacystaker
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 
@@ -78,6 +81,52 @@ func (n *NodeInfo) AfterState() *validator.ExecutionState {
 	return n.Assertion.AfterState
 }
 
+// AfterInboxBatchCount returns the number of batches read by the node's assertion,
+// computed the same way as the rollup contract computes it when creating the node.
+func (n *NodeInfo) AfterInboxBatchCount() uint64 {
+	afterState := n.AfterState()
+	count := afterState.GlobalState.Batch
+	if afterState.MachineStatus == validator.MachineStatusErrored || afterState.GlobalState.PosInBatch > 0 {
+		count++
+	}
+	return count
+}
+
+// ErrNodeInboxMismatch is returned when a node's committed inbox position disagrees with the inbox tracker.
+var ErrNodeInboxMismatch = errors.New("node inbox position doesn't match inbox tracker")
+
+type batchAccTracker interface {
+	GetBatchCount() (uint64, error)
+	GetBatchAcc(seqNum uint64) (common.Hash, error)
+}
+
+// verifyNodeInbox checks that the inbox position the node commits to, its after inbox batch count
+// and accumulator, matches what the tracker recorded, and that it doesn't exceed the node's inbox max count.
+func verifyNodeInbox(tracker batchAccTracker, n *NodeInfo) error {
+	afterCount := n.AfterInboxBatchCount()
+	if afterCount == 0 {
+		return fmt.Errorf("%w: node %v doesn't read any batch", ErrNodeInboxMismatch, n.NodeNum)
+	}
+	if n.InboxMaxCount != nil && (!n.InboxMaxCount.IsUint64() || afterCount > n.InboxMaxCount.Uint64()) {
+		return fmt.Errorf("%w: node %v reads %v batches but its inbox max count is %v", ErrNodeInboxMismatch, n.NodeNum, afterCount, n.InboxMaxCount)
+	}
+	batchCount, err := tracker.GetBatchCount()
+	if err != nil {
+		return err
+	}
+	if batchCount < afterCount {
+		return fmt.Errorf("inbox tracker has %v batches but node %v reads %v", batchCount, n.NodeNum, afterCount)
+	}
+	acc, err := tracker.GetBatchAcc(afterCount - 1)
+	if err != nil {
+		return fmt.Errorf("error getting batch %v accumulator: %w", afterCount-1, err)
+	}
+	if acc != n.AfterInboxBatchAcc {
+		return fmt.Errorf("%w: node %v commits to batch %v accumulator %v but inbox tracker has %v", ErrNodeInboxMismatch, n.NodeNum, afterCount-1, n.AfterInboxBatchAcc, acc)
+	}
+	return nil
+}
+
 func (n *NodeInfo) MachineStatuses() [2]uint8 {
 	return [2]uint8{
 		uint8(n.Assertion.BeforeState.MachineStatus),
@@ -91,3 +140,22 @@ func (n *NodeInfo) GlobalStates() [2]rollup_legacy_gen.GlobalState {
 		n.Assertion.AfterState.GlobalState.AsLegacySolidityStruct(),
 	}
 }
+
+// ErrNodePredecessorMismatch is returned when a node isn't built on the node the validator agrees with.
+var ErrNodePredecessorMismatch = errors.New("node predecessor isn't the agreed node")
+
+type nodeReader interface {
+	GetNode(opts *bind.CallOpts, nodeNum uint64) (rollup_legacy_gen.Node, error)
+}
+
+// verifyNodePredecessor checks that the rollup records agreedNode as the predecessor of nodeNum.
+func verifyNodePredecessor(opts *bind.CallOpts, rollup nodeReader, nodeNum uint64, agreedNode uint64) error {
+	node, err := rollup.GetNode(opts, nodeNum)
+	if err != nil {
+		return fmt.Errorf("error getting node %v: %w", nodeNum, err)
+	}
+	if node.PrevNum != agreedNode {
+		return fmt.Errorf("%w: node %v has predecessor %v but we agree with node %v", ErrNodePredecessorMismatch, nodeNum, node.PrevNum, agreedNode)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
+)
+
+var rollupABI, validatorUtilsABI *abi.ABI
+
+func init() {
+	var err error
+	rollupABI, err = rollup_legacy_gen.RollupUserLogicMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	validatorUtilsABI, err = rollup_legacy_gen.ValidatorUtilsMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// actRead is a read-only contract call made during an act cycle.
+type actRead struct {
+	to   common.Address
+	data []byte
+}
+
+func newActRead(to common.Address, contractABI *abi.ABI, method string, args ...interface{}) (actRead, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return actRead{}, fmt.Errorf("error packing %v call: %w", method, err)
+	}
+	return actRead{to: to, data: data}, nil
+}
+
+type actReadKey struct {
+	to   common.Address
+	data string
+}
+
+// actReads holds the results of the read-only calls prefetched for an act cycle.
+type actReads struct {
+	results map[actReadKey][]byte
+}
+
+type actReadsContextKey struct{}
+
+func withActReads(ctx context.Context, reads *actReads) context.Context {
+	return context.WithValue(ctx, actReadsContextKey{}, reads)
+}
+
+func actReadsFromContext(ctx context.Context) *actReads {
+	reads, _ := ctx.Value(actReadsContextKey{}).(*actReads)
+	return reads
+}
+
+type batchCaller interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// prefetchActReads performs the given calls against the latest block in a single JSON-RPC batch.
+// Calls which fail, e.g. because they revert, aren't cached and are made individually when needed.
+func prefetchActReads(ctx context.Context, client batchCaller, calls []actRead) (*actReads, error) {
+	batch := make([]rpc.BatchElem, len(calls))
+	results := make([]hexutil.Bytes, len(calls))
+	for i, call := range calls {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args: []interface{}{
+				map[string]interface{}{"to": call.to, "input": hexutil.Bytes(call.data)},
+				"latest",
+			},
+			Result: &results[i],
+		}
+	}
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+	reads := &actReads{results: make(map[actReadKey][]byte, len(calls))}
+	for i, call := range calls {
+		if batch[i].Error != nil {
+			log.Debug("batched act read failed, it'll be retried individually", "to", call.to, "err", batch[i].Error)
+			continue
+		}
+		reads.results[actReadKey{to: call.to, data: string(call.data)}] = results[i]
+	}
+	return reads, nil
+}
+
+// batchedReadClient serves contract calls from the reads prefetched for the act cycle of the
+// call's context, if any, and otherwise forwards them to the underlying client.
+type batchedReadClient struct {
+	RollupWatcherL1Interface
+}
+
+func newBatchedReadClient(client RollupWatcherL1Interface) RollupWatcherL1Interface {
+	return &batchedReadClient{RollupWatcherL1Interface: client}
+}
+
+func (c *batchedReadClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if reads := actReadsFromContext(ctx); reads != nil && blockNumber == nil && call.To != nil && call.From == (common.Address{}) {
+		if result, ok := reads.results[actReadKey{to: *call.To, data: string(call.Data)}]; ok {
+			return result, nil
+		}
+	}
+	return c.RollupWatcherL1Interface.CallContract(ctx, call, blockNumber)
+}
+
+// actReadCalls returns the read-only calls made by every act cycle, which can be batched.
+func (s *Staker) actReadCalls() ([]actRead, error) {
+	type method struct {
+		to          common.Address
+		contractABI *abi.ABI
+		name        string
+		args        []interface{}
+	}
+	methods := []method{
+		{s.rollupAddress, rollupABI, "validatorWhitelistDisabled", nil},
+		{s.rollupAddress, rollupABI, "paused", nil},
+		{s.rollupAddress, rollupABI, "latestConfirmed", nil},
+		{s.rollupAddress, rollupABI, "firstUnresolvedNode", nil},
+		{s.rollupAddress, rollupABI, "baseStake", nil},
+		{s.rollupAddress, rollupABI, "currentRequiredStake", nil},
+		{s.rollupAddress, rollupABI, "minimumAssertionPeriod", nil},
+		{s.validatorUtilsAddress, validatorUtilsABI, "areUnresolvedNodesLinear", []interface{}{s.rollupAddress}},
+	}
+	walletAddressOrZero := s.wallet.AddressOrZero()
+	methods = append(methods, method{s.validatorUtilsAddress, validatorUtilsABI, "latestStaked", []interface{}{s.rollupAddress, walletAddressOrZero}})
+	if walletAddressOrZero != (common.Address{}) {
+		methods = append(methods,
+			method{s.rollupAddress, rollupABI, "isValidator", []interface{}{walletAddressOrZero}},
+			method{s.rollupAddress, rollupABI, "_stakerMap", []interface{}{walletAddressOrZero}},
+			method{s.rollupAddress, rollupABI, "isZombie", []interface{}{walletAddressOrZero}},
+			method{s.rollupAddress, rollupABI, "withdrawableFunds", []interface{}{walletAddressOrZero}},
+		)
+	}
+	calls := make([]actRead, 0, len(methods))
+	for _, m := range methods {
+		call, err := newActRead(m.to, m.contractABI, m.name, m.args...)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	return calls, nil
+}
+
+// batchActReads prefetches the act cycle's read-only calls in a single round trip, returning a context
+// serving them. If batching fails, the original context is returned and calls are made individually.
+func (s *Staker) batchActReads(ctx context.Context) context.Context {
+	calls, err := s.actReadCalls()
+	if err != nil {
+		log.Warn("error preparing batched act reads", "err", err)
+		return ctx
+	}
+	reads, err := prefetchActReads(ctx, s.client.Client(), calls)
+	if err != nil {
+		log.Warn("error batching act reads, making them individually", "err", err)
+		return ctx
+	}
+	return withActReads(ctx, reads)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type fakeEthCallArgs struct {
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+// fakeEthService answers eth_call with fixed results by calldata, reverting unknown calls,
+// and serves what's needed to build (but not send) legacy transactions
+type fakeEthService struct {
+	results map[string]hexutil.Bytes
+}
+
+func (s *fakeEthService) Call(args fakeEthCallArgs, _ string) (hexutil.Bytes, error) {
+	result, ok := s.results[string(args.Input)]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return result, nil
+}
+
+func (s *fakeEthService) GetBlockByNumber(string, bool) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(1), Difficulty: common.Big0}, nil
+}
+
+func (s *fakeEthService) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(common.Big1)
+}
+
+func (s *fakeEthService) GetTransactionCount(common.Address, string) hexutil.Uint64 {
+	return 0
+}
+
+func TestBatchedActReads(t *testing.T) {
+	ctx := context.Background()
+	rollupAddress := common.HexToAddress("0x1000")
+	staker := common.HexToAddress("0x1234")
+
+	latestConfirmed, err := newActRead(rollupAddress, rollupABI, "latestConfirmed")
+	Require(t, err)
+	isZombie, err := newActRead(rollupAddress, rollupABI, "isZombie", staker)
+	Require(t, err)
+	firstUnresolved, err := newActRead(rollupAddress, rollupABI, "firstUnresolvedNode")
+	Require(t, err)
+	latestConfirmedResult, err := rollupABI.Methods["latestConfirmed"].Outputs.Pack(uint64(5))
+	Require(t, err)
+	isZombieResult, err := rollupABI.Methods["isZombie"].Outputs.Pack(true)
+	Require(t, err)
+	service := &fakeEthService{results: map[string]hexutil.Bytes{
+		string(latestConfirmed.data): latestConfirmedResult,
+		string(isZombie.data):        isZombieResult,
+	}}
+
+	server := rpc.NewServer()
+	Require(t, server.RegisterName("eth", service))
+	var roundTrips atomic.Int64
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		roundTrips.Add(1)
+		server.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+	rpcClient, err := rpc.DialHTTP(httpServer.URL)
+	Require(t, err)
+	defer rpcClient.Close()
+
+	rollup, err := NewRollupWatcher(rollupAddress, newBatchedReadClient(ethclient.NewClient(rpcClient)), bind.CallOpts{})
+	Require(t, err)
+	read := func(ctx context.Context) (uint64, bool) {
+		confirmed, err := rollup.LatestConfirmed(&bind.CallOpts{Context: ctx})
+		Require(t, err)
+		zombie, err := rollup.IsZombie(&bind.CallOpts{Context: ctx}, staker)
+		Require(t, err)
+		return confirmed, zombie
+	}
+
+	confirmed, zombie := read(ctx)
+	unbatchedRoundTrips := roundTrips.Swap(0)
+	if confirmed != 5 || !zombie {
+		Fail(t, "unexpected unbatched reads", confirmed, zombie)
+	}
+
+	reads, err := prefetchActReads(ctx, rpcClient, []actRead{latestConfirmed, isZombie, firstUnresolved})
+	Require(t, err)
+	batchedCtx := withActReads(ctx, reads)
+	batchedConfirmed, batchedZombie := read(batchedCtx)
+	batchedRoundTrips := roundTrips.Swap(0)
+	if batchedConfirmed != confirmed || batchedZombie != zombie {
+		Fail(t, "batched reads", batchedConfirmed, batchedZombie, "differ from unbatched reads", confirmed, zombie)
+	}
+	if batchedRoundTrips != 1 || unbatchedRoundTrips != 2 {
+		Fail(t, "expected batching to take reads from 2 round trips to 1, got", unbatchedRoundTrips, "and", batchedRoundTrips)
+	}
+
+	// failed calls aren't cached, and are made individually
+	if _, err := rollup.FirstUnresolvedNode(&bind.CallOpts{Context: batchedCtx}); err == nil {
+		Fail(t, "expected reverting call to fail")
+	}
+	if roundTrips.Load() != 1 {
+		Fail(t, "expected failed batched call to be retried individually, got", roundTrips.Load(), "round trips")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"math"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -193,6 +194,7 @@ func (b *BlockChallengeBackend) GetHashAtStep(_ context.Context, position uint64
 }
 
 func (b *BlockChallengeBackend) IssueExecChallenge(
+	ctx context.Context,
 	core *challengeCore,
 	oldState *ChallengeState,
 	startSegment int,
@@ -214,17 +216,20 @@ func (b *BlockChallengeBackend) IssueExecChallenge(
 		globalStates[0].Hash(),
 		globalStates[1].Hash(),
 	}
-	return core.con.ChallengeExecution(
-		core.auth,
-		core.challengeIndex,
-		challenge_legacy_gen.ChallengeLibSegmentSelection{
-			OldSegmentsStart:  oldState.Start,
-			OldSegmentsLength: new(big.Int).Sub(oldState.End, oldState.Start),
-			OldSegments:       oldState.RawSegments,
-			ChallengePosition: big.NewInt(int64(startSegment)),
-		},
-		machineStatuses,
-		globalStateHashes,
-		new(big.Int).SetUint64(numsteps),
-	)
+	makeMove := func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return core.con.ChallengeExecution(
+			auth,
+			core.challengeIndex,
+			challenge_legacy_gen.ChallengeLibSegmentSelection{
+				OldSegmentsStart:  oldState.Start,
+				OldSegmentsLength: new(big.Int).Sub(oldState.End, oldState.Start),
+				OldSegments:       oldState.RawSegments,
+				ChallengePosition: big.NewInt(int64(startSegment)),
+			},
+			machineStatuses,
+			globalStateHashes,
+			new(big.Int).SetUint64(numsteps),
+		)
+	}
+	return core.sendMove(ctx, "execution challenge", makeMove)
 }
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
+	"strings"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -29,9 +31,52 @@ const maxBisectionDegree uint64 = 40
 
 const challengeModeExecution = 2
 
+// ErrAgreedWithEntireChallenge is returned when we agree with every segment of a challenge we're
+// supposed to move in, meaning we have no disagreement to press.
+var ErrAgreedWithEntireChallenge = errors.New("agreed with entire challenge")
+
+// ErrChallengeMoveGasCeilingExceeded is returned instead of making a challenge move estimated to
+// need more gas than the configured ceiling, which requires operator intervention to resolve.
+var ErrChallengeMoveGasCeilingExceeded = errors.New("challenge move exceeds gas ceiling")
+
+// ErrChallengeMoveInsufficientFunds is returned when a challenge move can't be made because its sender
+// can't pay for it, and no emergency top-up is configured or it didn't make up for the shortfall.
+var ErrChallengeMoveInsufficientFunds = errors.New("insufficient funds for challenge move")
+
+// ErrChallengeModuleRootUnavailable is returned when none of the local execution spawners has the machine
+// for the wasm module root a challenge was created with, which after an upgrade may not be the latest one.
+var ErrChallengeModuleRootUnavailable = errors.New("challenge wasm module root not available locally")
+
+// EmergencyTopUpFunc funds account, the sender of a challenge move which failed for insufficient funds,
+// returning once the funds are available so that the move can be retried.
+type EmergencyTopUpFunc func(ctx context.Context, account common.Address) error
+
+// AgreedChallengeAction determines what the challenge manager does upon agreeing with an entire challenge.
+type AgreedChallengeAction uint8
+
+const (
+	// AgreedChallengeActionError surfaces ErrAgreedWithEntireChallenge on every act.
+	AgreedChallengeActionError AgreedChallengeAction = iota
+	// AgreedChallengeActionWithdraw stops making moves in the challenge, letting it resolve without us.
+	AgreedChallengeActionWithdraw
+)
+
+func ParseAgreedChallengeAction(action string) (AgreedChallengeAction, error) {
+	switch strings.ToLower(action) {
+	case "error":
+		return AgreedChallengeActionError, nil
+	case "withdraw":
+		return AgreedChallengeActionWithdraw, nil
+	default:
+		return AgreedChallengeActionError, fmt.Errorf("unknown agreed challenge action \"%v\"", action)
+	}
+}
+
 var initiatedChallengeID common.Hash
 var challengeBisectedID common.Hash
 var executionChallengeBegunID common.Hash
+var oneStepProofCompletedID common.Hash
+var challengeEndedID common.Hash
 
 func init() {
 	parsedChallengeManagerABI, err := challenge_legacy_gen.ChallengeManagerMetaData.GetAbi()
@@ -41,6 +86,8 @@ func init() {
 	initiatedChallengeID = parsedChallengeManagerABI.Events["InitiatedChallenge"].ID
 	challengeBisectedID = parsedChallengeManagerABI.Events["Bisected"].ID
 	executionChallengeBegunID = parsedChallengeManagerABI.Events["ExecutionChallengeBegun"].ID
+	oneStepProofCompletedID = parsedChallengeManagerABI.Events["OneStepProofCompleted"].ID
+	challengeEndedID = parsedChallengeManagerABI.Events["ChallengeEnded"].ID
 }
 
 type ChallengeBackend interface {
@@ -60,6 +107,69 @@ type challengeCore struct {
 	actingAs             common.Address
 	startL1Block         *big.Int
 	confirmationBlocks   int64
+	maxMoveGas           uint64
+	logger               log.Logger
+	emergencyTopUp       EmergencyTopUpFunc
+}
+
+// checkMoveGas estimates the gas of a challenge move without sending it, returning
+// ErrChallengeMoveGasCeilingExceeded if it exceeds the move gas ceiling.
+// The move is estimated on its own with a transactor acting as the validator wallet,
+// as the auth used to make moves may batch transactions or skip gas estimation.
+func (c *challengeCore) checkMoveGas(ctx context.Context, move string, makeMove func(*bind.TransactOpts) (*types.Transaction, error)) error {
+	if c.maxMoveGas == 0 {
+		return nil
+	}
+	estimateAuth := &bind.TransactOpts{
+		From:    c.actingAs,
+		Context: ctx,
+		NoSend:  true,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+	tx, err := makeMove(estimateAuth)
+	if err != nil {
+		return fmt.Errorf("error estimating gas of challenge %v %s: %w", c.challengeIndex, move, err)
+	}
+	if tx.Gas() > c.maxMoveGas {
+		c.logger.Error("challenge move exceeds gas ceiling, not posting it; operator intervention required", "challenge", c.challengeIndex, "move", move, "gas", tx.Gas(), "maxMoveGas", c.maxMoveGas)
+		return fmt.Errorf("%w: challenge %v %s needs %v gas but ceiling is %v", ErrChallengeMoveGasCeilingExceeded, c.challengeIndex, move, tx.Gas(), c.maxMoveGas)
+	}
+	return nil
+}
+
+// isInsufficientFunds returns whether err is a failure to pay for a transaction, as reported by the
+// parent chain node, whose errors are only available as strings over RPC.
+func isInsufficientFunds(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "insufficient funds")
+}
+
+// sendMove makes a challenge move once its gas is vetted by checkMoveGas. Running out of funds for a move
+// risks losing the challenge once the move window closes, so if the move's sender can't pay for it, this
+// raises a critical alert and, if an emergency top-up is configured, tops the sender up and retries the
+// move right away.
+func (c *challengeCore) sendMove(ctx context.Context, move string, makeMove func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	if err := c.checkMoveGas(ctx, move, makeMove); err != nil {
+		return nil, err
+	}
+	tx, err := makeMove(c.auth)
+	if !isInsufficientFunds(err) {
+		return tx, err
+	}
+	c.logger.Error("CRITICAL: insufficient funds to make challenge move, the challenge is lost if no move is made in time", "challenge", c.challengeIndex, "move", move, "sender", c.auth.From, "err", err)
+	if c.emergencyTopUp == nil {
+		return nil, fmt.Errorf("%w: challenge %v %s: %w", ErrChallengeMoveInsufficientFunds, c.challengeIndex, move, err)
+	}
+	if topUpErr := c.emergencyTopUp(ctx, c.auth.From); topUpErr != nil {
+		return nil, fmt.Errorf("%w: challenge %v %s: emergency top-up failed: %w", ErrChallengeMoveInsufficientFunds, c.challengeIndex, move, topUpErr)
+	}
+	c.logger.Warn("retrying challenge move after emergency top-up", "challenge", c.challengeIndex, "move", move, "sender", c.auth.From)
+	tx, err = makeMove(c.auth)
+	if isInsufficientFunds(err) {
+		return nil, fmt.Errorf("%w: challenge %v %s after emergency top-up: %w", ErrChallengeMoveInsufficientFunds, c.challengeIndex, move, err)
+	}
+	return tx, err
 }
 
 type ChallengeManager struct {
@@ -78,6 +188,9 @@ type ChallengeManager struct {
 	initialMachineMessageCount arbutil.MessageIndex
 	executionChallengeBackend  *staker.ExecutionChallengeBackend
 	machineFinalStepCount      uint64
+
+	agreedChallengeAction AgreedChallengeAction
+	withdrawn             bool
 }
 
 // NewChallengeManager constructs a new challenge manager.
@@ -128,6 +241,13 @@ func NewChallengeManager(
 		return nil, fmt.Errorf("error getting challenge %v info: %w", challengeIndex, err)
 	}
 
+	if val != nil {
+		// Fail early rather than bisect a challenge whose execution challenge we couldn't prove.
+		if _, err := executionSpawnerForModuleRoot(val.ExecutionSpawners(), challengeInfo.WasmModuleRoot); err != nil {
+			return nil, fmt.Errorf("challenge %v can't be proven with local machines: %w", challengeIndex, err)
+		}
+	}
+
 	backend, err := NewBlockChallengeBackend(
 		parsedLog,
 		challengeInfo.MaxInboxMessages,
@@ -147,6 +267,7 @@ func NewChallengeManager(
 			actingAs:             fromAddr,
 			startL1Block:         new(big.Int).SetUint64(startL1Block),
 			confirmationBlocks:   confirmationBlocks,
+			logger:               log.Root(),
 		},
 		blockChallengeBackend: backend,
 		validator:             val,
@@ -183,6 +304,7 @@ func NewExecutionChallengeManager(
 			actingAs:             auth.From,
 			startL1Block:         new(big.Int).SetUint64(startL1Block),
 			confirmationBlocks:   confirmationBlocks,
+			logger:               log.Root(),
 		},
 		executionChallengeBackend: backend,
 	}, nil
@@ -228,6 +350,36 @@ func (m *ChallengeManager) ChallengeIndex() uint64 {
 	return m.challengeIndex
 }
 
+// WasmModuleRoot returns the module root recorded for the challenged nodes, which the execution challenge is
+// proven against even if the node has since upgraded to a later root.
+func (m *ChallengeManager) WasmModuleRoot() common.Hash {
+	return m.wasmModuleRoot
+}
+
+// SetMaxMoveGas sets the gas ceiling of a single challenge move, where 0 means no ceiling.
+func (m *ChallengeManager) SetMaxMoveGas(maxMoveGas uint64) {
+	m.maxMoveGas = maxMoveGas
+}
+
+// SetEmergencyTopUp sets the hook funding the sender of a challenge move which fails for insufficient
+// funds before retrying the move, where nil means the move fails with ErrChallengeMoveInsufficientFunds.
+func (m *ChallengeManager) SetEmergencyTopUp(topUp EmergencyTopUpFunc) {
+	m.emergencyTopUp = topUp
+}
+
+func (m *ChallengeManager) SetLogger(logger log.Logger) {
+	m.logger = logger
+}
+
+func (m *ChallengeManager) SetAgreedChallengeAction(action AgreedChallengeAction) {
+	m.agreedChallengeAction = action
+}
+
+// Withdrawn returns true if we stopped making moves in this challenge after agreeing with all of it.
+func (m *ChallengeManager) Withdrawn() bool {
+	return m.withdrawn
+}
+
 func uint64ToIndex(val uint64) common.Hash {
 	var challengeIndex common.Hash
 	binary.BigEndian.PutUint64(challengeIndex[(32-8):], val)
@@ -248,7 +400,7 @@ func (m *ChallengeManager) resolveStateHash(ctx context.Context, stateHash commo
 		return ChallengeState{}, fmt.Errorf("didn't find Bisected event for challenge %v state hash %v starting at block %v", m.challengeIndex, stateHash, m.startL1Block)
 	}
 	if len(logs) > 1 {
-		log.Warn("found multiple Bisected logs", "challenge", m.challengeIndex, "count", len(logs), "fromBlock", m.startL1Block)
+		m.logger.Warn("found multiple Bisected logs", "challenge", m.challengeIndex, "count", len(logs), "fromBlock", m.startL1Block)
 	}
 	// Multiple logs are in theory fine, as they should all reveal the same preimage.
 	// We'll use the most recent log to be safe.
@@ -257,6 +409,11 @@ func (m *ChallengeManager) resolveStateHash(ctx context.Context, stateHash commo
 	if err != nil {
 		return ChallengeState{}, fmt.Errorf("error parsing Bisected event log for challenge %v state hash %v: %w", m.challengeIndex, stateHash, err)
 	}
+	return challengeStateFromBisection(parsedLog)
+}
+
+// challengeStateFromBisection computes the challenge state revealed by a Bisected event.
+func challengeStateFromBisection(parsedLog *challenge_legacy_gen.ChallengeManagerBisected) (ChallengeState, error) {
 	state := ChallengeState{
 		Start:       parsedLog.ChallengedSegmentStart,
 		End:         new(big.Int).Add(parsedLog.ChallengedSegmentStart, parsedLog.ChallengedSegmentLength),
@@ -314,17 +471,20 @@ func (m *ChallengeManager) bisect(ctx context.Context, backend ChallengeBackend,
 		}
 		position += normalSegmentLength
 	}
-	return m.con.BisectExecution(
-		m.auth,
-		m.challengeIndex,
-		challenge_legacy_gen.ChallengeLibSegmentSelection{
-			OldSegmentsStart:  oldState.Start,
-			OldSegmentsLength: new(big.Int).Sub(oldState.End, oldState.Start),
-			OldSegments:       oldState.RawSegments,
-			ChallengePosition: big.NewInt(int64(startSegment)),
-		},
-		newSegments,
-	)
+	makeMove := func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return m.con.BisectExecution(
+			auth,
+			m.challengeIndex,
+			challenge_legacy_gen.ChallengeLibSegmentSelection{
+				OldSegmentsStart:  oldState.Start,
+				OldSegmentsLength: new(big.Int).Sub(oldState.End, oldState.Start),
+				OldSegments:       oldState.RawSegments,
+				ChallengePosition: big.NewInt(int64(startSegment)),
+			},
+			newSegments,
+		)
+	}
+	return m.sendMove(ctx, "bisection", makeMove)
 }
 
 func (m *ChallengeManager) IsMyTurn(ctx context.Context) (bool, error) {
@@ -378,7 +538,7 @@ func (m *ChallengeManager) ScanChallengeState(ctx context.Context, backend Chall
 		if err != nil {
 			return 0, fmt.Errorf("error getting hash from challenge %v backend at step %v: %w", m.challengeIndex, segment.Position, err)
 		}
-		log.Debug("checking challenge segment", "challenge", m.challengeIndex, "position", segment.Position, "ourHash", ourHash, "segmentHash", segment.Hash)
+		m.logger.Debug("checking challenge segment", "challenge", m.challengeIndex, "position", segment.Position, "ourHash", ourHash, "segmentHash", segment.Hash)
 		if segment.Hash != ourHash {
 			if i == 0 {
 				return 0, fmt.Errorf(
@@ -389,7 +549,7 @@ func (m *ChallengeManager) ScanChallengeState(ctx context.Context, backend Chall
 			return i - 1, nil
 		}
 	}
-	return 0, fmt.Errorf("agreed with entire challenge %v (start step count %v and end step count %v)", m.challengeIndex, state.Start.String(), state.End.String())
+	return 0, fmt.Errorf("%w %v (start step count %v and end step count %v)", ErrAgreedWithEntireChallenge, m.challengeIndex, state.Start.String(), state.End.String())
 }
 
 // Checks if an execution challenge exists on-chain.
@@ -449,17 +609,38 @@ func (m *ChallengeManager) IssueOneStepProof(
 	if err != nil {
 		return nil, fmt.Errorf("error getting OSP from challenge %v backend at step %v: %w", m.challengeIndex, position, err)
 	}
-	return m.challengeCore.con.OneStepProveExecution(
-		m.challengeCore.auth,
-		m.challengeCore.challengeIndex,
-		challenge_legacy_gen.ChallengeLibSegmentSelection{
-			OldSegmentsStart:  oldState.Start,
-			OldSegmentsLength: new(big.Int).Sub(oldState.End, oldState.Start),
-			OldSegments:       oldState.RawSegments,
-			ChallengePosition: big.NewInt(int64(startSegment)),
-		},
-		proof,
-	)
+	makeMove := func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return m.challengeCore.con.OneStepProveExecution(
+			auth,
+			m.challengeCore.challengeIndex,
+			challenge_legacy_gen.ChallengeLibSegmentSelection{
+				OldSegmentsStart:  oldState.Start,
+				OldSegmentsLength: new(big.Int).Sub(oldState.End, oldState.Start),
+				OldSegments:       oldState.RawSegments,
+				ChallengePosition: big.NewInt(int64(startSegment)),
+			},
+			proof,
+		)
+	}
+	return m.sendMove(ctx, "one step proof", makeMove)
+}
+
+// executionSpawnerForModuleRoot returns the first of spawners which supports moduleRoot, or an error listing
+// the module roots which are available locally if none does.
+func executionSpawnerForModuleRoot(spawners []validator.ExecutionSpawner, moduleRoot common.Hash) (validator.ExecutionSpawner, error) {
+	var available []common.Hash
+	for _, spawner := range spawners {
+		roots, err := spawner.WasmModuleRoots()
+		if err != nil {
+			log.Warn("WasmModuleRoots returned error", "spawner", spawner.Name(), "err", err)
+			continue
+		}
+		if slices.Contains(roots, moduleRoot) {
+			return spawner, nil
+		}
+		available = append(available, roots...)
+	}
+	return nil, fmt.Errorf("%w: module root %v, available roots %v", ErrChallengeModuleRootUnavailable, moduleRoot, available)
 }
 
 func (m *ChallengeManager) createExecutionBackend(ctx context.Context, step uint64) error {
@@ -483,18 +664,13 @@ func (m *ChallengeManager) createExecutionBackend(ctx context.Context, step uint
 		}
 	}
 	input.BatchInfo = prunedBatches
-	var execRun validator.ExecutionRun
-	for _, spawner := range m.validator.ExecutionSpawners() {
-		if validator.SpawnerSupportsModule(spawner, m.wasmModuleRoot) {
-			execRun, err = spawner.CreateExecutionRun(m.wasmModuleRoot, input, false).Await(ctx)
-			if err != nil {
-				return fmt.Errorf("error creating execution backend for msg %v: %w", initialCount, err)
-			}
-			break
-		}
+	spawner, err := executionSpawnerForModuleRoot(m.validator.ExecutionSpawners(), m.wasmModuleRoot)
+	if err != nil {
+		return fmt.Errorf("error creating execution backend for challenge %v: %w", m.challengeIndex, err)
 	}
-	if execRun == nil {
-		return fmt.Errorf("did not find valid execution backend")
+	execRun, err := spawner.CreateExecutionRun(m.wasmModuleRoot, input, false).Await(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating execution backend for msg %v: %w", initialCount, err)
 	}
 	backend, err := staker.NewExecutionChallengeBackend(execRun)
 	if err != nil {
@@ -523,6 +699,9 @@ func (m *ChallengeManager) createExecutionBackend(ctx context.Context, step uint
 }
 
 func (m *ChallengeManager) Act(ctx context.Context) (*types.Transaction, error) {
+	if m.withdrawn {
+		return nil, nil
+	}
 	err := m.LoadExecChallengeIfExists(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error loading execution challenge: %w", err)
@@ -552,17 +731,22 @@ func (m *ChallengeManager) Act(ctx context.Context) (*types.Transaction, error)
 	}
 
 	nextMovePos, err := m.ScanChallengeState(ctx, backend, state)
+	if errors.Is(err, ErrAgreedWithEntireChallenge) && m.agreedChallengeAction == AgreedChallengeActionWithdraw {
+		m.logger.Error("agreed with entire challenge, withdrawing from it", "challenge", m.challengeIndex, "start", state.Start, "end", state.End)
+		m.withdrawn = true
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error scanning challenge state: %w", err)
 	}
 	startPosition := state.Segments[nextMovePos].Position
 	endPosition := state.Segments[nextMovePos+1].Position
 	if startPosition+1 != endPosition {
-		log.Info("bisecting execution", "challenge", m.challengeIndex, "startPosition", startPosition, "endPosition", endPosition)
+		m.logger.Info("bisecting execution", "challenge", m.challengeIndex, "startPosition", startPosition, "endPosition", endPosition)
 		return m.bisect(ctx, backend, state, nextMovePos)
 	}
 	if m.executionChallengeBackend != nil {
-		log.Info("sending onestepproof", "challenge", m.challengeIndex, "startPosition", startPosition, "endPosition", endPosition)
+		m.logger.Info("sending onestepproof", "challenge", m.challengeIndex, "startPosition", startPosition, "endPosition", endPosition)
 		return m.IssueOneStepProof(
 			ctx,
 			state,
@@ -575,8 +759,9 @@ func (m *ChallengeManager) Act(ctx context.Context) (*types.Transaction, error)
 		return nil, fmt.Errorf("error creating execution backend: %w", err)
 	}
 	machineStepCount := m.machineFinalStepCount
-	log.Info("issuing one step proof", "challenge", m.challengeIndex, "machineStepCount", machineStepCount, "initialCount", m.initialMachineMessageCount)
+	m.logger.Info("issuing one step proof", "challenge", m.challengeIndex, "machineStepCount", machineStepCount, "initialCount", m.initialMachineMessageCount)
 	return m.blockChallengeBackend.IssueExecChallenge(
+		ctx,
 		m.challengeCore,
 		state,
 		nextMovePos,
@@ -0,0 +1,78 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// challengeManagerVersion identifies the challenge manager the rollup uses, by its address and,
+// as it's usually behind a proxy, the implementation the proxy delegates to (zero if it isn't a proxy).
+type challengeManagerVersion struct {
+	address        common.Address
+	implementation common.Address
+}
+
+type challengeManagerGetter interface {
+	ChallengeManager(opts *bind.CallOpts) (common.Address, error)
+}
+
+type storageReader interface {
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+func readChallengeManagerVersion(callOpts *bind.CallOpts, rollup challengeManagerGetter, client storageReader) (challengeManagerVersion, error) {
+	address, err := rollup.ChallengeManager(callOpts)
+	if err != nil {
+		return challengeManagerVersion{}, fmt.Errorf("error getting challenge manager address: %w", err)
+	}
+	implementation, err := client.StorageAt(callOpts.Context, address, arbutil.ProxyImplementationSlot, callOpts.BlockNumber)
+	if err != nil {
+		return challengeManagerVersion{}, fmt.Errorf("error getting challenge manager %v implementation: %w", address, err)
+	}
+	return challengeManagerVersion{
+		address:        address,
+		implementation: common.BytesToAddress(implementation),
+	}, nil
+}
+
+// checkChallengeManagerUpgrade drops the active challenge's bindings if the rollup's challenge manager was
+// replaced or upgraded since they were created, so they're recreated from the new challenge manager's state
+// rather than acting on stale state.
+func (s *Staker) checkChallengeManagerUpgrade(ctx context.Context) error {
+	if s.activeChallenge == nil {
+		return nil
+	}
+	current, err := readChallengeManagerVersion(&bind.CallOpts{Context: ctx}, s.rollup, s.client)
+	if err != nil {
+		return err
+	}
+	s.dropStaleChallenge(current)
+	return nil
+}
+
+// dropStaleChallenge drops the active challenge if its bindings weren't created from the current challenge manager.
+func (s *Staker) dropStaleChallenge(current challengeManagerVersion) {
+	if s.activeChallenge == nil {
+		return
+	}
+	if current != s.activeChallengeManager {
+		s.challengeLog.Warn(
+			"challenge manager changed mid-challenge, re-reading challenge state",
+			"challenge", s.activeChallenge.ChallengeIndex(),
+			"address", current.address,
+			"implementation", current.implementation,
+			"previousAddress", s.activeChallengeManager.address,
+			"previousImplementation", s.activeChallengeManager.implementation,
+		)
+		s.activeChallenge = nil
+	}
+}
@@ -5,10 +5,13 @@ package legacystaker
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"math/big"
 	"os"
 	"path"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -20,6 +23,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/offchainlabs/nitro/solgen/go/mocks_legacy_gen"
 	"github.com/offchainlabs/nitro/solgen/go/osp_legacy_gen"
@@ -117,7 +121,7 @@ func runChallengeTest(
 	asserterIsCorrect bool,
 	testTimeout bool,
 	maxInboxMessage uint64,
-) {
+) (*ChallengeManager, *ChallengeManager, []ChallengeParty) {
 	glogger := log.NewGlogHandler(
 		log.NewTerminalHandler(io.Writer(os.Stderr), false))
 	glogger.Verbosity(log.LevelDebug)
@@ -192,6 +196,8 @@ func runChallengeTest(
 	)
 	Require(t, err)
 
+	// the parties of the moves made, in order
+	var moves []ChallengeParty
 	for i := 0; i < 100; i++ {
 		if testTimeout {
 			backend.Commit()
@@ -201,17 +207,20 @@ func runChallengeTest(
 		backend.Commit()
 
 		var currentCorrect bool
+		var tx *types.Transaction
+		currentParty := ChallengePartyChallenger
 		if i%2 == 0 {
-			_, err = challengerManager.Act(ctx)
+			tx, err = challengerManager.Act(ctx)
 			currentCorrect = !asserterIsCorrect
 		} else {
-			_, err = asserterManager.Act(ctx)
+			tx, err = asserterManager.Act(ctx)
 			currentCorrect = asserterIsCorrect
+			currentParty = ChallengePartyAsserter
 		}
 		if err != nil {
 			if testTimeout && strings.Contains(err.Error(), "CHAL_DEADLINE") {
 				t.Log("challenge completed in timeout")
-				return
+				return asserterManager, challengerManager, moves
 			}
 			if !currentCorrect &&
 				(strings.Contains(err.Error(), "lost challenge") || strings.Contains(err.Error(), "SAME_OSP_END")) {
@@ -219,10 +228,13 @@ func runChallengeTest(
 					t.Fatal("expected challenge to end in timeout")
 				}
 				t.Log("challenge completed! asserter hit expected error:", err)
-				return
+				return asserterManager, challengerManager, moves
 			}
 			t.Fatal(err)
 		}
+		if tx != nil {
+			moves = append(moves, currentParty)
+		}
 
 		backend.Commit()
 
@@ -238,6 +250,7 @@ func runChallengeTest(
 	}
 
 	t.Fatal("challenge timed out without winner")
+	return nil, nil, nil
 }
 
 func createBaseMachine(t *testing.T, wasmname string, wasmModules []string) *server_arb.ArbitratorMachine {
@@ -292,7 +305,8 @@ func TestChallengeToTooFar(t *testing.T) {
 	Require(t, machine.SetGlobalState(validator.GoGlobalState{PosInBatch: 10}))
 	incorrectMachine := machine.Clone()
 	Require(t, incorrectMachine.AddSequencerInboxMessage(10, []byte{0, 1, 2, 3}))
-	runChallengeTest(t, machine, incorrectMachine, false, false, 9)
+	asserterManager, challengerManager, _ := runChallengeTest(t, machine, incorrectMachine, false, false, 9)
+	requireTranscriptWinner(t, asserterManager, challengerManager, false)
 }
 
 func TestChallengeToFailedTooFar(t *testing.T) {
@@ -300,5 +314,317 @@ func TestChallengeToFailedTooFar(t *testing.T) {
 	Require(t, machine.SetGlobalState(validator.GoGlobalState{PosInBatch: 10}))
 	incorrectMachine := machine.Clone()
 	Require(t, machine.AddSequencerInboxMessage(10, []byte{0, 1, 2, 3}))
-	runChallengeTest(t, machine, incorrectMachine, true, false, 11)
+	asserterManager, challengerManager, _ := runChallengeTest(t, machine, incorrectMachine, true, false, 11)
+	requireTranscriptWinner(t, asserterManager, challengerManager, true)
+}
+
+// exportConcludedTranscript exports the transcript of a challenge run to completion, ending it by timing it
+// out if it was decided without ending, and checks both participants export the same transcript.
+func exportConcludedTranscript(t *testing.T, asserterManager, challengerManager *ChallengeManager) *ChallengeTranscript {
+	t.Helper()
+	ctx := context.Background()
+	transcript, err := challengerManager.ExportTranscript(ctx)
+	if errors.Is(err, ErrChallengeNotConcluded) {
+		// the losing party hit a failing one step proof, so end the challenge by timing it out
+		backend, ok := challengerManager.client.(*backends.SimulatedBackend)
+		if !ok {
+			Fail(t, "expected challenge test to use a simulated backend")
+		}
+		Require(t, backend.AdjustTime(time.Second*200))
+		backend.Commit()
+		_, err = challengerManager.con.Timeout(challengerManager.auth, challengerManager.challengeIndex)
+		Require(t, err)
+		backend.Commit()
+		transcript, err = challengerManager.ExportTranscript(ctx)
+	}
+	Require(t, err)
+	asserterTranscript, err := asserterManager.ExportTranscript(ctx)
+	Require(t, err)
+	if !reflect.DeepEqual(asserterTranscript, transcript) {
+		Fail(t, "challenge participants exported different transcripts")
+	}
+	return transcript
+}
+
+// requireTranscriptWinner checks the transcript of a challenge run to completion resolves it for the correct party.
+func requireTranscriptWinner(t *testing.T, asserterManager, challengerManager *ChallengeManager, asserterIsCorrect bool) {
+	t.Helper()
+	transcript := exportConcludedTranscript(t, asserterManager, challengerManager)
+	expectedWinner := ChallengePartyChallenger
+	if asserterIsCorrect {
+		expectedWinner = ChallengePartyAsserter
+	}
+	if transcript.Winner != expectedWinner {
+		Fail(t, "expected the", expectedWinner, "to win the challenge by", transcript.Outcome, "but transcript has winner", transcript.Winner)
+	}
+}
+
+func TestChallengeTranscript(t *testing.T) {
+	machine := createBaseMachine(t, "global-state.wasm", []string{"global-state-wrapper.wasm"})
+	incorrectMachine := NewIncorrectMachine(machine, 200)
+	asserterManager, challengerManager, moves := runChallengeTest(t, machine, incorrectMachine, false, false, 0)
+
+	transcript := exportConcludedTranscript(t, asserterManager, challengerManager)
+	if len(transcript.InitialSegments) < 2 {
+		Fail(t, "expected the asserter's initial claim in the transcript, got segments", transcript.InitialSegments)
+	}
+	if len(transcript.Moves) != len(moves) {
+		Fail(t, "expected", len(moves), "moves in the transcript, got", len(transcript.Moves))
+	}
+	for i, move := range transcript.Moves {
+		if move.Party != moves[i] {
+			Fail(t, "move", i, "made by", moves[i], "but transcript has", move.Party)
+		}
+		if move.TxHash == (common.Hash{}) {
+			Fail(t, "move", i, "has no transaction hash")
+		}
+		if move.Move == ChallengeMoveOneStepProof {
+			if i != len(transcript.Moves)-1 {
+				Fail(t, "found move after one step proof", i)
+			}
+			continue
+		}
+		if move.Move != ChallengeMoveBisection || len(move.Segments) < 2 {
+			Fail(t, "expected move", i, "to be a bisection, got", move.Move, "with segments", move.Segments)
+		}
+	}
+	lastMove := transcript.Moves[len(transcript.Moves)-1]
+	expectedOutcome := "timeout"
+	if lastMove.Move == ChallengeMoveOneStepProof {
+		expectedOutcome = "execution proof"
+	}
+	if transcript.Outcome != expectedOutcome {
+		Fail(t, "expected challenge outcome", expectedOutcome, "got", transcript.Outcome)
+	}
+	if transcript.Winner != ChallengePartyChallenger {
+		Fail(t, "expected the challenger to win, transcript has winner", transcript.Winner)
+	}
+	serialized, err := json.Marshal(transcript)
+	Require(t, err)
+	var deserialized ChallengeTranscript
+	Require(t, json.Unmarshal(serialized, &deserialized))
+	if !reflect.DeepEqual(&deserialized, transcript) {
+		Fail(t, "transcript changed by serialization, got", deserialized, "expected", transcript)
+	}
+}
+
+func TestChallengeWithdrawOnFullAgreement(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deployer := createTransactOpts(t)
+	asserter := createTransactOpts(t)
+	challenger := createTransactOpts(t)
+	alloc := createGenesisAlloc(deployer, asserter, challenger)
+	backend := backends.NewSimulatedBackend(alloc, 1_000_000_000)
+	backend.Commit()
+
+	ospEntry := DeployOneStepProofEntry(t, deployer, backend)
+	backend.Commit()
+
+	// The challenger runs the same machine as the asserter, so it agrees with the entire assertion
+	machine := createBaseMachine(t, "global-state.wasm", []string{"global-state-wrapper.wasm"})
+	_, challengeManager := CreateChallenge(t, ctx, deployer, backend, ospEntry, machine, 0, asserter.From, challenger.From)
+	backend.Commit()
+
+	newChallengerManager := func() *ChallengeManager {
+		challengerMachine := machine.Clone()
+		challengerRun, err := server_arb.NewExecutionRun(ctx,
+			func(context.Context) (server_arb.MachineInterface, error) { return challengerMachine, nil },
+			&server_arb.DefaultMachineCacheConfig)
+		Require(t, err)
+		manager, err := NewExecutionChallengeManager(backend, challenger, challengeManager, 1, challengerRun, 0, 12)
+		Require(t, err)
+		return manager
+	}
+
+	erroringManager := newChallengerManager()
+	_, err := erroringManager.Act(ctx)
+	if !errors.Is(err, ErrAgreedWithEntireChallenge) {
+		Fail(t, "expected agreed with entire challenge error, got", err)
+	}
+	if erroringManager.Withdrawn() {
+		Fail(t, "challenge manager withdrew without being configured to")
+	}
+
+	withdrawingManager := newChallengerManager()
+	withdrawingManager.SetAgreedChallengeAction(AgreedChallengeActionWithdraw)
+	for i := 0; i < 2; i++ {
+		tx, err := withdrawingManager.Act(ctx)
+		Require(t, err)
+		if tx != nil {
+			Fail(t, "withdrawn challenge manager made a move")
+		}
+		if !withdrawingManager.Withdrawn() {
+			Fail(t, "challenge manager didn't withdraw after agreeing with entire challenge")
+		}
+		backend.Commit()
+	}
+}
+
+func TestChallengeMoveGasCeiling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deployer := createTransactOpts(t)
+	asserter := createTransactOpts(t)
+	challenger := createTransactOpts(t)
+	alloc := createGenesisAlloc(deployer, asserter, challenger)
+	backend := backends.NewSimulatedBackend(alloc, 1_000_000_000)
+	backend.Commit()
+
+	ospEntry := DeployOneStepProofEntry(t, deployer, backend)
+	backend.Commit()
+
+	machine := createBaseMachine(t, "global-state.wasm", []string{"global-state-wrapper.wasm"})
+	incorrectMachine := NewIncorrectMachine(machine, 200)
+	_, challengeManager := CreateChallenge(t, ctx, deployer, backend, ospEntry, incorrectMachine, 0, asserter.From, challenger.From)
+	backend.Commit()
+
+	challengerRun, err := server_arb.NewExecutionRun(ctx,
+		func(context.Context) (server_arb.MachineInterface, error) { return machine.Clone(), nil },
+		&server_arb.DefaultMachineCacheConfig)
+	Require(t, err)
+	challengerManager, err := NewExecutionChallengeManager(backend, challenger, challengeManager, 1, challengerRun, 0, 12)
+	Require(t, err)
+
+	// A bisection needs far more gas than a plain transfer
+	challengerManager.SetMaxMoveGas(params.TxGas)
+	tx, err := challengerManager.Act(ctx)
+	if !errors.Is(err, ErrChallengeMoveGasCeilingExceeded) {
+		Fail(t, "expected move gas ceiling to be exceeded, got", err)
+	}
+	if tx != nil {
+		Fail(t, "posted a move exceeding the gas ceiling")
+	}
+	backend.Commit()
+	myTurn, err := challengerManager.IsMyTurn(ctx)
+	Require(t, err)
+	if !myTurn {
+		Fail(t, "challenge advanced despite the move exceeding the gas ceiling")
+	}
+
+	// Once the operator lifts the ceiling, the move is posted
+	challengerManager.SetMaxMoveGas(0)
+	tx, err = challengerManager.Act(ctx)
+	Require(t, err)
+	if tx == nil {
+		Fail(t, "didn't post move after lifting the gas ceiling")
+	}
+	backend.Commit()
+	myTurn, err = challengerManager.IsMyTurn(ctx)
+	Require(t, err)
+	if myTurn {
+		Fail(t, "challenge didn't advance after posting the move")
+	}
+}
+
+func TestChallengeMoveInsufficientFunds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deployer := createTransactOpts(t)
+	asserter := createTransactOpts(t)
+	challenger := createTransactOpts(t)
+	// The challenger runs out of funds mid-challenge
+	alloc := createGenesisAlloc(deployer, asserter)
+	backend := backends.NewSimulatedBackend(alloc, 1_000_000_000)
+	backend.Commit()
+
+	ospEntry := DeployOneStepProofEntry(t, deployer, backend)
+	backend.Commit()
+
+	machine := createBaseMachine(t, "global-state.wasm", []string{"global-state-wrapper.wasm"})
+	incorrectMachine := NewIncorrectMachine(machine, 200)
+	_, challengeManager := CreateChallenge(t, ctx, deployer, backend, ospEntry, incorrectMachine, 0, asserter.From, challenger.From)
+	backend.Commit()
+
+	challengerRun, err := server_arb.NewExecutionRun(ctx,
+		func(context.Context) (server_arb.MachineInterface, error) { return machine.Clone(), nil },
+		&server_arb.DefaultMachineCacheConfig)
+	Require(t, err)
+	// Skip gas estimation so that the move reaches the parent chain node, which rejects it for insufficient funds
+	challenger.GasLimit = 5_000_000
+	challengerManager, err := NewExecutionChallengeManager(backend, challenger, challengeManager, 1, challengerRun, 0, 12)
+	Require(t, err)
+
+	tx, err := challengerManager.Act(ctx)
+	if !errors.Is(err, ErrChallengeMoveInsufficientFunds) {
+		Fail(t, "expected insufficient funds for the move without an emergency top-up, got", err)
+	}
+	if tx != nil {
+		Fail(t, "posted a move without funds")
+	}
+
+	var toppedUp []common.Address
+	challengerManager.SetEmergencyTopUp(func(ctx context.Context, account common.Address) error {
+		toppedUp = append(toppedUp, account)
+		nonce, err := backend.PendingNonceAt(ctx, deployer.From)
+		if err != nil {
+			return err
+		}
+		gasPrice, err := backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return err
+		}
+		topUpTx, err := deployer.Signer(deployer.From, types.NewTransaction(nonce, account, big.NewInt(params.Ether), params.TxGas, gasPrice, nil))
+		if err != nil {
+			return err
+		}
+		if err := backend.SendTransaction(ctx, topUpTx); err != nil {
+			return err
+		}
+		backend.Commit()
+		return nil
+	})
+	tx, err = challengerManager.Act(ctx)
+	Require(t, err)
+	if tx == nil {
+		Fail(t, "didn't post move after the emergency top-up")
+	}
+	if len(toppedUp) != 1 || toppedUp[0] != challenger.From {
+		Fail(t, "expected a single emergency top-up of the challenger, got", toppedUp)
+	}
+	backend.Commit()
+	myTurn, err := challengerManager.IsMyTurn(ctx)
+	Require(t, err)
+	if myTurn {
+		Fail(t, "challenge didn't advance after posting the move")
+	}
+}
+
+type fixedRootsSpawner struct {
+	validator.ExecutionSpawner
+	name  string
+	roots []common.Hash
+	err   error
+}
+
+func (s *fixedRootsSpawner) Name() string { return s.name }
+
+func (s *fixedRootsSpawner) WasmModuleRoots() ([]common.Hash, error) { return s.roots, s.err }
+
+func TestExecutionSpawnerForModuleRoot(t *testing.T) {
+	oldRoot := common.HexToHash("0x01")
+	latestRoot := common.HexToHash("0x02")
+	missingRoot := common.HexToHash("0x03")
+	failing := &fixedRootsSpawner{name: "failing", err: errors.New("unreachable")}
+	latest := &fixedRootsSpawner{name: "latest", roots: []common.Hash{latestRoot}}
+	both := &fixedRootsSpawner{name: "both", roots: []common.Hash{latestRoot, oldRoot}}
+	spawners := []validator.ExecutionSpawner{failing, latest, both}
+
+	spawner, err := executionSpawnerForModuleRoot(spawners, oldRoot)
+	Require(t, err)
+	if spawner != both {
+		Fail(t, "expected the spawner with the historical root, got", spawner.Name())
+	}
+	spawner, err = executionSpawnerForModuleRoot(spawners, latestRoot)
+	Require(t, err)
+	if spawner != latest {
+		Fail(t, "expected the first spawner with the latest root, got", spawner.Name())
+	}
+	_, err = executionSpawnerForModuleRoot(spawners, missingRoot)
+	if !errors.Is(err, ErrChallengeModuleRootUnavailable) {
+		Fail(t, "expected ErrChallengeModuleRootUnavailable, got", err)
+	}
+	if !strings.Contains(err.Error(), missingRoot.String()) || !strings.Contains(err.Error(), oldRoot.String()) {
+		Fail(t, "error should name the requested and available roots:", err)
+	}
 }
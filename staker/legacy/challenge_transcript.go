@@ -0,0 +1,193 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrChallengeNotConcluded is returned when exporting the transcript of a challenge which hasn't ended yet.
+var ErrChallengeNotConcluded = errors.New("challenge not concluded")
+
+// ChallengeParty is a participant of a challenge.
+type ChallengeParty string
+
+const (
+	ChallengePartyAsserter   ChallengeParty = "asserter"
+	ChallengePartyChallenger ChallengeParty = "challenger"
+)
+
+func (p ChallengeParty) opponent() ChallengeParty {
+	if p == ChallengePartyAsserter {
+		return ChallengePartyChallenger
+	}
+	return ChallengePartyAsserter
+}
+
+// Challenge moves, named as in the challenge manager's move gas checks.
+const (
+	ChallengeMoveBisection          = "bisection"
+	ChallengeMoveExecutionChallenge = "execution challenge"
+	ChallengeMoveOneStepProof       = "one step proof"
+)
+
+// Challenge outcomes, indexed by the termination type of the challenge manager's ChallengeEnded event.
+var challengeOutcomes = []string{"timeout", "block proof", "execution proof", "cleared"}
+
+const (
+	challengeEndedTimeout        uint8 = 0
+	challengeEndedBlockProof     uint8 = 1
+	challengeEndedExecutionProof uint8 = 2
+)
+
+// ChallengeTranscriptMove is a single move made by a challenge participant.
+type ChallengeTranscriptMove struct {
+	Party   ChallengeParty `json:"party"`
+	Move    string         `json:"move"`
+	L1Block uint64         `json:"l1Block"`
+	TxHash  common.Hash    `json:"txHash"`
+	// the segments the challenged segment was bisected into, for bisections and execution challenges
+	Segments []ChallengeSegment `json:"segments,omitempty"`
+	// the number of block steps, i.e. messages, of the execution challenge
+	BlockSteps uint64 `json:"blockSteps,omitempty"`
+}
+
+// ChallengeTranscript is the complete sequence of moves of a concluded challenge, with its resolution.
+type ChallengeTranscript struct {
+	ChallengeIndex uint64 `json:"challengeIndex"`
+	// the segments of the asserter's claim the challenge started from
+	InitialSegments []ChallengeSegment        `json:"initialSegments"`
+	Moves           []ChallengeTranscriptMove `json:"moves"`
+	Outcome         string                    `json:"outcome"`
+	// the party the challenge was resolved for, empty if it was cleared without a winner
+	Winner       ChallengeParty `json:"winner,omitempty"`
+	EndedL1Block uint64         `json:"endedL1Block"`
+	EndedTxHash  common.Hash    `json:"endedTxHash"`
+}
+
+// ExportTranscript reconstructs the transcript of the concluded challenge from the challenge manager's events,
+// e.g. for post-mortems or as dispute evidence. It returns ErrChallengeNotConcluded if the challenge hasn't
+// ended nor been decided by a one step proof.
+// Participants are identified by their role, as the challenge manager deletes the challenge once it ends.
+func (m *ChallengeManager) ExportTranscript(ctx context.Context) (*ChallengeTranscript, error) {
+	logs, err := m.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: m.startL1Block,
+		Addresses: []common.Address{m.challengeManagerAddr},
+		Topics: [][]common.Hash{
+			{challengeBisectedID, executionChallengeBegunID, oneStepProofCompletedID, challengeEndedID},
+			{uint64ToIndex(m.challengeIndex)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error searching challenge %v logs from block %v: %w", m.challengeIndex, m.startL1Block, err)
+	}
+	transcript := &ChallengeTranscript{ChallengeIndex: m.challengeIndex}
+	var initialBisectionFound, decided, ended bool
+	// the challenger makes the first move after the asserter's claim
+	nextParty := ChallengePartyChallenger
+	lastMove := func(evmLog types.Log) *ChallengeTranscriptMove {
+		if len(transcript.Moves) == 0 {
+			return nil
+		}
+		move := &transcript.Moves[len(transcript.Moves)-1]
+		if move.TxHash != evmLog.TxHash {
+			return nil
+		}
+		return move
+	}
+	addMove := func(evmLog types.Log, moveName string) *ChallengeTranscriptMove {
+		transcript.Moves = append(transcript.Moves, ChallengeTranscriptMove{
+			Party:   nextParty,
+			Move:    moveName,
+			L1Block: evmLog.BlockNumber,
+			TxHash:  evmLog.TxHash,
+		})
+		nextParty = nextParty.opponent()
+		return &transcript.Moves[len(transcript.Moves)-1]
+	}
+	for _, evmLog := range logs {
+		if ended {
+			return nil, fmt.Errorf("found challenge %v event after it ended", m.challengeIndex)
+		}
+		switch evmLog.Topics[0] {
+		case challengeBisectedID:
+			parsedLog, err := m.con.ParseBisected(evmLog)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing Bisected event of challenge %v: %w", m.challengeIndex, err)
+			}
+			state, err := challengeStateFromBisection(parsedLog)
+			if err != nil {
+				return nil, fmt.Errorf("error resolving Bisected event of challenge %v: %w", m.challengeIndex, err)
+			}
+			if !initialBisectionFound {
+				initialBisectionFound = true
+				transcript.InitialSegments = state.Segments
+				continue
+			}
+			addMove(evmLog, ChallengeMoveBisection).Segments = state.Segments
+		case executionChallengeBegunID:
+			parsedLog, err := m.con.ParseExecutionChallengeBegun(evmLog)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing ExecutionChallengeBegun event of challenge %v: %w", m.challengeIndex, err)
+			}
+			if !parsedLog.BlockSteps.IsUint64() {
+				return nil, fmt.Errorf("ExecutionChallengeBegun event has non-uint64 blockSteps of %v", parsedLog.BlockSteps)
+			}
+			// the execution challenge's initial bisection is emitted by the same move
+			move := lastMove(evmLog)
+			if move == nil {
+				move = addMove(evmLog, ChallengeMoveExecutionChallenge)
+			}
+			move.Move = ChallengeMoveExecutionChallenge
+			move.BlockSteps = parsedLog.BlockSteps.Uint64()
+		case oneStepProofCompletedID:
+			// a proof decides the challenge for the party proving it, even if the challenge
+			// only ends once its opponent, left without a move, times out
+			move := addMove(evmLog, ChallengeMoveOneStepProof)
+			decided = true
+			transcript.Outcome = challengeOutcomes[challengeEndedExecutionProof]
+			transcript.Winner = move.Party
+			transcript.EndedL1Block = evmLog.BlockNumber
+			transcript.EndedTxHash = evmLog.TxHash
+		case challengeEndedID:
+			parsedLog, err := m.con.ParseChallengeEnded(evmLog)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing ChallengeEnded event of challenge %v: %w", m.challengeIndex, err)
+			}
+			if int(parsedLog.Kind) >= len(challengeOutcomes) {
+				return nil, fmt.Errorf("unknown challenge %v termination type %v", m.challengeIndex, parsedLog.Kind)
+			}
+			ended = true
+			if decided {
+				continue
+			}
+			decided = true
+			transcript.Outcome = challengeOutcomes[parsedLog.Kind]
+			transcript.EndedL1Block = evmLog.BlockNumber
+			transcript.EndedTxHash = evmLog.TxHash
+			switch parsedLog.Kind {
+			case challengeEndedTimeout:
+				// the party whose turn it was ran out of time
+				transcript.Winner = nextParty.opponent()
+			case challengeEndedBlockProof, challengeEndedExecutionProof:
+				// a proof ends the challenge for the party proving it, with its move if it was found
+				if move := lastMove(evmLog); move != nil {
+					transcript.Winner = move.Party
+				} else {
+					transcript.Winner = nextParty
+				}
+			}
+		}
+	}
+	if !decided {
+		return nil, fmt.Errorf("%w: challenge %v from block %v", ErrChallengeNotConcluded, m.challengeIndex, m.startL1Block)
+	}
+	return transcript, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
+)
+
+type confirmedChainReader interface {
+	GetNode(opts *bind.CallOpts, nodeNum uint64) (rollup_legacy_gen.Node, error)
+}
+
+// ConfirmedChainAnomaly is a break in the predecessor linkage of the confirmed nodes.
+type ConfirmedChainAnomaly struct {
+	Node    uint64
+	PrevNum uint64
+	Reason  string
+}
+
+// findConfirmedChainAnomaly walks the confirmed nodes back from latestConfirmed through their predecessors
+// until fromNode, which must be reached for the confirmed nodes to form an unbroken chain. Each node's
+// predecessor must be older than it, both in number and in creation block. It returns the first anomaly found,
+// or nil if there's none.
+func findConfirmedChainAnomaly(opts *bind.CallOpts, rollup confirmedChainReader, fromNode, latestConfirmed uint64) (*ConfirmedChainAnomaly, error) {
+	if fromNode > latestConfirmed {
+		return nil, fmt.Errorf("node %v to verify the confirmed chain from is after the latest confirmed node %v", fromNode, latestConfirmed)
+	}
+	nodeNum := latestConfirmed
+	node, err := rollup.GetNode(opts, nodeNum)
+	if err != nil {
+		return nil, fmt.Errorf("error getting node %v: %w", nodeNum, err)
+	}
+	for nodeNum > fromNode {
+		if node.PrevNum >= nodeNum {
+			return &ConfirmedChainAnomaly{
+				Node:    nodeNum,
+				PrevNum: node.PrevNum,
+				Reason:  "predecessor isn't older than the node",
+			}, nil
+		}
+		prev, err := rollup.GetNode(opts, node.PrevNum)
+		if err != nil {
+			return nil, fmt.Errorf("error getting node %v: %w", node.PrevNum, err)
+		}
+		if prev.CreatedAtBlock > node.CreatedAtBlock {
+			return &ConfirmedChainAnomaly{
+				Node:    nodeNum,
+				PrevNum: node.PrevNum,
+				Reason:  fmt.Sprintf("predecessor created at block %v, after the node's block %v", prev.CreatedAtBlock, node.CreatedAtBlock),
+			}, nil
+		}
+		if node.PrevNum < fromNode {
+			return &ConfirmedChainAnomaly{
+				Node:    nodeNum,
+				PrevNum: node.PrevNum,
+				Reason:  fmt.Sprintf("predecessor skips over confirmed node %v, which isn't an ancestor of the latest confirmed node", fromNode),
+			}, nil
+		}
+		nodeNum = node.PrevNum
+		node = prev
+	}
+	return nil, nil
+}
+
+// VerifyConfirmedChain checks that the nodes confirmed since fromNode, e.g. a confirmed node observed
+// earlier, form an unbroken chain of predecessors up to the latest confirmed node, returning the anomaly
+// found if they don't, a gap or a fork in the confirmed line, or nil if they do.
+func (v *L1Validator) VerifyConfirmedChain(ctx context.Context, fromNode uint64) (*ConfirmedChainAnomaly, error) {
+	callOpts := v.getCallOpts(ctx)
+	latestConfirmed, err := v.rollup.LatestConfirmed(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest confirmed node: %w", err)
+	}
+	anomaly, err := findConfirmedChainAnomaly(callOpts, v.rollup, fromNode, latestConfirmed)
+	if err != nil {
+		return nil, err
+	}
+	if anomaly != nil {
+		v.confirmLog.Error("confirmed nodes don't form an unbroken chain", "node", anomaly.Node, "prevNum", anomaly.PrevNum, "fromNode", fromNode, "latestConfirmed", latestConfirmed, "reason", anomaly.Reason)
+	}
+	return anomaly, nil
+}
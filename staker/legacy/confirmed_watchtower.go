@@ -0,0 +1,55 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+)
+
+type confirmedNodeVerifyFunc func(ctx context.Context, nodeNum uint64) (*SendRootVerification, error)
+
+// watchConfirmedNodes is the act of a confirmed-only watchtower: rather than validating unconfirmed
+// nodes, it checks each newly confirmed node's global state against local execution.
+func (s *Staker) watchConfirmedNodes(ctx context.Context) error {
+	latestConfirmed, err := s.rollup.LatestConfirmed(s.getCallOpts(ctx))
+	if err != nil {
+		return fmt.Errorf("error getting latest confirmed node: %w", err)
+	}
+	return s.verifyNewlyConfirmedNodes(ctx, latestConfirmed, s.VerifyConfirmedNodeSendRoot)
+}
+
+// verifyNewlyConfirmedNodes verifies every node confirmed since the last verified one, up to and including
+// latestConfirmed, starting with latestConfirmed itself on the first call. A node that doesn't match local
+// execution is reported as a divergence, while a node that couldn't be verified is retried on the next call.
+func (s *Staker) verifyNewlyConfirmedNodes(ctx context.Context, latestConfirmed uint64, verify confirmedNodeVerifyFunc) error {
+	next := latestConfirmed
+	if s.lastVerifiedConfirmed != nil {
+		next = *s.lastVerifiedConfirmed + 1
+	}
+	for ; next <= latestConfirmed; next++ {
+		if next == 0 {
+			// the genesis node has nothing to validate
+			continue
+		}
+		verification, err := verify(ctx, next)
+		if err != nil {
+			return fmt.Errorf("error verifying confirmed node %v: %w", next, err)
+		}
+		if !verification.Match {
+			s.confirmedDivergence.Store(verification)
+			s.metrics.IncCounter(stakerConfirmedDivergenceMetric, 1)
+		}
+		verified := next
+		s.lastVerifiedConfirmed = &verified
+		s.actStatus.LatestVerifiedConfirmedNode = verified
+	}
+	return nil
+}
+
+// ConfirmedDivergence returns the latest confirmed node found not to match local execution, by a
+// confirmed-only watchtower or a staker verifying confirmed send roots, or nil if there's been none.
+func (s *Staker) ConfirmedDivergence() *SendRootVerification {
+	return s.confirmedDivergence.Load()
+}
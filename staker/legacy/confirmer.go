@@ -0,0 +1,76 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StakerConflict is a conflict between two stakers staked on competing unresolved nodes.
+type StakerConflict struct {
+	Staker1 common.Address
+	Staker2 common.Address
+	Type    ConflictType
+	Node1   uint64
+	Node2   uint64
+}
+
+type stakerConflictFunc func(staker1, staker2 common.Address) (ConflictType, uint64, uint64, error)
+
+// conflictOverNode returns a conflict among conflicts which confirming node would settle, being between stakers
+// on node and a competing sibling, or one which can't be ruled out within the search depth. Conflicts further
+// down the chain are left to be settled later, rather than holding off confirmations until they are.
+func conflictOverNode(conflicts []StakerConflict, node uint64) *StakerConflict {
+	for i, conflict := range conflicts {
+		if conflict.Type == CONFLICT_TYPE_INCOMPLETE || conflict.Node1 == node || conflict.Node2 == node {
+			return &conflicts[i]
+		}
+	}
+	return nil
+}
+
+// confirmValidatedNode is the act of a confirmer: a watchtower which confirms the next unresolved node
+// whoever created it, once it's confirmable, matches local validation, and no stakers are in conflict over it.
+// It returns whether a confirmation was made.
+func (s *Staker) confirmValidatedNode(ctx context.Context, latestConfirmedNode *uint64, cfg *L1ValidatorConfig) (bool, error) {
+	callOpts := s.getCallOpts(ctx)
+	confirmType, err := s.validatorUtils.CheckDecidableNextNode(callOpts, s.rollupAddress)
+	if err != nil {
+		return false, err
+	}
+	if ConfirmType(confirmType) != CONFIRM_TYPE_VALID {
+		return false, nil
+	}
+	nodeNum, err := s.rollup.FirstUnresolvedNode(callOpts)
+	if err != nil {
+		return false, err
+	}
+	node, err := s.rollup.GetNode(callOpts, nodeNum)
+	if err != nil {
+		return false, err
+	}
+	if _, validated := s.inactiveValidatedNodes.Get(validatedNode{number: nodeNum, hash: node.NodeHash}); !validated {
+		s.confirmLog.Info("not confirming node which doesn't match local validation yet", "node", nodeNum, "nodeHash", node.NodeHash)
+		return false, nil
+	}
+	conflicts, err := s.FindAllStakerConflicts(ctx)
+	if err != nil {
+		return false, err
+	}
+	if conflict := conflictOverNode(conflicts, nodeNum); conflict != nil {
+		s.confirmLog.Warn(
+			"not confirming node while stakers are in conflict over it",
+			"node", nodeNum,
+			"staker1", conflict.Staker1,
+			"staker2", conflict.Staker2,
+			"conflictType", conflict.Type,
+			"node1", conflict.Node1,
+			"node2", conflict.Node2,
+		)
+		return false, nil
+	}
+	return s.confirmNode(ctx, nodeNum, latestConfirmedNode, cfg.ConfirmationSafetyDelayBlocks, cfg.ConfirmationStaggerBlocks, cfg.ConfirmationMaturityNodes, cfg.ConfirmationMaturityBlocks)
+}
@@ -0,0 +1,170 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// stakerConflictSearchDepth is how many nodes back ValidatorUtils' FindStakerConflict searches for a
+// common ancestor of two stakers' nodes, beyond which it reports the conflict as incomplete.
+const stakerConflictSearchDepth = 1024
+
+// stakerConflictSearchPages is how many times deeper than stakerConflictSearchDepth the scan of all
+// stakers searches for a conflict before reporting it as incomplete.
+const stakerConflictSearchPages = 16
+
+// ConflictInfo is a conflict between two stakers staked on competing unresolved nodes, as found by
+// ValidatorUtils' FindStakerConflict. Node1 is the older of the two nodes, Staker1 the one staked on it.
+type ConflictInfo struct {
+	Staker1 common.Address
+	Staker2 common.Address
+	Node1   uint64
+	Node2   uint64
+}
+
+// ConflictHandler is called with each conflict between stakers the first time the staker observes it
+// during an act, e.g. to page on-call. It's called from the act cycle, so it shouldn't block.
+type ConflictHandler func(ConflictInfo)
+
+func newConflictInfo(staker1, staker2 common.Address, node1, node2 uint64) ConflictInfo {
+	if node2 < node1 {
+		staker1, staker2 = staker2, staker1
+		node1, node2 = node2, node1
+	}
+	return ConflictInfo{Staker1: staker1, Staker2: staker2, Node1: node1, Node2: node2}
+}
+
+// reportConflict calls the conflict handler with conflict unless it was already reported, forgetting
+// the conflicts settled by confirming up to latestConfirmed.
+func (s *Staker) reportConflict(conflict ConflictInfo, latestConfirmed uint64) {
+	if s.conflictHandler == nil {
+		return
+	}
+	s.reportedConflictsMutex.Lock()
+	defer s.reportedConflictsMutex.Unlock()
+	for reported := range s.reportedConflicts {
+		if reported.Node1 <= latestConfirmed {
+			delete(s.reportedConflicts, reported)
+		}
+	}
+	if conflict.Node1 <= latestConfirmed || s.reportedConflicts[conflict] {
+		return
+	}
+	if s.reportedConflicts == nil {
+		s.reportedConflicts = make(map[ConflictInfo]bool)
+	}
+	s.reportedConflicts[conflict] = true
+	s.conflictHandler(conflict)
+}
+
+// FindAllStakerConflicts returns the current conflicts between all the rollup's stakers, e.g. for a dashboard:
+// pairs staked on competing nodes past the latest confirmed node, and pairs whose nodes can't be told apart
+// within the deepest search, reported as CONFLICT_TYPE_INCOMPLETE. Found conflicts are passed to the conflict handler.
+func (s *Staker) FindAllStakerConflicts(ctx context.Context) ([]StakerConflict, error) {
+	callOpts := s.getCallOpts(ctx)
+	stakers, err := s.getStakers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latestConfirmed, err := s.rollup.LatestConfirmed(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest confirmed node: %w", err)
+	}
+	latestStaked := func(staker common.Address) (uint64, error) {
+		info, err := s.rollup.StakerInfo(ctx, staker)
+		if err != nil {
+			return 0, fmt.Errorf("error getting staker %v info: %w", staker, err)
+		}
+		if info == nil {
+			return 0, fmt.Errorf("staker %v (returned from ValidatorUtils's GetStakers function) not found in rollup", staker)
+		}
+		return info.LatestStakedNode, nil
+	}
+	conflicts, err := findAllStakerConflicts(stakers, latestStaked, latestConfirmed, func(staker1, staker2 common.Address) (ConflictType, uint64, uint64, error) {
+		return findStakerConflictPaged(func(depth uint64) (ConflictType, uint64, uint64, error) {
+			conflictInfo, err := s.validatorUtils.FindStakerConflict(callOpts, s.rollupAddress, staker1, staker2, new(big.Int).SetUint64(depth))
+			return ConflictType(conflictInfo.Ty), conflictInfo.Node1, conflictInfo.Node2, err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.reportFoundConflicts(conflicts, latestConfirmed)
+	return conflicts, nil
+}
+
+// reportFoundConflicts reports the conflicts found by a scan of all stakers, skipping incomplete ones
+// as they don't identify the competing nodes.
+func (s *Staker) reportFoundConflicts(conflicts []StakerConflict, latestConfirmed uint64) {
+	for _, conflict := range conflicts {
+		if conflict.Type == CONFLICT_TYPE_FOUND {
+			s.reportConflict(newConflictInfo(conflict.Staker1, conflict.Staker2, conflict.Node1, conflict.Node2), latestConfirmed)
+		}
+	}
+}
+
+// findStakerConflictPaged searches for a conflict a page of stakerConflictSearchDepth nodes deeper each
+// time the search is incomplete, up to stakerConflictSearchPages pages. ValidatorUtils doesn't return where
+// an incomplete search stopped, so each page searches again from the stakers' nodes.
+func findStakerConflictPaged(find func(depth uint64) (ConflictType, uint64, uint64, error)) (ConflictType, uint64, uint64, error) {
+	for page := uint64(1); ; page++ {
+		ty, node1, node2, err := find(page * stakerConflictSearchDepth)
+		if err != nil || ty != CONFLICT_TYPE_INCOMPLETE || page >= stakerConflictSearchPages {
+			return ty, node1, node2, err
+		}
+	}
+}
+
+// findAllStakerConflicts groups stakers by the node they're staked on, as stakers on the same node can't
+// conflict, and checks a single pair of stakers for each pair of nodes, reporting a conflict between
+// every pair of their stakers.
+func findAllStakerConflicts(
+	stakers []common.Address,
+	latestStaked func(common.Address) (uint64, error),
+	latestConfirmed uint64,
+	findConflict stakerConflictFunc,
+) ([]StakerConflict, error) {
+	byNode := make(map[uint64][]common.Address)
+	for _, staker := range stakers {
+		node, err := latestStaked(staker)
+		if err != nil {
+			return nil, err
+		}
+		byNode[node] = append(byNode[node], staker)
+	}
+	nodes := make([]uint64, 0, len(byNode))
+	for node := range byNode {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	var conflicts []StakerConflict
+	for i, nodeA := range nodes {
+		for _, nodeB := range nodes[i+1:] {
+			stakersA, stakersB := byNode[nodeA], byNode[nodeB]
+			ty, node1, node2, err := findConflict(stakersA[0], stakersB[0])
+			if err != nil {
+				return nil, fmt.Errorf("error finding conflict between stakers %v and %v: %w", stakersA[0], stakersB[0], err)
+			}
+			if ty == CONFLICT_TYPE_FOUND && min(node1, node2) <= latestConfirmed {
+				// Immaterial as this is past the confirmation point
+				continue
+			}
+			if ty != CONFLICT_TYPE_FOUND && ty != CONFLICT_TYPE_INCOMPLETE {
+				continue
+			}
+			for _, staker1 := range stakersA {
+				for _, staker2 := range stakersB {
+					conflicts = append(conflicts, StakerConflict{Staker1: staker1, Staker2: staker2, Type: ty, Node1: node1, Node2: node2})
+				}
+			}
+		}
+	}
+	return conflicts, nil
+}
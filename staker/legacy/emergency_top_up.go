@@ -0,0 +1,68 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+// newKeyedTopUp returns an emergency top-up that sends challenge-move-top-up-amount-gwei to the account from
+// the challenge-move-top-up-private-key's account, returning once the transfer is included.
+func newKeyedTopUp(client *ethclient.Client, config L1ValidatorConfigFetcher) EmergencyTopUpFunc {
+	return func(ctx context.Context, account common.Address) error {
+		cfg := config()
+		key := cfg.challengeMoveTopUpKey
+		if key == nil {
+			return fmt.Errorf("no challenge move top-up private key configured")
+		}
+		from := crypto.PubkeyToAddress(key.PublicKey)
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		nonce, err := client.PendingNonceAt(ctx, from)
+		if err != nil {
+			return err
+		}
+		gasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return err
+		}
+		amount := arbmath.BigMulByUint(big.NewInt(params.GWei), cfg.ChallengeMoveTopUpAmountGwei)
+		tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: gasPrice,
+			Gas:      params.TxGas,
+			To:       &account,
+			Value:    amount,
+		})
+		if err != nil {
+			return err
+		}
+		if err := client.SendTransaction(ctx, tx); err != nil {
+			return fmt.Errorf("error sending top-up from %v: %w", from, err)
+		}
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		if err != nil {
+			return err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return fmt.Errorf("top-up transaction %v failed", tx.Hash())
+		}
+		log.Warn("sent emergency top-up of challenge move sender", "from", from, "to", account, "amount", amount, "tx", tx.Hash())
+		return nil
+	}
+}
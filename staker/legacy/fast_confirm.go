@@ -30,6 +30,7 @@ type FastConfirmSafe struct {
 	wallet                    ValidatorWalletInterface
 	l1Reader                  *headerreader.HeaderReader
 	rollupAddress             common.Address
+	logger                    log.Logger
 }
 
 func NewFastConfirmSafe(
@@ -45,6 +46,7 @@ func NewFastConfirmSafe(
 		wallet:        wallet,
 		l1Reader:      l1Reader,
 		rollupAddress: rollupAddress,
+		logger:        log.Root(),
 	}
 	safe, err := contractsgen.NewSafe(fastConfirmSafeAddress, wallet.L1Client())
 	if err != nil {
@@ -121,12 +123,12 @@ func (f *FastConfirmSafe) tryFastConfirmation(ctx context.Context, blockHash com
 		return err
 	}
 	if alreadyApproved.Cmp(common.Big1) == 0 {
-		log.Info("Already approved Safe tx hash for fast confirmation, checking if we can execute the Safe tx", "safeHash", safeTxHash, "nodeHash", nodeHash)
+		f.logger.Info("Already approved Safe tx hash for fast confirmation, checking if we can execute the Safe tx", "safeHash", safeTxHash, "nodeHash", nodeHash)
 		_, err = f.checkApprovedHashAndExecTransaction(ctx, fastConfirmCallData, safeTxHash)
 		return err
 	}
 
-	log.Info("Approving Safe tx hash to fast confirm", "safeHash", common.BytesToHash(safeTxHash[:]), "nodeHash", nodeHash, "self", f.wallet.Address())
+	f.logger.Info("Approving Safe tx hash to fast confirm", "safeHash", common.BytesToHash(safeTxHash[:]), "nodeHash", nodeHash, "self", f.wallet.Address())
 	_, err = f.safe.ApproveHash(f.builder.Auth(ctx), safeTxHash)
 	if err != nil {
 		return err
@@ -163,7 +165,7 @@ func (f *FastConfirmSafe) flushTransactions(ctx context.Context) error {
 	if arbTx != nil {
 		_, err = f.l1Reader.WaitForTxApproval(ctx, arbTx)
 		if err == nil {
-			log.Info("successfully executed staker transaction", "hash", arbTx.Hash())
+			f.logger.Info("successfully executed staker transaction", "hash", arbTx.Hash())
 		} else {
 			return fmt.Errorf("error waiting for tx receipt: %w", err)
 		}
@@ -224,7 +226,7 @@ func (f *FastConfirmSafe) checkApprovedHashAndExecTransaction(ctx context.Contex
 		}
 	}
 	if approvedHashCount >= f.threshold {
-		log.Info("Executing Safe tx to fast confirm", "safeHash", common.BytesToHash(safeTxHash[:]))
+		f.logger.Info("Executing Safe tx to fast confirm", "safeHash", common.BytesToHash(safeTxHash[:]))
 		_, err := f.safe.ExecTransaction(
 			f.builder.Auth(ctx),
 			f.rollupAddress,
@@ -243,6 +245,6 @@ func (f *FastConfirmSafe) checkApprovedHashAndExecTransaction(ctx context.Contex
 		}
 		return true, nil
 	}
-	log.Info("Not enough Safe tx approvals yet to fast confirm", "safeHash", common.BytesToHash(safeTxHash[:]).Hex(), "approved", approvedHashCount, "threshold", f.threshold, "self", f.wallet.Address())
+	f.logger.Info("Not enough Safe tx approvals yet to fast confirm", "safeHash", common.BytesToHash(safeTxHash[:]).Hex(), "approved", approvedHashCount, "threshold", f.threshold, "self", f.wallet.Address())
 	return false, nil
 }
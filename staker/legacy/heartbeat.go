@@ -0,0 +1,85 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/arbnode/dataposter"
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+type gasPriceSuggester interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// WalletHeartbeat posts a zero value self-transfer from the data poster's sender
+// once the sender's nonce hasn't moved for the heartbeat interval.
+// Activity is tracked through the data poster's next nonce, so transactions it
+// queued but that aren't yet on-chain count as activity too.
+type WalletHeartbeat struct {
+	dataPoster *dataposter.DataPoster
+	client     gasPriceSuggester
+
+	mutex        sync.Mutex
+	lastNonce    uint64
+	lastActivity time.Time
+}
+
+func NewWalletHeartbeat(dataPoster *dataposter.DataPoster, client gasPriceSuggester) *WalletHeartbeat {
+	return &WalletHeartbeat{
+		dataPoster:   dataPoster,
+		client:       client,
+		lastActivity: time.Now(),
+	}
+}
+
+// MaybeSend posts a heartbeat transaction if the wallet has been idle for at least interval
+// and the heartbeat would cost at most maxCost, which may be nil for no limit.
+// It returns a nil transaction if no heartbeat was due or it was too expensive.
+func (h *WalletHeartbeat) MaybeSend(ctx context.Context, interval time.Duration, maxCost *big.Int) (*types.Transaction, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	nonce, _, err := h.dataPoster.GetNextNonceAndMeta(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting data poster next nonce: %w", err)
+	}
+	now := time.Now()
+	if nonce != h.lastNonce {
+		h.lastNonce = nonce
+		h.lastActivity = now
+	}
+	if now.Sub(h.lastActivity) < interval {
+		return nil, nil
+	}
+	if maxCost != nil {
+		gasPrice, err := h.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting gas price: %w", err)
+		}
+		cost := arbmath.BigMulByUint(gasPrice, params.TxGas)
+		if cost.Cmp(maxCost) > 0 {
+			log.Warn("skipping validator wallet heartbeat as it's too expensive", "cost", cost, "maxCost", maxCost)
+			return nil, nil
+		}
+	}
+	sender := h.dataPoster.Sender()
+	tx, err := h.dataPoster.PostSimpleTransaction(ctx, sender, nil, params.TxGas, common.Big0)
+	if err != nil {
+		return nil, fmt.Errorf("error posting heartbeat transaction: %w", err)
+	}
+	log.Info("posted validator wallet heartbeat", "sender", sender, "nonce", tx.Nonce(), "idleFor", now.Sub(h.lastActivity))
+	h.lastNonce = tx.Nonce() + 1
+	h.lastActivity = now
+	return tx, nil
+}
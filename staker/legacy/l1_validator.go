@@ -5,6 +5,7 @@ package legacystaker
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/big"
@@ -45,18 +46,32 @@ const (
 )
 
 type L1Validator struct {
-	rollup         *RollupWatcher
-	rollupAddress  common.Address
-	validatorUtils *rollup_legacy_gen.ValidatorUtils
-	client         *ethclient.Client
-	builder        *txbuilder.Builder
-	wallet         ValidatorWalletInterface
-	callOpts       bind.CallOpts
+	rollup                *RollupWatcher
+	rollupAddress         common.Address
+	validatorUtils        *rollup_legacy_gen.ValidatorUtils
+	validatorUtilsAddress common.Address
+	client                *ethclient.Client
+	builder               *txbuilder.Builder
+	wallet                ValidatorWalletInterface
+	callOpts              bind.CallOpts
 
 	inboxTracker       staker.InboxTrackerInterface
 	txStreamer         staker.TransactionStreamerInterface
 	blockValidator     *staker.BlockValidator
 	lastWasmModuleRoot common.Hash
+	// childrenScan is the search for the staked node's children, resumed across acts when
+	// limited by max-scan-blocks-per-act. If nil, children are always searched in full.
+	childrenScan *nodeChildrenScan
+	// when a new node was last created, throttling creating more by min-post-interval
+	lastNodePosted time.Time
+	// whether the act cycle in progress queued a new node, which sets lastNodePosted once its transaction succeeds
+	nodePostedInAct bool
+	// simulation is set on the views of simulated act cycles, which must leave the block validator unchanged
+	simulation bool
+
+	challengeLog log.Logger
+	confirmLog   log.Logger
+	createLog    log.Logger
 }
 
 func NewL1Validator(
@@ -74,28 +89,35 @@ func NewL1Validator(
 	if err != nil {
 		return nil, err
 	}
-	rollup, err := NewRollupWatcher(rollupAddress, wallet.L1Client(), callOpts)
+	// Reads go to the given client, while transactions are built against the wallet's client.
+	// Reads prefetched for an act cycle are served without a round trip.
+	rollup, err := NewRollupWatcher(rollupAddress, newBatchedReadClient(newSplitL1Client(client, wallet.L1Client())), callOpts)
 	if err != nil {
 		return nil, err
 	}
 	validatorUtils, err := rollup_legacy_gen.NewValidatorUtils(
 		validatorUtilsAddress,
-		client,
+		newBatchedReadClient(client),
 	)
 	if err != nil {
 		return nil, err
 	}
 	return &L1Validator{
-		rollup:         rollup,
-		rollupAddress:  rollupAddress,
-		validatorUtils: validatorUtils,
-		client:         client,
-		builder:        builder,
-		wallet:         wallet,
-		callOpts:       callOpts,
-		inboxTracker:   inboxTracker,
-		txStreamer:     txStreamer,
-		blockValidator: blockValidator,
+		rollup:                rollup,
+		rollupAddress:         rollupAddress,
+		validatorUtils:        validatorUtils,
+		validatorUtilsAddress: validatorUtilsAddress,
+		client:                client,
+		builder:               builder,
+		wallet:                wallet,
+		callOpts:              callOpts,
+		inboxTracker:          inboxTracker,
+		txStreamer:            txStreamer,
+		blockValidator:        blockValidator,
+		childrenScan:          &nodeChildrenScan{},
+		challengeLog:          log.Root(),
+		confirmLog:            log.Root(),
+		createLog:             log.Root(),
 	}, nil
 }
 
@@ -105,6 +127,15 @@ func (v *L1Validator) getCallOpts(ctx context.Context) *bind.CallOpts {
 	return &opts
 }
 
+// currentL1BlockNumber returns the parent chain block the validator reads the rollup's state at:
+// the block its call options are pinned to, if any, or else the latest block.
+func (v *L1Validator) currentL1BlockNumber(ctx context.Context) (uint64, error) {
+	if v.callOpts.BlockNumber != nil {
+		return v.callOpts.BlockNumber.Uint64(), nil
+	}
+	return v.client.BlockNumber(ctx)
+}
+
 func (v *L1Validator) Initialize(ctx context.Context) error {
 	if err := v.rollup.Initialize(ctx); err != nil {
 		return err
@@ -121,6 +152,14 @@ func (v *L1Validator) updateBlockValidatorModuleRoot(ctx context.Context) error
 		return err
 	}
 	if moduleRoot != v.lastWasmModuleRoot {
+		if v.simulation {
+			// decide with the root read, without switching the block validator over to it
+			v.lastWasmModuleRoot = moduleRoot
+			return nil
+		}
+		if err := v.blockValidator.CheckOnChainWasmModuleRoot(moduleRoot); err != nil {
+			log.Warn("validating with a machine other than the latest", "err", err)
+		}
 		err := v.blockValidator.SetCurrentWasmModuleRoot(moduleRoot)
 		if err != nil {
 			return err
@@ -140,7 +179,7 @@ func (v *L1Validator) resolveTimedOutChallenges(ctx context.Context) (*types.Tra
 	if len(challengesToEliminate) == 0 {
 		return nil, nil
 	}
-	log.Info("timing out challenges", "count", len(challengesToEliminate))
+	v.challengeLog.Info("timing out challenges", "count", len(challengesToEliminate))
 	challengeManagerAddress, err := v.rollup.ChallengeManager(v.getCallOpts(ctx))
 	if err != nil {
 		return nil, err
@@ -148,7 +187,71 @@ func (v *L1Validator) resolveTimedOutChallenges(ctx context.Context) (*types.Tra
 	return v.wallet.TimeoutChallenges(ctx, challengesToEliminate, challengeManagerAddress)
 }
 
-func (v *L1Validator) resolveNextNode(ctx context.Context, info *StakerInfo, latestConfirmedNode *uint64) (bool, error) {
+// confirmationDelayElapsed returns true if currentL1Block is at least delayBlocks past fromBlock
+func confirmationDelayElapsed(fromBlock uint64, delayBlocks uint64, currentL1Block uint64) bool {
+	return currentL1Block >= arbmath.SaturatingUAdd(fromBlock, delayBlocks)
+}
+
+// confirmationStaggerOffset returns the staker's extra confirmation delay for the node, in [0, staggerBlocks]
+func confirmationStaggerOffset(staker common.Address, nodeNum uint64, staggerBlocks uint64) uint64 {
+	if staggerBlocks == 0 {
+		return 0
+	}
+	seed := crypto.Keccak256(staker.Bytes(), binary.BigEndian.AppendUint64(nil, nodeNum))
+	return binary.BigEndian.Uint64(seed[:8]) % (staggerBlocks + 1)
+}
+
+// confirmationMatured returns true if at least maturityNodes nodes were created after the node
+func confirmationMatured(nodeNum uint64, latestNodeCreated uint64, maturityNodes uint64) bool {
+	return latestNodeCreated >= arbmath.SaturatingUAdd(nodeNum, maturityNodes)
+}
+
+// confirmationMaturityReached returns true if the node has matured by either of the criteria configured:
+// maturityNodes successor nodes created after it, or maturityBlocks L1 blocks passed since its creation.
+// A criterion of 0 is disabled, and a node without any criterion is always mature.
+func confirmationMaturityReached(nodeNum uint64, latestNodeCreated uint64, maturityNodes uint64, createdAtBlock uint64, currentL1Block uint64, maturityBlocks uint64) bool {
+	if maturityNodes == 0 && maturityBlocks == 0 {
+		return true
+	}
+	if maturityNodes > 0 && confirmationMatured(nodeNum, latestNodeCreated, maturityNodes) {
+		return true
+	}
+	return maturityBlocks > 0 && confirmationDelayElapsed(createdAtBlock, maturityBlocks, currentL1Block)
+}
+
+// currentL1Block returns the number of the L1 block corresponding to the latest parent chain block.
+func (v *L1Validator) currentL1Block(ctx context.Context) (uint64, error) {
+	currentParentChainBlock, err := v.client.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error getting latest parent chain block number: %w", err)
+	}
+	return arbutil.CorrespondingL1BlockNumber(ctx, v.client, currentParentChainBlock)
+}
+
+// confirmationDelayPassed returns true once delayBlocks L1 blocks have passed since the node's deadline
+func (v *L1Validator) confirmationDelayPassed(ctx context.Context, nodeNum uint64, delayBlocks uint64) (bool, error) {
+	node, err := v.rollup.GetNode(v.getCallOpts(ctx), nodeNum)
+	if err != nil {
+		return false, err
+	}
+	currentL1Block, err := v.currentL1Block(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !confirmationDelayElapsed(node.DeadlineBlock, delayBlocks, currentL1Block) {
+		v.confirmLog.Info(
+			"waiting for confirmation delay before confirming node",
+			"node", nodeNum,
+			"deadlineBlock", node.DeadlineBlock,
+			"delayBlocks", delayBlocks,
+			"currentL1Block", currentL1Block,
+		)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (v *L1Validator) resolveNextNode(ctx context.Context, info *StakerInfo, latestConfirmedNode *uint64, confirmationDelayBlocks uint64, confirmationStaggerBlocks uint64, confirmationMaturityNodes uint64, confirmationMaturityBlocks uint64) (bool, error) {
 	callOpts := v.getCallOpts(ctx)
 	confirmType, err := v.validatorUtils.CheckDecidableNextNode(callOpts, v.rollupAddress)
 	if err != nil {
@@ -165,25 +268,126 @@ func (v *L1Validator) resolveNextNode(ctx context.Context, info *StakerInfo, lat
 			// We aren't an example of someone staked on a competitor
 			return false, nil
 		}
-		log.Warn("rejecting node", "node", unresolvedNodeIndex)
+		v.confirmLog.Warn("rejecting node", "node", unresolvedNodeIndex)
 		_, err = v.rollup.RejectNextNode(v.builder.Auth(ctx), *addr)
 		return true, err
 	case CONFIRM_TYPE_VALID:
-		nodeInfo, err := v.rollup.LookupNode(ctx, unresolvedNodeIndex)
+		return v.confirmNode(ctx, unresolvedNodeIndex, latestConfirmedNode, confirmationDelayBlocks, confirmationStaggerBlocks, confirmationMaturityNodes, confirmationMaturityBlocks)
+	default:
+		return false, nil
+	}
+}
+
+// confirmNode confirms nodeNum, the next node to be resolved, once the confirmation delay
+// and maturity have passed, returning whether a confirmation was made.
+func (v *L1Validator) confirmNode(ctx context.Context, nodeNum uint64, latestConfirmedNode *uint64, confirmationDelayBlocks uint64, confirmationStaggerBlocks uint64, confirmationMaturityNodes uint64, confirmationMaturityBlocks uint64) (bool, error) {
+	callOpts := v.getCallOpts(ctx)
+	delayBlocks := confirmationDelayBlocks + confirmationStaggerOffset(v.wallet.AddressOrZero(), nodeNum, confirmationStaggerBlocks)
+	if delayBlocks > 0 {
+		delayPassed, err := v.confirmationDelayPassed(ctx, nodeNum, delayBlocks)
+		if err != nil {
+			return false, err
+		}
+		if !delayPassed {
+			return false, nil
+		}
+	}
+	if confirmationMaturityNodes > 0 || confirmationMaturityBlocks > 0 {
+		latestNodeCreated, err := v.rollup.LatestNodeCreated(callOpts)
 		if err != nil {
 			return false, err
 		}
-		afterGs := nodeInfo.AfterState().GlobalState
-		log.Info("confirming node", "node", unresolvedNodeIndex)
-		_, err = v.rollup.ConfirmNextNode(v.builder.Auth(ctx), afterGs.BlockHash, afterGs.SendRoot)
+		node, err := v.rollup.GetNode(callOpts, nodeNum)
 		if err != nil {
 			return false, err
 		}
-		*latestConfirmedNode = unresolvedNodeIndex
-		return true, nil
-	default:
-		return false, nil
+		currentL1Block, err := v.currentL1Block(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !confirmationMaturityReached(nodeNum, latestNodeCreated, confirmationMaturityNodes, node.CreatedAtBlock, currentL1Block, confirmationMaturityBlocks) {
+			v.confirmLog.Info(
+				"waiting for node to mature before confirming it",
+				"node", nodeNum,
+				"latestNodeCreated", latestNodeCreated,
+				"maturityNodes", confirmationMaturityNodes,
+				"createdAtBlock", node.CreatedAtBlock,
+				"currentL1Block", currentL1Block,
+				"maturityBlocks", confirmationMaturityBlocks,
+			)
+			return false, nil
+		}
+	}
+	nodeInfo, err := v.rollup.LookupNode(ctx, nodeNum)
+	if err != nil {
+		return false, err
+	}
+	return v.confirmNodeInfo(ctx, nodeInfo, latestConfirmedNode)
+}
+
+// confirmNodeInfo confirms the next node to be resolved, unless the inbox position it commits to
+// disagrees with the inbox tracker.
+func (v *L1Validator) confirmNodeInfo(ctx context.Context, nodeInfo *NodeInfo, latestConfirmedNode *uint64) (bool, error) {
+	if err := v.VerifyNodeInbox(nodeInfo); err != nil {
+		v.confirmLog.Error("not confirming node with inbox position not matching inbox tracker", "node", nodeInfo.NodeNum, "err", err)
+		return false, fmt.Errorf("error verifying node %v inbox position: %w", nodeInfo.NodeNum, err)
+	}
+	afterGs := nodeInfo.AfterState().GlobalState
+	v.confirmLog.Info("confirming node", "node", nodeInfo.NodeNum)
+	_, err := v.rollup.ConfirmNextNode(v.builder.Auth(ctx), afterGs.BlockHash, afterGs.SendRoot)
+	if err != nil {
+		return false, err
 	}
+	*latestConfirmedNode = nodeInfo.NodeNum
+	return true, nil
+}
+
+// confirmNodes calls confirmNext until it doesn't confirm a node or maxConfirmations nodes were confirmed,
+// returning the number of nodes confirmed.
+func confirmNodes(maxConfirmations uint64, confirmNext func() (bool, error)) (uint64, error) {
+	var confirmed uint64
+	for confirmed < maxConfirmations {
+		ok, err := confirmNext()
+		if err != nil {
+			return confirmed, err
+		}
+		if !ok {
+			break
+		}
+		confirmed++
+	}
+	return confirmed, nil
+}
+
+// confirmFollowingNodes adds confirmations of up to maxConfirmations nodes following latestConfirmedNode
+// to the builder's batch, after the confirmations already in it. It stops at the first node which can't
+// be confirmed yet, such as one the batch reverts on, leaving it to the next act.
+func (v *L1Validator) confirmFollowingNodes(ctx context.Context, latestConfirmedNode *uint64, maxConfirmations uint64, confirmationDelayBlocks uint64, confirmationStaggerBlocks uint64, confirmationMaturityNodes uint64, confirmationMaturityBlocks uint64) (uint64, error) {
+	callOpts := v.getCallOpts(ctx)
+	latestNodeCreated, err := v.rollup.LatestNodeCreated(callOpts)
+	if err != nil {
+		return 0, err
+	}
+	return confirmNodes(maxConfirmations, func() (bool, error) {
+		nodeNum := *latestConfirmedNode + 1
+		if nodeNum > latestNodeCreated {
+			return false, nil
+		}
+		node, err := v.rollup.GetNode(callOpts, nodeNum)
+		if err != nil {
+			return false, err
+		}
+		if node.PrevNum != *latestConfirmedNode {
+			// A competing node has to be rejected first
+			return false, nil
+		}
+		confirmed, err := v.confirmNode(ctx, nodeNum, latestConfirmedNode, confirmationDelayBlocks, confirmationStaggerBlocks, confirmationMaturityNodes, confirmationMaturityBlocks)
+		if err != nil && headerreader.IsExecutionReverted(err) {
+			v.confirmLog.Info("node can't be confirmed yet, leaving it to the next act", "node", nodeNum, "err", err)
+			return false, nil
+		}
+		return confirmed, err
+	})
 }
 
 func (v *L1Validator) isRequiredStakeElevated(ctx context.Context) (bool, error) {
@@ -221,53 +425,92 @@ type OurStakerInfo struct {
 	LatestStakedNodeHash common.Hash
 	CanProgress          bool
 	StakeExists          bool
+	// CatchingUp is set if the node hasn't caught up to the staked node yet
+	CatchingUp bool
+	// StakeMoves counts the existing nodes the stake moved through in this act
+	StakeMoves uint64
+	// NodesCreated counts the nodes created in this act
+	NodesCreated uint64
+	// pending is set if the stake is on a node created in this act, which doesn't exist on chain yet
+	pending *pendingNode
 	*StakerInfo
 }
 
+// pendingNode is a node created by a transaction of the current act, which the following nodes created
+// in the act build on.
+type pendingNode struct {
+	afterState    *validator.ExecutionState
+	inboxMaxCount *big.Int
+}
+
+// lookupNodeChildren returns the children of the given node, searching at most max-scan-blocks-per-act
+// parent chain blocks for them. Returns false if the search has yet to be completed by later calls.
+func (v *L1Validator) lookupNodeChildren(ctx context.Context, nodeNum uint64, nodeHash common.Hash, stakerConfig *L1ValidatorConfig) ([]*NodeInfo, bool, error) {
+	if v.childrenScan == nil {
+		children, err := v.rollup.LookupNodeChildren(ctx, nodeNum, stakerConfig.LogQueryBatchSize, nodeHash)
+		return children, err == nil, err
+	}
+	return v.rollup.scanNodeChildren(ctx, v.childrenScan, nodeNum, nodeHash, stakerConfig.LogQueryBatchSize, stakerConfig.MaxScanBlocksPerAct)
+}
+
 func (v *L1Validator) generateNodeAction(
 	ctx context.Context,
 	stakerInfo *OurStakerInfo,
 	strategy StakerStrategy,
 	stakerConfig *L1ValidatorConfig,
-) (nodeAction, bool, error) {
-	startState, prevInboxMaxCount, startStateProposedL1, startStateProposedParentChain, err := lookupNodeStartState(
-		ctx, v.rollup, stakerInfo.LatestStakedNode, stakerInfo.LatestStakedNodeHash,
-	)
-	if err != nil {
-		return nil, false, fmt.Errorf(
-			"error looking up node %v (hash %v) start state: %w",
-			stakerInfo.LatestStakedNode, stakerInfo.LatestStakedNodeHash, err,
+) (nodeAction, []uint64, error) {
+	var startState *validator.ExecutionState
+	var prevInboxMaxCount *big.Int
+	var startStateProposedL1 uint64
+	var startStateProposedTime time.Time
+	var err error
+	if stakerInfo.pending != nil {
+		// the node is being created in this act
+		startState = stakerInfo.pending.afterState
+		prevInboxMaxCount = stakerInfo.pending.inboxMaxCount
+		startStateProposedTime = time.Now()
+	} else {
+		var startStateProposedParentChain uint64
+		startState, prevInboxMaxCount, startStateProposedL1, startStateProposedParentChain, err = lookupNodeStartState(
+			ctx, v.rollup, stakerInfo.LatestStakedNode, stakerInfo.LatestStakedNodeHash,
 		)
-	}
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"error looking up node %v (hash %v) start state: %w",
+				stakerInfo.LatestStakedNode, stakerInfo.LatestStakedNodeHash, err,
+			)
+		}
 
-	startStateProposedHeader, err := v.client.HeaderByNumber(ctx, arbmath.UintToBig(startStateProposedParentChain))
-	if err != nil {
-		return nil, false, fmt.Errorf(
-			"error looking up L1 header of block %v of node start state: %w",
-			startStateProposedParentChain, err,
-		)
+		startStateProposedHeader, err := v.client.HeaderByNumber(ctx, arbmath.UintToBig(startStateProposedParentChain))
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"error looking up L1 header of block %v of node start state: %w",
+				startStateProposedParentChain, err,
+			)
+		}
+		// #nosec G115
+		startStateProposedTime = time.Unix(int64(startStateProposedHeader.Time), 0)
 	}
-	// #nosec G115
-	startStateProposedTime := time.Unix(int64(startStateProposedHeader.Time), 0)
 
 	v.txStreamer.PauseReorgs()
 	defer v.txStreamer.ResumeReorgs()
 
 	localBatchCount, err := v.inboxTracker.GetBatchCount()
 	if err != nil {
-		return nil, false, fmt.Errorf("error getting batch count from inbox tracker: %w", err)
+		return nil, nil, fmt.Errorf("error getting batch count from inbox tracker: %w", err)
 	}
 	if localBatchCount < startState.RequiredBatches() || localBatchCount == 0 {
 		log.Info(
 			"catching up to chain batches", "localBatches", localBatchCount,
 			"target", startState.RequiredBatches(),
 		)
-		return nil, false, nil
+		stakerInfo.CatchingUp = true
+		return nil, nil, nil
 	}
 
 	caughtUp, startCount, err := staker.GlobalStateToMsgCount(v.inboxTracker, v.txStreamer, startState.GlobalState)
 	if err != nil {
-		return nil, false, fmt.Errorf("start state not in chain: %w", err)
+		return nil, nil, fmt.Errorf("start state not in chain: %w", err)
 	}
 	if !caughtUp {
 		target := staker.GlobalStatePosition{
@@ -284,7 +527,8 @@ func (v *L1Validator) generateNodeAction(
 		} else {
 			log.Info("catching up to chain blocks", "target", target, "current", current)
 		}
-		return nil, false, nil
+		stakerInfo.CatchingUp = true
+		return nil, nil, nil
 	}
 
 	var validatedCount arbutil.MessageIndex
@@ -292,21 +536,22 @@ func (v *L1Validator) generateNodeAction(
 	if v.blockValidator != nil {
 		valInfo, err := v.blockValidator.ReadLastValidatedInfo()
 		if err != nil || valInfo == nil {
-			return nil, false, err
+			return nil, nil, err
 		}
 		validatedGlobalState = valInfo.GlobalState
 		caughtUp, validatedCount, err = staker.GlobalStateToMsgCount(
 			v.inboxTracker, v.txStreamer, valInfo.GlobalState,
 		)
 		if err != nil {
-			return nil, false, fmt.Errorf("%w: not found validated block in blockchain", err)
+			return nil, nil, fmt.Errorf("%w: not found validated block in blockchain", err)
 		}
 		if !caughtUp {
 			log.Info("catching up to last validated block", "target", valInfo.GlobalState)
-			return nil, false, nil
+			stakerInfo.CatchingUp = true
+			return nil, nil, nil
 		}
 		if err := v.updateBlockValidatorModuleRoot(ctx); err != nil {
-			return nil, false, fmt.Errorf("error updating block validator module root: %w", err)
+			return nil, nil, fmt.Errorf("error updating block validator module root: %w", err)
 		}
 		wasmRootValid := false
 		for _, root := range valInfo.WasmRoots {
@@ -318,9 +563,9 @@ func (v *L1Validator) generateNodeAction(
 		if !wasmRootValid {
 			if !stakerConfig.Dangerous.IgnoreRollupWasmModuleRoot {
 				if len(valInfo.WasmRoots) == 0 {
-					return nil, false, fmt.Errorf("block validation is still pending")
+					return nil, nil, ErrBlockValidationPending
 				}
-				return nil, false, fmt.Errorf(
+				return nil, nil, fmt.Errorf(
 					"wasmroot doesn't match rollup : %v, valid: %v",
 					v.lastWasmModuleRoot, valInfo.WasmRoots,
 				)
@@ -330,12 +575,12 @@ func (v *L1Validator) generateNodeAction(
 	} else {
 		validatedCount, err = v.txStreamer.GetProcessedMessageCount()
 		if err != nil || validatedCount == 0 {
-			return nil, false, err
+			return nil, nil, err
 		}
 		var batchNum uint64
 		messageCount, err := v.inboxTracker.GetBatchMessageCount(localBatchCount - 1)
 		if err != nil {
-			return nil, false, fmt.Errorf("error getting latest batch %v message count: %w", localBatchCount-1, err)
+			return nil, nil, fmt.Errorf("error getting latest batch %v message count: %w", localBatchCount-1, err)
 		}
 		if validatedCount >= messageCount {
 			batchNum = localBatchCount - 1
@@ -344,66 +589,54 @@ func (v *L1Validator) generateNodeAction(
 			var found bool
 			batchNum, found, err = v.inboxTracker.FindInboxBatchContainingMessage(validatedCount - 1)
 			if err != nil {
-				return nil, false, err
+				return nil, nil, err
 			}
 			if !found {
-				return nil, false, errors.New("batch not found on L1")
+				return nil, nil, errors.New("batch not found on L1")
 			}
 		}
 		execResult := &execution.MessageResult{}
 		if validatedCount > 0 {
 			execResult, err = v.txStreamer.ResultAtMessageIndex(validatedCount - 1)
 			if err != nil {
-				return nil, false, err
+				return nil, nil, err
 			}
 		}
 		_, gsPos, err := staker.GlobalStatePositionsAtCount(v.inboxTracker, validatedCount, batchNum)
 		if err != nil {
-			return nil, false, fmt.Errorf("%w: failed calculating GSposition for count %d", err, validatedCount)
+			return nil, nil, fmt.Errorf("%w: failed calculating GSposition for count %d", err, validatedCount)
 		}
 		validatedGlobalState = staker.BuildGlobalState(*execResult, gsPos)
 	}
 
-	currentL1BlockNum, err := v.client.BlockNumber(ctx)
-	if err != nil {
-		return nil, false, fmt.Errorf("error getting latest L1 block number: %w", err)
-	}
-
-	l1BlockNumber, err := arbutil.CorrespondingL1BlockNumber(ctx, v.client, currentL1BlockNum)
-	if err != nil {
-		return nil, false, err
-	}
-
-	minAssertionPeriod, err := v.rollup.MinimumAssertionPeriod(v.getCallOpts(ctx))
-	if err != nil {
-		return nil, false, fmt.Errorf("error getting rollup minimum assertion period: %w", err)
-	}
-
-	// #nosec G115
-	timeSinceProposed := big.NewInt(int64(l1BlockNumber) - int64(startStateProposedL1))
-	if timeSinceProposed.Cmp(minAssertionPeriod) < 0 {
-		// Too soon to assert
-		return nil, false, nil
+	// a node being created in this act has no children yet
+	var successorNodes []*NodeInfo
+	complete := true
+	if stakerInfo.pending == nil {
+		successorNodes, complete, err = v.lookupNodeChildren(ctx, stakerInfo.LatestStakedNode, stakerInfo.LatestStakedNodeHash, stakerConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error looking up node %v (hash %v) children: %w", stakerInfo.LatestStakedNode, stakerInfo.LatestStakedNodeHash, err)
+		}
 	}
-
-	successorNodes, err := v.rollup.LookupNodeChildren(ctx, stakerInfo.LatestStakedNode, stakerConfig.LogQueryBatchSize, stakerInfo.LatestStakedNodeHash)
-	if err != nil {
-		return nil, false, fmt.Errorf("error looking up node %v (hash %v) children: %w", stakerInfo.LatestStakedNode, stakerInfo.LatestStakedNodeHash, err)
+	if !complete {
+		v.createLog.Info("staker: still searching for existing successors", "node", stakerInfo.LatestStakedNode, "nextBlock", v.childrenScan.nextBlock)
+		stakerInfo.CatchingUp = true
+		return nil, nil, nil
 	}
 
 	var correctNode nodeAction
-	wrongNodesExist := false
+	var wrongNodes []uint64
 	if len(successorNodes) > 0 {
-		log.Info("examining existing potential successors", "count", len(successorNodes))
+		v.createLog.Info("examining existing potential successors", "count", len(successorNodes))
 	}
 	for _, nd := range successorNodes {
-		if correctNode != nil && wrongNodesExist {
+		if correctNode != nil && len(wrongNodes) > 0 {
 			// We've found everything we could hope to find
 			break
 		}
 		if correctNode != nil {
-			log.Error("found younger sibling to correct assertion (implicitly invalid)", "node", nd.NodeNum)
-			wrongNodesExist = true
+			v.createLog.Error("found younger sibling to correct assertion (implicitly invalid)", "node", nd.NodeNum)
+			wrongNodes = append(wrongNodes, nd.NodeNum)
 			continue
 		}
 		afterGS := nd.AfterState().GlobalState
@@ -412,35 +645,42 @@ func (v *L1Validator) generateNodeAction(
 			requiredBatch -= 1
 		}
 		if localBatchCount <= requiredBatch {
-			log.Info("staker: waiting for node to catch up to assertion batch", "current", localBatchCount, "target", requiredBatch-1)
-			return nil, false, nil
+			v.createLog.Info("staker: waiting for node to catch up to assertion batch", "current", localBatchCount, "target", requiredBatch-1)
+			return nil, nil, nil
 		}
 		nodeBatchMsgCount, err := v.inboxTracker.GetBatchMessageCount(requiredBatch)
 		if err != nil {
-			return nil, false, err
+			return nil, nil, err
 		}
 		if validatedCount < nodeBatchMsgCount {
-			log.Info("staker: waiting for validator to catch up to assertion batch messages", "current", validatedCount, "target", nodeBatchMsgCount)
-			return nil, false, nil
+			v.createLog.Info("staker: waiting for validator to catch up to assertion batch messages", "current", validatedCount, "target", nodeBatchMsgCount)
+			return nil, nil, nil
 		}
 		if nd.Assertion.AfterState.MachineStatus != validator.MachineStatusFinished {
-			wrongNodesExist = true
-			log.Error("Found incorrect assertion: Machine status not finished", "node", nd.NodeNum, "machineStatus", nd.Assertion.AfterState.MachineStatus)
+			wrongNodes = append(wrongNodes, nd.NodeNum)
+			v.flagIncorrectAssertion(startCount, nodeBatchMsgCount)
+			v.createLog.Error("Found incorrect assertion: Machine status not finished", "node", nd.NodeNum, "machineStatus", nd.Assertion.AfterState.MachineStatus)
 			continue
 		}
 		caughtUp, nodeMsgCount, err := staker.GlobalStateToMsgCount(v.inboxTracker, v.txStreamer, afterGS)
 		if errors.Is(err, staker.ErrGlobalStateNotInChain) {
-			wrongNodesExist = true
-			log.Error("Found incorrect assertion", "node", nd.NodeNum, "afterGS", afterGS, "err", err)
+			wrongNodes = append(wrongNodes, nd.NodeNum)
+			v.flagIncorrectAssertion(startCount, nodeBatchMsgCount)
+			v.createLog.Error("Found incorrect assertion", "node", nd.NodeNum, "afterGS", afterGS, "err", err)
 			continue
 		}
 		if err != nil {
-			return nil, false, fmt.Errorf("error getting message number from global state: %w", err)
+			return nil, nil, fmt.Errorf("error getting message number from global state: %w", err)
 		}
 		if !caughtUp {
-			return nil, false, fmt.Errorf("unexpected no-caught-up parsing assertion. Current: %d target: %v", validatedCount, afterGS)
+			return nil, nil, fmt.Errorf("unexpected no-caught-up parsing assertion. Current: %d target: %v", validatedCount, afterGS)
 		}
-		log.Info(
+		if err := v.VerifyNodeInbox(nd); err != nil {
+			// our view of the inbox disagrees with the rollup's, so don't stake on the node
+			v.createLog.Error("node inbox position doesn't match inbox tracker", "node", nd.NodeNum, "err", err)
+			return nil, nil, fmt.Errorf("error verifying node %v inbox position: %w", nd.NodeNum, err)
+		}
+		v.createLog.Info(
 			"found correct assertion",
 			"node", nd.NodeNum,
 			"count", nodeMsgCount,
@@ -453,24 +693,106 @@ func (v *L1Validator) generateNodeAction(
 	}
 
 	if correctNode != nil || strategy == WatchtowerStrategy {
-		return correctNode, wrongNodesExist, nil
+		return correctNode, wrongNodes, nil
 	}
 
 	makeAssertionInterval := stakerConfig.MakeAssertionInterval
-	if wrongNodesExist || (strategy >= MakeNodesStrategy && time.Since(startStateProposedTime) >= makeAssertionInterval) {
+	if strategy == MakeNodesAggressiveStrategy && (stakerInfo.StakeMoves > 0 || stakerInfo.pending != nil) {
+		// We were behind the latest node, so assert right away rather than waiting out the interval
+		makeAssertionInterval = 0
+	}
+	if len(wrongNodes) > 0 || (strategy >= MakeNodesStrategy && time.Since(startStateProposedTime) >= makeAssertionInterval) {
+		// The minimum assertion period only limits creating nodes, so it's checked after looking for
+		// an existing correct node, letting the stake move forward through existing nodes regardless.
+		var tooSoon bool
+		if stakerInfo.pending != nil {
+			tooSoon, err = v.tooSoonToFollowPendingNode(ctx)
+		} else {
+			tooSoon, err = v.tooSoonToAssert(ctx, startStateProposedL1)
+		}
+		if err != nil || tooSoon {
+			return nil, wrongNodes, err
+		}
+		// The min post interval spaces out acts creating nodes, not the nodes created by one act
+		if len(wrongNodes) == 0 && stakerInfo.pending == nil && !minPostIntervalElapsed(v.lastNodePosted, stakerConfig.MinPostInterval, time.Now()) {
+			v.createLog.Info("waiting out min post interval before creating a new node", "lastNodePosted", v.lastNodePosted, "minPostInterval", stakerConfig.MinPostInterval)
+			return nil, wrongNodes, nil
+		}
 		// There's no correct node; create one.
 		var lastNodeHashIfExists *common.Hash
 		if len(successorNodes) > 0 {
 			lastNodeHashIfExists = &successorNodes[len(successorNodes)-1].NodeHash
 		}
-		action, err := v.createNewNodeAction(ctx, stakerInfo, prevInboxMaxCount, startCount, startState, validatedCount, validatedGlobalState, lastNodeHashIfExists)
+		// When making nodes aggressively with more nodes left to create in the act, the node only covers
+		// what the rollup requires it to, leaving the rest of the validated messages to the following nodes.
+		split := strategy == MakeNodesAggressiveStrategy && stakerInfo.StakeMoves+stakerInfo.NodesCreated+1 < stakerConfig.AggressiveDepth
+		action, err := v.createNewNodeAction(ctx, stakerInfo, prevInboxMaxCount, startCount, startState, validatedCount, validatedGlobalState, lastNodeHashIfExists, split)
 		if err != nil {
-			return nil, wrongNodesExist, fmt.Errorf("error generating create new node action (from pos %d to %d): %w", startCount, validatedCount, err)
+			return nil, wrongNodes, fmt.Errorf("error generating create new node action (from pos %d to %d): %w", startCount, validatedCount, err)
 		}
-		return action, wrongNodesExist, nil
+		return action, wrongNodes, nil
+	}
+
+	return nil, wrongNodes, nil
+}
+
+// tooSoonToAssert returns true if the rollup's minimum assertion period hasn't passed since the
+// parent chain block the node we're staked on was proposed in, so no successor can be created yet.
+func (v *L1Validator) tooSoonToAssert(ctx context.Context, startStateProposedL1 uint64) (bool, error) {
+	currentL1BlockNum, err := v.currentL1BlockNumber(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error getting latest L1 block number: %w", err)
+	}
+
+	l1BlockNumber, err := arbutil.CorrespondingL1BlockNumber(ctx, v.client, currentL1BlockNum)
+	if err != nil {
+		return false, err
+	}
+
+	minAssertionPeriod, err := v.rollup.MinimumAssertionPeriod(v.getCallOpts(ctx))
+	if err != nil {
+		return false, fmt.Errorf("error getting rollup minimum assertion period: %w", err)
+	}
+
+	// #nosec G115
+	timeSinceProposed := big.NewInt(int64(l1BlockNumber) - int64(startStateProposedL1))
+	return timeSinceProposed.Cmp(minAssertionPeriod) < 0, nil
+}
+
+// tooSoonToFollowPendingNode returns true if a node can't be created on top of one created in the same act,
+// as the rollup's minimum assertion period can't have passed since it was proposed.
+func (v *L1Validator) tooSoonToFollowPendingNode(ctx context.Context) (bool, error) {
+	minAssertionPeriod, err := v.rollup.MinimumAssertionPeriod(v.getCallOpts(ctx))
+	if err != nil {
+		return false, fmt.Errorf("error getting rollup minimum assertion period: %w", err)
 	}
+	return minAssertionPeriod.Sign() > 0, nil
+}
+
+// minPostIntervalElapsed returns true if interval has passed since a node was last created at lastPosted,
+// or if none was.
+func minPostIntervalElapsed(lastPosted time.Time, interval time.Duration, now time.Time) bool {
+	return interval <= 0 || lastPosted.IsZero() || now.Sub(lastPosted) >= interval
+}
+
+// VerifyNodeInbox checks that the inbox position the node's assertion commits to matches the
+// validator's inbox tracker. It returns an error wrapping ErrNodeInboxMismatch on a discrepancy.
+func (v *L1Validator) VerifyNodeInbox(nd *NodeInfo) error {
+	return verifyNodeInbox(v.inboxTracker, nd)
+}
 
-	return nil, wrongNodesExist, nil
+// VerifyNodePredecessor checks that nodeNum is built on agreedNode, the node the validator agrees with.
+// It returns an error wrapping ErrNodePredecessorMismatch if the node has a different predecessor.
+func (v *L1Validator) VerifyNodePredecessor(ctx context.Context, nodeNum uint64, agreedNode uint64) error {
+	return verifyNodePredecessor(v.getCallOpts(ctx), v.rollup, nodeNum, agreedNode)
+}
+
+// flagIncorrectAssertion makes the block validator fully validate the messages in [start, end)
+// covered by an incorrect assertion, even if it's only validating a sample of messages.
+func (v *L1Validator) flagIncorrectAssertion(start, end arbutil.MessageIndex) {
+	if v.blockValidator != nil && !v.simulation {
+		v.blockValidator.ForceFullValidation(start, end)
+	}
 }
 
 func (v *L1Validator) createNewNodeAction(
@@ -482,6 +804,7 @@ func (v *L1Validator) createNewNodeAction(
 	validatedCount arbutil.MessageIndex,
 	validatedGS validator.GoGlobalState,
 	lastNodeHashIfExists *common.Hash,
+	split bool,
 ) (nodeAction, error) {
 	if !prevInboxMaxCount.IsUint64() {
 		return nil, fmt.Errorf("inbox max count %v isn't a uint64", prevInboxMaxCount)
@@ -492,9 +815,24 @@ func (v *L1Validator) createNewNodeAction(
 	}
 	if validatedGS.Batch < prevInboxMaxCount.Uint64() {
 		// didn't validate enough batches
-		log.Info("staker: not enough batches validated to create new assertion", "validated.Batch", validatedGS.Batch, "posInBatch", validatedGS.PosInBatch, "required batch", prevInboxMaxCount)
+		v.createLog.Info("staker: not enough batches validated to create new assertion", "validated.Batch", validatedGS.Batch, "posInBatch", validatedGS.PosInBatch, "required batch", prevInboxMaxCount)
 		return nil, nil
 	}
+	if split {
+		// end the node at the start of the batch it's required to reach, if there's more validated after it
+		splitCount, err := v.inboxTracker.GetBatchMessageCount(prevInboxMaxCount.Uint64() - 1)
+		if err != nil {
+			return nil, fmt.Errorf("error getting batch %v message count: %w", prevInboxMaxCount.Uint64()-1, err)
+		}
+		if splitCount > startCount && splitCount < validatedCount {
+			execResult, err := v.txStreamer.ResultAtMessageIndex(splitCount - 1)
+			if err != nil {
+				return nil, err
+			}
+			validatedCount = splitCount
+			validatedGS = staker.BuildGlobalState(*execResult, staker.GlobalStatePosition{BatchNumber: prevInboxMaxCount.Uint64()})
+		}
+	}
 	batchValidated := validatedGS.Batch
 	if validatedGS.PosInBatch == 0 {
 		batchValidated--
@@ -537,7 +875,7 @@ func (v *L1Validator) createNewNodeAction(
 		hash:              newNodeHash,
 		prevInboxMaxCount: prevInboxMaxCount,
 	}
-	log.Info("creating node", "hash", newNodeHash, "lastNode", prevNum, "parentNode", stakerInfo.LatestStakedNode)
+	v.createLog.Info("creating node", "hash", newNodeHash, "lastNode", prevNum, "parentNode", stakerInfo.LatestStakedNode)
 	return action, nil
 }
 
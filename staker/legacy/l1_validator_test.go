@@ -0,0 +1,365 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/staker"
+	"github.com/offchainlabs/nitro/staker/txbuilder"
+	"github.com/offchainlabs/nitro/staker/validatorwallet"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+func TestConfirmationDelayElapsed(t *testing.T) {
+	cases := []struct {
+		name                     string
+		deadline, delay, current uint64
+		expected                 bool
+	}{
+		{"no delay", 100, 0, 100, true},
+		{"within delay", 100, 10, 105, false},
+		{"last block of delay", 100, 10, 109, false},
+		{"delay just elapsed", 100, 10, 110, true},
+		{"long after delay", 100, 10, 200, true},
+		{"saturated deadline", math.MaxUint64 - 1, 10, math.MaxUint64, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if confirmationDelayElapsed(c.deadline, c.delay, c.current) != c.expected {
+				Fail(t, "unexpected confirmation delay result", c.deadline, c.delay, c.current, "expected", c.expected)
+			}
+		})
+	}
+}
+
+func TestConfirmationMatured(t *testing.T) {
+	cases := []struct {
+		name                               string
+		node, latestCreated, maturityNodes uint64
+		expected                           bool
+	}{
+		{"no maturity", 5, 5, 0, true},
+		{"no successor", 5, 5, 1, false},
+		{"too few successors", 5, 7, 3, false},
+		{"just matured", 5, 8, 3, true},
+		{"long matured", 5, 20, 3, true},
+		{"saturated maturity", 5, math.MaxUint64, math.MaxUint64, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if confirmationMatured(c.node, c.latestCreated, c.maturityNodes) != c.expected {
+				Fail(t, "unexpected confirmation maturity result", c.node, c.latestCreated, c.maturityNodes, "expected", c.expected)
+			}
+		})
+	}
+}
+
+func TestMinPostIntervalElapsed(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	cases := []struct {
+		name       string
+		lastPosted time.Time
+		interval   time.Duration
+		expected   bool
+	}{
+		{"never posted", time.Time{}, time.Hour, true},
+		{"no interval", now, 0, true},
+		{"just posted", now.Add(-time.Minute), time.Hour, false},
+		{"last second of interval", now.Add(-time.Hour + time.Second), time.Hour, false},
+		{"interval just elapsed", now.Add(-time.Hour), time.Hour, true},
+		{"long after interval", now.Add(-2 * time.Hour), time.Hour, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if minPostIntervalElapsed(c.lastPosted, c.interval, now) != c.expected {
+				Fail(t, "unexpected min post interval result", c.lastPosted, c.interval, "expected", c.expected)
+			}
+		})
+	}
+}
+
+func TestConfirmationStaggerOffset(t *testing.T) {
+	stakerA := common.HexToAddress("0xa")
+	stakerB := common.HexToAddress("0xb")
+	cases := []struct {
+		name          string
+		staggerBlocks uint64
+		// most nodes both stakers may be due to confirm at the same block, out of 50
+		maxTies int
+	}{
+		{"disabled", 0, 50},
+		{"one block", 1, 50},
+		{"100 blocks", 100, 9},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ties := 0
+			for node := uint64(1); node <= 50; node++ {
+				offsetA := confirmationStaggerOffset(stakerA, node, c.staggerBlocks)
+				offsetB := confirmationStaggerOffset(stakerB, node, c.staggerBlocks)
+				if offsetA > c.staggerBlocks || offsetB > c.staggerBlocks {
+					Fail(t, "offset exceeds stagger blocks", offsetA, offsetB)
+				}
+				if offsetA != confirmationStaggerOffset(stakerA, node, c.staggerBlocks) {
+					Fail(t, "expected offset to be deterministic")
+				}
+				if offsetA == offsetB {
+					ties++
+				}
+			}
+			// stakers which don't tie confirm at different blocks, so only the first one posts
+			if ties > c.maxTies {
+				Fail(t, "too many nodes where both stakers would confirm at the same block", ties)
+			}
+		})
+	}
+}
+
+type fakeBatchAccTracker []common.Hash
+
+func (t fakeBatchAccTracker) GetBatchCount() (uint64, error) {
+	return uint64(len(t)), nil
+}
+
+func (t fakeBatchAccTracker) GetBatchAcc(seqNum uint64) (common.Hash, error) {
+	if seqNum >= uint64(len(t)) {
+		return common.Hash{}, errors.New("batch not found")
+	}
+	return t[seqNum], nil
+}
+
+func TestVerifyNodeInbox(t *testing.T) {
+	tracker := fakeBatchAccTracker{common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3")}
+	node := func(batch, posInBatch uint64, inboxMaxCount int64, acc common.Hash) *NodeInfo {
+		return &NodeInfo{
+			NodeNum: 1,
+			Assertion: &Assertion{
+				AfterState: &validator.ExecutionState{
+					GlobalState:   validator.GoGlobalState{Batch: batch, PosInBatch: posInBatch},
+					MachineStatus: validator.MachineStatusFinished,
+				},
+			},
+			InboxMaxCount:      big.NewInt(inboxMaxCount),
+			AfterInboxBatchAcc: acc,
+		}
+	}
+
+	// an assertion ending at the start of batch 2 read batches 0 and 1
+	Require(t, verifyNodeInbox(tracker, node(2, 0, 3, tracker[1])))
+	// an assertion ending inside batch 2 read batches 0 to 2
+	Require(t, verifyNodeInbox(tracker, node(2, 5, 3, tracker[2])))
+
+	if err := verifyNodeInbox(tracker, node(2, 0, 3, tracker[2])); !errors.Is(err, ErrNodeInboxMismatch) {
+		Fail(t, "expected inbox mismatch for wrong batch accumulator, got", err)
+	}
+	if err := verifyNodeInbox(tracker, node(2, 5, 2, tracker[2])); !errors.Is(err, ErrNodeInboxMismatch) {
+		Fail(t, "expected inbox mismatch for batch count above inbox max count, got", err)
+	}
+	err := verifyNodeInbox(tracker, node(3, 5, 4, common.Hash{}))
+	if err == nil || errors.Is(err, ErrNodeInboxMismatch) {
+		Fail(t, "expected not caught up error for batch missing from tracker, got", err)
+	}
+}
+
+// fakeInboxTracker is an inbox tracker only knowing batch accumulators
+type fakeInboxTracker struct {
+	staker.InboxTrackerInterface
+	accs fakeBatchAccTracker
+}
+
+func (t *fakeInboxTracker) GetBatchCount() (uint64, error) {
+	return t.accs.GetBatchCount()
+}
+
+func (t *fakeInboxTracker) GetBatchAcc(seqNum uint64) (common.Hash, error) {
+	return t.accs.GetBatchAcc(seqNum)
+}
+
+func TestNotConfirmingNodeWithMismatchedInbox(t *testing.T) {
+	ctx := context.Background()
+	rollupAddress := common.HexToAddress("0x1000")
+	rollup, err := NewRollupWatcher(rollupAddress, newFakeEthClient(t, &fakeEthService{}), bind.CallOpts{})
+	Require(t, err)
+	builder, err := txbuilder.NewBuilder(validatorwallet.NewNoOp(nil), common.Address{})
+	Require(t, err)
+	tracker := fakeBatchAccTracker{common.HexToHash("0x1"), common.HexToHash("0x2")}
+	v := &L1Validator{
+		rollup:        rollup,
+		rollupAddress: rollupAddress,
+		builder:       builder,
+		inboxTracker:  &fakeInboxTracker{accs: tracker},
+		confirmLog:    log.New(),
+	}
+
+	// the node reads both batches the tracker has
+	for _, tc := range []struct {
+		name          string
+		inboxMaxCount int64
+		acc           common.Hash
+	}{
+		{"inbox max count below batches read", 1, tracker[1]},
+		{"wrong batch accumulator", 3, tracker[0]},
+	} {
+		node := &NodeInfo{
+			NodeNum: 2,
+			Assertion: &Assertion{
+				AfterState: &validator.ExecutionState{
+					GlobalState:   validator.GoGlobalState{Batch: 2},
+					MachineStatus: validator.MachineStatusFinished,
+				},
+			},
+			InboxMaxCount:      big.NewInt(tc.inboxMaxCount),
+			AfterInboxBatchAcc: tc.acc,
+		}
+		latestConfirmedNode := uint64(1)
+		confirmed, err := v.confirmNodeInfo(ctx, node, &latestConfirmedNode)
+		if !errors.Is(err, ErrNodeInboxMismatch) {
+			Fail(t, tc.name, "expected inbox mismatch, got", err)
+		}
+		if confirmed || latestConfirmedNode != 1 {
+			Fail(t, tc.name, "confirmed node with mismatched inbox, latest confirmed node", latestConfirmedNode)
+		}
+		if count := builder.BuildingTransactionCount(); count != 0 {
+			Fail(t, tc.name, "queued", count, "transactions confirming node with mismatched inbox")
+		}
+	}
+}
+
+func TestVerifyNodePredecessor(t *testing.T) {
+	// node 1 is continued by 2, while 3 was built on node 2's sibling 4
+	rollup := &fakeZombieRollup{prevNodes: map[uint64]uint64{2: 1, 3: 4, 4: 1}}
+	Require(t, verifyNodePredecessor(nil, rollup, 2, 1))
+	if err := verifyNodePredecessor(nil, rollup, 3, 2); !errors.Is(err, ErrNodePredecessorMismatch) {
+		Fail(t, "expected node with a bad predecessor not to be staked on, got", err)
+	}
+	err := verifyNodePredecessor(nil, rollup, 5, 2)
+	if err == nil || errors.Is(err, ErrNodePredecessorMismatch) {
+		Fail(t, "expected error looking up missing node, got", err)
+	}
+}
+
+func TestNodeChildrenScanBoundedPerAct(t *testing.T) {
+	ctx := context.Background()
+	const fromBlock, toBlock, maxBlocks, rangeSize = 1000, 3499, 300, 100
+	var queried [][2]uint64
+	filterLogs := func(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+		queried = append(queried, [2]uint64{q.FromBlock.Uint64(), q.ToBlock.Uint64()})
+		var logs []types.Log
+		for block := q.FromBlock.Uint64(); block <= q.ToBlock.Uint64(); block++ {
+			if block%500 == 0 {
+				logs = append(logs, types.Log{BlockNumber: block})
+			}
+		}
+		return logs, nil
+	}
+	scan := &nodeChildrenScan{nextBlock: big.NewInt(fromBlock)}
+	end := big.NewInt(toBlock)
+	acts := 0
+	for {
+		acts++
+		if acts > 100 {
+			Fail(t, "scan didn't catch up")
+		}
+		queried = nil
+		complete, err := scan.advance(ctx, filterLogs, ethereum.FilterQuery{}, end, rangeSize, maxBlocks)
+		Require(t, err)
+		var scanned uint64
+		for _, q := range queried {
+			if q[1]-q[0]+1 > rangeSize+1 {
+				Fail(t, "query range", q, "exceeds the log query range size")
+			}
+			scanned += q[1] - q[0] + 1
+		}
+		if scanned > maxBlocks {
+			Fail(t, "act", acts, "scanned", scanned, "blocks, more than the limit", maxBlocks)
+		}
+		if complete {
+			break
+		}
+		if scanned != maxBlocks {
+			Fail(t, "incomplete act", acts, "scanned", scanned, "blocks instead of the limit", maxBlocks)
+		}
+	}
+	// 2500 blocks at 300 per act
+	if acts != 9 {
+		Fail(t, "expected the backlog to be caught up in 9 acts, took", acts)
+	}
+	if scan.nextBlock.Uint64() != toBlock+1 {
+		Fail(t, "scan stopped at", scan.nextBlock, "instead of after", toBlock)
+	}
+	if len(scan.logs) != 5 {
+		Fail(t, "expected a log every 500 blocks to be found once, got", len(scan.logs))
+	}
+	for i, found := range scan.logs {
+		if found.BlockNumber != uint64(1000+500*i) {
+			Fail(t, "unexpected log", i, "at block", found.BlockNumber)
+		}
+	}
+
+	// without a limit, the remaining blocks are scanned at once
+	scan = &nodeChildrenScan{nextBlock: big.NewInt(fromBlock)}
+	complete, err := scan.advance(ctx, filterLogs, ethereum.FilterQuery{}, end, rangeSize, 0)
+	Require(t, err)
+	if !complete || len(scan.logs) != 5 {
+		Fail(t, "expected unlimited scan to complete, got complete", complete, "with", len(scan.logs), "logs")
+	}
+}
+
+func TestConfirmationsBoundedPerAct(t *testing.T) {
+	const backlog, maxConfirmations = 10, 3
+	latestConfirmed := uint64(0)
+	confirmNext := func() (bool, error) {
+		if latestConfirmed >= backlog {
+			return false, nil
+		}
+		latestConfirmed++
+		return true, nil
+	}
+	acts := 0
+	for latestConfirmed < backlog {
+		acts++
+		if acts > 100 {
+			Fail(t, "confirmations didn't catch up")
+		}
+		before := latestConfirmed
+		confirmed, err := confirmNodes(maxConfirmations, confirmNext)
+		Require(t, err)
+		if confirmed > maxConfirmations {
+			Fail(t, "act", acts, "confirmed", confirmed, "nodes, more than the limit", maxConfirmations)
+		}
+		if latestConfirmed-before != confirmed {
+			Fail(t, "act", acts, "reported", confirmed, "confirmations but confirmed", latestConfirmed-before)
+		}
+		if latestConfirmed < backlog && confirmed != maxConfirmations {
+			Fail(t, "incomplete act", acts, "confirmed", confirmed, "nodes instead of the limit", maxConfirmations)
+		}
+	}
+	// 10 nodes at 3 per act
+	if acts != 4 {
+		Fail(t, "expected the backlog to be confirmed in 4 acts, took", acts)
+	}
+	confirmed, err := confirmNodes(maxConfirmations, confirmNext)
+	Require(t, err)
+	if confirmed != 0 {
+		Fail(t, "confirmed", confirmed, "nodes without a backlog")
+	}
+
+	errConfirm := errors.New("confirmation failed")
+	confirmed, err = confirmNodes(maxConfirmations, func() (bool, error) { return false, errConfirm })
+	if !errors.Is(err, errConfirm) || confirmed != 0 {
+		Fail(t, "expected confirmation error, got", confirmed, err)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"fmt"
+	"log/slog"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/cmd/genericconf"
+	"github.com/offchainlabs/nitro/util"
+)
+
+const (
+	LogSubsystemChallenge  = "challenge"
+	LogSubsystemDataPoster = "dataposter"
+	LogSubsystemConfirm    = "confirm"
+	LogSubsystemCreate     = "create"
+)
+
+// StakerLogLevelsConfig overrides the log level of the staker's subsystems.
+// An empty level means the subsystem logs at the node's log level.
+type StakerLogLevelsConfig struct {
+	Challenge  string `koanf:"challenge" reload:"hot"`
+	DataPoster string `koanf:"data-poster" reload:"hot"`
+	Confirm    string `koanf:"confirm" reload:"hot"`
+	Create     string `koanf:"create" reload:"hot"`
+}
+
+var DefaultStakerLogLevelsConfig = StakerLogLevelsConfig{
+	Challenge:  "",
+	DataPoster: "",
+	Confirm:    "",
+	Create:     "",
+}
+
+func StakerLogLevelsConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.String(prefix+".challenge", DefaultStakerLogLevelsConfig.Challenge, "log level of challenge moves, overriding the node's log level if set")
+	f.String(prefix+".data-poster", DefaultStakerLogLevelsConfig.DataPoster, "log level of the staker's data poster, overriding the node's log level if set")
+	f.String(prefix+".confirm", DefaultStakerLogLevelsConfig.Confirm, "log level of node confirmation and rejection, overriding the node's log level if set")
+	f.String(prefix+".create", DefaultStakerLogLevelsConfig.Create, "log level of node creation and staking, overriding the node's log level if set")
+}
+
+func (c *StakerLogLevelsConfig) Validate() error {
+	for subsystem, level := range c.levels() {
+		if level == "" {
+			continue
+		}
+		if _, err := genericconf.ToSlogLevel(level); err != nil {
+			return fmt.Errorf("invalid %v log level: %w", subsystem, err)
+		}
+	}
+	return nil
+}
+
+func (c *StakerLogLevelsConfig) levels() map[string]string {
+	return map[string]string{
+		LogSubsystemChallenge:  c.Challenge,
+		LogSubsystemDataPoster: c.DataPoster,
+		LogSubsystemConfirm:    c.Confirm,
+		LogSubsystemCreate:     c.Create,
+	}
+}
+
+// NewSubsystemLogger returns a logger for one of the staker's subsystems,
+// filtered by the subsystem's currently configured log level, if any.
+func NewSubsystemLogger(subsystem string, config L1ValidatorConfigFetcher) log.Logger {
+	return util.NewSubsystemLogger(subsystem, func() (slog.Level, bool) {
+		level := config().LogLevels.levels()[subsystem]
+		if level == "" {
+			return 0, false
+		}
+		slogLevel, err := genericconf.ToSlogLevel(level)
+		if err != nil {
+			return 0, false
+		}
+		return slogLevel, true
+	})
+}
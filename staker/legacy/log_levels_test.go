@@ -0,0 +1,87 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/util"
+)
+
+// capturingHandler records the messages of every record it handles
+type capturingHandler struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (h *capturingHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *capturingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.messages = append(h.messages, record.Message)
+	return nil
+}
+
+func (h *capturingHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *capturingHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func (h *capturingHandler) captured() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return slices.Clone(h.messages)
+}
+
+func TestStakerLogLevels(t *testing.T) {
+	capture := &capturingHandler{}
+	glogger := log.NewGlogHandler(capture)
+	glogger.Verbosity(log.LevelInfo)
+	prevRoot := log.Root()
+	log.SetDefault(log.NewLogger(glogger))
+	defer log.SetDefault(prevRoot)
+	util.SetLogOutputHandler(capture)
+
+	config := TestL1ValidatorConfig
+	config.LogLevels.Challenge = "trace"
+	Require(t, config.Validate())
+	fetcher := func() *L1ValidatorConfig { return &config }
+	challengeLog := NewSubsystemLogger(LogSubsystemChallenge, fetcher)
+	confirmLog := NewSubsystemLogger(LogSubsystemConfirm, fetcher)
+	createLog := NewSubsystemLogger(LogSubsystemCreate, fetcher)
+
+	challengeLog.Trace("challenge trace")
+	confirmLog.Trace("confirm trace")
+	createLog.Trace("create trace")
+	confirmLog.Info("confirm info")
+	if captured := capture.captured(); !slices.Equal(captured, []string{"challenge trace", "confirm info"}) {
+		Fail(t, "unexpected logs with only the challenge subsystem at trace level", captured)
+	}
+
+	// levels are hot reloadable
+	config.LogLevels.Challenge = "error"
+	config.LogLevels.Confirm = "debug"
+	challengeLog.Warn("challenge warn")
+	confirmLog.Debug("confirm debug")
+	if captured := capture.captured(); !slices.Equal(captured, []string{"challenge trace", "confirm info", "confirm debug"}) {
+		Fail(t, "unexpected logs after reloading subsystem log levels", captured)
+	}
+
+	config.LogLevels.Create = "loud"
+	if config.Validate() == nil {
+		Fail(t, "expected invalid create log level to fail validation")
+	}
+}
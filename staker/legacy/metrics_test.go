@@ -0,0 +1,122 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+type recordingMetricsSink struct {
+	mutex  sync.Mutex
+	gauges map[string]float64
+}
+
+func newRecordingMetricsSink() *recordingMetricsSink {
+	return &recordingMetricsSink{gauges: make(map[string]float64)}
+}
+
+func (s *recordingMetricsSink) UpdateGauge(name string, value int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.gauges[name] = float64(value)
+}
+
+func (s *recordingMetricsSink) UpdateGaugeFloat64(name string, value float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.gauges[name] = value
+}
+
+func (s *recordingMetricsSink) IncCounter(name string, delta int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.gauges[name] += float64(delta)
+}
+
+func (s *recordingMetricsSink) UpdateHistogram(name string, value int64) {
+	s.UpdateGauge(name, value)
+}
+
+type noSenderWallet struct {
+	ValidatorWalletInterface
+}
+
+func (w *noSenderWallet) TxSenderAddress() *common.Address {
+	return nil
+}
+
+func TestStakerMetricsReachCustomSink(t *testing.T) {
+	sink := newRecordingMetricsSink()
+	var options stakerOptions
+	WithMetricsSink(sink)(&options)
+	s := &Staker{
+		L1Validator: &L1Validator{wallet: &noSenderWallet{}},
+		metrics:     options.metricsSink,
+	}
+	sink.gauges[stakerBalanceMetric] = 1
+	s.updateStakerBalanceMetric(context.Background())
+	value, ok := sink.gauges[stakerBalanceMetric]
+	if !ok || value != 0 {
+		Fail(t, "staker balance metric did not reach custom sink", value)
+	}
+}
+
+func TestSpendTrackerRunway(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	tracker := newSpendTracker(start)
+	gwei := big.NewInt(params.GWei)
+	balance := arbmath.BigMulByUint(gwei, 1_000_000)
+
+	estimate := tracker.estimate(balance, start.Add(time.Hour), 24*time.Hour)
+	if estimate.SpendKnown {
+		Fail(t, "expected no runway estimate before anything was spent")
+	}
+
+	// 4 actions costing 1000 gwei each over the first 2 hours
+	for i := 0; i < 4; i++ {
+		tracker.record(start.Add(time.Duration(i)*30*time.Minute), arbmath.BigMulByUint(gwei, 1000))
+	}
+	estimate = tracker.estimate(balance, start.Add(2*time.Hour), 24*time.Hour)
+	if !estimate.SpendKnown || estimate.AverageActionCost.Cmp(arbmath.BigMulByUint(gwei, 1000)) != 0 {
+		Fail(t, "unexpected average action cost", estimate.AverageActionCost)
+	}
+	if estimate.ActionsRemaining != 1000 {
+		Fail(t, "expected 1000 actions remaining, got", estimate.ActionsRemaining)
+	}
+	// spending 4000 gwei every 2 hours, a million gwei lasts 500 hours
+	if estimate.TimeRemaining != 500*time.Hour {
+		Fail(t, "expected 500 hours remaining, got", estimate.TimeRemaining)
+	}
+
+	// once the actions fall out of the window, the spend rate is unknown again
+	estimate = tracker.estimate(balance, start.Add(26*time.Hour), 24*time.Hour)
+	if estimate.SpendKnown {
+		Fail(t, "expected spend outside the window to be forgotten, got", estimate)
+	}
+
+	sink := newRecordingMetricsSink()
+	config := DefaultL1ValidatorConfig
+	s := &Staker{
+		config:  func() *L1ValidatorConfig { return &config },
+		metrics: sink,
+		spend:   newSpendTracker(time.Now().Add(-time.Hour)),
+	}
+	s.spend.record(time.Now(), arbmath.BigMulByUint(gwei, 1000))
+	s.updateRunwayMetrics(balance)
+	if sink.gauges[stakerRunwayActionsMetric] != 1000 {
+		Fail(t, "unexpected runway actions metric", sink.gauges[stakerRunwayActionsMetric])
+	}
+	if seconds := sink.gauges[stakerRunwaySecondsMetric]; seconds < 999*3600 || seconds > 1001*3600 {
+		Fail(t, "unexpected runway seconds metric", seconds)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrStakerNotRunning is returned by Poke when the staker hasn't been started or was already stopped.
+var ErrStakerNotRunning = errors.New("staker isn't running")
+
+// PokeResult is the outcome of an act cycle triggered with Poke.
+type PokeResult struct {
+	// Tx is the last transaction the act cycle posted, if any
+	Tx *types.Transaction
+	// Reason explains why the act cycle didn't post a transaction, if Tx is nil
+	Reason string
+}
+
+// Poke immediately runs an act cycle, e.g. after topping up the staker's funds, rather than waiting
+// for the loop's next poll. The cycle is run like one of the loop's and serialized with them: it waits
+// for a pending transaction of the loop to be approved before acting, and for its own before returning.
+func (s *Staker) Poke(ctx context.Context) (PokeResult, error) {
+	if !s.Started() || s.Stopped() {
+		return PokeResult{}, ErrStakerNotRunning
+	}
+	tx, err := s.actCycle(ctx)
+	result, err := pokeResult(tx, err, s.State())
+	if err != nil {
+		return result, err
+	}
+	if result.Tx != nil {
+		log.Info("staker poked into posting a transaction", "hash", result.Tx.Hash())
+	} else {
+		log.Info("staker poked but posted no transaction", "reason", result.Reason)
+	}
+	return result, nil
+}
+
+// pokeResult converts the outcome of an act cycle, which ended in state, into a PokeResult.
+// Waiting for a pending transaction isn't a failure of the poke, so it's reported as a reason instead.
+func pokeResult(tx *types.Transaction, err error, state StakerState) (PokeResult, error) {
+	if errors.Is(err, ErrDataPosterNotReady) {
+		return PokeResult{Reason: err.Error()}, nil
+	}
+	if err != nil {
+		return PokeResult{Tx: tx}, err
+	}
+	if tx != nil {
+		return PokeResult{Tx: tx}, nil
+	}
+	return PokeResult{Reason: fmt.Sprintf("nothing to do while %v", state)}, nil
+}
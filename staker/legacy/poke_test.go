@@ -0,0 +1,159 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestPokeRequiresRunningStaker(t *testing.T) {
+	s := &Staker{}
+	if _, err := s.Poke(context.Background()); !errors.Is(err, ErrStakerNotRunning) {
+		Fail(t, "expected ErrStakerNotRunning poking an unstarted staker, got", err)
+	}
+}
+
+func TestPokeResult(t *testing.T) {
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 1})
+
+	result, err := pokeResult(tx, nil, StakerStateCreating)
+	Require(t, err)
+	if result.Tx != tx || result.Reason != "" {
+		Fail(t, "unexpected result for posted transaction", result)
+	}
+
+	result, err = pokeResult(nil, nil, StakerStateIdle)
+	Require(t, err)
+	if result.Tx != nil || result.Reason != "nothing to do while idle" {
+		Fail(t, "unexpected result for idle act", result)
+	}
+
+	pending := fmt.Errorf("%w: data poster nonce 2 is ahead of on-chain nonce 1", ErrDataPosterNotReady)
+	result, err = pokeResult(nil, pending, StakerStateIdle)
+	Require(t, err)
+	if result.Tx != nil || result.Reason != pending.Error() {
+		Fail(t, "expected pending transaction reported as reason", result)
+	}
+
+	failed := errors.New("l1 unreachable")
+	if _, err = pokeResult(nil, failed, StakerStateIdle); !errors.Is(err, failed) {
+		Fail(t, "expected act error to be returned, got", err)
+	}
+}
+
+func TestPokeConcurrentWithLoopDoesNotDoubleSubmit(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultL1ValidatorConfig
+	config.Strategy = "MakeNodes"
+	Require(t, config.Validate())
+	sink := newRecordingMetricsSink()
+	s := &Staker{
+		L1Validator: &L1Validator{config: func() *L1ValidatorConfig { return &config }},
+		metrics:     sink,
+		spend:       newSpendTracker(time.Now()),
+	}
+
+	var nonce atomic.Uint64
+	var pending atomic.Bool
+	var doubleSubmits atomic.Int64
+	act := func(context.Context) (*types.Transaction, error) {
+		if pending.Load() {
+			doubleSubmits.Add(1)
+		}
+		pending.Store(true)
+		return types.NewTx(&types.DynamicFeeTx{Nonce: nonce.Add(1)}), nil
+	}
+	waitForApproval := func(context.Context, *types.Transaction) (*types.Receipt, error) {
+		time.Sleep(time.Millisecond)
+		pending.Store(false)
+		return &types.Receipt{GasUsed: 21000, EffectiveGasPrice: big.NewInt(1)}, nil
+	}
+
+	// the loop and pokes act at the same time
+	const cycles = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*cycles)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < cycles; j++ {
+				_, err := s.runActCycle(ctx, act, waitForApproval)
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		Require(t, err)
+	}
+	if doubleSubmits.Load() != 0 {
+		Fail(t, "acted", doubleSubmits.Load(), "times while a transaction was pending")
+	}
+	// every cycle went through the loop's outcome path
+	if sink.gauges[stakerActionSuccessMetric] != 2*cycles {
+		Fail(t, "expected", 2*cycles, "successful acts recorded, got", sink.gauges[stakerActionSuccessMetric])
+	}
+	if len(s.spend.samples) != 2*cycles {
+		Fail(t, "expected the spend of", 2*cycles, "cycles to be recorded, got", len(s.spend.samples))
+	}
+}
+
+func TestActCycleSetsLastNodePostedOnSuccess(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultL1ValidatorConfig
+	config.Strategy = "MakeNodes"
+	Require(t, config.Validate())
+	s := &Staker{
+		L1Validator: &L1Validator{config: func() *L1ValidatorConfig { return &config }},
+		metrics:     newRecordingMetricsSink(),
+		spend:       newSpendTracker(time.Now()),
+	}
+	postNode := func(context.Context) (*types.Transaction, error) {
+		s.nodePostedInAct = true
+		return types.NewTx(&types.DynamicFeeTx{}), nil
+	}
+	failed := errors.New("transaction reverted")
+	reverted := func(context.Context, *types.Transaction) (*types.Receipt, error) {
+		return nil, failed
+	}
+	approved := func(context.Context, *types.Transaction) (*types.Receipt, error) {
+		return &types.Receipt{GasUsed: 21000, EffectiveGasPrice: big.NewInt(1)}, nil
+	}
+
+	// a queued node which never made it on chain doesn't hold off the next one
+	if _, err := s.runActCycle(ctx, postNode, reverted); !errors.Is(err, failed) {
+		Fail(t, "expected the failed transaction's error, got", err)
+	}
+	if !s.lastNodePosted.IsZero() {
+		Fail(t, "failed node creation set the last node posted time", s.lastNodePosted)
+	}
+
+	before := time.Now()
+	_, err := s.runActCycle(ctx, postNode, approved)
+	Require(t, err)
+	if s.lastNodePosted.Before(before) {
+		Fail(t, "successful node creation didn't set the last node posted time")
+	}
+
+	// an act posting other transactions, e.g. a confirmation, leaves it be
+	posted := s.lastNodePosted
+	_, err = s.runActCycle(ctx, func(context.Context) (*types.Transaction, error) {
+		return types.NewTx(&types.DynamicFeeTx{}), nil
+	}, approved)
+	Require(t, err)
+	if s.lastNodePosted != posted {
+		Fail(t, "act without a new node changed the last node posted time")
+	}
+}
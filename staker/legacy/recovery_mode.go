@@ -0,0 +1,100 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+// defaultMaxAdvances is the number of times the stake is advanced in one act, outside of recovery mode
+// and of the makeNodesAggressive strategy.
+const defaultMaxAdvances = 20
+
+// recoveryMode tracks whether the staker is catching up on a large backlog of unresolved nodes, as found
+// by its first act, e.g. after a long downtime. Recovery mode lasts until the backlog falls below the
+// threshold it was entered at, or recovery mode is disabled.
+type recoveryMode struct {
+	checked bool
+	active  bool
+}
+
+// update records the backlog of unresolved nodes found by an act, returning whether the act is in recovery
+// mode, and whether it just entered or exited it. A threshold of 0 disables recovery mode.
+func (r *recoveryMode) update(backlog uint64, threshold uint64) (active bool, entered bool, exited bool) {
+	first := !r.checked
+	r.checked = true
+	if r.active && (threshold == 0 || backlog < threshold) {
+		r.active = false
+		return false, false, true
+	}
+	if first && threshold > 0 && backlog >= threshold {
+		r.active = true
+		return true, true, false
+	}
+	return r.active, false, false
+}
+
+// actPacing limits the work done by a single act.
+type actPacing struct {
+	// whether to defend and open challenges before any routine work, and to act on nothing else if there's any
+	safetyFirst      bool
+	maxConfirmations uint64
+	maxAdvances      uint64
+}
+
+// paceAct returns the limits of an act. In recovery mode the catch-up work is spread over several acts:
+// safety-critical challenge moves are made before anything else, and only a node is confirmed and the stake
+// advanced a few times per act. Acts as a watchtower, including downgraded ones, have no routine work to defer
+// challenges for, so they handle challenges as usual.
+func paceAct(cfg *L1ValidatorConfig, effectiveStrategy StakerStrategy, recovering bool) actPacing {
+	if recovering {
+		return actPacing{
+			safetyFirst:      effectiveStrategy > WatchtowerStrategy,
+			maxConfirmations: 1,
+			maxAdvances:      cfg.RecoveryStakeAdvances,
+		}
+	}
+	pacing := actPacing{
+		maxConfirmations: cfg.MaxConfirmationsPerAct,
+		maxAdvances:      defaultMaxAdvances,
+	}
+	if effectiveStrategy == MakeNodesAggressiveStrategy {
+		pacing.maxAdvances = cfg.AggressiveDepth
+	}
+	return pacing
+}
+
+// checkRecoveryMode returns whether the act is in recovery mode, given the latest confirmed node.
+func (s *Staker) checkRecoveryMode(callOpts *bind.CallOpts, latestConfirmed uint64, cfg *L1ValidatorConfig) (bool, error) {
+	var backlog uint64
+	if cfg.RecoveryBacklogNodes > 0 {
+		latestNodeCreated, err := s.rollup.LatestNodeCreated(callOpts)
+		if err != nil {
+			return false, err
+		}
+		backlog = arbmath.SaturatingUSub(latestNodeCreated, latestConfirmed)
+	}
+	return s.recordRecoveryBacklog(backlog, cfg), nil
+}
+
+// recordRecoveryBacklog records the backlog of unresolved nodes found by an act, returning whether the act is in recovery mode.
+func (s *Staker) recordRecoveryBacklog(backlog uint64, cfg *L1ValidatorConfig) bool {
+	active, entered, exited := s.recovery.update(backlog, cfg.RecoveryBacklogNodes)
+	if entered {
+		log.Warn(
+			"staker starting with a large backlog of unresolved nodes, pacing catch-up work in recovery mode",
+			"backlog", backlog,
+			"threshold", cfg.RecoveryBacklogNodes,
+			"stakeAdvancesPerAct", cfg.RecoveryStakeAdvances,
+		)
+		s.metrics.UpdateGauge(stakerRecoveryMetric, 1)
+	} else if exited {
+		log.Info("staker caught up on its backlog, leaving recovery mode", "backlog", backlog)
+		s.metrics.UpdateGauge(stakerRecoveryMetric, 0)
+	}
+	return active
+}
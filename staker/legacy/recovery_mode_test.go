@@ -0,0 +1,68 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"testing"
+)
+
+func TestRecoveryModeBacklog(t *testing.T) {
+	config := TestL1ValidatorConfig
+	config.Strategy = "MakeNodes"
+	config.MaxConfirmationsPerAct = 10
+	config.RecoveryBacklogNodes = 50
+	config.RecoveryStakeAdvances = 2
+	Require(t, config.Validate())
+	sink := newRecordingMetricsSink()
+	s := &Staker{metrics: sink}
+
+	// the staker comes back to a backlog of 100 unresolved nodes, and stays in recovery mode until it falls below 50
+	if !s.recordRecoveryBacklog(100, &config) {
+		Fail(t, "expected a large backlog to enter recovery mode")
+	}
+	if sink.gauges[stakerRecoveryMetric] != 1 {
+		Fail(t, "unexpected recovery metric in recovery mode", sink.gauges[stakerRecoveryMetric])
+	}
+	pacing := paceAct(&config, config.StrategyType(), true)
+	if !pacing.safetyFirst || pacing.maxConfirmations != 1 || pacing.maxAdvances != config.RecoveryStakeAdvances {
+		Fail(t, "unexpected pacing in recovery mode", pacing)
+	}
+	if !s.recordRecoveryBacklog(config.RecoveryBacklogNodes, &config) {
+		Fail(t, "left recovery mode before the backlog fell below the threshold")
+	}
+	if s.recordRecoveryBacklog(config.RecoveryBacklogNodes-1, &config) {
+		Fail(t, "still in recovery mode after the backlog fell below the threshold")
+	}
+	if sink.gauges[stakerRecoveryMetric] != 0 {
+		Fail(t, "unexpected recovery metric after catching up", sink.gauges[stakerRecoveryMetric])
+	}
+
+	// recovery mode is only entered by the first act
+	if s.recordRecoveryBacklog(100, &config) {
+		Fail(t, "entered recovery mode after the first act")
+	}
+	fresh := &Staker{metrics: newRecordingMetricsSink()}
+	if fresh.recordRecoveryBacklog(config.RecoveryBacklogNodes-1, &config) {
+		Fail(t, "entered recovery mode with a backlog below the threshold")
+	}
+
+	// watchtower acts, such as those of a downgraded staker, handle challenges as usual
+	if paceAct(&config, WatchtowerStrategy, true).safetyFirst {
+		Fail(t, "watchtower act put challenges first in recovery mode")
+	}
+
+	// disabling recovery mode ends it
+	fresh = &Staker{metrics: newRecordingMetricsSink()}
+	if !fresh.recordRecoveryBacklog(100, &config) {
+		Fail(t, "expected a large backlog to enter recovery mode")
+	}
+	config.RecoveryBacklogNodes = 0
+	if fresh.recordRecoveryBacklog(100, &config) {
+		Fail(t, "expected disabling recovery mode to end it")
+	}
+	pacing = paceAct(&config, config.StrategyType(), false)
+	if pacing.safetyFirst || pacing.maxConfirmations != config.MaxConfirmationsPerAct || pacing.maxAdvances != defaultMaxAdvances {
+		Fail(t, "unexpected pacing outside of recovery mode", pacing)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrRollupPaused is returned when the rollup contract is paused, and the staker is configured to error in that case.
+var ErrRollupPaused = errors.New("rollup is paused")
+
+// PausedRollupAction determines what the staker does while the rollup contract is paused.
+type PausedRollupAction uint8
+
+const (
+	// PausedRollupActionWait stops posting until the rollup is unpaused, checking again on every act.
+	PausedRollupActionWait PausedRollupAction = iota
+	// PausedRollupActionError fails the act with ErrRollupPaused.
+	PausedRollupActionError
+)
+
+func ParsePausedRollupAction(action string) (PausedRollupAction, error) {
+	switch strings.ToLower(action) {
+	case "wait":
+		return PausedRollupActionWait, nil
+	case "error":
+		return PausedRollupActionError, nil
+	default:
+		return PausedRollupActionWait, fmt.Errorf("unknown paused rollup action \"%v\"", action)
+	}
+}
+
+type rollupPausedReader interface {
+	Paused(opts *bind.CallOpts) (bool, error)
+}
+
+// checkRollupPaused returns true if the rollup contract is paused, in which case the staker shouldn't post
+// anything this act. Pausing and unpausing are logged once, rather than on every act while paused.
+func (s *Staker) checkRollupPaused(ctx context.Context) (bool, error) {
+	callOpts := s.baseCallOpts
+	callOpts.Context = ctx
+	paused, err := s.pausedRollup.Paused(&callOpts)
+	if err != nil {
+		return false, fmt.Errorf("error checking if rollup is paused: %w", err)
+	}
+	wasPaused := s.rollupPaused
+	s.rollupPaused = paused
+	if !paused {
+		if wasPaused {
+			log.Info("rollup was unpaused, staker resuming")
+		}
+		return false, nil
+	}
+	if s.config().PausedRollupActionType() == PausedRollupActionError {
+		return true, ErrRollupPaused
+	}
+	if !wasPaused {
+		log.Warn("rollup is paused, staker waiting until it's unpaused")
+	}
+	return true, nil
+}
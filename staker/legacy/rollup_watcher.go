@@ -23,6 +23,7 @@ import (
 
 	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
+	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/headerreader"
 )
 
@@ -83,6 +84,22 @@ func (r *RollupWatcher) getCallOpts(ctx context.Context) *bind.CallOpts {
 	return &opts
 }
 
+// atBlock returns a watcher reading the rollup's state as of the given parent chain block.
+func (r *RollupWatcher) atBlock(blockNumber *big.Int) *RollupWatcher {
+	opts := r.baseCallOpts
+	opts.BlockNumber = blockNumber
+	pinned := &RollupWatcher{
+		RollupUserLogic: r.RollupUserLogic,
+		address:         r.address,
+		fromBlock:       r.fromBlock,
+		client:          r.client,
+		baseCallOpts:    opts,
+	}
+	pinned.unSupportedL3Method.Store(r.unSupportedL3Method.Load())
+	pinned.supportedL3Method.Store(r.supportedL3Method.Load())
+	return pinned
+}
+
 const noNodeErr string = "NO_NODE"
 
 func looksLikeNoNodeError(err error) bool {
@@ -180,7 +197,7 @@ func (r *RollupWatcher) LookupNode(ctx context.Context, number uint64) (*NodeInf
 		return nil, err
 	}
 	if len(logs) == 0 {
-		return nil, fmt.Errorf("couldn't find requested node %v", number)
+		return nil, fmt.Errorf("%w %v", ErrNodeNotFound, number)
 	}
 	if len(logs) > 1 {
 		return nil, fmt.Errorf("found multiple instances of requested node %v", number)
@@ -207,53 +224,99 @@ func (r *RollupWatcher) LookupNode(ctx context.Context, number uint64) (*NodeInf
 }
 
 func (r *RollupWatcher) LookupNodeChildren(ctx context.Context, nodeNum uint64, logQueryRangeSize uint64, nodeHash common.Hash) ([]*NodeInfo, error) {
+	infos, _, err := r.scanNodeChildren(ctx, &nodeChildrenScan{}, nodeNum, nodeHash, logQueryRangeSize, 0)
+	return infos, err
+}
+
+// nodeChildrenScan is the progress of a search for a node's children through the parent chain blocks
+// they may have been created in, so that a search bounded in blocks can be resumed later.
+type nodeChildrenScan struct {
+	nodeNum   uint64
+	nodeHash  common.Hash
+	nextBlock *big.Int
+	logs      []types.Log
+}
+
+// advance reads the logs matching query from the scan's next block up to toBlock, in ranges of at most
+// logQueryRangeSize blocks, stopping once maxBlocks blocks are read (0 = unlimited).
+// Returns whether every block up to toBlock has been read.
+func (s *nodeChildrenScan) advance(
+	ctx context.Context,
+	filterLogs func(context.Context, ethereum.FilterQuery) ([]types.Log, error),
+	query ethereum.FilterQuery,
+	toBlock *big.Int,
+	logQueryRangeSize uint64,
+	maxBlocks uint64,
+) (bool, error) {
+	var scanned uint64
+	// break down the query to avoid eth_getLogs query limit
+	for s.nextBlock.Cmp(toBlock) <= 0 {
+		if maxBlocks > 0 && scanned >= maxBlocks {
+			return false, nil
+		}
+		query.FromBlock = s.nextBlock
+		query.ToBlock = toBlock
+		if logQueryRangeSize > 0 {
+			query.ToBlock = arbmath.BigMin(query.ToBlock, new(big.Int).Add(s.nextBlock, new(big.Int).SetUint64(logQueryRangeSize)))
+		}
+		if maxBlocks > 0 {
+			query.ToBlock = arbmath.BigMin(query.ToBlock, new(big.Int).Add(s.nextBlock, new(big.Int).SetUint64(maxBlocks-scanned-1)))
+		}
+		segment, err := filterLogs(ctx, query)
+		if err != nil {
+			return false, err
+		}
+		s.logs = append(s.logs, segment...)
+		scanned += new(big.Int).Sub(query.ToBlock, query.FromBlock).Uint64() + 1
+		s.nextBlock = new(big.Int).Add(query.ToBlock, big.NewInt(1))
+	}
+	return true, nil
+}
+
+// scanNodeChildren continues scan's search for the children of the given node, reading the logs of at most
+// maxBlocks parent chain blocks (0 = unlimited), and returns the children once the search is complete.
+// The search restarts if scan was for another node, or if the rollup no longer has a child it found (reorg).
+func (r *RollupWatcher) scanNodeChildren(ctx context.Context, scan *nodeChildrenScan, nodeNum uint64, nodeHash common.Hash, logQueryRangeSize uint64, maxBlocks uint64) ([]*NodeInfo, bool, error) {
 	node, err := r.RollupUserLogic.GetNode(r.getCallOpts(ctx), nodeNum)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	if node.LatestChildNumber == 0 {
-		return nil, nil
+		return nil, true, nil
 	}
 	if node.NodeHash != nodeHash {
-		return nil, fmt.Errorf("got unexpected node hash %v looking for node number %v with expected hash %v (reorg?)", node.NodeHash, nodeNum, nodeHash)
+		return nil, false, fmt.Errorf("got unexpected node hash %v looking for node number %v with expected hash %v (reorg?)", node.NodeHash, nodeNum, nodeHash)
 	}
-	var query = ethereum.FilterQuery{
-		Addresses: []common.Address{r.address},
-		Topics:    [][]common.Hash{{nodeCreatedID}, nil, {nodeHash}},
-	}
-	fromBlock, err := r.getNodeCreationBlock(ctx, nodeNum)
-	if err != nil {
-		return nil, err
+	if scan.nextBlock == nil || scan.nodeNum != nodeNum || scan.nodeHash != nodeHash {
+		fromBlock, err := r.getNodeCreationBlock(ctx, nodeNum)
+		if err != nil {
+			return nil, false, err
+		}
+		*scan = nodeChildrenScan{nodeNum: nodeNum, nodeHash: nodeHash, nextBlock: fromBlock}
 	}
 	toBlock, err := r.getNodeCreationBlock(ctx, node.LatestChildNumber)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	var logs []types.Log
-	// break down the query to avoid eth_getLogs query limit
-	for toBlock.Cmp(fromBlock) > 0 {
-		query.FromBlock = fromBlock
-		if logQueryRangeSize == 0 {
-			query.ToBlock = toBlock
-		} else {
-			query.ToBlock = new(big.Int).Add(fromBlock, new(big.Int).SetUint64(logQueryRangeSize))
-		}
-		if query.ToBlock.Cmp(toBlock) > 0 {
-			query.ToBlock = toBlock
-		}
-		segment, err := r.client.FilterLogs(ctx, query)
-		if err != nil {
-			return nil, err
-		}
-		logs = append(logs, segment...)
-		fromBlock = new(big.Int).Add(query.ToBlock, big.NewInt(1))
+	var query = ethereum.FilterQuery{
+		Addresses: []common.Address{r.address},
+		Topics:    [][]common.Hash{{nodeCreatedID}, nil, {nodeHash}},
+	}
+	complete, err := scan.advance(ctx, r.client.FilterLogs, query, toBlock, logQueryRangeSize, maxBlocks)
+	if err != nil || !complete {
+		return nil, false, err
 	}
-	infos := make([]*NodeInfo, 0, len(logs))
+	infos := make([]*NodeInfo, 0, len(scan.logs))
 	lastHash := nodeHash
-	for i, ethLog := range logs {
+	for i, ethLog := range scan.logs {
 		parsedLog, err := r.ParseNodeCreated(ethLog)
 		if err != nil {
-			return nil, err
+			return nil, false, err
+		}
+		if parsedLog.NodeNum > node.LatestChildNumber {
+			// the node was found in blocks since reorged out, so search from scratch next time
+			*scan = nodeChildrenScan{}
+			return nil, false, fmt.Errorf("found node %v child %v beyond its latest child %v (reorg?)", nodeNum, parsedLog.NodeNum, node.LatestChildNumber)
 		}
 		lastHashIsSibling := [1]byte{0}
 		if i > 0 {
@@ -262,7 +325,7 @@ func (r *RollupWatcher) LookupNodeChildren(ctx context.Context, nodeNum uint64,
 		lastHash = crypto.Keccak256Hash(lastHashIsSibling[:], lastHash[:], parsedLog.ExecutionHash[:], parsedLog.AfterInboxBatchAcc[:], parsedLog.WasmModuleRoot[:])
 		l1BlockProposed, err := arbutil.CorrespondingL1BlockNumber(ctx, r.client, ethLog.BlockNumber)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		infos = append(infos, &NodeInfo{
 			NodeNum:                  parsedLog.NodeNum,
@@ -275,7 +338,7 @@ func (r *RollupWatcher) LookupNodeChildren(ctx context.Context, nodeNum uint64,
 			WasmModuleRoot:           parsedLog.WasmModuleRoot,
 		})
 	}
-	return infos, nil
+	return infos, true, nil
 }
 
 func (r *RollupWatcher) LatestConfirmedCreationBlock(ctx context.Context) (uint64, error) {
@@ -0,0 +1,123 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RunwayEstimate is how long the staker's balance lasts if it keeps spending at its recent rate.
+type RunwayEstimate struct {
+	Balance *big.Int
+	// SpendKnown is false if the staker hasn't spent anything recently, leaving the other fields unset
+	SpendKnown        bool
+	AverageActionCost *big.Int
+	ActionsRemaining  uint64
+	TimeRemaining     time.Duration
+}
+
+type spendSample struct {
+	at   time.Time
+	cost *big.Int
+}
+
+// spendTracker records the cost of the staker's transactions over a sliding window.
+type spendTracker struct {
+	mutex   sync.Mutex
+	started time.Time
+	samples []spendSample
+}
+
+func newSpendTracker(now time.Time) *spendTracker {
+	return &spendTracker{started: now}
+}
+
+func (t *spendTracker) record(at time.Time, cost *big.Int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.samples = append(t.samples, spendSample{at: at, cost: cost})
+}
+
+// estimate extrapolates the spend within window before now to the given balance. If the tracker
+// started less than window ago, the spend rate is averaged over the time since it started instead.
+func (t *spendTracker) estimate(balance *big.Int, now time.Time, window time.Duration) RunwayEstimate {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	cutoff := now.Add(-window)
+	for len(t.samples) > 0 && t.samples[0].at.Before(cutoff) {
+		t.samples = t.samples[1:]
+	}
+	estimate := RunwayEstimate{Balance: balance}
+	spent := new(big.Int)
+	for _, sample := range t.samples {
+		spent.Add(spent, sample.cost)
+	}
+	if spent.Sign() <= 0 {
+		return estimate
+	}
+	elapsed := window
+	if started := now.Sub(t.started); started < elapsed {
+		elapsed = started
+	}
+	estimate.SpendKnown = true
+	estimate.AverageActionCost = new(big.Int).Div(spent, big.NewInt(int64(len(t.samples))))
+	actions := new(big.Int).Div(balance, estimate.AverageActionCost)
+	if actions.IsUint64() {
+		estimate.ActionsRemaining = actions.Uint64()
+	} else {
+		estimate.ActionsRemaining = math.MaxUint64
+	}
+	remaining := new(big.Int).Mul(balance, big.NewInt(int64(elapsed)))
+	remaining.Div(remaining, spent)
+	if remaining.IsInt64() {
+		estimate.TimeRemaining = time.Duration(remaining.Int64())
+	} else {
+		estimate.TimeRemaining = time.Duration(math.MaxInt64)
+	}
+	return estimate
+}
+
+// receiptCost returns the parent chain fee paid for a transaction.
+func receiptCost(receipt *types.Receipt) *big.Int {
+	if receipt == nil || receipt.EffectiveGasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+}
+
+// EstimateRunway estimates how many more actions, and how much longer, the staker's transaction sender
+// can keep acting with its current balance, at the rate it spent over the configured runway window.
+func (s *Staker) EstimateRunway(ctx context.Context) (*RunwayEstimate, error) {
+	txSenderAddress := s.wallet.TxSenderAddress()
+	if txSenderAddress == nil {
+		return nil, errors.New("staker has no transaction sender")
+	}
+	balance, err := s.client.BalanceAt(ctx, *txSenderAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting staker %v balance: %w", *txSenderAddress, err)
+	}
+	estimate := s.spend.estimate(balance, time.Now(), s.config().RunwayWindow)
+	return &estimate, nil
+}
+
+func (s *Staker) updateRunwayMetrics(balance *big.Int) {
+	if s.spend == nil {
+		return
+	}
+	estimate := s.spend.estimate(balance, time.Now(), s.config().RunwayWindow)
+	if !estimate.SpendKnown {
+		return
+	}
+	// #nosec G115
+	s.metrics.UpdateGauge(stakerRunwayActionsMetric, int64(min(estimate.ActionsRemaining, math.MaxInt64)))
+	s.metrics.UpdateGauge(stakerRunwaySecondsMetric, int64(estimate.TimeRemaining/time.Second))
+}
@@ -0,0 +1,83 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/staker"
+)
+
+type SendRootVerification struct {
+	Node             uint64
+	MessageCount     arbutil.MessageIndex
+	OnChainSendRoot  common.Hash
+	ComputedSendRoot common.Hash
+	Match            bool
+}
+
+// VerifyConfirmedNodeSendRoot validates the last message of a confirmed node and
+// compares the resulting send root with the one the node confirmed on-chain.
+func (s *Staker) VerifyConfirmedNodeSendRoot(ctx context.Context, nodeNum uint64) (*SendRootVerification, error) {
+	if s.statelessBlockValidator == nil {
+		return nil, errors.New("verifying send root requires a stateless block validator")
+	}
+	callOpts := s.getCallOpts(ctx)
+	latestConfirmed, err := s.rollup.LatestConfirmed(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest confirmed node: %w", err)
+	}
+	if nodeNum > latestConfirmed {
+		return nil, fmt.Errorf("node %d is not confirmed, latest confirmed node is %d", nodeNum, latestConfirmed)
+	}
+	nodeInfo, err := s.rollup.LookupNode(ctx, nodeNum)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up node %d: %w", nodeNum, err)
+	}
+	afterGS := nodeInfo.AfterState().GlobalState
+	caughtUp, msgCount, err := staker.GlobalStateToMsgCount(s.inboxTracker, s.txStreamer, afterGS)
+	if err != nil {
+		return nil, fmt.Errorf("error getting message count of node %d: %w", nodeNum, err)
+	}
+	if !caughtUp {
+		return nil, fmt.Errorf("%w to node %d", ErrNotCaughtUp, nodeNum)
+	}
+	if msgCount == 0 {
+		return nil, fmt.Errorf("node %d has no messages to validate", nodeNum)
+	}
+	moduleRoot, err := s.rollup.WasmModuleRoot(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting rollup wasm module root: %w", err)
+	}
+	valid, gs, err := s.statelessBlockValidator.ValidateResult(ctx, msgCount-1, false, moduleRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error validating last message of node %d: %w", nodeNum, err)
+	}
+	result := &SendRootVerification{
+		Node:            nodeNum,
+		MessageCount:    msgCount,
+		OnChainSendRoot: afterGS.SendRoot,
+	}
+	if gs != nil {
+		result.ComputedSendRoot = gs.SendRoot
+	}
+	result.Match = valid && result.ComputedSendRoot == result.OnChainSendRoot
+	if !result.Match {
+		log.Error(
+			"confirmed node send root doesn't match validated send root",
+			"node", nodeNum,
+			"msgCount", msgCount,
+			"onChain", result.OnChainSendRoot,
+			"computed", result.ComputedSendRoot,
+			"valid", valid,
+		)
+	}
+	return result, nil
+}
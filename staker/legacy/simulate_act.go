@@ -0,0 +1,121 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SimulatedNodeAction is what the staker would have done with its stake in a simulated act cycle.
+type SimulatedNodeAction uint8
+
+const (
+	// SimulatedNodeActionNone means the staker would have left its stake where it was
+	SimulatedNodeActionNone SimulatedNodeAction = iota
+	// SimulatedNodeActionStakeOnExisting means the staker would have staked on an existing node
+	SimulatedNodeActionStakeOnExisting
+	// SimulatedNodeActionCreate means the staker would have created a new node
+	SimulatedNodeActionCreate
+)
+
+func (a SimulatedNodeAction) String() string {
+	switch a {
+	case SimulatedNodeActionNone:
+		return "none"
+	case SimulatedNodeActionStakeOnExisting:
+		return "stake-on-existing"
+	case SimulatedNodeActionCreate:
+		return "create"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(a))
+	}
+}
+
+// SimulatedAct is the decision the staker would have made acting at a historical parent chain block.
+type SimulatedAct struct {
+	ParentChainBlock    uint64
+	Staked              bool
+	LatestStakedNode    uint64
+	LatestConfirmedNode uint64
+	// CatchingUp is set if the node hadn't caught up to the staked node, so no decision could be made
+	CatchingUp bool
+	Action     SimulatedNodeAction
+	// Node is the existing node the staker would have staked on, if Action is SimulatedNodeActionStakeOnExisting
+	Node       uint64
+	NodeHash   common.Hash
+	WrongNodes []uint64
+}
+
+// simulationAt returns a view of the validator reading the rollup's state as of the given parent chain block.
+// The view never changes the block validator, and searches the pinned block's nodes in full without
+// touching the validator's own search.
+func (v *L1Validator) simulationAt(blockNumber *big.Int) *L1Validator {
+	view := *v
+	view.callOpts.BlockNumber = blockNumber
+	view.rollup = v.rollup.atBlock(blockNumber)
+	view.childrenScan = nil
+	view.simulation = true
+	return &view
+}
+
+// SimulateActAt runs the staker's node decision logic against the rollup's state as of the given
+// parent chain block, returning what it would have done without posting any transaction.
+// The local chain and validation progress used are the node's current ones, not those at that block.
+func (s *Staker) SimulateActAt(ctx context.Context, parentChainBlock uint64) (*SimulatedAct, error) {
+	pinned := s.simulationAt(new(big.Int).SetUint64(parentChainBlock))
+	callOpts := pinned.getCallOpts(ctx)
+	cfg := s.config()
+
+	result := &SimulatedAct{ParentChainBlock: parentChainBlock}
+	walletAddressOrZero := s.wallet.AddressOrZero()
+	var rawInfo *StakerInfo
+	if walletAddressOrZero != (common.Address{}) {
+		var err error
+		rawInfo, err = pinned.rollup.StakerInfo(ctx, walletAddressOrZero)
+		if err != nil {
+			return nil, fmt.Errorf("error getting own staker (%v) info at block %v: %w", walletAddressOrZero, parentChainBlock, err)
+		}
+	}
+	latestStakedNodeNum, latestStakedNodeInfo, err := s.validatorUtils.LatestStaked(callOpts, s.rollupAddress, walletAddressOrZero)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest staked node of own wallet %v at block %v: %w", walletAddressOrZero, parentChainBlock, err)
+	}
+	result.LatestConfirmedNode, err = pinned.rollup.LatestConfirmed(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest confirmed node at block %v: %w", parentChainBlock, err)
+	}
+	if rawInfo != nil {
+		rawInfo.LatestStakedNode = latestStakedNodeNum
+	}
+	result.Staked = rawInfo != nil
+	result.LatestStakedNode = latestStakedNodeNum
+
+	info := OurStakerInfo{
+		CanProgress:          true,
+		LatestStakedNode:     latestStakedNodeNum,
+		LatestStakedNodeHash: latestStakedNodeInfo.NodeHash,
+		StakerInfo:           rawInfo,
+		StakeExists:          rawInfo != nil,
+	}
+	action, wrongNodes, err := pinned.generateNodeAction(ctx, &info, cfg.StrategyType(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error generating node action at block %v: %w", parentChainBlock, err)
+	}
+	result.CatchingUp = info.CatchingUp
+	result.WrongNodes = wrongNodes
+	switch action := action.(type) {
+	case createNodeAction:
+		result.Action = SimulatedNodeActionCreate
+		result.NodeHash = action.hash
+	case existingNodeAction:
+		result.Action = SimulatedNodeActionStakeOnExisting
+		result.Node = action.number
+		result.NodeHash = action.hash
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// splitL1Client routes read-only chain queries to a reader client,
+// while everything involved in building and posting transactions
+// (nonces, gas estimation, sending) goes to the writer client.
+type splitL1Client struct {
+	RollupWatcherL1Interface
+	reader RollupWatcherL1Interface
+}
+
+func newSplitL1Client(reader RollupWatcherL1Interface, writer RollupWatcherL1Interface) RollupWatcherL1Interface {
+	if reader == nil || reader == writer {
+		return writer
+	}
+	return &splitL1Client{
+		RollupWatcherL1Interface: writer,
+		reader:                   reader,
+	}
+}
+
+func (c *splitL1Client) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.reader.CodeAt(ctx, contract, blockNumber)
+}
+
+func (c *splitL1Client) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.reader.CallContract(ctx, call, blockNumber)
+}
+
+func (c *splitL1Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return c.reader.FilterLogs(ctx, q)
+}
+
+func (c *splitL1Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return c.reader.SubscribeFilterLogs(ctx, q, ch)
+}
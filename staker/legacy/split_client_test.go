@@ -0,0 +1,88 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type recordingL1Client struct {
+	RollupWatcherL1Interface
+	calls []string
+}
+
+func (c *recordingL1Client) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	c.calls = append(c.calls, "CallContract")
+	return nil, nil
+}
+
+func (c *recordingL1Client) FilterLogs(context.Context, ethereum.FilterQuery) ([]types.Log, error) {
+	c.calls = append(c.calls, "FilterLogs")
+	return nil, nil
+}
+
+func (c *recordingL1Client) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	c.calls = append(c.calls, "EstimateGas")
+	return 0, nil
+}
+
+func (c *recordingL1Client) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	c.calls = append(c.calls, "PendingNonceAt")
+	return 0, nil
+}
+
+func (c *recordingL1Client) SendTransaction(context.Context, *types.Transaction) error {
+	c.calls = append(c.calls, "SendTransaction")
+	return nil
+}
+
+func TestSplitL1ClientRoutesReadsAndPosts(t *testing.T) {
+	ctx := context.Background()
+	reader := &recordingL1Client{}
+	writer := &recordingL1Client{}
+	client := newSplitL1Client(reader, writer)
+
+	_, err := client.CallContract(ctx, ethereum.CallMsg{}, nil)
+	Require(t, err)
+	_, err = client.FilterLogs(ctx, ethereum.FilterQuery{})
+	Require(t, err)
+	_, err = client.EstimateGas(ctx, ethereum.CallMsg{})
+	Require(t, err)
+	_, err = client.PendingNonceAt(ctx, common.Address{})
+	Require(t, err)
+	err = client.SendTransaction(ctx, types.NewTx(&types.DynamicFeeTx{}))
+	Require(t, err)
+
+	expectedReads := []string{"CallContract", "FilterLogs"}
+	expectedWrites := []string{"EstimateGas", "PendingNonceAt", "SendTransaction"}
+	if len(reader.calls) != len(expectedReads) {
+		Fail(t, "unexpected read client calls", reader.calls)
+	}
+	for i, call := range expectedReads {
+		if reader.calls[i] != call {
+			Fail(t, "unexpected read client call", reader.calls[i], "expected", call)
+		}
+	}
+	if len(writer.calls) != len(expectedWrites) {
+		Fail(t, "unexpected write client calls", writer.calls)
+	}
+	for i, call := range expectedWrites {
+		if writer.calls[i] != call {
+			Fail(t, "unexpected write client call", writer.calls[i], "expected", call)
+		}
+	}
+}
+
+func TestSplitL1ClientSameClient(t *testing.T) {
+	client := &recordingL1Client{}
+	if newSplitL1Client(client, client) != RollupWatcherL1Interface(client) {
+		Fail(t, "expected identical read and write clients not to be wrapped")
+	}
+}
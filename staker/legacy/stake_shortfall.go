@@ -0,0 +1,68 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+// stakeShortfall returns how much amountStaked falls short of baseStake, or zero if it covers it.
+func stakeShortfall(amountStaked *big.Int, baseStake *big.Int) *big.Int {
+	shortfall := arbmath.BigSub(baseStake, amountStaked)
+	if shortfall.Sign() < 0 {
+		return new(big.Int)
+	}
+	return shortfall
+}
+
+// observeStakeShortfall reports in the stake shortfall metric how much the staker's stake falls short of the
+// rollup's base stake, e.g. after governance raised it, alerting if the staker is under-collateralized.
+func (s *Staker) observeStakeShortfall(staker common.Address, amountStaked *big.Int, baseStake *big.Int) *big.Int {
+	shortfall := stakeShortfall(amountStaked, baseStake)
+	s.metrics.UpdateGaugeFloat64(stakerStakeShortfallMetric, arbmath.BalancePerEther(shortfall))
+	if shortfall.Sign() > 0 {
+		log.Error(
+			"staker's stake is below the rollup's base stake",
+			"staker", staker,
+			"amountStaked", amountStaked,
+			"baseStake", baseStake,
+			"shortfall", shortfall,
+		)
+	}
+	return shortfall
+}
+
+// checkStakeShortfall compares the staker's stake to the rollup's base stake, adding the shortfall to the
+// stake if the staker is configured to top it up. The current required stake isn't used, as it's only
+// elevated temporarily for new stakes while nodes are created faster than they're resolved.
+func (s *Staker) checkStakeShortfall(ctx context.Context, info *StakerInfo, staker common.Address, topUp bool) error {
+	baseStake, err := s.rollup.BaseStake(s.getCallOpts(ctx))
+	if err != nil {
+		return fmt.Errorf("error getting base stake: %w", err)
+	}
+	shortfall := s.observeStakeShortfall(staker, info.AmountStaked, baseStake)
+	if shortfall.Sign() == 0 || !topUp {
+		return nil
+	}
+	decision, err := s.decideStake(ctx, staker, shortfall)
+	if err != nil {
+		return err
+	}
+	if decision != StakeDecisionProceed {
+		return nil
+	}
+	log.Info("topping up stake to the base stake", "staker", staker, "amount", shortfall)
+	_, err = s.rollup.AddToDeposit(s.builder.AuthWithAmount(ctx, shortfall), staker)
+	if err != nil {
+		return fmt.Errorf("error adding %v to the stake of %v: %w", shortfall, staker, err)
+	}
+	return nil
+}
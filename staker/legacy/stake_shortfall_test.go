@@ -0,0 +1,85 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestStakeShortfallAfterBaseStakeRaised(t *testing.T) {
+	sink := newRecordingMetricsSink()
+	s := &Staker{metrics: sink}
+	staker := common.HexToAddress("0x5a")
+	amountStaked := big.NewInt(params.Ether)
+
+	// staked at the base stake
+	baseStake := big.NewInt(params.Ether)
+	if shortfall := s.observeStakeShortfall(staker, amountStaked, baseStake); shortfall.Sign() != 0 {
+		Fail(t, "unexpected shortfall staked at the base stake", shortfall)
+	}
+	if sink.gauges[stakerStakeShortfallMetric] != 0 {
+		Fail(t, "unexpected stake shortfall metric", sink.gauges[stakerStakeShortfallMetric])
+	}
+
+	// governance raises the base stake to 2.5 ether
+	baseStake = new(big.Int).Div(big.NewInt(5*params.Ether), big.NewInt(2))
+	shortfall := s.observeStakeShortfall(staker, amountStaked, baseStake)
+	if expected := new(big.Int).Div(big.NewInt(3*params.Ether), big.NewInt(2)); shortfall.Cmp(expected) != 0 {
+		Fail(t, "expected a shortfall of", expected, "got", shortfall)
+	}
+	if sink.gauges[stakerStakeShortfallMetric] != 1.5 {
+		Fail(t, "expected the stake shortfall metric to report 1.5 ether, got", sink.gauges[stakerStakeShortfallMetric])
+	}
+
+	// topping up the stake clears the shortfall
+	amountStaked.Add(amountStaked, shortfall)
+	if shortfall := s.observeStakeShortfall(staker, amountStaked, baseStake); shortfall.Sign() != 0 {
+		Fail(t, "unexpected shortfall after topping up", shortfall)
+	}
+	if sink.gauges[stakerStakeShortfallMetric] != 0 {
+		Fail(t, "unexpected stake shortfall metric after topping up", sink.gauges[stakerStakeShortfallMetric])
+	}
+
+	// staking above the base stake isn't a negative shortfall
+	if shortfall := stakeShortfall(big.NewInt(3*params.Ether), baseStake); shortfall.Sign() != 0 {
+		Fail(t, "unexpected shortfall staked above the base stake", shortfall)
+	}
+}
+
+func TestStakeShortfallIgnoresElevatedRequiredStake(t *testing.T) {
+	ctx := context.Background()
+	rollupAddress := common.HexToAddress("0x1000")
+	staker := common.HexToAddress("0x5a")
+	amountStaked := big.NewInt(params.Ether)
+
+	baseStakeCall, err := rollupABI.Pack("baseStake")
+	Require(t, err)
+	baseStakeResult, err := rollupABI.Methods["baseStake"].Outputs.Pack(big.NewInt(params.Ether))
+	Require(t, err)
+	requiredStakeCall, err := rollupABI.Pack("currentRequiredStake")
+	Require(t, err)
+	// elevated while nodes are created faster than they're resolved
+	requiredStakeResult, err := rollupABI.Methods["currentRequiredStake"].Outputs.Pack(big.NewInt(3 * params.Ether))
+	Require(t, err)
+	client := newFakeEthClient(t, &fakeEthService{results: map[string]hexutil.Bytes{
+		string(baseStakeCall):     baseStakeResult,
+		string(requiredStakeCall): requiredStakeResult,
+	}})
+	rollup, err := NewRollupWatcher(rollupAddress, client, bind.CallOpts{})
+	Require(t, err)
+	sink := newRecordingMetricsSink()
+	s := &Staker{L1Validator: &L1Validator{rollup: rollup}, metrics: sink}
+
+	Require(t, s.checkStakeShortfall(ctx, &StakerInfo{AmountStaked: amountStaked}, staker, false))
+	if sink.gauges[stakerStakeShortfallMetric] != 0 {
+		Fail(t, "stake at the base stake reported as short of the elevated required stake", sink.gauges[stakerStakeShortfallMetric])
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrInsufficientStakeToken is returned when the staker's stake token balance doesn't cover
+// the stake it needs to place, and the staker is configured to error in that case.
+var ErrInsufficientStakeToken = errors.New("insufficient stake token balance")
+
+// StakeDecision is the outcome of checking whether the staker can place its initial stake.
+type StakeDecision uint8
+
+const (
+	StakeDecisionProceed StakeDecision = iota
+	// StakeDecisionAwaitingApproval means the stake approval hook didn't approve the stake yet
+	StakeDecisionAwaitingApproval
+	// StakeDecisionInsufficientStakeToken means the staker doesn't hold enough stake token to stake
+	StakeDecisionInsufficientStakeToken
+)
+
+func (d StakeDecision) String() string {
+	switch d {
+	case StakeDecisionProceed:
+		return "proceed"
+	case StakeDecisionAwaitingApproval:
+		return "awaiting-approval"
+	case StakeDecisionInsufficientStakeToken:
+		return "insufficient-stake-token"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(d))
+	}
+}
+
+// InsufficientStakeTokenAction determines what the staker does when it can't afford its stake.
+type InsufficientStakeTokenAction uint8
+
+const (
+	// InsufficientStakeTokenActionWait declines to stake, retrying on the next act.
+	InsufficientStakeTokenActionWait InsufficientStakeTokenAction = iota
+	// InsufficientStakeTokenActionError fails the act with ErrInsufficientStakeToken.
+	InsufficientStakeTokenActionError
+)
+
+func ParseInsufficientStakeTokenAction(action string) (InsufficientStakeTokenAction, error) {
+	switch strings.ToLower(action) {
+	case "wait":
+		return InsufficientStakeTokenActionWait, nil
+	case "error":
+		return InsufficientStakeTokenActionError, nil
+	default:
+		return InsufficientStakeTokenActionWait, fmt.Errorf("unknown insufficient stake token action \"%v\"", action)
+	}
+}
+
+const erc20BalanceOfABI = `[{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"balanceOf","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+var erc20BalanceOf abi.ABI
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(erc20BalanceOfABI))
+	if err != nil {
+		panic(err)
+	}
+	erc20BalanceOf = parsed
+}
+
+// StakeToken reads balances of an ERC-20 stake token.
+type StakeToken struct {
+	contract *bind.BoundContract
+}
+
+func NewStakeToken(address common.Address, caller bind.ContractCaller) *StakeToken {
+	return &StakeToken{
+		contract: bind.NewBoundContract(address, erc20BalanceOf, caller, nil, nil),
+	}
+}
+
+func (t *StakeToken) BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error) {
+	var out []interface{}
+	if err := t.contract.Call(opts, &out, "balanceOf", account); err != nil {
+		return nil, err
+	}
+	if len(out) != 1 {
+		return nil, fmt.Errorf("unexpected stake token balanceOf output length %d", len(out))
+	}
+	balance, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected stake token balanceOf output type %T", out[0])
+	}
+	return balance, nil
+}
+
+type stakeTokenBalanceReader interface {
+	BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error)
+}
+
+// decideStake checks whether staker can place a stake of amount: that it holds enough
+// stake token, if a stake token is configured, and that the stake approval hook approves it.
+func (s *Staker) decideStake(ctx context.Context, staker common.Address, amount *big.Int) (StakeDecision, error) {
+	if s.stakeToken != nil {
+		callOpts := s.baseCallOpts
+		callOpts.Context = ctx
+		balance, err := s.stakeToken.BalanceOf(&callOpts, staker)
+		if err != nil {
+			return StakeDecisionProceed, fmt.Errorf("error getting stake token balance of %v: %w", staker, err)
+		}
+		if balance.Cmp(amount) < 0 {
+			if s.config().InsufficientStakeTokenActionType() == InsufficientStakeTokenActionError {
+				return StakeDecisionInsufficientStakeToken, fmt.Errorf("%w: %v has %v, stake requires %v", ErrInsufficientStakeToken, staker, balance, amount)
+			}
+			log.Warn("insufficient stake token balance, not staking", "staker", staker, "balance", balance, "required", amount)
+			return StakeDecisionInsufficientStakeToken, nil
+		}
+	}
+	if !s.stakeApproved(ctx, amount) {
+		return StakeDecisionAwaitingApproval, nil
+	}
+	return StakeDecisionProceed, nil
+}
@@ -5,22 +5,28 @@ package legacystaker
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math/big"
 	"runtime/debug"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/btree"
 	flag "github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/offchainlabs/nitro/arbnode/dataposter"
@@ -31,27 +37,46 @@ import (
 	"github.com/offchainlabs/nitro/util"
 	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/headerreader"
+	"github.com/offchainlabs/nitro/util/metricsutil"
+	"github.com/offchainlabs/nitro/util/rpcclient"
 	"github.com/offchainlabs/nitro/util/stopwaiter"
 	"github.com/offchainlabs/nitro/validator"
 )
 
-var (
-	stakerBalanceGauge              = metrics.NewRegisteredGaugeFloat64("arb/staker/balance", nil)
-	stakerAmountStakedGauge         = metrics.NewRegisteredGauge("arb/staker/amount_staked", nil)
-	stakerLatestStakedNodeGauge     = metrics.NewRegisteredGauge("arb/staker/staked_node", nil)
-	stakerLatestConfirmedNodeGauge  = metrics.NewRegisteredGauge("arb/staker/confirmed_node", nil)
-	stakerLastSuccessfulActionGauge = metrics.NewRegisteredGauge("arb/staker/action/last_success", nil)
-	stakerActionSuccessCounter      = metrics.NewRegisteredCounter("arb/staker/action/success", nil)
-	stakerActionFailureCounter      = metrics.NewRegisteredCounter("arb/staker/action/failure", nil)
-	validatorGasRefunderBalance     = metrics.NewRegisteredGaugeFloat64("arb/validator/gasrefunder/balanceether", nil)
+// stakerMeterName names the OpenTelemetry meter of a staker reporting its metrics through one
+const stakerMeterName = "github.com/offchainlabs/nitro/staker/legacy"
+
+const (
+	stakerBalanceMetric               = "arb/staker/balance"
+	stakerAmountStakedMetric          = "arb/staker/amount_staked"
+	stakerLatestStakedNodeMetric      = "arb/staker/staked_node"
+	stakerLatestConfirmedNodeMetric   = "arb/staker/confirmed_node"
+	stakerLastSuccessfulActionMetric  = "arb/staker/action/last_success"
+	stakerActionSuccessMetric         = "arb/staker/action/success"
+	stakerActionFailureMetric         = "arb/staker/action/failure"
+	validatorGasRefunderBalanceMetric = "arb/validator/gasrefunder/balanceether"
+	stakerChallengeWithdrawnMetric    = "arb/staker/challenge/withdrawn"
+	stakerChallengeMoveGasMetric      = "arb/staker/challenge/move_gas_exceeded"
+	stakerChallengeFundsMetric        = "arb/staker/challenge/insufficient_funds"
+	stakerStateMetric                 = "arb/staker/state"
+	stakerRunwayActionsMetric         = "arb/staker/runway/actions"
+	stakerRunwaySecondsMetric         = "arb/staker/runway/seconds"
+	stakerConfirmedDivergenceMetric   = "arb/staker/confirmed_divergence"
+	stakerDowngradedMetric            = "arb/staker/downgraded"
+	stakerStakeShortfallMetric        = "arb/staker/stake_shortfall"
+	stakerRecoveryMetric              = "arb/staker/recovery"
 )
 
+// ErrActTimeout is returned when a staker act cycle is cancelled by its deadline
+var ErrActTimeout = errors.New("staker act cycle timed out")
+
 type StakerStrategy uint8
 
 const (
 	// Watchtower: don't do anything on L1, but log if there's a bad assertion
 	WatchtowerStrategy StakerStrategy = iota
 	// Defensive: stake if there's a bad assertion
+	// (the challengeOnly strategy is defensive too, but never creates a node, see IsChallengeOnlyStrategy)
 	DefensiveStrategy
 	// Stake latest: stay staked on the latest node, challenging bad assertions
 	StakeLatestStrategy
@@ -59,6 +84,9 @@ const (
 	ResolveNodesStrategy
 	// Make nodes: continually create new nodes, challenging bad assertions
 	MakeNodesStrategy
+	// Make nodes aggressively: like make nodes, but when behind, move through existing nodes and create
+	// several sequential nodes in one act, up to aggressive-depth of them and within aggressive-max-gas
+	MakeNodesAggressiveStrategy
 )
 
 type L1PostingStrategy struct {
@@ -77,35 +105,88 @@ func L1PostingStrategyAddOptions(prefix string, f *flag.FlagSet) {
 }
 
 type L1ValidatorConfig struct {
-	Enable                    bool                        `koanf:"enable"`
-	Strategy                  string                      `koanf:"strategy"`
-	StakerInterval            time.Duration               `koanf:"staker-interval"`
-	MakeAssertionInterval     time.Duration               `koanf:"make-assertion-interval"`
-	PostingStrategy           L1PostingStrategy           `koanf:"posting-strategy"`
-	DisableChallenge          bool                        `koanf:"disable-challenge"`
-	ConfirmationBlocks        int64                       `koanf:"confirmation-blocks"`
-	UseSmartContractWallet    bool                        `koanf:"use-smart-contract-wallet"`
-	OnlyCreateWalletContract  bool                        `koanf:"only-create-wallet-contract"`
-	StartValidationFromStaked bool                        `koanf:"start-validation-from-staked"`
-	ContractWalletAddress     string                      `koanf:"contract-wallet-address"`
-	GasRefunderAddress        string                      `koanf:"gas-refunder-address"`
-	DataPoster                dataposter.DataPosterConfig `koanf:"data-poster" reload:"hot"`
-	RedisUrl                  string                      `koanf:"redis-url"`
-	ExtraGas                  uint64                      `koanf:"extra-gas" reload:"hot"`
-	Dangerous                 DangerousConfig             `koanf:"dangerous"`
-	ParentChainWallet         genericconf.WalletConfig    `koanf:"parent-chain-wallet"`
-	LogQueryBatchSize         uint64                      `koanf:"log-query-batch-size" reload:"hot"`
-	EnableFastConfirmation    bool                        `koanf:"enable-fast-confirmation"`
-
-	strategy    StakerStrategy
-	gasRefunder common.Address
+	Enable                        bool                        `koanf:"enable"`
+	Strategy                      string                      `koanf:"strategy"`
+	StakerInterval                time.Duration               `koanf:"staker-interval"`
+	MakeAssertionInterval         time.Duration               `koanf:"make-assertion-interval"`
+	PostingStrategy               L1PostingStrategy           `koanf:"posting-strategy"`
+	DisableChallenge              bool                        `koanf:"disable-challenge"`
+	ConfirmationBlocks            int64                       `koanf:"confirmation-blocks"`
+	UseSmartContractWallet        bool                        `koanf:"use-smart-contract-wallet"`
+	OnlyCreateWalletContract      bool                        `koanf:"only-create-wallet-contract"`
+	StartValidationFromStaked     bool                        `koanf:"start-validation-from-staked"`
+	ContractWalletAddress         string                      `koanf:"contract-wallet-address"`
+	GasRefunderAddress            string                      `koanf:"gas-refunder-address"`
+	DataPoster                    dataposter.DataPosterConfig `koanf:"data-poster" reload:"hot"`
+	RedisUrl                      string                      `koanf:"redis-url"`
+	ExtraGas                      uint64                      `koanf:"extra-gas" reload:"hot"`
+	Dangerous                     DangerousConfig             `koanf:"dangerous"`
+	ParentChainWallet             genericconf.WalletConfig    `koanf:"parent-chain-wallet"`
+	LogQueryBatchSize             uint64                      `koanf:"log-query-batch-size" reload:"hot"`
+	EnableFastConfirmation        bool                        `koanf:"enable-fast-confirmation"`
+	ConfirmationSafetyDelayBlocks uint64                      `koanf:"confirmation-safety-delay-blocks" reload:"hot"`
+	ConfirmationStaggerBlocks     uint64                      `koanf:"confirmation-stagger-blocks" reload:"hot"`
+	ConfirmationMaturityNodes     uint64                      `koanf:"confirmation-maturity-nodes" reload:"hot"`
+	ConfirmationMaturityBlocks    uint64                      `koanf:"confirmation-maturity-blocks" reload:"hot"`
+	StakeAmountGwei               uint64                      `koanf:"stake-amount-gwei" reload:"hot"`
+	AgreedChallengeAction         string                      `koanf:"agreed-challenge-action"`
+	HeartbeatInterval             time.Duration               `koanf:"heartbeat-interval" reload:"hot"`
+	HeartbeatMaxCostGwei          uint64                      `koanf:"heartbeat-max-cost-gwei" reload:"hot"`
+	ChallengeMoveMaxGas           uint64                      `koanf:"challenge-move-max-gas" reload:"hot"`
+	ActTimeout                    time.Duration               `koanf:"act-timeout" reload:"hot"`
+	StakeTokenAddress             string                      `koanf:"stake-token-address"`
+	InsufficientStakeTokenAction  string                      `koanf:"insufficient-stake-token-action" reload:"hot"`
+	RescueZombieStake             bool                        `koanf:"rescue-zombie-stake" reload:"hot"`
+	ExitWithdrawalAddress         string                      `koanf:"exit-withdrawal-address"`
+	BatchActReads                 bool                        `koanf:"batch-act-reads" reload:"hot"`
+	LogLevels                     StakerLogLevelsConfig       `koanf:"log-levels" reload:"hot"`
+	PausedRollupAction            string                      `koanf:"paused-rollup-action" reload:"hot"`
+	RunwayWindow                  time.Duration               `koanf:"runway-window" reload:"hot"`
+	ConfirmedOnlyWatchtower       bool                        `koanf:"confirmed-only-watchtower" reload:"hot"`
+	VerifyConfirmedSendRoots      bool                        `koanf:"verify-confirmed-send-roots" reload:"hot"`
+	MaxScanBlocksPerAct           uint64                      `koanf:"max-scan-blocks-per-act" reload:"hot"`
+	AggressiveDepth               uint64                      `koanf:"aggressive-depth" reload:"hot"`
+	AggressiveMaxGas              uint64                      `koanf:"aggressive-max-gas" reload:"hot"`
+	ChallengeManagerAddress       string                      `koanf:"challenge-manager-address"`
+	DowngradeAfterFailures        uint64                      `koanf:"downgrade-after-failures" reload:"hot"`
+	DowngradeRetryInterval        time.Duration               `koanf:"downgrade-retry-interval" reload:"hot"`
+	MaxConfirmationsPerAct        uint64                      `koanf:"max-confirmations-per-act" reload:"hot"`
+	TopUpStake                    bool                        `koanf:"top-up-stake" reload:"hot"`
+	ChallengeMoveTopUp            bool                        `koanf:"challenge-move-top-up" reload:"hot"`
+	ChallengeMoveTopUpPrivateKey  string                      `koanf:"challenge-move-top-up-private-key"`
+	ChallengeMoveTopUpAmountGwei  uint64                      `koanf:"challenge-move-top-up-amount-gwei" reload:"hot"`
+	BackupEOAPrivateKey           string                      `koanf:"backup-eoa-private-key"`
+	RecoveryBacklogNodes          uint64                      `koanf:"recovery-backlog-nodes" reload:"hot"`
+	RecoveryStakeAdvances         uint64                      `koanf:"recovery-stake-advances" reload:"hot"`
+	Confirmer                     bool                        `koanf:"confirmer" reload:"hot"`
+	MinPostInterval               time.Duration               `koanf:"min-post-interval" reload:"hot"`
+	WalletCreationExtraGas        uint64                      `koanf:"wallet-creation-extra-gas" reload:"hot"`
+	ParentChainReadConnection     rpcclient.ClientConfig      `koanf:"parent-chain-read-connection"`
+	OtelMetrics                   bool                        `koanf:"otel-metrics"`
+
+	strategy                     StakerStrategy
+	challengeOnly                bool
+	agreedChallengeAction        AgreedChallengeAction
+	gasRefunder                  common.Address
+	stakeToken                   common.Address
+	challengeManager             common.Address
+	insufficientStakeTokenAction InsufficientStakeTokenAction
+	pausedRollupAction           PausedRollupAction
+	challengeMoveTopUpKey        *ecdsa.PrivateKey
+	backupEOAKey                 *ecdsa.PrivateKey
+}
+
+// IsChallengeOnlyStrategy returns whether strategy is the challengeOnly strategy, which is the defensive
+// strategy, staking on existing correct nodes to challenge bad assertions, but never creating a node.
+func IsChallengeOnlyStrategy(strategy string) bool {
+	return strings.EqualFold(strategy, "challengeOnly")
 }
 
 func ParseStrategy(strategy string) (StakerStrategy, error) {
 	switch strings.ToLower(strategy) {
 	case "watchtower":
 		return WatchtowerStrategy, nil
-	case "defensive":
+	case "defensive", "challengeonly":
 		return DefensiveStrategy, nil
 	case "stakelatest":
 		return StakeLatestStrategy, nil
@@ -113,6 +194,8 @@ func ParseStrategy(strategy string) (StakerStrategy, error) {
 		return ResolveNodesStrategy, nil
 	case "makenodes":
 		return MakeNodesStrategy, nil
+	case "makenodesaggressive":
+		return MakeNodesAggressiveStrategy, nil
 	default:
 		return WatchtowerStrategy, fmt.Errorf("unknown staker strategy \"%v\"", strategy)
 	}
@@ -125,7 +208,7 @@ func (c *L1ValidatorConfig) ValidatorRequired() bool {
 	if c.Dangerous.WithoutBlockValidator {
 		return false
 	}
-	if c.strategy == WatchtowerStrategy && !c.EnableFastConfirmation {
+	if c.strategy == WatchtowerStrategy && !c.EnableFastConfirmation && !c.Confirmer {
 		return false
 	}
 	return true
@@ -137,11 +220,76 @@ func (c *L1ValidatorConfig) Validate() error {
 		return err
 	}
 	c.strategy = strategy
+	c.challengeOnly = IsChallengeOnlyStrategy(c.Strategy)
+	c.agreedChallengeAction, err = ParseAgreedChallengeAction(c.AgreedChallengeAction)
+	if err != nil {
+		return err
+	}
 	if len(c.GasRefunderAddress) > 0 && !common.IsHexAddress(c.GasRefunderAddress) {
 		return errors.New("invalid validator gas refunder address")
 	}
 	c.gasRefunder = common.HexToAddress(c.GasRefunderAddress)
-	return nil
+	if len(c.StakeTokenAddress) > 0 && !common.IsHexAddress(c.StakeTokenAddress) {
+		return errors.New("invalid validator stake token address")
+	}
+	c.stakeToken = common.HexToAddress(c.StakeTokenAddress)
+	if len(c.ChallengeManagerAddress) > 0 && !common.IsHexAddress(c.ChallengeManagerAddress) {
+		return errors.New("invalid validator challenge manager address")
+	}
+	c.challengeManager = common.HexToAddress(c.ChallengeManagerAddress)
+	c.insufficientStakeTokenAction, err = ParseInsufficientStakeTokenAction(c.InsufficientStakeTokenAction)
+	if err != nil {
+		return err
+	}
+	c.pausedRollupAction, err = ParsePausedRollupAction(c.PausedRollupAction)
+	if err != nil {
+		return err
+	}
+	if c.ConfirmedOnlyWatchtower && c.strategy != WatchtowerStrategy {
+		return errors.New("confirmed-only-watchtower requires the watchtower strategy")
+	}
+	if c.Confirmer && (c.strategy != WatchtowerStrategy || c.ConfirmedOnlyWatchtower) {
+		return errors.New("confirmer requires the watchtower strategy, validating unconfirmed nodes")
+	}
+	if c.strategy == MakeNodesAggressiveStrategy && c.AggressiveDepth == 0 {
+		return errors.New("the makeNodesAggressive strategy requires a positive aggressive-depth")
+	}
+	if c.MaxConfirmationsPerAct == 0 {
+		return errors.New("max-confirmations-per-act must be at least 1")
+	}
+	if c.MinPostInterval < 0 {
+		return errors.New("min-post-interval must not be negative")
+	}
+	if c.RecoveryBacklogNodes > 0 && c.RecoveryStakeAdvances == 0 {
+		return errors.New("recovery mode requires a positive recovery-stake-advances")
+	}
+	if err := c.ParentChainReadConnection.Validate(); err != nil {
+		return fmt.Errorf("failed to validate staker parent-chain-read-connection config: %w", err)
+	}
+	c.challengeMoveTopUpKey = nil
+	if c.ChallengeMoveTopUpPrivateKey != "" {
+		c.challengeMoveTopUpKey, err = crypto.HexToECDSA(strings.TrimPrefix(c.ChallengeMoveTopUpPrivateKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid challenge move top-up private key: %w", err)
+		}
+	}
+	if c.ExitWithdrawalAddress != "" && !common.IsHexAddress(c.ExitWithdrawalAddress) {
+		return fmt.Errorf("invalid exit-withdrawal-address %q", c.ExitWithdrawalAddress)
+	}
+	c.backupEOAKey = nil
+	if c.BackupEOAPrivateKey != "" {
+		if !c.UseSmartContractWallet {
+			return errors.New("a backup EOA requires use-smart-contract-wallet")
+		}
+		if c.DataPoster.ExternalSigner.URL != "" {
+			return errors.New("a backup EOA isn't supported with an external signer")
+		}
+		c.backupEOAKey, err = crypto.HexToECDSA(strings.TrimPrefix(c.BackupEOAPrivateKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid backup EOA private key: %w", err)
+		}
+	}
+	return c.LogLevels.Validate()
 }
 
 func (c *L1ValidatorConfig) GasRefunder() common.Address {
@@ -151,48 +299,165 @@ func (c *L1ValidatorConfig) StrategyType() StakerStrategy {
 	return c.strategy
 }
 
+// BackupEOAKey returns the key of the EOA paying for the smart contract wallet's gas if its sender can't, if any.
+func (c *L1ValidatorConfig) BackupEOAKey() *ecdsa.PrivateKey {
+	return c.backupEOAKey
+}
+
+// WalletCreationGas returns the extra gas to create the validator smart contract wallet with,
+// which defaults to the extra gas of other transactions.
+func (c *L1ValidatorConfig) WalletCreationGas() uint64 {
+	if c.WalletCreationExtraGas == 0 {
+		return c.ExtraGas
+	}
+	return c.WalletCreationExtraGas
+}
+
+// ChallengeOnly returns whether the staker only ever challenges bad assertions, never creating a node.
+func (c *L1ValidatorConfig) ChallengeOnly() bool {
+	return c.challengeOnly
+}
+
+func (c *L1ValidatorConfig) AgreedChallengeActionType() AgreedChallengeAction {
+	return c.agreedChallengeAction
+}
+
+func (c *L1ValidatorConfig) StakeToken() common.Address {
+	return c.stakeToken
+}
+
+func (c *L1ValidatorConfig) ChallengeManager() common.Address {
+	return c.challengeManager
+}
+
+func (c *L1ValidatorConfig) InsufficientStakeTokenActionType() InsufficientStakeTokenAction {
+	return c.insufficientStakeTokenAction
+}
+
+func (c *L1ValidatorConfig) PausedRollupActionType() PausedRollupAction {
+	return c.pausedRollupAction
+}
+
 var DefaultL1ValidatorConfig = L1ValidatorConfig{
-	Enable:                    true,
-	Strategy:                  "Watchtower",
-	StakerInterval:            time.Minute,
-	MakeAssertionInterval:     time.Hour,
-	PostingStrategy:           L1PostingStrategy{},
-	DisableChallenge:          false,
-	ConfirmationBlocks:        12,
-	UseSmartContractWallet:    false,
-	OnlyCreateWalletContract:  false,
-	StartValidationFromStaked: true,
-	ContractWalletAddress:     "",
-	GasRefunderAddress:        "",
-	DataPoster:                dataposter.DefaultDataPosterConfigForValidator,
-	RedisUrl:                  "",
-	ExtraGas:                  50000,
-	Dangerous:                 DefaultDangerousConfig,
-	ParentChainWallet:         DefaultValidatorL1WalletConfig,
-	LogQueryBatchSize:         0,
-	EnableFastConfirmation:    false,
+	Enable:                        true,
+	Strategy:                      "Watchtower",
+	StakerInterval:                time.Minute,
+	MakeAssertionInterval:         time.Hour,
+	PostingStrategy:               L1PostingStrategy{},
+	DisableChallenge:              false,
+	ConfirmationBlocks:            12,
+	UseSmartContractWallet:        false,
+	OnlyCreateWalletContract:      false,
+	StartValidationFromStaked:     true,
+	ContractWalletAddress:         "",
+	GasRefunderAddress:            "",
+	DataPoster:                    dataposter.DefaultDataPosterConfigForValidator,
+	RedisUrl:                      "",
+	ExtraGas:                      50000,
+	Dangerous:                     DefaultDangerousConfig,
+	ParentChainWallet:             DefaultValidatorL1WalletConfig,
+	LogQueryBatchSize:             0,
+	EnableFastConfirmation:        false,
+	ConfirmationSafetyDelayBlocks: 0,
+	ConfirmationStaggerBlocks:     0,
+	ConfirmationMaturityNodes:     0,
+	ConfirmationMaturityBlocks:    0,
+	StakeAmountGwei:               0,
+	AgreedChallengeAction:         "error",
+	HeartbeatInterval:             0,
+	HeartbeatMaxCostGwei:          1_000_000,
+	ChallengeMoveMaxGas:           0,
+	ActTimeout:                    0,
+	StakeTokenAddress:             "",
+	InsufficientStakeTokenAction:  "wait",
+	RescueZombieStake:             false,
+	ExitWithdrawalAddress:         "",
+	BatchActReads:                 false,
+	LogLevels:                     DefaultStakerLogLevelsConfig,
+	PausedRollupAction:            "wait",
+	RunwayWindow:                  24 * time.Hour,
+	ConfirmedOnlyWatchtower:       false,
+	VerifyConfirmedSendRoots:      false,
+	MaxScanBlocksPerAct:           0,
+	AggressiveDepth:               50,
+	AggressiveMaxGas:              10_000_000,
+	ChallengeManagerAddress:       "",
+	DowngradeAfterFailures:        0,
+	DowngradeRetryInterval:        10 * time.Minute,
+	MaxConfirmationsPerAct:        1,
+	TopUpStake:                    false,
+	ChallengeMoveTopUp:            false,
+	ChallengeMoveTopUpPrivateKey:  "",
+	ChallengeMoveTopUpAmountGwei:  100_000_000,
+	BackupEOAPrivateKey:           "",
+	RecoveryBacklogNodes:          0,
+	RecoveryStakeAdvances:         10,
+	Confirmer:                     false,
+	MinPostInterval:               0,
+	WalletCreationExtraGas:        0,
+	ParentChainReadConnection:     DefaultParentChainReadConnectionConfig,
+	OtelMetrics:                   false,
 }
 
 var TestL1ValidatorConfig = L1ValidatorConfig{
-	Enable:                    true,
-	Strategy:                  "Watchtower",
-	StakerInterval:            time.Millisecond * 10,
-	MakeAssertionInterval:     -time.Hour * 1000,
-	PostingStrategy:           L1PostingStrategy{},
-	DisableChallenge:          false,
-	ConfirmationBlocks:        0,
-	UseSmartContractWallet:    false,
-	OnlyCreateWalletContract:  false,
-	StartValidationFromStaked: true,
-	ContractWalletAddress:     "",
-	GasRefunderAddress:        "",
-	DataPoster:                dataposter.TestDataPosterConfigForValidator,
-	RedisUrl:                  "",
-	ExtraGas:                  50000,
-	Dangerous:                 DefaultDangerousConfig,
-	ParentChainWallet:         DefaultValidatorL1WalletConfig,
-	LogQueryBatchSize:         0,
-	EnableFastConfirmation:    false,
+	Enable:                        true,
+	Strategy:                      "Watchtower",
+	StakerInterval:                time.Millisecond * 10,
+	MakeAssertionInterval:         -time.Hour * 1000,
+	PostingStrategy:               L1PostingStrategy{},
+	DisableChallenge:              false,
+	ConfirmationBlocks:            0,
+	UseSmartContractWallet:        false,
+	OnlyCreateWalletContract:      false,
+	StartValidationFromStaked:     true,
+	ContractWalletAddress:         "",
+	GasRefunderAddress:            "",
+	DataPoster:                    dataposter.TestDataPosterConfigForValidator,
+	RedisUrl:                      "",
+	ExtraGas:                      50000,
+	Dangerous:                     DefaultDangerousConfig,
+	ParentChainWallet:             DefaultValidatorL1WalletConfig,
+	LogQueryBatchSize:             0,
+	EnableFastConfirmation:        false,
+	ConfirmationSafetyDelayBlocks: 0,
+	ConfirmationStaggerBlocks:     0,
+	ConfirmationMaturityNodes:     0,
+	ConfirmationMaturityBlocks:    0,
+	StakeAmountGwei:               0,
+	AgreedChallengeAction:         "error",
+	HeartbeatInterval:             0,
+	HeartbeatMaxCostGwei:          1_000_000,
+	ChallengeMoveMaxGas:           0,
+	ActTimeout:                    0,
+	StakeTokenAddress:             "",
+	InsufficientStakeTokenAction:  "wait",
+	RescueZombieStake:             false,
+	ExitWithdrawalAddress:         "",
+	BatchActReads:                 false,
+	LogLevels:                     DefaultStakerLogLevelsConfig,
+	PausedRollupAction:            "wait",
+	RunwayWindow:                  24 * time.Hour,
+	ConfirmedOnlyWatchtower:       false,
+	VerifyConfirmedSendRoots:      false,
+	MaxScanBlocksPerAct:           0,
+	AggressiveDepth:               50,
+	AggressiveMaxGas:              10_000_000,
+	ChallengeManagerAddress:       "",
+	DowngradeAfterFailures:        0,
+	DowngradeRetryInterval:        10 * time.Minute,
+	MaxConfirmationsPerAct:        1,
+	TopUpStake:                    false,
+	ChallengeMoveTopUp:            false,
+	ChallengeMoveTopUpPrivateKey:  "",
+	ChallengeMoveTopUpAmountGwei:  100_000_000,
+	BackupEOAPrivateKey:           "",
+	RecoveryBacklogNodes:          0,
+	RecoveryStakeAdvances:         10,
+	Confirmer:                     false,
+	MinPostInterval:               0,
+	WalletCreationExtraGas:        0,
+	ParentChainReadConnection:     DefaultParentChainReadConnectionConfig,
+	OtelMetrics:                   false,
 }
 
 var DefaultValidatorL1WalletConfig = genericconf.WalletConfig{
@@ -205,7 +470,7 @@ var DefaultValidatorL1WalletConfig = genericconf.WalletConfig{
 
 func L1ValidatorConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Bool(prefix+".enable", DefaultL1ValidatorConfig.Enable, "enable validator")
-	f.String(prefix+".strategy", DefaultL1ValidatorConfig.Strategy, "L1 validator strategy, either watchtower, defensive, stakeLatest, or makeNodes")
+	f.String(prefix+".strategy", DefaultL1ValidatorConfig.Strategy, "L1 validator strategy, either watchtower, defensive, challengeOnly, stakeLatest, resolveNodes, makeNodes, or makeNodesAggressive")
 	f.Duration(prefix+".staker-interval", DefaultL1ValidatorConfig.StakerInterval, "how often the L1 validator should check the status of the L1 rollup and maybe take action with its stake")
 	f.Duration(prefix+".make-assertion-interval", DefaultL1ValidatorConfig.MakeAssertionInterval, "if configured with the makeNodes strategy, how often to create new assertions (bypassed in case of a dispute)")
 	L1PostingStrategyAddOptions(prefix+".posting-strategy", f)
@@ -223,6 +488,45 @@ func L1ValidatorConfigAddOptions(prefix string, f *flag.FlagSet) {
 	DangerousConfigAddOptions(prefix+".dangerous", f)
 	genericconf.WalletConfigAddOptions(prefix+".parent-chain-wallet", f, DefaultL1ValidatorConfig.ParentChainWallet.Pathname)
 	f.Bool(prefix+".enable-fast-confirmation", DefaultL1ValidatorConfig.EnableFastConfirmation, "enable fast confirmation")
+	f.Uint64(prefix+".confirmation-safety-delay-blocks", DefaultL1ValidatorConfig.ConfirmationSafetyDelayBlocks, "number of extra L1 blocks to wait after a node's challenge period ends before confirming it")
+	f.Uint64(prefix+".confirmation-stagger-blocks", DefaultL1ValidatorConfig.ConfirmationStaggerBlocks, "spread confirmations among multiple stakers by waiting up to this many extra L1 blocks, derived from the wallet address and node number, before confirming a node")
+	f.Uint64(prefix+".confirmation-maturity-nodes", DefaultL1ValidatorConfig.ConfirmationMaturityNodes, "only confirm a node once at least this many successor nodes were created after it, or it matured by confirmation-maturity-blocks, in addition to waiting confirmation-safety-delay-blocks past its deadline (0 to disable)")
+	f.Uint64(prefix+".confirmation-maturity-blocks", DefaultL1ValidatorConfig.ConfirmationMaturityBlocks, "only confirm a node once at least this many L1 blocks passed since its creation, or it matured by confirmation-maturity-nodes (0 to disable)")
+	f.Uint64(prefix+".stake-amount-gwei", DefaultL1ValidatorConfig.StakeAmountGwei, "amount in gwei to put down when placing a new stake; must be at least the rollup's current required stake, 0 stakes exactly the required amount")
+	f.String(prefix+".agreed-challenge-action", DefaultL1ValidatorConfig.AgreedChallengeAction, "what to do upon agreeing with an entire challenge we're in, either error (keep failing to act) or withdraw (stop making moves and let the challenge resolve without us)")
+	f.Duration(prefix+".heartbeat-interval", DefaultL1ValidatorConfig.HeartbeatInterval, "if the validator wallet's nonce hasn't changed for this long, post a zero value self-transfer to keep it active (0 to disable)")
+	f.Uint64(prefix+".heartbeat-max-cost-gwei", DefaultL1ValidatorConfig.HeartbeatMaxCostGwei, "maximum estimated cost in gwei of a heartbeat transaction, skipping the heartbeat if it'd be more expensive (0 for no limit)")
+	f.Uint64(prefix+".challenge-move-max-gas", DefaultL1ValidatorConfig.ChallengeMoveMaxGas, "gas ceiling of a single challenge move; moves estimated to need more aren't posted and require operator intervention (0 for no ceiling)")
+	f.Duration(prefix+".act-timeout", DefaultL1ValidatorConfig.ActTimeout, "deadline of a single staker act cycle, after which it's cancelled and retried on the next interval (0 for no deadline)")
+	f.String(prefix+".stake-token-address", DefaultL1ValidatorConfig.StakeTokenAddress, "address of the ERC-20 token the rollup is staked with, whose balance is checked before staking (empty if staked with the parent chain's native currency)")
+	f.String(prefix+".insufficient-stake-token-action", DefaultL1ValidatorConfig.InsufficientStakeTokenAction, "what to do when the stake token balance doesn't cover the stake, either wait (decline to stake and retry on the next act) or error")
+	f.Bool(prefix+".rescue-zombie-stake", DefaultL1ValidatorConfig.RescueZombieStake, "if the staker became a zombie by losing a challenge, remove it from the rollup's zombies once a conflicting node is confirmed and withdraw its remaining funds")
+	f.String(prefix+".exit-withdrawal-address", DefaultL1ValidatorConfig.ExitWithdrawalAddress, "address to send the staker's funds to when exiting the rollup through its smart contract wallet (empty leaves them in the wallet)")
+	f.Bool(prefix+".batch-act-reads", DefaultL1ValidatorConfig.BatchActReads, "prefetch the read-only parent chain calls made by every act cycle in a single JSON-RPC batch, reducing round trips on high latency RPCs")
+	StakerLogLevelsConfigAddOptions(prefix+".log-levels", f)
+	f.String(prefix+".paused-rollup-action", DefaultL1ValidatorConfig.PausedRollupAction, "what to do while the rollup contract is paused, either wait (stop posting until it's unpaused) or error")
+	f.Duration(prefix+".runway-window", DefaultL1ValidatorConfig.RunwayWindow, "how far back the staker's transaction fees are averaged over to estimate how long its balance lasts")
+	f.Bool(prefix+".confirmed-only-watchtower", DefaultL1ValidatorConfig.ConfirmedOnlyWatchtower, "as a watchtower, skip validating unconfirmed nodes and only check each newly confirmed node's global state against local execution, using the stateless block validator")
+	f.Bool(prefix+".verify-confirmed-send-roots", DefaultL1ValidatorConfig.VerifyConfirmedSendRoots, "each act, check the outbox send root of every newly confirmed node against the one computed by the stateless block validator, alerting on a mismatch")
+	f.Uint64(prefix+".max-scan-blocks-per-act", DefaultL1ValidatorConfig.MaxScanBlocksPerAct, "maximum number of parent chain blocks to search for new nodes in one act, catching up over the following acts (0 = unlimited)")
+	f.Uint64(prefix+".aggressive-depth", DefaultL1ValidatorConfig.AggressiveDepth, "if configured with the makeNodesAggressive strategy, maximum number of nodes to move the stake through or create in one act")
+	f.Uint64(prefix+".aggressive-max-gas", DefaultL1ValidatorConfig.AggressiveMaxGas, "if configured with the makeNodesAggressive strategy, stop creating nodes in an act once its transaction is estimated to need this much gas (0 = no limit)")
+	f.Uint64(prefix+".downgrade-after-failures", DefaultL1ValidatorConfig.DowngradeAfterFailures, "downgrade to the watchtower strategy after this many consecutive failed acts, alerting the operator (0 = never)")
+	f.Duration(prefix+".downgrade-retry-interval", DefaultL1ValidatorConfig.DowngradeRetryInterval, "once downgraded, how often to retry the configured strategy, resuming it when an act succeeds")
+	f.Uint64(prefix+".max-confirmations-per-act", DefaultL1ValidatorConfig.MaxConfirmationsPerAct, "maximum number of nodes to confirm in one act, continuing with the backlog over the following acts (more than one requires a contract validator wallet to batch the confirmations)")
+	f.Bool(prefix+".top-up-stake", DefaultL1ValidatorConfig.TopUpStake, "if the rollup's base stake rises above the staker's stake, add the shortfall to the stake (the shortfall is always alerted on and reported in a metric)")
+	f.Bool(prefix+".challenge-move-top-up", DefaultL1ValidatorConfig.ChallengeMoveTopUp, "if a challenge move fails for insufficient funds, top up its sender from the challenge-move-top-up-private-key account and retry the move (the failure is always alerted on and reported in a metric)")
+	f.String(prefix+".challenge-move-top-up-private-key", DefaultL1ValidatorConfig.ChallengeMoveTopUpPrivateKey, "private key of the parent chain account funding emergency top-ups of challenge move senders")
+	f.Uint64(prefix+".challenge-move-top-up-amount-gwei", DefaultL1ValidatorConfig.ChallengeMoveTopUpAmountGwei, "amount in gwei to send to a challenge move sender in an emergency top-up")
+	f.String(prefix+".backup-eoa-private-key", DefaultL1ValidatorConfig.BackupEOAPrivateKey, "private key of a parent chain account, an executor of the smart contract wallet, paying for the gas of wallet transactions the wallet's sender can't afford")
+	f.Uint64(prefix+".recovery-backlog-nodes", DefaultL1ValidatorConfig.RecoveryBacklogNodes, "if the first act finds at least this many unresolved nodes, e.g. after a long downtime, pace the catch-up over several acts in recovery mode until the backlog falls below it, making challenge moves before any routine work (0 = disabled)")
+	f.Uint64(prefix+".recovery-stake-advances", DefaultL1ValidatorConfig.RecoveryStakeAdvances, "in recovery mode, maximum number of times to advance the stake in one act")
+	f.Bool(prefix+".confirmer", DefaultL1ValidatorConfig.Confirmer, "as a watchtower, confirm the next unresolved node whoever created it, once it's confirmable, matches local validation and no stakers are in conflict, without placing a stake")
+	f.Duration(prefix+".min-post-interval", DefaultL1ValidatorConfig.MinPostInterval, "minimum time between creating new nodes, on top of the rollup's minimum assertion period (bypassed in case of a dispute, 0 = disabled)")
+	f.Uint64(prefix+".wallet-creation-extra-gas", DefaultL1ValidatorConfig.WalletCreationExtraGas, "use this much more gas than estimation says is necessary to create the validator smart contract wallet (0 = extra-gas)")
+	rpcclient.RPCClientAddOptions(prefix+".parent-chain-read-connection", f, &DefaultL1ValidatorConfig.ParentChainReadConnection)
+	f.Bool(prefix+".otel-metrics", DefaultL1ValidatorConfig.OtelMetrics, "report the staker's metrics through the globally registered OpenTelemetry meter provider instead of the default metrics registry")
+	f.String(prefix+".challenge-manager-address", DefaultL1ValidatorConfig.ChallengeManagerAddress, "address of the challenge manager the validator expects to interact with, verified against the rollup's at startup (empty to skip the check)")
 }
 
 type DangerousConfig struct {
@@ -230,6 +534,15 @@ type DangerousConfig struct {
 	WithoutBlockValidator      bool `koanf:"without-block-validator"`
 }
 
+// DefaultParentChainReadConnectionConfig leaves the staker reading the parent chain through its main connection.
+var DefaultParentChainReadConnectionConfig = rpcclient.ClientConfig{
+	URL:                       "",
+	Retries:                   3,
+	RetryErrors:               "websocket: close.*|dial tcp .*|.*i/o timeout|.*connection reset by peer|.*connection refused",
+	ArgLogLimit:               2048,
+	WebsocketMessageSizeLimit: 256 * 1024 * 1024,
+}
+
 var DefaultDangerousConfig = DangerousConfig{
 	IgnoreRollupWasmModuleRoot: false,
 	WithoutBlockValidator:      false,
@@ -253,6 +566,16 @@ type LatestConfirmedNotifier interface {
 	UpdateLatestConfirmed(count arbutil.MessageIndex, globalState validator.GoGlobalState)
 }
 
+const WatchtowerActionChallenge = "challenge"
+
+// WatchtowerAction is an action a watchtower staker found necessary but
+// suppressed, as it never posts to the parent chain.
+type WatchtowerAction struct {
+	Action     string
+	Node       uint64
+	ObservedAt time.Time
+}
+
 type validatedNode struct {
 	number uint64
 	hash   common.Hash
@@ -264,6 +587,7 @@ type Staker struct {
 	*L1Validator
 	stopwaiter.StopWaiter
 	l1Reader                *headerreader.HeaderReader
+	readRpcClient           *rpcclient.RpcClient // if reads go to a connection separate from l1Reader's
 	stakedNotifiers         []LatestStakedNotifier
 	confirmedNotifiers      []LatestConfirmedNotifier
 	activeChallenge         *ChallengeManager
@@ -278,6 +602,43 @@ type Staker struct {
 	statelessBlockValidator *staker.StatelessBlockValidator
 	fatalErr                chan<- error
 	fastConfirmSafe         *FastConfirmSafe
+	metrics                 metricsutil.Sink
+	suppressedAction        atomic.Pointer[WatchtowerAction]
+	actMutex                sync.Mutex
+	// serializes act cycles of the loop and pokes, including the wait for their transaction's approval
+	cycleMutex            sync.Mutex
+	stakeApproval         StakeApprovalFunc
+	stakeToken            stakeTokenBalanceReader
+	heartbeat             *WalletHeartbeat
+	onStakedNodeConfirmed StakedNodeConfirmedFunc
+	emergencyTopUp        EmergencyTopUpFunc
+	// whether a challenge move is among the transactions batched by the act in progress
+	challengeMoveBatched bool
+	conflictHandler      ConflictHandler
+	pausedRollup         rollupPausedReader
+	// whether the rollup was paused as of the latest act
+	rollupPaused bool
+	spend        *spendTracker
+	// conflicts between stakers already passed to the conflict handler, until settled
+	reportedConflicts      map[ConflictInfo]bool
+	reportedConflictsMutex sync.Mutex
+	// latest confirmed node whose send root was verified, nil until first checked
+	lastVerifiedConfirmed *uint64
+	confirmedDivergence   atomic.Pointer[SendRootVerification]
+	// latest confirmed node checked for nodes we're staked on, nil until first checked
+	lastCheckedConfirmed *uint64
+	// state observed by the act cycle in progress, and the one of the latest completed act cycle
+	actState StakerState
+	state    atomic.Uint32
+	// status observed by act cycles, and the one as of the latest completed act cycle
+	actStatus StakerStatus
+	status    atomic.Pointer[StakerStatus]
+	// consecutive failures to act, and whether the act in progress was downgraded to the watchtower strategy
+	downgrade     strategyDowngrade
+	downgradedAct atomic.Bool
+	recovery      recoveryMode
+	// the challenge manager the active challenge's bindings were created from
+	activeChallengeManager challengeManagerVersion
 }
 
 type ValidatorWalletInterface interface {
@@ -290,6 +651,7 @@ type ValidatorWalletInterface interface {
 	L1Client() *ethclient.Client
 	TestTransactions(context.Context, []*types.Transaction) error
 	ExecuteTransactions(context.Context, []*types.Transaction, common.Address) (*types.Transaction, error)
+	EstimateTransactionsGas(context.Context, []*types.Transaction) (uint64, error)
 	TimeoutChallenges(context.Context, []uint64, common.Address) (*types.Transaction, error)
 	CanBatchTxs() bool
 	AuthIfEoa() *bind.TransactOpts
@@ -297,6 +659,79 @@ type ValidatorWalletInterface interface {
 	StopAndWait()
 	// May be nil
 	DataPoster() *dataposter.DataPoster
+	// May be nil
+	BackupDataPoster() *dataposter.DataPoster
+	WithdrawStakeAndExit(ctx context.Context, rollupAddress common.Address, destination common.Address) (*types.Transaction, error)
+}
+
+type stakerOptions struct {
+	l1ReadClient  *ethclient.Client
+	metricsSink   metricsutil.Sink
+	stakeApproval StakeApprovalFunc
+	onConfirmed   StakedNodeConfirmedFunc
+	topUp         EmergencyTopUpFunc
+	onConflict    ConflictHandler
+}
+
+type StakerOption func(*stakerOptions)
+
+// StakedNodeConfirmedFunc is called with the number of each node the staker is staked on
+// once it's confirmed, in increasing order.
+type StakedNodeConfirmedFunc func(node uint64)
+
+// StakeApprovalFunc is consulted before the staker places its initial stake,
+// which is only placed once it returns true.
+type StakeApprovalFunc func(ctx context.Context, amount *big.Int) bool
+
+// WithL1ReadClient makes the staker use a separate parent chain client for all
+// read-only queries, e.g. a fast read replica, while transactions are still
+// built and posted through the wallet's client.
+func WithL1ReadClient(client *ethclient.Client) StakerOption {
+	return func(o *stakerOptions) {
+		o.l1ReadClient = client
+	}
+}
+
+// WithMetricsSink makes the staker report its metrics to the given sink
+// instead of the default go-ethereum metrics registry.
+func WithMetricsSink(sink metricsutil.Sink) StakerOption {
+	return func(o *stakerOptions) {
+		o.metricsSink = sink
+	}
+}
+
+// WithStakeApproval requires the given hook to approve the staker's initial stake,
+// e.g. for manual approval by an operator. By default the stake is auto-approved.
+func WithStakeApproval(approve StakeApprovalFunc) StakerOption {
+	return func(o *stakerOptions) {
+		o.stakeApproval = approve
+	}
+}
+
+// WithStakedNodeConfirmed makes the staker call onConfirmed whenever a node it's staked on
+// is confirmed, e.g. so that operators can reclaim their stake. Only nodes confirmed after
+// the staker starts are reported.
+func WithStakedNodeConfirmed(onConfirmed StakedNodeConfirmedFunc) StakerOption {
+	return func(o *stakerOptions) {
+		o.onConfirmed = onConfirmed
+	}
+}
+
+// WithEmergencyTopUp makes the staker fund the sender of a challenge move which failed for insufficient
+// funds with the given hook, e.g. from an operator's treasury, before retrying the move.
+// It's only used if the challenge-move-top-up config is enabled.
+func WithEmergencyTopUp(topUp EmergencyTopUpFunc) StakerOption {
+	return func(o *stakerOptions) {
+		o.topUp = topUp
+	}
+}
+
+// WithConflictHandler makes the staker call onConflict the first time it observes each conflict between
+// stakers, i.e. when looking for a challenge to open or, as a confirmer, before confirming a node.
+func WithConflictHandler(onConflict ConflictHandler) StakerOption {
+	return func(o *stakerOptions) {
+		o.onConflict = onConflict
+	}
 }
 
 func NewStaker(
@@ -314,23 +749,59 @@ func NewStaker(
 	inboxStreamer staker.TransactionStreamerInterface,
 	inboxReader staker.InboxReaderInterface,
 	fatalErr chan<- error,
+	opts ...StakerOption,
 ) (*Staker, error) {
 	if err := config().Validate(); err != nil {
 		return nil, err
 	}
+	var options stakerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	client := l1Reader.Client()
+	// reads go to a separate connection if configured, e.g. to a read replica
+	var readRpcClient *rpcclient.RpcClient
+	if options.l1ReadClient != nil {
+		client = options.l1ReadClient
+	} else if config().ParentChainReadConnection.URL != "" {
+		readRpcClient = rpcclient.NewRpcClient(func() *rpcclient.ClientConfig { return &config().ParentChainReadConnection }, nil)
+		client = ethclient.NewClient(readRpcClient)
+	}
+	metricsSink := options.metricsSink
+	if metricsSink == nil {
+		metricsSink = metricsutil.DefaultSink
+		if config().OtelMetrics {
+			metricsSink = metricsutil.NewOtelSink(otel.GetMeterProvider(), stakerMeterName)
+		}
+	}
 	val, err := NewL1Validator(client, wallet, validatorUtilsAddress, rollupAddress, config().GasRefunder(), callOpts,
 		inboxTracker, inboxStreamer, blockValidator)
 	if err != nil {
 		return nil, err
 	}
-	stakerLastSuccessfulActionGauge.Update(time.Now().Unix())
+	val.challengeLog = NewSubsystemLogger(LogSubsystemChallenge, config)
+	val.confirmLog = NewSubsystemLogger(LogSubsystemConfirm, config)
+	val.createLog = NewSubsystemLogger(LogSubsystemCreate, config)
+	emergencyTopUp := options.topUp
+	if emergencyTopUp == nil && config().challengeMoveTopUpKey != nil {
+		emergencyTopUp = newKeyedTopUp(l1Reader.Client(), config)
+	}
+	metricsSink.UpdateGauge(stakerLastSuccessfulActionMetric, time.Now().Unix())
 	inactiveValidatedNodes := btree.NewG(2, func(a, b validatedNode) bool {
 		return a.number < b.number || (a.number == b.number && a.hash.Cmp(b.hash) < 0)
 	})
+	var heartbeat *WalletHeartbeat
+	if dataPoster := wallet.DataPoster(); dataPoster != nil {
+		heartbeat = NewWalletHeartbeat(dataPoster, client)
+	}
+	var stakeToken stakeTokenBalanceReader
+	if stakeTokenAddress := config().StakeToken(); stakeTokenAddress != (common.Address{}) {
+		stakeToken = NewStakeToken(stakeTokenAddress, client)
+	}
 	return &Staker{
 		L1Validator:             val,
 		l1Reader:                l1Reader,
+		readRpcClient:           readRpcClient,
 		stakedNotifiers:         stakedNotifiers,
 		confirmedNotifiers:      confirmedNotifiers,
 		baseCallOpts:            callOpts,
@@ -341,14 +812,31 @@ func NewStaker(
 		statelessBlockValidator: statelessBlockValidator,
 		fatalErr:                fatalErr,
 		inactiveValidatedNodes:  inactiveValidatedNodes,
+		metrics:                 metricsSink,
+		stakeApproval:           options.stakeApproval,
+		stakeToken:              stakeToken,
+		heartbeat:               heartbeat,
+		onStakedNodeConfirmed:   options.onConfirmed,
+		emergencyTopUp:          emergencyTopUp,
+		conflictHandler:         options.onConflict,
+		pausedRollup:            val.rollup,
+		spend:                   newSpendTracker(time.Now()),
 	}, nil
 }
 
 func (s *Staker) Initialize(ctx context.Context) error {
+	if s.readRpcClient != nil {
+		if err := s.readRpcClient.Start(ctx); err != nil {
+			return fmt.Errorf("error connecting to the parent chain read connection: %w", err)
+		}
+	}
 	err := s.L1Validator.Initialize(ctx)
 	if err != nil {
 		return err
 	}
+	if err := s.verifyChallengeManager(ctx); err != nil {
+		return err
+	}
 	walletAddressOrZero := s.wallet.AddressOrZero()
 	if walletAddressOrZero != (common.Address{}) {
 		s.updateStakerBalanceMetric(ctx)
@@ -374,7 +862,7 @@ func (s *Staker) Initialize(ctx context.Context) error {
 			return err
 		}
 		// #nosec G115
-		stakerLatestStakedNodeGauge.Update(int64(latestStaked))
+		s.metrics.UpdateGauge(stakerLatestStakedNodeMetric, int64(latestStaked))
 		if latestStaked == 0 {
 			return nil
 		}
@@ -392,6 +880,26 @@ func (s *Staker) Initialize(ctx context.Context) error {
 	return s.setupFastConfirmation(ctx)
 }
 
+// ErrChallengeManagerMismatch is returned when the configured challenge manager isn't the one the rollup points to.
+var ErrChallengeManagerMismatch = errors.New("challenge manager doesn't match the rollup's")
+
+// verifyChallengeManager checks the configured challenge manager, if any, against the rollup's,
+// catching a validator misconfigured to interact with the wrong challenge manager.
+func (s *Staker) verifyChallengeManager(ctx context.Context) error {
+	expected := s.config().ChallengeManager()
+	if expected == (common.Address{}) {
+		return nil
+	}
+	rollupChallengeManager, err := s.rollup.ChallengeManager(s.getCallOpts(ctx))
+	if err != nil {
+		return fmt.Errorf("error getting rollup challenge manager: %w", err)
+	}
+	if rollupChallengeManager != expected {
+		return fmt.Errorf("%w: configured %v but rollup %v uses %v", ErrChallengeManagerMismatch, expected, s.rollupAddress, rollupChallengeManager)
+	}
+	return nil
+}
+
 // setupFastConfirmation sets the enableFastConfirmation and fastConfirmSafe variables of staker
 // based on the config, the wallet address, and the on-chain rollup designated fast confirmer.
 // Before this function, both variables should be their default (i.e. fast confirmation is disabled).
@@ -404,8 +912,7 @@ func (s *Staker) setupFastConfirmation(ctx context.Context) error {
 		return errors.New("fast confirmation requires wallet setup")
 	}
 	walletAddress := *s.wallet.Address()
-	client := s.l1Reader.Client()
-	rollup, err := rollup_legacy_gen.NewRollupUserLogic(s.rollupAddress, client)
+	rollup, err := rollup_legacy_gen.NewRollupUserLogic(s.rollupAddress, s.client)
 	if err != nil {
 		return err
 	}
@@ -414,7 +921,7 @@ func (s *Staker) setupFastConfirmation(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("getting rollup fast confirmer address: %w", err)
 	}
-	log.Info("Setting up fast confirmation", "wallet", walletAddress, "fastConfirmer", fastConfirmer)
+	s.confirmLog.Info("Setting up fast confirmation", "wallet", walletAddress, "fastConfirmer", fastConfirmer)
 	if fastConfirmer == walletAddress {
 		// We can directly fast confirm nodes
 		return nil
@@ -435,6 +942,7 @@ func (s *Staker) setupFastConfirmation(ctx context.Context) error {
 		// Unknown while loading the safe contract.
 		return fmt.Errorf("loading fast confirm safe: %w", err)
 	}
+	fastConfirmSafe.logger = s.confirmLog
 	// Fast confirmer address implements getOwners() and is probably a safe.
 	isOwner, err := fastConfirmSafe.safe.IsOwner(callOpts, walletAddress)
 	if err != nil {
@@ -466,7 +974,7 @@ func (s *Staker) tryFastConfirmation(ctx context.Context, blockHash common.Hash,
 		return s.fastConfirmSafe.tryFastConfirmation(ctx, blockHash, sendRoot, nodeHash)
 	}
 	auth := s.builder.Auth(ctx)
-	log.Info("Fast confirming node with wallet", "wallet", auth.From, "nodeHash", nodeHash)
+	s.confirmLog.Info("Fast confirming node with wallet", "wallet", auth.From, "nodeHash", nodeHash)
 	_, err := s.rollup.FastConfirmNextNode(auth, blockHash, sendRoot, nodeHash)
 	return err
 }
@@ -522,6 +1030,9 @@ func (s *Staker) StopAndWait() {
 	if s.Strategy() != WatchtowerStrategy {
 		s.wallet.StopAndWait()
 	}
+	if s.readRpcClient != nil {
+		s.readRpcClient.Close()
+	}
 }
 
 func (s *Staker) Start(ctxIn context.Context) {
@@ -539,43 +1050,31 @@ func (s *Staker) Start(ctxIn context.Context) {
 				returningWait = time.Minute
 			}
 		}()
-		var err error
 		cfg := s.config()
 		if common.HexToAddress(cfg.GasRefunderAddress) != (common.Address{}) {
 			gasRefunderBalance, err := s.client.BalanceAt(ctx, common.HexToAddress(cfg.GasRefunderAddress), nil)
 			if err != nil {
 				log.Warn("error fetching validator gas refunder balance", "err", err)
 			} else {
-				validatorGasRefunderBalance.Update(arbmath.BalancePerEther(gasRefunderBalance))
+				s.metrics.UpdateGaugeFloat64(validatorGasRefunderBalanceMetric, arbmath.BalancePerEther(gasRefunderBalance))
 			}
 		}
-		err = s.updateBlockValidatorModuleRoot(ctx)
-		if err != nil {
-			log.Warn("error updating latest wasm module root", "err", err)
-		}
-		arbTx, err := s.Act(ctx)
-		if err == nil && arbTx != nil {
-			_, err = s.l1Reader.WaitForTxApproval(ctx, arbTx)
-			if err == nil {
-				log.Info("successfully executed staker transaction", "hash", arbTx.Hash())
-			} else {
-				err = fmt.Errorf("error waiting for tx receipt: %w", err)
-			}
+		arbTx, err := s.actCycle(ctx)
+		if errors.Is(err, ErrActTimeout) {
+			log.Warn("staker act cycle timed out", "err", err)
+			return cfg.StakerInterval
 		}
 		if err == nil {
 			isAheadOfOnChainNonceEphemeralErrorHandler.Reset()
 			exceedsMaxMempoolSizeEphemeralErrorHandler.Reset()
 			blockValidationPendingEphemeralErrorHandler.Reset()
 			backoff = time.Second
-			stakerLastSuccessfulActionGauge.Update(time.Now().Unix())
-			stakerActionSuccessCounter.Inc(1)
 			if arbTx != nil && !s.wallet.CanBatchTxs() {
 				// Try to create another tx
 				return 0
 			}
 			return cfg.StakerInterval
 		}
-		stakerActionFailureCounter.Inc(1)
 		backoff *= 2
 		logLevel := log.Error
 		if backoff > time.Minute {
@@ -596,7 +1095,7 @@ func (s *Staker) Start(ctxIn context.Context) {
 			log.Error("staker: error checking latest staked", "err", err)
 		}
 		// #nosec G115
-		stakerLatestStakedNodeGauge.Update(int64(staked))
+		s.metrics.UpdateGauge(stakerLatestStakedNodeMetric, int64(staked))
 		if stakedGlobalState != nil {
 			for _, notifier := range s.stakedNotifiers {
 				notifier.UpdateLatestStaked(stakedMsgCount, *stakedGlobalState)
@@ -612,7 +1111,12 @@ func (s *Staker) Start(ctxIn context.Context) {
 			}
 		}
 		// #nosec G115
-		stakerLatestConfirmedNodeGauge.Update(int64(confirmed))
+		s.metrics.UpdateGauge(stakerLatestConfirmedNodeMetric, int64(confirmed))
+		if err == nil && wallet != (common.Address{}) {
+			if err := s.notifyStakedNodesConfirmed(ctx, s.rollup, wallet, confirmed); err != nil && ctx.Err() == nil {
+				log.Error("staker: error checking confirmed nodes we're staked on", "err", err)
+			}
+		}
 		if confirmedGlobalState != nil {
 			for _, notifier := range s.confirmedNotifiers {
 				notifier.UpdateLatestConfirmed(confirmedMsgCount, *confirmedGlobalState)
@@ -620,6 +1124,62 @@ func (s *Staker) Start(ctxIn context.Context) {
 		}
 		return s.config().StakerInterval
 	})
+	if s.heartbeat != nil {
+		s.CallIteratively(func(ctx context.Context) time.Duration {
+			cfg := s.config()
+			if cfg.HeartbeatInterval == 0 {
+				return time.Minute
+			}
+			if _, err := s.SendHeartbeatIfIdle(ctx); err != nil && ctx.Err() == nil {
+				log.Warn("error sending validator wallet heartbeat", "err", err)
+			}
+			return cfg.StakerInterval
+		})
+	}
+}
+
+// SendHeartbeatIfIdle posts a heartbeat transaction from the validator wallet if it's been
+// idle for the configured heartbeat interval, returning nil if no heartbeat was posted.
+func (s *Staker) SendHeartbeatIfIdle(ctx context.Context) (*types.Transaction, error) {
+	cfg := s.config()
+	if s.heartbeat == nil || cfg.HeartbeatInterval == 0 {
+		return nil, nil
+	}
+	var maxCost *big.Int
+	if cfg.HeartbeatMaxCostGwei != 0 {
+		maxCost = arbmath.BigMulByUint(big.NewInt(params.GWei), cfg.HeartbeatMaxCostGwei)
+	}
+	return s.heartbeat.MaybeSend(ctx, cfg.HeartbeatInterval, maxCost)
+}
+
+type nodeStakerChecker interface {
+	NodeHasStaker(opts *bind.CallOpts, nodeNum uint64, staker common.Address) (bool, error)
+}
+
+// notifyStakedNodesConfirmed calls the staked node confirmed callback for every node staker is
+// staked on that was confirmed since the last check, up to and including confirmed.
+func (s *Staker) notifyStakedNodesConfirmed(ctx context.Context, nodes nodeStakerChecker, staker common.Address, confirmed uint64) error {
+	if s.onStakedNodeConfirmed == nil {
+		return nil
+	}
+	if s.lastCheckedConfirmed == nil {
+		s.lastCheckedConfirmed = &confirmed
+		return nil
+	}
+	callOpts := s.baseCallOpts
+	callOpts.Context = ctx
+	for node := *s.lastCheckedConfirmed + 1; node <= confirmed; node++ {
+		staked, err := nodes.NodeHasStaker(&callOpts, node, staker)
+		if err != nil {
+			return fmt.Errorf("error checking if %v is staked on confirmed node %v: %w", staker, node, err)
+		}
+		*s.lastCheckedConfirmed = node
+		if staked {
+			s.confirmLog.Info("node we're staked on was confirmed", "node", node, "staker", staker)
+			s.onStakedNodeConfirmed(node)
+		}
+	}
+	return nil
 }
 
 func (s *Staker) isWhitelisted(ctx context.Context) (bool, error) {
@@ -687,11 +1247,21 @@ func (s *Staker) shouldAct(ctx context.Context) bool {
 	return true
 }
 
+// confirmDataPosterIsReady checks that no transaction of the wallet's data posters, including its backup EOA's,
+// is pending.
 func (s *Staker) confirmDataPosterIsReady(ctx context.Context) error {
-	dp := s.wallet.DataPoster()
-	if dp == nil {
-		return nil
+	for _, dp := range []*dataposter.DataPoster{s.wallet.DataPoster(), s.wallet.BackupDataPoster()} {
+		if dp == nil {
+			continue
+		}
+		if err := s.confirmNonceIsReady(ctx, dp); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+func (s *Staker) confirmNonceIsReady(ctx context.Context, dp *dataposter.DataPoster) error {
 	dataPosterNonce, _, err := dp.GetNextNonceAndMeta(ctx)
 	if err != nil {
 		return err
@@ -701,17 +1271,135 @@ func (s *Staker) confirmDataPosterIsReady(ctx context.Context) error {
 		return err
 	}
 	if dataPosterNonce > latestNonce {
-		return fmt.Errorf("data poster nonce %v is ahead of on-chain nonce %v -- probably waiting for a pending transaction to be included in a block", dataPosterNonce, latestNonce)
+		return fmt.Errorf("%w: data poster of %v nonce %v is ahead of on-chain nonce %v -- probably waiting for a pending transaction to be included in a block", ErrDataPosterNotReady, dp.Sender(), dataPosterNonce, latestNonce)
 	}
 	if dataPosterNonce < latestNonce {
-		return fmt.Errorf("data poster nonce %v is behind on-chain nonce %v -- is something else making transactions on this address?", dataPosterNonce, latestNonce)
+		return fmt.Errorf("data poster of %v nonce %v is behind on-chain nonce %v -- is something else making transactions on this address?", dp.Sender(), dataPosterNonce, latestNonce)
 	}
 	return nil
 }
 
+// actCycle runs an act cycle of the staker loop, waiting for the transaction it posted, if any, to be
+// approved, and records its outcome in the spend tracker, the act metrics and towards a downgrade.
+func (s *Staker) actCycle(ctx context.Context) (*types.Transaction, error) {
+	return s.runActCycle(ctx, s.actOnce, s.l1Reader.WaitForTxApproval)
+}
+
+// runActCycle is actCycle with the act and the wait for approval given. Cycles are serialized up to
+// the approval of their transaction, so a cycle never acts while another's transaction is pending.
+func (s *Staker) runActCycle(
+	ctx context.Context,
+	act func(context.Context) (*types.Transaction, error),
+	waitForApproval func(context.Context, *types.Transaction) (*types.Receipt, error),
+) (*types.Transaction, error) {
+	s.cycleMutex.Lock()
+	defer s.cycleMutex.Unlock()
+	cfg := s.config()
+	s.downgradedAct.Store(cfg.StrategyType() != WatchtowerStrategy && s.downgrade.watchtower(time.Now(), cfg.DowngradeAfterFailures, cfg.DowngradeRetryInterval))
+	s.nodePostedInAct = false
+	arbTx, err := act(ctx)
+	if err == nil && arbTx != nil {
+		var receipt *types.Receipt
+		receipt, err = waitForApproval(ctx, arbTx)
+		if err == nil {
+			s.spend.record(time.Now(), receiptCost(receipt))
+			if s.nodePostedInAct {
+				// the min post interval runs from the node's creation, not from queueing it
+				s.lastNodePosted = time.Now()
+			}
+			log.Info("successfully executed staker transaction", "hash", arbTx.Hash())
+		} else {
+			err = fmt.Errorf("error waiting for tx receipt: %w", err)
+		}
+	}
+	s.recordActOutcome(err, cfg)
+	if err != nil {
+		s.metrics.IncCounter(stakerActionFailureMetric, 1)
+		return arbTx, err
+	}
+	s.metrics.UpdateGauge(stakerLastSuccessfulActionMetric, time.Now().Unix())
+	s.metrics.IncCounter(stakerActionSuccessMetric, 1)
+	return arbTx, nil
+}
+
+// actOnce runs a single act cycle, serialized with any other act cycles.
+func (s *Staker) actOnce(ctx context.Context) (*types.Transaction, error) {
+	s.actMutex.Lock()
+	defer s.actMutex.Unlock()
+	return actWithDeadline(ctx, s.config().ActTimeout, func(ctx context.Context) (*types.Transaction, error) {
+		err := s.updateBlockValidatorModuleRoot(ctx)
+		if err != nil {
+			log.Warn("error updating latest wasm module root", "err", err)
+		}
+		return s.Act(ctx)
+	}, s.builder.ClearTransactions)
+}
+
+// actWithDeadline runs act, cancelling it once timeout elapses if timeout is non-zero.
+// If the deadline cancelled act, clearBuilder drops any transactions it left queued, and
+// ErrActTimeout is returned unless act already posted a transaction, which is returned as progress.
+func actWithDeadline(ctx context.Context, timeout time.Duration, act func(context.Context) (*types.Transaction, error), clearBuilder func()) (*types.Transaction, error) {
+	if timeout == 0 {
+		return act(ctx)
+	}
+	actCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	tx, err := act(actCtx)
+	if err == nil || ctx.Err() != nil || !errors.Is(actCtx.Err(), context.DeadlineExceeded) {
+		return tx, err
+	}
+	clearBuilder()
+	if tx != nil {
+		log.Warn("staker act cycle hit its deadline after posting a transaction", "tx", tx.Hash(), "timeout", timeout, "err", err)
+		return tx, nil
+	}
+	return nil, fmt.Errorf("%w after %v: %w", ErrActTimeout, timeout, err)
+}
+
+// WithdrawStakeAndExit takes the next step of unwinding the staker's position in the rollup through its wallet,
+// e.g. after losing a challenge, sending its funds to exit-withdrawal-address once they're withdrawn. It should be
+// called until it returns a nil transaction, retrying on validatorwallet.ErrExitPending.
+func (s *Staker) WithdrawStakeAndExit(ctx context.Context) (*types.Transaction, error) {
+	var destination common.Address
+	if addr := s.config().ExitWithdrawalAddress; addr != "" {
+		destination = common.HexToAddress(addr)
+	}
+	return s.wallet.WithdrawStakeAndExit(ctx, s.rollupAddress, destination)
+}
+
+// ActMany runs an act cycle like Act, but returns every transaction posted during it,
+// such as challenge moves and fast confirmations, rather than just the last one.
+// Callers should wait for each of them to be approved.
+func (s *Staker) ActMany(ctx context.Context) ([]*types.Transaction, error) {
+	var tx *types.Transaction
+	var err error
+	txs := s.builder.RecordExecutedTransactions(func() {
+		tx, err = s.Act(ctx)
+	})
+	if tx != nil && !slices.ContainsFunc(txs, func(posted *types.Transaction) bool { return posted.Hash() == tx.Hash() }) {
+		// the transaction was posted by the wallet directly, e.g. to time out challenges
+		txs = append(txs, tx)
+	}
+	return txs, err
+}
+
+// Act runs an act cycle, returning the last transaction it posted, if any, and updates the staker's status.
 func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
+	tx, err := s.act(ctx)
+	s.publishStatus(tx, err)
+	return tx, err
+}
+
+func (s *Staker) act(ctx context.Context) (*types.Transaction, error) {
+	s.actState = StakerStateIdle
+	defer s.publishState()
 	cfg := s.config()
-	if cfg.StrategyType() != WatchtowerStrategy {
+	if cfg.BatchActReads {
+		ctx = s.batchActReads(ctx)
+	}
+	downgraded := s.downgradedAct.Load()
+	// a downgraded staker still has its stake, and may still move in challenges, so it keeps checking its nonce
+	if cfg.StrategyType() != WatchtowerStrategy || cfg.Confirmer {
 		err := s.confirmDataPosterIsReady(ctx)
 		if err != nil {
 			return nil, err
@@ -722,15 +1410,36 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 		}
 		if !whitelisted {
 			log.Warn("validator address isn't whitelisted", "address", s.wallet.Address(), "txSender", s.wallet.TxSenderAddress())
+			s.observeState(StakerStateDeauthorized)
+		}
+	}
+	paused, err := s.checkRollupPaused(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if paused {
+		s.observeState(StakerStatePausedUpstream)
+		return nil, nil
+	}
+	if cfg.StrategyType() == WatchtowerStrategy && cfg.ConfirmedOnlyWatchtower {
+		return nil, s.watchConfirmedNodes(ctx)
+	}
+	if cfg.VerifyConfirmedSendRoots {
+		// a confirmed node not verified yet, e.g. because we're still catching up to it, is retried next act
+		if err := s.watchConfirmedNodes(ctx); err != nil {
+			log.Warn("error verifying send roots of confirmed nodes", "err", err)
 		}
 	}
 	if !s.shouldAct(ctx) {
 		// The fact that we're delaying acting is already logged in `shouldAct`
+		s.observeState(StakerStatePaused)
 		return nil, nil
 	}
 	callOpts := s.getCallOpts(ctx)
 	s.builder.ClearTransactions()
+	s.challengeMoveBatched = false
 	var rawInfo *StakerInfo
+	var isZombie bool
 	walletAddressOrZero := s.wallet.AddressOrZero()
 	if walletAddressOrZero != (common.Address{}) {
 		var err error
@@ -739,12 +1448,23 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 			return nil, fmt.Errorf("error getting own staker (%v) info: %w", walletAddressOrZero, err)
 		}
 		if rawInfo != nil {
-			stakerAmountStakedGauge.Update(rawInfo.AmountStaked.Int64())
+			s.metrics.UpdateGauge(stakerAmountStakedMetric, rawInfo.AmountStaked.Int64())
+			s.observeState(StakerStateStaking)
 		} else {
-			stakerAmountStakedGauge.Update(0)
+			s.metrics.UpdateGauge(stakerAmountStakedMetric, 0)
+			isZombie, err = s.rollup.IsZombie(callOpts, walletAddressOrZero)
+			if err != nil {
+				return nil, fmt.Errorf("error checking if own staker (%v) is a zombie: %w", walletAddressOrZero, err)
+			}
+			if isZombie {
+				s.observeState(StakerStateZombie)
+			}
 		}
 		s.updateStakerBalanceMetric(ctx)
 	}
+	s.actStatus.Staked = rawInfo != nil
+	s.actStatus.Zombie = isZombie
+	s.actStatus.InChallenge = rawInfo != nil && rawInfo.CurrentChallenge != nil
 	// If the wallet address is zero, or the wallet address isn't staked,
 	// this will return the latest node and its hash (atomically).
 	latestStakedNodeNum, latestStakedNodeInfo, err := s.validatorUtils.LatestStaked(
@@ -754,7 +1474,8 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 		return nil, fmt.Errorf("error getting latest staked node of own wallet %v: %w", walletAddressOrZero, err)
 	}
 	// #nosec G115
-	stakerLatestStakedNodeGauge.Update(int64(latestStakedNodeNum))
+	s.metrics.UpdateGauge(stakerLatestStakedNodeMetric, int64(latestStakedNodeNum))
+	s.actStatus.LatestStakedNode = latestStakedNodeNum
 	if rawInfo != nil {
 		rawInfo.LatestStakedNode = latestStakedNodeNum
 	}
@@ -767,6 +1488,9 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 	}
 
 	effectiveStrategy := cfg.StrategyType()
+	if downgraded {
+		effectiveStrategy = WatchtowerStrategy
+	}
 	nodesLinear, err := s.validatorUtils.AreUnresolvedNodesLinear(callOpts, s.rollupAddress)
 	if err != nil {
 		return nil, fmt.Errorf("error checking for rollup assertion fork: %w", err)
@@ -826,14 +1550,15 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 				confirmedCorrect = stakedOnNode
 			}
 			if confirmedCorrect {
-				log.Info("trying to fast confirm previous node", "node", firstUnresolvedNode, "nodeHash", nodeInfo.NodeHash)
+				s.confirmLog.Info("trying to fast confirm previous node", "node", firstUnresolvedNode, "nodeHash", nodeInfo.NodeHash)
 				err = s.tryFastConfirmationNodeNumber(ctx, firstUnresolvedNode, nodeInfo.NodeHash)
 				if err != nil {
 					return nil, err
 				}
 				if s.builder.BuildingTransactionCount() > 0 {
 					// Try to fast confirm previous nodes before working on new ones
-					return s.builder.ExecuteTransactions(ctx)
+					s.observeState(StakerStateConfirming)
+					return s.executeTransactions(ctx)
 				}
 			}
 		}
@@ -843,6 +1568,12 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error getting latest confirmed node: %w", err)
 	}
+	s.actStatus.LatestConfirmedNode = latestConfirmedNode
+	latestNode, err := s.rollup.LatestNodeCreated(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest node created: %w", err)
+	}
+	s.actStatus.LatestNode = latestNode
 
 	// Clear s.inactiveValidatedNodes of any entries before or equal to latestConfirmedNode
 	for {
@@ -856,6 +1587,30 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 		s.inactiveValidatedNodes.DeleteMin()
 	}
 
+	recovering, err := s.checkRecoveryMode(callOpts, latestConfirmedNode, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for recovery mode: %w", err)
+	}
+	pacing := paceAct(cfg, effectiveStrategy, recovering)
+	canActFurther := func() bool {
+		return s.wallet.CanBatchTxs() || s.builder.BuildingTransactionCount() == 0
+	}
+	if pacing.safetyFirst && rawInfo != nil && canActFurther() {
+		// Defend and open challenges before catching up on anything else
+		if err = s.handleConflict(ctx, rawInfo); err != nil {
+			return nil, fmt.Errorf("error handling conflict: %w", err)
+		}
+		if s.builder.BuildingTransactionCount() == 0 && canActFurther() {
+			if err := s.createConflict(ctx, rawInfo); err != nil {
+				return nil, fmt.Errorf("error creating conflict: %w", err)
+			}
+		}
+		if s.builder.BuildingTransactionCount() > 0 {
+			s.observeState(StakerStateChallenging)
+			return s.executeTransactions(ctx)
+		}
+	}
+
 	requiredStakeElevated, err := s.isRequiredStakeElevated(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error checking if required stake is elevated: %w", err)
@@ -872,12 +1627,23 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 			return nil, fmt.Errorf("error resolving timed out challenges: %w", err)
 		}
 		if arbTx != nil {
+			s.observeState(StakerStateConfirming)
 			return arbTx, nil
 		}
-		resolvingNode, err = s.resolveNextNode(ctx, rawInfo, &latestConfirmedNode)
+		previousConfirmedNode := latestConfirmedNode
+		resolvingNode, err = s.resolveNextNode(ctx, rawInfo, &latestConfirmedNode, cfg.ConfirmationSafetyDelayBlocks, cfg.ConfirmationStaggerBlocks, cfg.ConfirmationMaturityNodes, cfg.ConfirmationMaturityBlocks)
 		if err != nil {
 			return nil, fmt.Errorf("error resolving node %v: %w", latestConfirmedNode+1, err)
 		}
+		if latestConfirmedNode != previousConfirmedNode && pacing.maxConfirmations > 1 && s.wallet.CanBatchTxs() {
+			_, err = s.confirmFollowingNodes(ctx, &latestConfirmedNode, pacing.maxConfirmations-1, cfg.ConfirmationSafetyDelayBlocks, cfg.ConfirmationStaggerBlocks, cfg.ConfirmationMaturityNodes, cfg.ConfirmationMaturityBlocks)
+			if err != nil {
+				return nil, fmt.Errorf("error confirming node %v: %w", latestConfirmedNode+1, err)
+			}
+		}
+		if resolvingNode {
+			s.observeState(StakerStateConfirming)
+		}
 		if resolvingNode && rawInfo == nil && latestConfirmedNode > info.LatestStakedNode {
 			// If we hit this condition, we've resolved what was previously the latest confirmed node,
 			// and we don't have a stake yet. That means we were planning to enter the rollup on
@@ -892,15 +1658,19 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 		}
 	}
 
-	canActFurther := func() bool {
-		return s.wallet.CanBatchTxs() || s.builder.BuildingTransactionCount() == 0
+	if isZombie && cfg.RescueZombieStake && canActFurther() {
+		if err := s.rescueZombieStake(ctx, walletAddressOrZero, latestConfirmedNode); err != nil {
+			return nil, fmt.Errorf("error rescuing zombie stake: %w", err)
+		}
+		if s.builder.BuildingTransactionCount() > 0 {
+			return s.executeTransactions(ctx)
+		}
 	}
 
 	// If we have an old stake, remove it
 	if rawInfo != nil && rawInfo.LatestStakedNode <= latestConfirmedNode && canActFurther() {
 		stakeIsTooOutdated := rawInfo.LatestStakedNode < latestConfirmedNode
-		// We're not trying to stake anyways
-		stakeIsUnwanted := effectiveStrategy < StakeLatestStrategy
+		stakeIsUnwanted := isStakeUnwanted(effectiveStrategy, downgraded)
 		if stakeIsTooOutdated || stakeIsUnwanted {
 			// Note: we must have an address if rawInfo != nil
 			auth := s.builder.Auth(ctx)
@@ -913,7 +1683,7 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 				return nil, fmt.Errorf("error withdrawing staker funds from our staker %v: %w", walletAddressOrZero, err)
 			}
 			log.Info("removing old stake and withdrawing funds")
-			return s.builder.ExecuteTransactions(ctx)
+			return s.executeTransactions(ctx)
 		}
 	}
 
@@ -930,7 +1700,14 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 		}
 	}
 
-	if rawInfo != nil && canActFurther() {
+	if rawInfo != nil {
+		topUp := cfg.TopUpStake && effectiveStrategy >= StakeLatestStrategy && canActFurther()
+		if err := s.checkStakeShortfall(ctx, rawInfo, walletAddressOrZero, topUp); err != nil {
+			return nil, fmt.Errorf("error checking stake of our staker %v: %w", walletAddressOrZero, err)
+		}
+	}
+
+	if rawInfo != nil && !pacing.safetyFirst && canActFurther() {
 		if err = s.handleConflict(ctx, rawInfo); err != nil {
 			return nil, fmt.Errorf("error handling conflict: %w", err)
 		}
@@ -939,8 +1716,12 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 	// Don't attempt to create a new stake if we're resolving a node and the stake is elevated,
 	// as that might affect the current required stake.
 	if (rawInfo != nil || !resolvingNode || !requiredStakeElevated) && canActFurther() {
-		// Advance stake up to 20 times in one transaction
-		for i := 0; info.CanProgress && i < 20; i++ {
+		// Advance stake up to 20 times in one transaction, up to the aggressive depth when making nodes aggressively,
+		// or as configured in recovery mode
+		for i := uint64(0); info.CanProgress && i < pacing.maxAdvances; i++ {
+			if info.pending != nil && !s.canAffordNextNode(ctx, &info, cfg) {
+				break
+			}
 			if err := s.advanceStake(ctx, &info, effectiveStrategy); err != nil {
 				return nil, fmt.Errorf("error advancing stake from node %v (hash %v): %w", info.LatestStakedNode, info.LatestStakedNodeHash, err)
 			}
@@ -950,10 +1731,23 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 		}
 	}
 
-	if rawInfo != nil && s.builder.BuildingTransactionCount() == 0 && canActFurther() {
+	if effectiveStrategy == WatchtowerStrategy && cfg.Confirmer && canActFurther() {
+		confirmed, err := s.confirmValidatedNode(ctx, &latestConfirmedNode, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("error confirming node %v: %w", latestConfirmedNode+1, err)
+		}
+		if confirmed {
+			s.observeState(StakerStateConfirming)
+		}
+	}
+
+	if rawInfo != nil && !pacing.safetyFirst && s.builder.BuildingTransactionCount() == 0 && canActFurther() {
 		if err := s.createConflict(ctx, rawInfo); err != nil {
 			return nil, fmt.Errorf("error creating conflict: %w", err)
 		}
+		if s.builder.BuildingTransactionCount() > 0 {
+			s.observeState(StakerStateChallenging)
+		}
 	}
 
 	if s.builder.BuildingTransactionCount() == 0 {
@@ -963,7 +1757,25 @@ func (s *Staker) Act(ctx context.Context) (*types.Transaction, error) {
 	if info.StakerInfo == nil && info.StakeExists {
 		log.Info("staking to execute transactions")
 	}
-	return s.builder.ExecuteTransactions(ctx)
+	return s.executeTransactions(ctx)
+}
+
+// canAffordNextNode returns whether another node can be created in this act without its transaction
+// exceeding the aggressive-max-gas, assuming the node costs as much gas as the act's nodes so far on average.
+func (s *Staker) canAffordNextNode(ctx context.Context, info *OurStakerInfo, cfg *L1ValidatorConfig) bool {
+	if cfg.AggressiveMaxGas == 0 || info.NodesCreated == 0 {
+		return true
+	}
+	gas, err := s.wallet.EstimateTransactionsGas(ctx, s.builder.BuildingTransactions())
+	if err != nil {
+		log.Warn("error estimating gas of the nodes created in this act, not creating more", "err", err)
+		return false
+	}
+	if gas+gas/info.NodesCreated > cfg.AggressiveMaxGas {
+		log.Info("not creating more nodes in this act, as it would exceed the max gas", "nodes", info.NodesCreated, "gas", gas, "maxGas", cfg.AggressiveMaxGas)
+		return false
+	}
+	return true
 }
 
 func (s *Staker) handleConflict(ctx context.Context, info *StakerInfo) error {
@@ -971,19 +1783,24 @@ func (s *Staker) handleConflict(ctx context.Context, info *StakerInfo) error {
 		s.activeChallenge = nil
 		return nil
 	}
+	s.observeState(StakerStateChallenging)
 
+	if err := s.checkChallengeManagerUpgrade(ctx); err != nil {
+		return err
+	}
 	if s.activeChallenge == nil || s.activeChallenge.ChallengeIndex() != *info.CurrentChallenge {
-		log.Error("entered challenge", "challenge", *info.CurrentChallenge)
+		s.challengeLog.Error("entered challenge", "challenge", *info.CurrentChallenge)
 
 		latestConfirmedCreated, err := s.rollup.LatestConfirmedCreationBlock(ctx)
 		if err != nil {
 			return fmt.Errorf("error getting latest confirmed creation block: %w", err)
 		}
 
-		challengeManagerAddress, err := s.rollup.ChallengeManager(&bind.CallOpts{Context: ctx})
+		managerVersion, err := readChallengeManagerVersion(&bind.CallOpts{Context: ctx}, s.rollup, s.client)
 		if err != nil {
-			return fmt.Errorf("error getting challenge manager address: %w", err)
+			return err
 		}
+		challengeManagerAddress := managerVersion.address
 		newChallengeManager, err := NewChallengeManager(
 			ctx,
 			s.client,
@@ -1001,22 +1818,162 @@ func (s *Staker) handleConflict(ctx context.Context, info *StakerInfo) error {
 			return fmt.Errorf("error creating challenge manager: %w", err)
 		}
 
+		newChallengeManager.SetAgreedChallengeAction(s.config().AgreedChallengeActionType())
+		newChallengeManager.SetLogger(s.challengeLog)
 		s.activeChallenge = newChallengeManager
+		s.activeChallengeManager = managerVersion
 	}
 
+	s.activeChallenge.SetMaxMoveGas(s.config().ChallengeMoveMaxGas)
+	s.activeChallenge.SetEmergencyTopUp(s.challengeMoveTopUp())
+	wasWithdrawn := s.activeChallenge.Withdrawn()
+	batched := s.builder.BuildingTransactionCount()
 	_, err := s.activeChallenge.Act(ctx)
+	if s.builder.BuildingTransactionCount() > batched {
+		s.challengeMoveBatched = true
+	}
+	if !wasWithdrawn && s.activeChallenge.Withdrawn() {
+		s.metrics.IncCounter(stakerChallengeWithdrawnMetric, 1)
+	}
+	if errors.Is(err, ErrChallengeMoveGasCeilingExceeded) {
+		s.metrics.IncCounter(stakerChallengeMoveGasMetric, 1)
+	}
+	if errors.Is(err, ErrChallengeMoveInsufficientFunds) {
+		s.metrics.IncCounter(stakerChallengeFundsMetric, 1)
+	}
 	return err
 }
 
+// challengeMoveTopUp returns the emergency top-up of challenge move senders, or nil if it isn't configured.
+// The top-up funds the address sending the staker's transactions, which pays for the moves even if
+// they're made through a validator wallet contract.
+func (s *Staker) challengeMoveTopUp() EmergencyTopUpFunc {
+	if !s.config().ChallengeMoveTopUp {
+		return nil
+	}
+	if s.emergencyTopUp == nil {
+		s.challengeLog.Warn("challenge move top-up enabled but no challenge-move-top-up-private-key is configured")
+		return nil
+	}
+	return func(ctx context.Context, account common.Address) error {
+		if sender := s.wallet.TxSenderAddress(); sender != nil {
+			account = *sender
+		}
+		return s.emergencyTopUp(ctx, account)
+	}
+}
+
+// executeTransactions sends the transactions batched by the act. If a challenge move is among them and their
+// sender can't pay for them, the move is as good as lost once the move window closes, so this raises a
+// critical alert and, if configured, tops up the sender and sends them again right away.
+func (s *Staker) executeTransactions(ctx context.Context) (*types.Transaction, error) {
+	if !s.challengeMoveBatched {
+		return s.builder.ExecuteTransactions(ctx)
+	}
+	tx, err := s.builder.ExecuteTransactionsRetrying(ctx, func(err error) bool {
+		if !isInsufficientFunds(err) {
+			return false
+		}
+		s.challengeLog.Error("CRITICAL: insufficient funds to send challenge move, the challenge is lost if no move is made in time", "sender", s.wallet.TxSenderAddress(), "err", err)
+		topUp := s.challengeMoveTopUp()
+		if topUp == nil {
+			return false
+		}
+		if topUpErr := topUp(ctx, common.Address{}); topUpErr != nil {
+			s.challengeLog.Error("emergency top-up of challenge move sender failed", "err", topUpErr)
+			return false
+		}
+		s.challengeLog.Warn("resending challenge move after emergency top-up", "sender", s.wallet.TxSenderAddress())
+		return true
+	})
+	if isInsufficientFunds(err) {
+		s.metrics.IncCounter(stakerChallengeFundsMetric, 1)
+		return nil, fmt.Errorf("%w: %w", ErrChallengeMoveInsufficientFunds, err)
+	}
+	return tx, err
+}
+
+// chooseStakeAmount returns the configured stake amount, or the required stake if it's 0
+func chooseStakeAmount(requiredStake *big.Int, stakeAmountGwei uint64) (*big.Int, error) {
+	if stakeAmountGwei == 0 {
+		return requiredStake, nil
+	}
+	stakeAmount := arbmath.BigMulByUint(big.NewInt(params.GWei), stakeAmountGwei)
+	if stakeAmount.Cmp(requiredStake) < 0 {
+		return nil, fmt.Errorf("configured stake amount %v is below the current required stake %v", stakeAmount, requiredStake)
+	}
+	return stakeAmount, nil
+}
+
+func (s *Staker) newStakeAmount(ctx context.Context) (*big.Int, error) {
+	requiredStake, err := s.rollup.CurrentRequiredStake(s.getCallOpts(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error getting current required stake: %w", err)
+	}
+	return chooseStakeAmount(requiredStake, s.config().StakeAmountGwei)
+}
+
+func (s *Staker) stakeApproved(ctx context.Context, amount *big.Int) bool {
+	if s.stakeApproval == nil {
+		return true
+	}
+	if !s.stakeApproval(ctx, amount) {
+		log.Warn("initial stake not approved yet, waiting for approval", "amount", amount)
+		return false
+	}
+	return true
+}
+
+// stakerAddress returns the address holding the staker's stake: its validator wallet
+// contract if it has one, or otherwise the address sending its transactions.
+func (s *Staker) stakerAddress() common.Address {
+	if addr := s.wallet.AddressOrZero(); addr != (common.Address{}) {
+		return addr
+	}
+	if sender := s.wallet.TxSenderAddress(); sender != nil {
+		return *sender
+	}
+	return common.Address{}
+}
+
+// followNewNode moves the stake onto a node just created in this act, so that the act's next advance
+// creates a node on top of it, if making nodes aggressively.
+func (s *Staker) followNewNode(ctx context.Context, info *OurStakerInfo, action createNodeAction, effectiveStrategy StakerStrategy) error {
+	info.NodesCreated++
+	if effectiveStrategy != MakeNodesAggressiveStrategy {
+		return nil
+	}
+	latestNodeCreated, err := s.rollup.LatestNodeCreated(s.getCallOpts(ctx))
+	if err != nil {
+		return fmt.Errorf("error getting latest node created: %w", err)
+	}
+	inboxMaxCount, err := s.inboxTracker.GetBatchCount()
+	if err != nil {
+		return fmt.Errorf("error getting batch count from inbox tracker: %w", err)
+	}
+	// the nodes created in this act are numbered in order after the latest node on chain
+	info.LatestStakedNode = latestNodeCreated + info.NodesCreated
+	info.pending = &pendingNode{
+		afterState:    action.assertion.AfterState,
+		inboxMaxCount: new(big.Int).SetUint64(inboxMaxCount),
+	}
+	info.CanProgress = true
+	return nil
+}
+
 func (s *Staker) advanceStake(ctx context.Context, info *OurStakerInfo, effectiveStrategy StakerStrategy) error {
 	cfg := s.config()
 	active := effectiveStrategy >= StakeLatestStrategy
-	action, wrongNodesExist, err := s.generateNodeAction(ctx, info, effectiveStrategy, cfg)
+	action, wrongNodes, err := s.generateNodeAction(ctx, info, effectiveStrategy, cfg)
 	if err != nil {
 		return fmt.Errorf("error generating node action: %w", err)
 	}
-	if wrongNodesExist && effectiveStrategy == WatchtowerStrategy {
-		log.Error("found incorrect assertion in watchtower mode")
+	if info.CatchingUp {
+		s.observeState(StakerStateSyncing)
+	}
+	wrongNodesExist := len(wrongNodes) > 0
+	if effectiveStrategy == WatchtowerStrategy {
+		s.observeSuppressedAction(action, wrongNodes)
 	}
 	if action == nil {
 		info.CanProgress = false
@@ -1026,7 +1983,14 @@ func (s *Staker) advanceStake(ctx context.Context, info *OurStakerInfo, effectiv
 	switch action := action.(type) {
 	case createNodeAction:
 		if wrongNodesExist && cfg.DisableChallenge {
-			log.Error("refusing to challenge assertion as config disables challenges")
+			s.challengeLog.Error("refusing to challenge assertion as config disables challenges")
+			info.CanProgress = false
+			return nil
+		}
+		if cfg.ChallengeOnly() {
+			if wrongNodesExist {
+				s.challengeLog.Error("challenge-only validator can't challenge incorrect assertion until a correct node is created", "node", wrongNodes[0])
+			}
 			info.CanProgress = false
 			return nil
 		}
@@ -1041,6 +2005,7 @@ func (s *Staker) advanceStake(ctx context.Context, info *OurStakerInfo, effectiv
 		}
 
 		// Details are already logged with more details in generateNodeAction
+		following := info.pending != nil
 		info.CanProgress = false
 		info.LatestStakedNode = 0
 		info.LatestStakedNodeHash = action.hash
@@ -1048,16 +2013,34 @@ func (s *Staker) advanceStake(ctx context.Context, info *OurStakerInfo, effectiv
 		// We'll return early if we already have a stake
 		if info.StakeExists {
 			_, err = s.rollup.StakeOnNewNode(s.builder.Auth(ctx), action.assertion.AsLegacySolidityStruct(), action.hash, action.prevInboxMaxCount)
+			if err != nil && following {
+				// the nodes already created in this act are still worth posting
+				log.Warn("not creating more nodes in this act", "err", err)
+				return nil
+			}
 			if err != nil {
 				return fmt.Errorf("error staking on new node: %w", err)
 			}
+			s.nodePostedInAct = true
+			s.observeState(StakerStateCreating)
+			if err := s.followNewNode(ctx, info, action, effectiveStrategy); err != nil {
+				return err
+			}
 			return s.tryFastConfirmation(ctx, action.assertion.AfterState.GlobalState.BlockHash, action.assertion.AfterState.GlobalState.SendRoot, action.hash)
 		}
 
 		// If we have no stake yet, we'll put one down
-		stakeAmount, err := s.rollup.CurrentRequiredStake(s.getCallOpts(ctx))
+		stakeAmount, err := s.newStakeAmount(ctx)
+		if err != nil {
+			return err
+		}
+		decision, err := s.decideStake(ctx, s.stakerAddress(), stakeAmount)
 		if err != nil {
-			return fmt.Errorf("error getting current required stake: %w", err)
+			return err
+		}
+		if decision != StakeDecisionProceed {
+			info.CanProgress = false
+			return nil
 		}
 		_, err = s.rollup.NewStakeOnNewNode(
 			s.builder.AuthWithAmount(ctx, stakeAmount),
@@ -1068,76 +2051,118 @@ func (s *Staker) advanceStake(ctx context.Context, info *OurStakerInfo, effectiv
 		if err != nil {
 			return fmt.Errorf("error placing new stake on new node: %w", err)
 		}
+		s.nodePostedInAct = true
+		s.observeState(StakerStateCreating)
 		info.StakeExists = true
+		if err := s.followNewNode(ctx, info, action, effectiveStrategy); err != nil {
+			return err
+		}
 		return s.tryFastConfirmation(ctx, action.assertion.AfterState.GlobalState.BlockHash, action.assertion.AfterState.GlobalState.SendRoot, action.hash)
 	case existingNodeAction:
-		info.LatestStakedNode = action.number
-		info.LatestStakedNodeHash = action.hash
-		if !active {
-			if wrongNodesExist && effectiveStrategy >= DefensiveStrategy {
-				log.Error("bringing defensive validator online because of incorrect assertion")
-				s.bringActiveUntilNode = action.number
-				info.CanProgress = false
-			} else {
-				s.inactiveLastCheckedNode = &nodeAndHash{
-					id:   action.number,
-					hash: action.hash,
-				}
-				s.inactiveValidatedNodes.ReplaceOrInsert(validatedNode{
-					number: action.number,
-					hash:   action.hash,
-				})
-			}
-			return s.tryFastConfirmationNodeNumber(ctx, action.number, action.hash)
+		return s.advanceToExistingNode(ctx, info, action, wrongNodesExist, active, effectiveStrategy)
+	default:
+		panic("invalid action type")
+	}
+}
+
+// advanceToExistingNode moves the staker's stake, or its view of the chain if it's inactive, to the existing node
+// of action, once verifying the node is built on the node the staker agrees with, its latest staked node.
+func (s *Staker) advanceToExistingNode(ctx context.Context, info *OurStakerInfo, action existingNodeAction, wrongNodesExist bool, active bool, effectiveStrategy StakerStrategy) error {
+	if err := s.VerifyNodePredecessor(ctx, action.number, info.LatestStakedNode); err != nil {
+		if !errors.Is(err, ErrNodePredecessorMismatch) {
+			return err
 		}
-		log.Info("staking on existing node", "node", action.number)
-		// We'll return early if we already havea stake
-		if info.StakeExists {
-			_, err = s.rollup.StakeOnExistingNode(s.builder.Auth(ctx), action.number, action.hash)
-			if err != nil {
-				return fmt.Errorf("error staking on existing node: %w", err)
+		s.createLog.Error("not staking on node which isn't built on the node we agree with", "node", action.number, "err", err)
+		info.CanProgress = false
+		return nil
+	}
+	info.LatestStakedNode = action.number
+	info.LatestStakedNodeHash = action.hash
+	info.StakeMoves++
+	if !active {
+		if wrongNodesExist && effectiveStrategy >= DefensiveStrategy {
+			log.Error("bringing defensive validator online because of incorrect assertion")
+			s.bringActiveUntilNode = action.number
+			info.CanProgress = false
+		} else {
+			s.inactiveLastCheckedNode = &nodeAndHash{
+				id:   action.number,
+				hash: action.hash,
 			}
-			return s.tryFastConfirmationNodeNumber(ctx, action.number, action.hash)
-		}
-
-		// If we have no stake yet, we'll put one down
-		stakeAmount, err := s.rollup.CurrentRequiredStake(s.getCallOpts(ctx))
-		if err != nil {
-			return fmt.Errorf("error getting current required stake: %w", err)
+			s.inactiveValidatedNodes.ReplaceOrInsert(validatedNode{
+				number: action.number,
+				hash:   action.hash,
+			})
 		}
-		_, err = s.rollup.NewStakeOnExistingNode(
-			s.builder.AuthWithAmount(ctx, stakeAmount),
-			action.number,
-			action.hash,
-		)
+		return s.tryFastConfirmationNodeNumber(ctx, action.number, action.hash)
+	}
+	s.createLog.Info("staking on existing node", "node", action.number)
+	// We'll return early if we already havea stake
+	if info.StakeExists {
+		_, err := s.rollup.StakeOnExistingNode(s.builder.Auth(ctx), action.number, action.hash)
 		if err != nil {
-			return fmt.Errorf("error placing new stake on existing node: %w", err)
+			return fmt.Errorf("error staking on existing node: %w", err)
 		}
-		info.StakeExists = true
+		s.observeState(StakerStateStaking)
 		return s.tryFastConfirmationNodeNumber(ctx, action.number, action.hash)
-	default:
-		panic("invalid action type")
 	}
-}
 
-func (s *Staker) createConflict(ctx context.Context, info *StakerInfo) error {
-	if info.CurrentChallenge != nil {
+	// If we have no stake yet, we'll put one down
+	stakeAmount, err := s.newStakeAmount(ctx)
+	if err != nil {
+		return err
+	}
+	decision, err := s.decideStake(ctx, s.stakerAddress(), stakeAmount)
+	if err != nil {
+		return err
+	}
+	if decision != StakeDecisionProceed {
+		info.CanProgress = false
 		return nil
 	}
+	_, err = s.rollup.NewStakeOnExistingNode(
+		s.builder.AuthWithAmount(ctx, stakeAmount),
+		action.number,
+		action.hash,
+	)
+	if err != nil {
+		return fmt.Errorf("error placing new stake on existing node: %w", err)
+	}
+	s.observeState(StakerStateStaking)
+	info.StakeExists = true
+	return s.tryFastConfirmationNodeNumber(ctx, action.number, action.hash)
+}
 
+// getStakers returns every staker in the rollup.
+func (s *Staker) getStakers(ctx context.Context) ([]common.Address, error) {
 	callOpts := s.getCallOpts(ctx)
 	stakers, moreStakers, err := s.validatorUtils.GetStakers(callOpts, s.rollupAddress, 0, 1024)
 	if err != nil {
-		return fmt.Errorf("error getting stakers list: %w", err)
+		return nil, fmt.Errorf("error getting stakers list: %w", err)
 	}
 	for moreStakers {
 		var newStakers []common.Address
 		newStakers, moreStakers, err = s.validatorUtils.GetStakers(callOpts, s.rollupAddress, uint64(len(stakers)), 1024)
 		if err != nil {
-			return fmt.Errorf("error getting more stakers: %w", err)
+			return nil, fmt.Errorf("error getting more stakers: %w", err)
 		}
 		stakers = append(stakers, newStakers...)
 	}
+	return stakers, nil
+}
+
+func (s *Staker) createConflict(ctx context.Context, info *StakerInfo) error {
+	if info.CurrentChallenge != nil {
+		return nil
+	}
+
+	callOpts := s.getCallOpts(ctx)
+	// A stake only ever moves to a child of the staker's latest staked node, so a staker can't be staked on
+	// competing nodes itself, and conflicts are only ever between stakers.
+	stakers, err := s.getStakers(ctx)
+	if err != nil {
+		return err
+	}
 	latestNode, err := s.rollup.LatestConfirmed(callOpts)
 	if err != nil {
 		return err
@@ -1155,23 +2180,21 @@ func (s *Staker) createConflict(ctx context.Context, info *StakerInfo) error {
 		if stakerInfo.CurrentChallenge != nil {
 			continue
 		}
-		conflictInfo, err := s.validatorUtils.FindStakerConflict(callOpts, s.rollupAddress, walletAddr, staker, big.NewInt(1024))
+		conflictInfo, err := s.validatorUtils.FindStakerConflict(callOpts, s.rollupAddress, walletAddr, staker, big.NewInt(stakerConflictSearchDepth))
 		if err != nil {
 			return fmt.Errorf("error finding conflict with staker %v: %w", staker, err)
 		}
 		if ConflictType(conflictInfo.Ty) != CONFLICT_TYPE_FOUND {
 			continue
 		}
-		staker1 := walletAddr
-		staker2 := staker
-		if conflictInfo.Node2 < conflictInfo.Node1 {
-			staker1, staker2 = staker2, staker1
-			conflictInfo.Node1, conflictInfo.Node2 = conflictInfo.Node2, conflictInfo.Node1
-		}
+		conflict := newConflictInfo(walletAddr, staker, conflictInfo.Node1, conflictInfo.Node2)
+		staker1, staker2 := conflict.Staker1, conflict.Staker2
+		conflictInfo.Node1, conflictInfo.Node2 = conflict.Node1, conflict.Node2
 		if conflictInfo.Node1 <= latestNode {
 			// Immaterial as this is past the confirmation point; this must be a zombie
 			continue
 		}
+		s.reportConflict(conflict, latestNode)
 
 		node1Info, err := s.rollup.LookupNode(ctx, conflictInfo.Node1)
 		if err != nil {
@@ -1181,7 +2204,7 @@ func (s *Staker) createConflict(ctx context.Context, info *StakerInfo) error {
 		if err != nil {
 			return fmt.Errorf("error looking up node %v: %w", conflictInfo.Node2, err)
 		}
-		log.Warn("creating challenge", "node1", conflictInfo.Node1, "node2", conflictInfo.Node2, "otherStaker", staker)
+		s.challengeLog.Warn("creating challenge", "node1", conflictInfo.Node1, "node2", conflictInfo.Node2, "otherStaker", staker)
 		_, err = s.rollup.CreateChallenge(
 			s.builder.Auth(ctx),
 			[2]common.Address{staker1, staker2},
@@ -1196,6 +2219,10 @@ func (s *Staker) createConflict(ctx context.Context, info *StakerInfo) error {
 		if err != nil {
 			return fmt.Errorf("error creating challenge: %w", err)
 		}
+		// The rollup only allows a staker to be in one challenge at a time, so any further challenge
+		// would revert, along with this one if the wallet batches them. Conflicts with other stakers
+		// are challenged in later act cycles, once this challenge is resolved.
+		return nil
 	}
 	// No conflicts exist
 	return nil
@@ -1205,6 +2232,28 @@ func (s *Staker) Strategy() StakerStrategy {
 	return s.config().StrategyType()
 }
 
+// observeSuppressedAction records the challenge a watchtower would have started against the first incorrect
+// successor of its staked node, forgetting it once the successors found are all correct.
+func (s *Staker) observeSuppressedAction(action nodeAction, wrongNodes []uint64) {
+	if len(wrongNodes) > 0 {
+		s.challengeLog.Error("found incorrect assertion in watchtower mode", "node", wrongNodes[0])
+		s.suppressedAction.Store(&WatchtowerAction{
+			Action:     WatchtowerActionChallenge,
+			Node:       wrongNodes[0],
+			ObservedAt: time.Now(),
+		})
+	} else if action != nil {
+		s.suppressedAction.Store(nil)
+	}
+}
+
+// SuppressedAction returns the latest action this staker would have taken had it
+// not been running as a watchtower, or nil if it hasn't observed any since it last
+// found its staked node's successors correct.
+func (s *Staker) SuppressedAction() *WatchtowerAction {
+	return s.suppressedAction.Load()
+}
+
 func (s *Staker) Rollup() *RollupWatcher {
 	return s.rollup
 }
@@ -1212,7 +2261,7 @@ func (s *Staker) Rollup() *RollupWatcher {
 func (s *Staker) updateStakerBalanceMetric(ctx context.Context) {
 	txSenderAddress := s.wallet.TxSenderAddress()
 	if txSenderAddress == nil {
-		stakerBalanceGauge.Update(0)
+		s.metrics.UpdateGaugeFloat64(stakerBalanceMetric, 0)
 		return
 	}
 	balance, err := s.client.BalanceAt(ctx, *txSenderAddress, nil)
@@ -1220,5 +2269,6 @@ func (s *Staker) updateStakerBalanceMetric(ctx context.Context) {
 		log.Warn("error getting staker balance", "txSenderAddress", *txSenderAddress, "err", err)
 		return
 	}
-	stakerBalanceGauge.Update(arbmath.BalancePerEther(balance))
+	s.metrics.UpdateGaugeFloat64(stakerBalanceMetric, arbmath.BalancePerEther(balance))
+	s.updateRunwayMetrics(balance)
 }
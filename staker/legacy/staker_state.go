@@ -0,0 +1,87 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// StakerState summarizes what the staker did in its latest act cycle.
+// States are ordered by precedence: if a cycle observed several of them,
+// the staker reports the highest one.
+type StakerState uint8
+
+const (
+	// StakerStateIdle means the staker had nothing to do
+	StakerStateIdle StakerState = iota
+	// StakerStateSyncing means the staker is waiting for the node to catch up to the rollup
+	StakerStateSyncing
+	// StakerStateStaking means the staker holds a stake, or moved or placed one on an existing node
+	StakerStateStaking
+	// StakerStateConfirming means the staker resolved nodes or timed out challenges
+	StakerStateConfirming
+	// StakerStateCreating means the staker created a new node
+	StakerStateCreating
+	// StakerStateChallenging means the staker is in, or started, a challenge
+	StakerStateChallenging
+	// StakerStateZombie means the staker lost its stake in a challenge and can't act until it's removed
+	StakerStateZombie
+	// StakerStatePaused means the staker delayed acting because of high gas prices
+	StakerStatePaused
+	// StakerStateDeauthorized means the staker's address isn't on the rollup's validator whitelist
+	StakerStateDeauthorized
+	// StakerStatePausedUpstream means the staker isn't posting because the rollup contract is paused
+	StakerStatePausedUpstream
+)
+
+func (s StakerState) String() string {
+	switch s {
+	case StakerStateIdle:
+		return "idle"
+	case StakerStateSyncing:
+		return "syncing"
+	case StakerStateStaking:
+		return "staking"
+	case StakerStateConfirming:
+		return "confirming"
+	case StakerStateCreating:
+		return "creating"
+	case StakerStateChallenging:
+		return "challenging"
+	case StakerStateZombie:
+		return "zombie"
+	case StakerStatePaused:
+		return "paused"
+	case StakerStateDeauthorized:
+		return "deauthorized"
+	case StakerStatePausedUpstream:
+		return "paused-upstream"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// State returns the staker's state as of its latest act cycle.
+func (s *Staker) State() StakerState {
+	return StakerState(s.state.Load())
+}
+
+// observeState records that the current act cycle reached state, keeping the highest state observed.
+func (s *Staker) observeState(state StakerState) {
+	if state > s.actState {
+		s.actState = state
+	}
+}
+
+// publishState makes the state observed by the act cycle the staker's current state.
+func (s *Staker) publishState() {
+	state := s.actState
+	previous := StakerState(s.state.Swap(uint32(state)))
+	if previous != state {
+		log.Info("staker state changed", "from", previous, "to", state)
+	}
+	s.metrics.UpdateGauge(stakerStateMetric, int64(state))
+}
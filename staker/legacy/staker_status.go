@@ -0,0 +1,60 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// StakerStatus summarizes the staker's health as of its latest act cycle, e.g. for an admin RPC to surface.
+type StakerStatus struct {
+	State StakerState
+	// Staked is set if the staker holds a stake, and Zombie if it lost it in a challenge
+	Staked bool
+	Zombie bool
+	// InChallenge is set if the staker is in an active challenge
+	InChallenge bool
+	// LatestStakedNode is the node the staker is staked on, or the rollup's latest node if it isn't staked
+	LatestStakedNode    uint64
+	LatestConfirmedNode uint64
+	LatestNode          uint64
+	// Behind is set if the staker is staked on a node older than the rollup's latest node
+	Behind bool
+	// LatestVerifiedConfirmedNode is the latest confirmed node whose send root was checked against local execution
+	LatestVerifiedConfirmedNode uint64
+	// LastActTime is when the latest act cycle completed, and LastActionTime when one last posted a transaction
+	LastActTime    time.Time
+	LastActionTime time.Time
+	// LastError is the error the latest act cycle failed with, or empty if it succeeded
+	LastError string
+}
+
+// Status returns the staker's status as of its latest act cycle. Values the latest act cycle failed
+// before reading are kept from the previous ones.
+func (s *Staker) Status() StakerStatus {
+	status := s.status.Load()
+	if status == nil {
+		return StakerStatus{}
+	}
+	return *status
+}
+
+// publishStatus makes the status observed by the act cycle, which posted tx or failed with err, the staker's status.
+func (s *Staker) publishStatus(tx *types.Transaction, err error) {
+	now := time.Now()
+	if tx != nil {
+		s.actStatus.LastActionTime = now
+	}
+	status := s.actStatus
+	status.State = s.State()
+	status.Behind = status.LatestStakedNode < status.LatestNode
+	status.LastActTime = now
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	s.status.Store(&status)
+}
@@ -0,0 +1,1154 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/btree"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
+	"github.com/offchainlabs/nitro/staker"
+	"github.com/offchainlabs/nitro/staker/txbuilder"
+	"github.com/offchainlabs/nitro/staker/validatorwallet"
+)
+
+func TestChooseStakeAmount(t *testing.T) {
+	requiredStake := big.NewInt(params.Ether)
+	for _, c := range []struct {
+		name            string
+		stakeAmountGwei uint64
+		expected        *big.Int
+	}{
+		{"unconfigured", 0, requiredStake},
+		{"required stake", params.GWei, requiredStake},
+		{"above minimum", 2 * params.GWei, big.NewInt(2 * params.Ether)},
+		{"below minimum", params.GWei / 2, nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			amount, err := chooseStakeAmount(requiredStake, c.stakeAmountGwei)
+			if c.expected == nil {
+				if err == nil {
+					Fail(t, "expected error for stake amount below required minimum, got", amount)
+				}
+				return
+			}
+			Require(t, err)
+			if amount.Cmp(c.expected) != 0 {
+				Fail(t, "staking", amount, "expected", c.expected)
+			}
+		})
+	}
+}
+
+func TestStakeApproval(t *testing.T) {
+	ctx := context.Background()
+	amount := big.NewInt(params.Ether)
+	s := &Staker{}
+	if !s.stakeApproved(ctx, amount) {
+		Fail(t, "expected stake to be auto-approved without a hook")
+	}
+
+	approved := false
+	var options stakerOptions
+	WithStakeApproval(func(_ context.Context, requested *big.Int) bool {
+		if requested.Cmp(amount) != 0 {
+			Fail(t, "unexpected stake amount passed to approval hook", requested)
+		}
+		return approved
+	})(&options)
+	s.stakeApproval = options.stakeApproval
+	if s.stakeApproved(ctx, amount) {
+		Fail(t, "expected stake to be held back until approved")
+	}
+	approved = true
+	if !s.stakeApproved(ctx, amount) {
+		Fail(t, "expected stake to proceed once approved")
+	}
+}
+
+type fakeStakeToken struct {
+	balances map[common.Address]*big.Int
+}
+
+func (f *fakeStakeToken) BalanceOf(_ *bind.CallOpts, account common.Address) (*big.Int, error) {
+	if balance, ok := f.balances[account]; ok {
+		return balance, nil
+	}
+	return common.Big0, nil
+}
+
+func TestDecideStakeWithInsufficientStakeToken(t *testing.T) {
+	ctx := context.Background()
+	amount := big.NewInt(params.Ether)
+	staker := common.HexToAddress("0x1234")
+	token := &fakeStakeToken{balances: map[common.Address]*big.Int{staker: big.NewInt(params.Ether / 2)}}
+	config := TestL1ValidatorConfig
+	Require(t, config.Validate())
+	approvalRequested := false
+	s := &Staker{
+		config:     func() *L1ValidatorConfig { return &config },
+		stakeToken: token,
+		stakeApproval: func(context.Context, *big.Int) bool {
+			approvalRequested = true
+			return true
+		},
+	}
+
+	decision, err := s.decideStake(ctx, staker, amount)
+	Require(t, err)
+	if decision != StakeDecisionInsufficientStakeToken {
+		Fail(t, "expected underfunded staker to decline staking, got", decision)
+	}
+	if approvalRequested {
+		Fail(t, "stake approval requested despite insufficient stake token")
+	}
+
+	config.InsufficientStakeTokenAction = "error"
+	Require(t, config.Validate())
+	decision, err = s.decideStake(ctx, staker, amount)
+	if !errors.Is(err, ErrInsufficientStakeToken) {
+		Fail(t, "expected insufficient stake token error, got", err)
+	}
+	if decision != StakeDecisionInsufficientStakeToken {
+		Fail(t, "unexpected stake decision", decision)
+	}
+
+	token.balances[staker] = amount
+	decision, err = s.decideStake(ctx, staker, amount)
+	Require(t, err)
+	if decision != StakeDecisionProceed || !approvalRequested {
+		Fail(t, "expected funded staker to proceed after approval, got", decision)
+	}
+}
+
+type fakeNodeStakers map[uint64]map[common.Address]bool
+
+func (f fakeNodeStakers) NodeHasStaker(_ *bind.CallOpts, nodeNum uint64, staker common.Address) (bool, error) {
+	return f[nodeNum][staker], nil
+}
+
+type fakeZombie struct {
+	address          common.Address
+	latestStakedNode uint64
+}
+
+type fakeZombieRollup struct {
+	zombies []fakeZombie
+	// prevNodes maps each node to its parent
+	prevNodes map[uint64]uint64
+}
+
+func (r *fakeZombieRollup) ZombieCount(*bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(int64(len(r.zombies))), nil
+}
+
+func (r *fakeZombieRollup) ZombieAddress(_ *bind.CallOpts, zombieNum *big.Int) (common.Address, error) {
+	return r.zombies[zombieNum.Int64()].address, nil
+}
+
+func (r *fakeZombieRollup) ZombieLatestStakedNode(_ *bind.CallOpts, zombieNum *big.Int) (uint64, error) {
+	return r.zombies[zombieNum.Int64()].latestStakedNode, nil
+}
+
+func (r *fakeZombieRollup) GetNode(_ *bind.CallOpts, nodeNum uint64) (rollup_legacy_gen.Node, error) {
+	prev, ok := r.prevNodes[nodeNum]
+	if !ok {
+		return rollup_legacy_gen.Node{}, fmt.Errorf("node %v not found", nodeNum)
+	}
+	return rollup_legacy_gen.Node{PrevNum: prev}, nil
+}
+
+func TestFindZombieRemoval(t *testing.T) {
+	us := common.HexToAddress("0x1234")
+	other := common.HexToAddress("0x5678")
+	// node 1 has two children: 2, continued by 4 and 5, and the conflicting 3
+	rollup := &fakeZombieRollup{
+		zombies:   []fakeZombie{{other, 3}, {us, 5}},
+		prevNodes: map[uint64]uint64{2: 1, 3: 1, 4: 2, 5: 4},
+	}
+
+	removal, err := findZombieRemoval(&bind.CallOpts{}, rollup, us, 1)
+	Require(t, err)
+	if removal != nil {
+		Fail(t, "zombie with an unconfirmed chain shouldn't be removed yet")
+	}
+	removal, err = findZombieRemoval(&bind.CallOpts{}, rollup, us, 2)
+	Require(t, err)
+	if removal != nil {
+		Fail(t, "zombie staked on a descendant of the latest confirmed node shouldn't be removed yet")
+	}
+
+	removal, err = findZombieRemoval(&bind.CallOpts{}, rollup, us, 3)
+	Require(t, err)
+	if removal == nil {
+		Fail(t, "zombie whose chain conflicts with the latest confirmed node wasn't removed")
+	}
+	if removal.zombieNum.Int64() != 1 || removal.maxNodes != 2 {
+		Fail(t, "unexpected zombie removal", removal.zombieNum, removal.maxNodes)
+	}
+
+	removal, err = findZombieRemoval(&bind.CallOpts{}, rollup, common.HexToAddress("0x9abc"), 3)
+	Require(t, err)
+	if removal != nil {
+		Fail(t, "found zombie removal for a staker which isn't a zombie")
+	}
+}
+
+func TestRescueZombieStake(t *testing.T) {
+	ctx := context.Background()
+	rollupAddress := common.HexToAddress("0x1000")
+	us := common.HexToAddress("0x1234")
+	// we're a zombie staked on node 3, which conflicts with node 2
+	results := map[string]hexutil.Bytes{}
+	addResult := func(method string, output interface{}, args ...interface{}) {
+		call, err := rollupABI.Pack(method, args...)
+		Require(t, err)
+		result, err := rollupABI.Methods[method].Outputs.Pack(output)
+		Require(t, err)
+		results[string(call)] = result
+	}
+	addResult("zombieCount", big.NewInt(1))
+	addResult("zombieAddress", us, big.NewInt(0))
+	addResult("zombieLatestStakedNode", uint64(3), big.NewInt(0))
+	addResult("getNode", rollup_legacy_gen.Node{PrevNum: 1}, uint64(2))
+	addResult("getNode", rollup_legacy_gen.Node{PrevNum: 1}, uint64(3))
+	addResult("withdrawableFunds", big.NewInt(5), us)
+	client := newFakeEthClient(t, &fakeEthService{results: results})
+	rollup, err := NewRollupWatcher(rollupAddress, client, bind.CallOpts{})
+	Require(t, err)
+	s := &Staker{L1Validator: &L1Validator{rollup: rollup, rollupAddress: rollupAddress, client: client}}
+	s.builder, err = txbuilder.NewBuilder(validatorwallet.NewNoOp(nil), common.Address{})
+	Require(t, err)
+
+	// our chain might still be confirmed
+	Require(t, s.rescueZombieStake(ctx, us, 1))
+	if count := s.builder.BuildingTransactionCount(); count != 0 {
+		Fail(t, "queued", count, "transactions rescuing a zombie whose chain includes the latest confirmed node")
+	}
+
+	// once node 2 is confirmed, we remove ourselves from the zombies and withdraw our funds
+	Require(t, s.rescueZombieStake(ctx, us, 2))
+	txs := s.builder.BuildingTransactions()
+	if len(txs) != 2 {
+		Fail(t, "expected 2 transactions rescuing the zombie stake, got", len(txs))
+	}
+	removeZombie := rollupABI.Methods["removeZombie"]
+	if !bytes.Equal(txs[0].Data()[:4], removeZombie.ID) {
+		Fail(t, "first rescue transaction doesn't remove the zombie")
+	}
+	args, err := removeZombie.Inputs.Unpack(txs[0].Data()[4:])
+	Require(t, err)
+	if zombieNum, maxNodes := args[0].(*big.Int), args[1].(*big.Int); zombieNum.Sign() != 0 || maxNodes.Uint64() != 1 {
+		Fail(t, "removing zombie", zombieNum, "from", maxNodes, "nodes, expected zombie 0 from 1 node")
+	}
+	if !bytes.Equal(txs[1].Data()[:4], rollupABI.Methods["withdrawStakerFunds"].ID) {
+		Fail(t, "second rescue transaction doesn't withdraw the staker's funds")
+	}
+}
+
+func TestStakedNodeConfirmedCallback(t *testing.T) {
+	ctx := context.Background()
+	us := common.HexToAddress("0x1234")
+	other := common.HexToAddress("0x5678")
+	nodes := fakeNodeStakers{
+		1: {us: true},
+		2: {us: true, other: true},
+		3: {other: true},
+		4: {us: true},
+	}
+	var confirmedNodes []uint64
+	var options stakerOptions
+	WithStakedNodeConfirmed(func(node uint64) {
+		confirmedNodes = append(confirmedNodes, node)
+	})(&options)
+	s := &Staker{onStakedNodeConfirmed: options.onConfirmed}
+
+	// nodes confirmed before the staker started aren't reported
+	Require(t, s.notifyStakedNodesConfirmed(ctx, nodes, us, 1))
+	if len(confirmedNodes) != 0 {
+		Fail(t, "unexpected callback for node confirmed before starting", confirmedNodes)
+	}
+
+	// we staked on node 2, which gets confirmed along with node 3 we didn't stake on
+	Require(t, s.notifyStakedNodesConfirmed(ctx, nodes, us, 3))
+	if len(confirmedNodes) != 1 || confirmedNodes[0] != 2 {
+		Fail(t, "expected callback for confirmed staked node 2, got", confirmedNodes)
+	}
+
+	// no new confirmations
+	Require(t, s.notifyStakedNodesConfirmed(ctx, nodes, us, 3))
+	if len(confirmedNodes) != 1 {
+		Fail(t, "callback fired again without new confirmations", confirmedNodes)
+	}
+
+	Require(t, s.notifyStakedNodesConfirmed(ctx, nodes, us, 4))
+	if len(confirmedNodes) != 2 || confirmedNodes[1] != 4 {
+		Fail(t, "expected callback for confirmed staked node 4, got", confirmedNodes)
+	}
+}
+
+func TestActWithDeadline(t *testing.T) {
+	ctx := context.Background()
+	timeout := 50 * time.Millisecond
+	// slowL1Act blocks like an L1 call which doesn't respond until the context is cancelled
+	slowL1Act := func(postedTx *types.Transaction) func(context.Context) (*types.Transaction, error) {
+		return func(ctx context.Context) (*types.Transaction, error) {
+			<-ctx.Done()
+			return postedTx, fmt.Errorf("error getting staker info: %w", ctx.Err())
+		}
+	}
+
+	cleared := false
+	clearBuilder := func() { cleared = true }
+	start := time.Now()
+	tx, err := actWithDeadline(ctx, timeout, slowL1Act(nil), clearBuilder)
+	if !errors.Is(err, ErrActTimeout) {
+		Fail(t, "expected act timeout, got", err)
+	}
+	if tx != nil {
+		Fail(t, "unexpected transaction from timed out act")
+	}
+	if elapsed := time.Since(start); elapsed > 10*timeout {
+		Fail(t, "act didn't respect its deadline, took", elapsed)
+	}
+	if !cleared {
+		Fail(t, "builder wasn't cleared after act timed out")
+	}
+
+	// A transaction posted before the deadline is returned as progress
+	cleared = false
+	postedTx := types.NewTx(&types.LegacyTx{Nonce: 1})
+	tx, err = actWithDeadline(ctx, timeout, slowL1Act(postedTx), clearBuilder)
+	Require(t, err)
+	if tx != postedTx {
+		Fail(t, "expected the posted transaction to be returned, got", tx)
+	}
+	if !cleared {
+		Fail(t, "builder wasn't cleared after act timed out")
+	}
+
+	// Errors not caused by the deadline are returned as is
+	actErr := errors.New("rollup call failed")
+	_, err = actWithDeadline(ctx, timeout, func(context.Context) (*types.Transaction, error) { return nil, actErr }, clearBuilder)
+	if !errors.Is(err, actErr) || errors.Is(err, ErrActTimeout) {
+		Fail(t, "expected the act error, got", err)
+	}
+
+	// Cancelling the parent context isn't a timeout
+	parentCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = actWithDeadline(parentCtx, timeout, slowL1Act(nil), clearBuilder)
+	if errors.Is(err, ErrActTimeout) {
+		Fail(t, "cancelled parent context reported as act timeout")
+	}
+}
+
+type fakePausedRollup struct {
+	paused bool
+}
+
+func (f *fakePausedRollup) Paused(*bind.CallOpts) (bool, error) {
+	return f.paused, nil
+}
+
+func TestCheckRollupPaused(t *testing.T) {
+	ctx := context.Background()
+	capture := &capturingHandler{}
+	prevRoot := log.Root()
+	log.SetDefault(log.NewLogger(capture))
+	defer log.SetDefault(prevRoot)
+
+	config := TestL1ValidatorConfig
+	Require(t, config.Validate())
+	rollup := &fakePausedRollup{}
+	s := &Staker{
+		config:       func() *L1ValidatorConfig { return &config },
+		pausedRollup: rollup,
+	}
+
+	paused, err := s.checkRollupPaused(ctx)
+	Require(t, err)
+	if paused {
+		Fail(t, "expected unpaused rollup to not pause the staker")
+	}
+
+	// the staker idles while the rollup is paused, only logging when it gets paused
+	rollup.paused = true
+	for i := 0; i < 3; i++ {
+		paused, err = s.checkRollupPaused(ctx)
+		Require(t, err)
+		if !paused {
+			Fail(t, "expected paused rollup to pause the staker")
+		}
+	}
+	if captured := capture.captured(); len(captured) != 1 {
+		Fail(t, "expected a single log while the rollup is paused, got", captured)
+	}
+
+	rollup.paused = false
+	paused, err = s.checkRollupPaused(ctx)
+	Require(t, err)
+	if paused {
+		Fail(t, "expected staker to resume once the rollup is unpaused")
+	}
+	if captured := capture.captured(); len(captured) != 2 {
+		Fail(t, "expected resuming to be logged once, got", captured)
+	}
+
+	config.PausedRollupAction = "error"
+	Require(t, config.Validate())
+	rollup.paused = true
+	if _, err := s.checkRollupPaused(ctx); !errors.Is(err, ErrRollupPaused) {
+		Fail(t, "expected paused rollup error, got", err)
+	}
+}
+
+func TestVerifyNewlyConfirmedNodes(t *testing.T) {
+	ctx := context.Background()
+	sink := newRecordingMetricsSink()
+	s := &Staker{metrics: sink}
+	divergent := uint64(5)
+	var verified []uint64
+	verify := func(_ context.Context, nodeNum uint64) (*SendRootVerification, error) {
+		verified = append(verified, nodeNum)
+		return &SendRootVerification{Node: nodeNum, Match: nodeNum != divergent}, nil
+	}
+	expectVerified := func(expected ...uint64) {
+		t.Helper()
+		if fmt.Sprint(verified) != fmt.Sprint(expected) {
+			Fail(t, "expected confirmed nodes", expected, "to be verified, got", verified)
+		}
+		verified = nil
+	}
+
+	// the first check only verifies the latest confirmed node, rather than the whole history
+	Require(t, s.verifyNewlyConfirmedNodes(ctx, 3, verify))
+	expectVerified(3)
+	// nothing is validated until another node is confirmed
+	Require(t, s.verifyNewlyConfirmedNodes(ctx, 3, verify))
+	expectVerified()
+	if s.ConfirmedDivergence() != nil {
+		Fail(t, "unexpected divergence", s.ConfirmedDivergence())
+	}
+
+	Require(t, s.verifyNewlyConfirmedNodes(ctx, 6, verify))
+	expectVerified(4, 5, 6)
+	divergence := s.ConfirmedDivergence()
+	if divergence == nil || divergence.Node != divergent {
+		Fail(t, "expected divergence of node", divergent, "to be reported, got", divergence)
+	}
+	if sink.gauges[stakerConfirmedDivergenceMetric] != 1 {
+		Fail(t, "unexpected confirmed divergence metric", sink.gauges[stakerConfirmedDivergenceMetric])
+	}
+
+	// a node which can't be verified yet is retried on the next check
+	failing := errors.New("not caught up")
+	err := s.verifyNewlyConfirmedNodes(ctx, 7, func(context.Context, uint64) (*SendRootVerification, error) {
+		return nil, failing
+	})
+	if !errors.Is(err, failing) {
+		Fail(t, "expected verification error, got", err)
+	}
+	Require(t, s.verifyNewlyConfirmedNodes(ctx, 7, verify))
+	expectVerified(7)
+}
+
+func TestMakeNodesAggressiveStrategy(t *testing.T) {
+	config := TestL1ValidatorConfig
+	config.Strategy = "MakeNodesAggressive"
+	Require(t, config.Validate())
+	if config.StrategyType() != MakeNodesAggressiveStrategy {
+		Fail(t, "unexpected strategy", config.StrategyType())
+	}
+	// the aggressive strategy still does everything the make nodes strategy does
+	if config.StrategyType() < MakeNodesStrategy {
+		Fail(t, "expected the aggressive strategy to make nodes")
+	}
+
+	config.AggressiveDepth = 0
+	if config.Validate() == nil {
+		Fail(t, "expected the aggressive strategy to require a positive depth")
+	}
+	config.Strategy = "MakeNodes"
+	Require(t, config.Validate())
+}
+
+type gasEstimatingWallet struct {
+	*validatorwallet.NoOp
+	gas uint64
+	err error
+}
+
+func (w *gasEstimatingWallet) EstimateTransactionsGas(context.Context, []*types.Transaction) (uint64, error) {
+	return w.gas, w.err
+}
+
+func TestCanAffordNextNode(t *testing.T) {
+	ctx := context.Background()
+	wallet := &gasEstimatingWallet{NoOp: validatorwallet.NewNoOp(nil), gas: 3_000_000}
+	s := &Staker{L1Validator: &L1Validator{wallet: wallet}}
+	s.builder, _ = txbuilder.NewBuilder(wallet, common.Address{})
+	config := TestL1ValidatorConfig
+	info := &OurStakerInfo{NodesCreated: 2}
+
+	// two nodes estimated at 3M gas, so a third is expected to bring it to 4.5M
+	config.AggressiveMaxGas = 5_000_000
+	if !s.canAffordNextNode(ctx, info, &config) {
+		Fail(t, "expected another node to fit within the max gas")
+	}
+	config.AggressiveMaxGas = 4_000_000
+	if s.canAffordNextNode(ctx, info, &config) {
+		Fail(t, "expected another node to exceed the max gas")
+	}
+	wallet.err = errors.New("execution reverted")
+	if s.canAffordNextNode(ctx, info, &config) {
+		Fail(t, "expected no more nodes when the gas can't be estimated")
+	}
+	config.AggressiveMaxGas = 0
+	if !s.canAffordNextNode(ctx, info, &config) {
+		Fail(t, "expected no limit with the max gas disabled")
+	}
+}
+
+type unfundedWallet struct {
+	*validatorwallet.NoOp
+	sender   common.Address
+	funded   bool
+	attempts int
+}
+
+func (w *unfundedWallet) TxSenderAddress() *common.Address {
+	return &w.sender
+}
+
+func (w *unfundedWallet) ExecuteTransactions(_ context.Context, txs []*types.Transaction, _ common.Address) (*types.Transaction, error) {
+	w.attempts++
+	if !w.funded {
+		return nil, errors.New("insufficient funds for gas * price + value")
+	}
+	return txs[0], nil
+}
+
+func TestStakerChallengeMoveInsufficientFunds(t *testing.T) {
+	ctx := context.Background()
+	wallet := &unfundedWallet{NoOp: validatorwallet.NewNoOp(nil), sender: common.HexToAddress("0x1234")}
+	config := TestL1ValidatorConfig
+	sink := newRecordingMetricsSink()
+	s := &Staker{
+		L1Validator:  &L1Validator{wallet: wallet},
+		config:       func() *L1ValidatorConfig { return &config },
+		metrics:      sink,
+		challengeLog: log.New(),
+	}
+	var err error
+	s.builder, err = txbuilder.NewBuilder(wallet, common.Address{})
+	Require(t, err)
+	batchMove := func() {
+		s.builder.ClearTransactions()
+		_, err := s.builder.Auth(ctx).Signer(common.Address{}, types.NewTx(&types.LegacyTx{}))
+		Require(t, err)
+		s.challengeMoveBatched = true
+	}
+
+	// without a challenge move in the batch, running out of funds is an ordinary failure
+	s.builder.ClearTransactions()
+	_, err = s.builder.Auth(ctx).Signer(common.Address{}, types.NewTx(&types.LegacyTx{}))
+	Require(t, err)
+	_, err = s.executeTransactions(ctx)
+	if err == nil || errors.Is(err, ErrChallengeMoveInsufficientFunds) {
+		Fail(t, "expected a plain insufficient funds error, got", err)
+	}
+
+	batchMove()
+	_, err = s.executeTransactions(ctx)
+	if !errors.Is(err, ErrChallengeMoveInsufficientFunds) {
+		Fail(t, "expected insufficient funds for the challenge move, got", err)
+	}
+	if sink.gauges[stakerChallengeFundsMetric] != 1 {
+		Fail(t, "unexpected insufficient funds metric", sink.gauges[stakerChallengeFundsMetric])
+	}
+
+	var toppedUp []common.Address
+	config.ChallengeMoveTopUp = true
+	s.emergencyTopUp = func(_ context.Context, account common.Address) error {
+		toppedUp = append(toppedUp, account)
+		wallet.funded = true
+		return nil
+	}
+	wallet.attempts = 0
+	batchMove()
+	tx, err := s.executeTransactions(ctx)
+	Require(t, err)
+	if tx == nil || wallet.attempts != 2 {
+		Fail(t, "expected the batch to be resent once after the top-up, attempts", wallet.attempts)
+	}
+	if len(toppedUp) != 1 || toppedUp[0] != wallet.sender {
+		Fail(t, "expected the transaction sender to be topped up once, got", toppedUp)
+	}
+	if s.builder.BuildingTransactionCount() != 0 {
+		Fail(t, "batch not cleared after being sent")
+	}
+}
+
+type fakeConfirmedChainRollup map[uint64]rollup_legacy_gen.Node
+
+func (r fakeConfirmedChainRollup) GetNode(_ *bind.CallOpts, nodeNum uint64) (rollup_legacy_gen.Node, error) {
+	node, ok := r[nodeNum]
+	if !ok {
+		return rollup_legacy_gen.Node{}, fmt.Errorf("node %v not found", nodeNum)
+	}
+	return node, nil
+}
+
+func TestFindConfirmedChainAnomaly(t *testing.T) {
+	// node 3 was rejected, so the confirmed chain is 0, 1, 2, 4 and 5
+	rollup := fakeConfirmedChainRollup{
+		0: {CreatedAtBlock: 10},
+		1: {PrevNum: 0, CreatedAtBlock: 20},
+		2: {PrevNum: 1, CreatedAtBlock: 30},
+		3: {PrevNum: 1, CreatedAtBlock: 35},
+		4: {PrevNum: 2, CreatedAtBlock: 40},
+		5: {PrevNum: 4, CreatedAtBlock: 50},
+	}
+	for _, fromNode := range []uint64{0, 2, 5} {
+		anomaly, err := findConfirmedChainAnomaly(nil, rollup, fromNode, 5)
+		Require(t, err)
+		if anomaly != nil {
+			Fail(t, "healthy confirmed chain from node", fromNode, "flagged:", anomaly)
+		}
+	}
+
+	// a node believed confirmed which isn't an ancestor of the latest confirmed node is a fork
+	anomaly, err := findConfirmedChainAnomaly(nil, rollup, 3, 5)
+	Require(t, err)
+	if anomaly == nil || anomaly.Node != 4 || anomaly.PrevNum != 2 {
+		Fail(t, "expected the confirmed chain to skip over node 3 at node 4, got", anomaly)
+	}
+
+	// a predecessor link pointing forward breaks the chain
+	rollup[4] = rollup_legacy_gen.Node{PrevNum: 5, CreatedAtBlock: 40}
+	anomaly, err = findConfirmedChainAnomaly(nil, rollup, 0, 5)
+	Require(t, err)
+	if anomaly == nil || anomaly.Node != 4 || anomaly.PrevNum != 5 {
+		Fail(t, "expected the forward predecessor link of node 4 to be flagged, got", anomaly)
+	}
+
+	// as does a predecessor created after the node
+	rollup[4] = rollup_legacy_gen.Node{PrevNum: 2, CreatedAtBlock: 25}
+	anomaly, err = findConfirmedChainAnomaly(nil, rollup, 0, 5)
+	Require(t, err)
+	if anomaly == nil || anomaly.Node != 4 || anomaly.PrevNum != 2 {
+		Fail(t, "expected the predecessor of node 4 created after it to be flagged, got", anomaly)
+	}
+}
+
+func TestConfirmerConfig(t *testing.T) {
+	config := TestL1ValidatorConfig
+	config.Confirmer = true
+	Require(t, config.Validate())
+	if !config.ValidatorRequired() {
+		Fail(t, "expected a confirmer to require a block validator")
+	}
+	// a confirmer doesn't stake, so it can only run as a watchtower validating unconfirmed nodes
+	config.Strategy = "MakeNodes"
+	if config.Validate() == nil {
+		Fail(t, "expected a confirmer to require the watchtower strategy")
+	}
+	config.Strategy = "Watchtower"
+	config.ConfirmedOnlyWatchtower = true
+	if config.Validate() == nil {
+		Fail(t, "expected a confirmer not to be a confirmed-only watchtower")
+	}
+}
+
+func TestWalletCreationGas(t *testing.T) {
+	config := TestL1ValidatorConfig
+	config.ExtraGas = 50000
+	Require(t, config.Validate())
+	if config.WalletCreationGas() != config.ExtraGas {
+		Fail(t, "expected the wallet to be created with the extra gas by default, got", config.WalletCreationGas())
+	}
+	config.WalletCreationExtraGas = 500000
+	if config.WalletCreationGas() != config.WalletCreationExtraGas {
+		Fail(t, "expected the wallet to be created with the wallet creation extra gas, got", config.WalletCreationGas())
+	}
+}
+
+func TestConfirmerConflictOverNode(t *testing.T) {
+	// node 11 is next to be confirmed after node 10, with nodes 12 and 13 competing children of it, and node 14
+	// competing with it. Staker 1 lags behind on node 10, in agreement with everyone.
+	stakerNodes := map[common.Address]uint64{{1}: 10, {2}: 12, {3}: 14, {4}: 13}
+	stakers := []common.Address{{1}, {2}, {3}, {4}}
+	latestStaked := func(staker common.Address) (uint64, error) {
+		return stakerNodes[staker], nil
+	}
+	incomplete := false
+	findConflict := func(staker1, staker2 common.Address) (ConflictType, uint64, uint64, error) {
+		nodes := [2]uint64{stakerNodes[staker1], stakerNodes[staker2]}
+		switch nodes {
+		case [2]uint64{12, 13}:
+			if incomplete {
+				return CONFLICT_TYPE_INCOMPLETE, 0, 0, nil
+			}
+			return CONFLICT_TYPE_FOUND, 12, 13, nil
+		case [2]uint64{12, 14}, [2]uint64{13, 14}:
+			return CONFLICT_TYPE_FOUND, 11, 14, nil
+		default:
+			return CONFLICT_TYPE_NONE, 0, 0, nil
+		}
+	}
+	conflictOverNext := func() *StakerConflict {
+		t.Helper()
+		conflicts, err := findAllStakerConflicts(stakers, latestStaked, 10, findConflict)
+		Require(t, err)
+		return conflictOverNode(conflicts, 11)
+	}
+
+	// the stakers disagreeing over node 11 hold off confirming it, though neither disagrees with the first staker
+	if conflict := conflictOverNext(); conflict == nil || conflict.Node1 != 11 || conflict.Node2 != 14 || conflict.Staker2 != (common.Address{3}) {
+		Fail(t, "expected the conflict over node 11 with staker 3, got", conflict)
+	}
+
+	// once staker 3 is gone, the conflict between node 11's children is left to be settled later
+	stakers = []common.Address{{1}, {2}, {4}}
+	if conflict := conflictOverNext(); conflict != nil {
+		Fail(t, "conflict past node 11 held off confirming it:", conflict)
+	}
+
+	// a conflict which can't be ruled out holds off confirming
+	incomplete = true
+	if conflict := conflictOverNext(); conflict == nil || conflict.Type != CONFLICT_TYPE_INCOMPLETE {
+		Fail(t, "expected the incomplete conflict search to hold off confirming, got", conflict)
+	}
+}
+
+func TestReportConflict(t *testing.T) {
+	var options stakerOptions
+	var reported []ConflictInfo
+	WithConflictHandler(func(conflict ConflictInfo) { reported = append(reported, conflict) })(&options)
+	s := &Staker{conflictHandler: options.onConflict}
+
+	// conflicts are reported with the older node first
+	ours, theirs := common.Address{1}, common.Address{2}
+	conflict := newConflictInfo(ours, theirs, 12, 11)
+	if conflict != (ConflictInfo{Staker1: theirs, Staker2: ours, Node1: 11, Node2: 12}) {
+		Fail(t, "unexpected conflict", conflict)
+	}
+	s.reportConflict(conflict, 10)
+	s.reportConflict(newConflictInfo(theirs, ours, 11, 12), 10)
+	if len(reported) != 1 || reported[0] != conflict {
+		Fail(t, "expected the conflict to be reported once, got", reported)
+	}
+
+	other := newConflictInfo(ours, common.Address{3}, 13, 14)
+	s.reportConflict(other, 10)
+	if len(reported) != 2 || reported[1] != other {
+		Fail(t, "expected another conflict to be reported, got", reported)
+	}
+
+	// a conflict settled by confirmation isn't reported, and is forgotten
+	s.reportConflict(conflict, 11)
+	if len(reported) != 2 {
+		Fail(t, "settled conflict reported", reported)
+	}
+	if s.reportedConflicts[conflict] || !s.reportedConflicts[other] {
+		Fail(t, "unexpected reported conflicts", s.reportedConflicts)
+	}
+
+	// without a handler there's nothing to report to
+	s = &Staker{}
+	s.reportConflict(conflict, 10)
+	if s.reportedConflicts != nil {
+		Fail(t, "conflicts tracked without a handler", s.reportedConflicts)
+	}
+}
+
+func TestRetryTransientActErrors(t *testing.T) {
+	ctx := context.Background()
+	policy := ActRetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+	failures := []error{
+		fmt.Errorf("%w: data poster nonce 2 is ahead of on-chain nonce 1", ErrDataPosterNotReady),
+		fmt.Errorf("%w to node 3", ErrNotCaughtUp),
+	}
+	acts := 0
+	act := func(context.Context) (*types.Transaction, error) {
+		acts++
+		if acts <= len(failures) {
+			return nil, failures[acts-1]
+		}
+		return tx, nil
+	}
+	got, err := retryTransientActErrors(ctx, policy, act)
+	Require(t, err)
+	if got != tx || acts != 3 {
+		Fail(t, "expected the act to succeed after 3 attempts, got", got, "after", acts)
+	}
+
+	// permanent errors are returned immediately
+	acts = 0
+	permanent := errors.New("wasmroot doesn't match rollup")
+	_, err = retryTransientActErrors(ctx, policy, func(context.Context) (*types.Transaction, error) {
+		acts++
+		return nil, permanent
+	})
+	if !errors.Is(err, permanent) || acts != 1 {
+		Fail(t, "expected permanent error after 1 attempt, got", err, "after", acts)
+	}
+
+	// transient errors are returned once the attempts run out
+	acts = 0
+	_, err = retryTransientActErrors(ctx, policy, func(context.Context) (*types.Transaction, error) {
+		acts++
+		return nil, ErrBlockValidationPending
+	})
+	if !errors.Is(err, ErrBlockValidationPending) || acts != policy.MaxAttempts {
+		Fail(t, "expected transient error after", policy.MaxAttempts, "attempts, got", err, "after", acts)
+	}
+
+	// retrying stops once the context is done
+	cancelCtx, cancel := context.WithCancel(ctx)
+	_, err = retryTransientActErrors(cancelCtx, ActRetryPolicy{InitialBackoff: time.Hour}, func(context.Context) (*types.Transaction, error) {
+		cancel()
+		return nil, fmt.Errorf("%w 5", ErrNodeNotFound)
+	})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrNodeNotFound) {
+		Fail(t, "expected cancellation while retrying, got", err)
+	}
+}
+
+func TestWatchtowerSuppressedAction(t *testing.T) {
+	s := &Staker{challengeLog: log.New()}
+	if s.SuppressedAction() != nil {
+		Fail(t, "unexpected suppressed action before acting")
+	}
+
+	// an incorrect node would have been challenged, even with a correct sibling
+	s.observeSuppressedAction(existingNodeAction{number: 6}, []uint64{7, 9})
+	suppressed := s.SuppressedAction()
+	if suppressed == nil || suppressed.Action != WatchtowerActionChallenge || suppressed.Node != 7 || suppressed.ObservedAt.IsZero() {
+		Fail(t, "unexpected suppressed action", suppressed)
+	}
+
+	// not examining the successors, e.g. while catching up, keeps the suppressed action
+	s.observeSuppressedAction(nil, nil)
+	if s.SuppressedAction() != suppressed {
+		Fail(t, "suppressed action forgotten without examining successors")
+	}
+
+	// finding only a correct successor forgets it
+	s.observeSuppressedAction(existingNodeAction{number: 10}, nil)
+	if s.SuppressedAction() != nil {
+		Fail(t, "suppressed action kept after finding only correct successors", s.SuppressedAction())
+	}
+}
+
+func TestStakerStatus(t *testing.T) {
+	s := &Staker{}
+	if status := s.Status(); status.LastActTime != (time.Time{}) {
+		Fail(t, "unexpected status before acting", status)
+	}
+
+	s.actStatus = StakerStatus{Staked: true, InChallenge: true, LatestStakedNode: 5, LatestConfirmedNode: 3, LatestNode: 8}
+	s.state.Store(uint32(StakerStateChallenging))
+	failure := errors.New("act failed")
+	s.publishStatus(nil, failure)
+	status := s.Status()
+	if !status.Staked || !status.InChallenge || status.LatestStakedNode != 5 || status.LatestConfirmedNode != 3 || status.LatestNode != 8 {
+		Fail(t, "unexpected status", status)
+	}
+	if !status.Behind {
+		Fail(t, "staker staked behind the latest node not reported as behind", status)
+	}
+	if status.State != StakerStateChallenging || status.LastError != failure.Error() {
+		Fail(t, "unexpected status state or error", status.State, status.LastError)
+	}
+	if status.LastActTime.IsZero() || !status.LastActionTime.IsZero() {
+		Fail(t, "unexpected status times", status.LastActTime, status.LastActionTime)
+	}
+
+	// a successful act cycle clears the error, and one posting a transaction is the latest action
+	s.actStatus.LatestStakedNode = 8
+	s.publishStatus(types.NewTx(&types.LegacyTx{}), nil)
+	status = s.Status()
+	if status.Behind {
+		Fail(t, "staker staked on the latest node reported as behind", status)
+	}
+	if status.LastError != "" || status.LastActionTime.IsZero() || status.LastActionTime != status.LastActTime {
+		Fail(t, "unexpected status after posting a transaction", status)
+	}
+	lastAction := status.LastActionTime
+	s.publishStatus(nil, nil)
+	if status = s.Status(); status.LastActionTime != lastAction {
+		Fail(t, "last action time changed without posting a transaction", status.LastActionTime, lastAction)
+	}
+}
+
+type fakeChallengeManagerProxy struct {
+	address        common.Address
+	implementation common.Address
+}
+
+func (p *fakeChallengeManagerProxy) ChallengeManager(*bind.CallOpts) (common.Address, error) {
+	return p.address, nil
+}
+
+func (p *fakeChallengeManagerProxy) StorageAt(_ context.Context, account common.Address, key common.Hash, _ *big.Int) ([]byte, error) {
+	if account != p.address || key != arbutil.ProxyImplementationSlot {
+		return common.Hash{}.Bytes(), nil
+	}
+	return common.BytesToHash(p.implementation.Bytes()).Bytes(), nil
+}
+
+func TestChallengeManagerVersion(t *testing.T) {
+	proxy := &fakeChallengeManagerProxy{address: common.Address{1}, implementation: common.Address{2}}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+	initial, err := readChallengeManagerVersion(callOpts, proxy, proxy)
+	Require(t, err)
+	if initial.address != proxy.address || initial.implementation != proxy.implementation {
+		Fail(t, "unexpected challenge manager version", initial)
+	}
+
+	// upgrading the proxy's implementation changes the version, as does replacing the proxy
+	proxy.implementation = common.Address{3}
+	upgraded, err := readChallengeManagerVersion(callOpts, proxy, proxy)
+	Require(t, err)
+	if upgraded == initial || upgraded.implementation != proxy.implementation {
+		Fail(t, "upgraded implementation not detected", upgraded)
+	}
+	proxy.address = common.Address{4}
+	replaced, err := readChallengeManagerVersion(callOpts, proxy, proxy)
+	Require(t, err)
+	if replaced == upgraded || replaced.address != proxy.address {
+		Fail(t, "replaced challenge manager not detected", replaced)
+	}
+}
+
+func TestFindAllStakerConflicts(t *testing.T) {
+	// stakers 1 and 2 agree on node 12, which competes with staker 3's node 13 and staker 4's node 14,
+	// while staker 5's node 9 competes with node 12 but was settled by confirming node 10
+	stakerNodes := map[common.Address]uint64{{1}: 12, {2}: 12, {3}: 13, {4}: 14, {5}: 9}
+	stakers := []common.Address{{1}, {2}, {3}, {4}, {5}}
+	latestStaked := func(staker common.Address) (uint64, error) {
+		return stakerNodes[staker], nil
+	}
+	checked := make(map[[2]uint64]bool)
+	findConflict := func(staker1, staker2 common.Address) (ConflictType, uint64, uint64, error) {
+		nodes := [2]uint64{stakerNodes[staker1], stakerNodes[staker2]}
+		if checked[nodes] {
+			return 0, 0, 0, fmt.Errorf("nodes %v checked twice", nodes)
+		}
+		checked[nodes] = true
+		switch nodes {
+		case [2]uint64{12, 13}:
+			return CONFLICT_TYPE_FOUND, 12, 13, nil
+		case [2]uint64{12, 14}:
+			return CONFLICT_TYPE_INCOMPLETE, 0, 0, nil
+		case [2]uint64{9, 12}:
+			return CONFLICT_TYPE_FOUND, 9, 12, nil
+		default:
+			return CONFLICT_TYPE_NONE, 0, 0, nil
+		}
+	}
+	conflicts, err := findAllStakerConflicts(stakers, latestStaked, 10, findConflict)
+	Require(t, err)
+	expected := []StakerConflict{
+		{Staker1: common.Address{1}, Staker2: common.Address{3}, Type: CONFLICT_TYPE_FOUND, Node1: 12, Node2: 13},
+		{Staker1: common.Address{2}, Staker2: common.Address{3}, Type: CONFLICT_TYPE_FOUND, Node1: 12, Node2: 13},
+		{Staker1: common.Address{1}, Staker2: common.Address{4}, Type: CONFLICT_TYPE_INCOMPLETE},
+		{Staker1: common.Address{2}, Staker2: common.Address{4}, Type: CONFLICT_TYPE_INCOMPLETE},
+	}
+	if !slices.Equal(conflicts, expected) {
+		Fail(t, "unexpected conflicts", conflicts, "expected", expected)
+	}
+	// each pair of distinct nodes is checked once, rather than each pair of stakers
+	if len(checked) != 6 {
+		Fail(t, "expected 6 node pairs checked, got", len(checked))
+	}
+
+	// the found conflicts are reported, once each
+	var reported []ConflictInfo
+	s := &Staker{conflictHandler: func(conflict ConflictInfo) { reported = append(reported, conflict) }}
+	s.reportFoundConflicts(conflicts, 10)
+	s.reportFoundConflicts(conflicts, 10)
+	expectedReported := []ConflictInfo{
+		{Staker1: common.Address{1}, Staker2: common.Address{3}, Node1: 12, Node2: 13},
+		{Staker1: common.Address{2}, Staker2: common.Address{3}, Node1: 12, Node2: 13},
+	}
+	if !slices.Equal(reported, expectedReported) {
+		Fail(t, "unexpected reported conflicts", reported, "expected", expectedReported)
+	}
+}
+
+func TestFindStakerConflictPaged(t *testing.T) {
+	// the nodes' common ancestor is found within the third page
+	var depths []uint64
+	find := func(depth uint64) (ConflictType, uint64, uint64, error) {
+		depths = append(depths, depth)
+		if depth < 3*stakerConflictSearchDepth {
+			return CONFLICT_TYPE_INCOMPLETE, 0, 0, nil
+		}
+		return CONFLICT_TYPE_FOUND, 12, 5000, nil
+	}
+	ty, node1, node2, err := findStakerConflictPaged(find)
+	Require(t, err)
+	if ty != CONFLICT_TYPE_FOUND || node1 != 12 || node2 != 5000 {
+		Fail(t, "unexpected conflict", ty, node1, node2)
+	}
+	if !slices.Equal(depths, []uint64{stakerConflictSearchDepth, 2 * stakerConflictSearchDepth, 3 * stakerConflictSearchDepth}) {
+		Fail(t, "unexpected search depths", depths)
+	}
+
+	// a search still incomplete after the last page is reported as incomplete
+	depths = nil
+	ty, _, _, err = findStakerConflictPaged(func(depth uint64) (ConflictType, uint64, uint64, error) {
+		depths = append(depths, depth)
+		return CONFLICT_TYPE_INCOMPLETE, 0, 0, nil
+	})
+	Require(t, err)
+	if ty != CONFLICT_TYPE_INCOMPLETE || len(depths) != stakerConflictSearchPages {
+		Fail(t, "unexpected incomplete search", ty, len(depths))
+	}
+}
+
+// newFakeEthClient returns a client whose eth_calls are answered by service
+func newFakeEthClient(t *testing.T, service *fakeEthService) *ethclient.Client {
+	t.Helper()
+	server := rpc.NewServer()
+	Require(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+	return ethclient.NewClient(rpc.DialInProc(server))
+}
+
+func TestSimulationLeavesBlockValidatorUnchanged(t *testing.T) {
+	ctx := context.Background()
+	rollupAddress := common.HexToAddress("0x1000")
+	historicalRoot := common.HexToHash("0x5678")
+	wasmModuleRootCall, err := rollupABI.Pack("wasmModuleRoot")
+	Require(t, err)
+	wasmModuleRootResult, err := rollupABI.Methods["wasmModuleRoot"].Outputs.Pack(historicalRoot)
+	Require(t, err)
+	client := newFakeEthClient(t, &fakeEthService{results: map[string]hexutil.Bytes{
+		string(wasmModuleRootCall): wasmModuleRootResult,
+	}})
+	rollup, err := NewRollupWatcher(rollupAddress, client, bind.CallOpts{})
+	Require(t, err)
+	blockValidator := &staker.BlockValidator{}
+	live := &L1Validator{
+		rollup:         rollup,
+		rollupAddress:  rollupAddress,
+		client:         client,
+		blockValidator: blockValidator,
+		childrenScan:   &nodeChildrenScan{},
+	}
+
+	simulation := live.simulationAt(big.NewInt(10))
+	Require(t, simulation.updateBlockValidatorModuleRoot(ctx))
+	// would revalidate the range on the live block validator
+	simulation.flagIncorrectAssertion(0, 10)
+	if simulation.lastWasmModuleRoot != historicalRoot {
+		Fail(t, "simulation decided with module root", simulation.lastWasmModuleRoot, "expected", historicalRoot)
+	}
+	if live.lastWasmModuleRoot != (common.Hash{}) || live.childrenScan == nil || live.callOpts.BlockNumber != nil {
+		Fail(t, "simulation changed the live validator")
+	}
+	if roots := blockValidator.GetModuleRootsToValidate(); roots[0] != (common.Hash{}) {
+		Fail(t, "simulation set the block validator's module root to", roots[0])
+	}
+}
+
+func TestDropStaleChallengeOnManagerUpgrade(t *testing.T) {
+	proxy := &fakeChallengeManagerProxy{address: common.Address{1}, implementation: common.Address{2}}
+	callOpts := &bind.CallOpts{Context: context.Background()}
+	version, err := readChallengeManagerVersion(callOpts, proxy, proxy)
+	Require(t, err)
+	challenge := &ChallengeManager{challengeIndex: 7}
+	s := &Staker{
+		activeChallenge:        challenge,
+		activeChallengeManager: version,
+		challengeLog:           log.New(),
+	}
+
+	s.dropStaleChallenge(version)
+	if s.activeChallenge != challenge {
+		Fail(t, "challenge dropped without a challenge manager upgrade")
+	}
+
+	// once upgraded, the bindings are dropped so the next act recreates them from the new implementation
+	proxy.implementation = common.Address{3}
+	upgraded, err := readChallengeManagerVersion(callOpts, proxy, proxy)
+	Require(t, err)
+	s.dropStaleChallenge(upgraded)
+	if s.activeChallenge != nil {
+		Fail(t, "challenge bindings kept after the challenge manager was upgraded")
+	}
+	s.dropStaleChallenge(upgraded)
+	if s.activeChallenge != nil {
+		Fail(t, "unexpected challenge after dropping it")
+	}
+}
+
+func TestNotStakingOnNodeWithBadPredecessor(t *testing.T) {
+	ctx := context.Background()
+	rollupAddress := common.HexToAddress("0x1000")
+	// node 2 is built on node 1, which we're staked on, while node 3 was built on node 2's sibling 4
+	results := map[string]hexutil.Bytes{}
+	for nodeNum, prevNum := range map[uint64]uint64{2: 1, 3: 4} {
+		getNodeCall, err := rollupABI.Pack("getNode", nodeNum)
+		Require(t, err)
+		getNodeResult, err := rollupABI.Methods["getNode"].Outputs.Pack(rollup_legacy_gen.Node{PrevNum: prevNum})
+		Require(t, err)
+		results[string(getNodeCall)] = getNodeResult
+	}
+	client := newFakeEthClient(t, &fakeEthService{results: results})
+	rollup, err := NewRollupWatcher(rollupAddress, client, bind.CallOpts{})
+	Require(t, err)
+	config := TestL1ValidatorConfig
+	s := &Staker{
+		L1Validator: &L1Validator{rollup: rollup, rollupAddress: rollupAddress, client: client, createLog: log.New()},
+		config:      func() *L1ValidatorConfig { return &config },
+		inactiveValidatedNodes: btree.NewG(2, func(a, b validatedNode) bool {
+			return a.number < b.number
+		}),
+	}
+	s.builder, err = txbuilder.NewBuilder(validatorwallet.NewNoOp(nil), common.Address{})
+	Require(t, err)
+
+	// an active staker doesn't stake on the node with a bad predecessor
+	info := &OurStakerInfo{LatestStakedNode: 1, CanProgress: true, StakeExists: true}
+	Require(t, s.advanceToExistingNode(ctx, info, existingNodeAction{number: 3, hash: common.Hash{3}}, false, true, StakeLatestStrategy))
+	if info.LatestStakedNode != 1 || info.StakeMoves != 0 || info.CanProgress {
+		Fail(t, "advanced to node with a bad predecessor, staked on node", info.LatestStakedNode, "after", info.StakeMoves, "moves")
+	}
+	if count := s.builder.BuildingTransactionCount(); count != 0 {
+		Fail(t, "queued", count, "transactions staking on node with a bad predecessor")
+	}
+
+	// while the node built on the agreed node is followed
+	Require(t, s.advanceToExistingNode(ctx, info, existingNodeAction{number: 2, hash: common.Hash{2}}, false, false, WatchtowerStrategy))
+	if info.LatestStakedNode != 2 || info.StakeMoves != 1 {
+		Fail(t, "didn't advance to node built on the agreed node, at node", info.LatestStakedNode, "after", info.StakeMoves, "moves")
+	}
+	if s.inactiveLastCheckedNode == nil || s.inactiveLastCheckedNode.id != 2 {
+		Fail(t, "didn't check node built on the agreed node", s.inactiveLastCheckedNode)
+	}
+}
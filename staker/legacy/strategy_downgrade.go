@@ -0,0 +1,89 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// strategyDowngrade tracks consecutive act failures of a staker posting with its configured strategy.
+// After too many, the staker is downgraded to the watchtower strategy, retrying the configured one
+// every retry interval until an act succeeds again.
+type strategyDowngrade struct {
+	mutex      sync.Mutex
+	failures   uint64
+	downgraded bool
+	// when the configured strategy was last attempted
+	lastAttempt time.Time
+}
+
+// watchtower returns true if an act at the given time should fall back to the watchtower strategy.
+func (d *strategyDowngrade) watchtower(now time.Time, maxFailures uint64, retryInterval time.Duration) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return maxFailures > 0 && d.downgraded && now.Sub(d.lastAttempt) < retryInterval
+}
+
+// record records the outcome of an act with the configured strategy,
+// returning whether the staker was just downgraded, or just recovered from a downgrade.
+func (d *strategyDowngrade) record(now time.Time, err error, maxFailures uint64) (downgraded bool, recovered bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.lastAttempt = now
+	if err == nil {
+		recovered = d.downgraded
+		d.failures = 0
+		d.downgraded = false
+		return false, recovered
+	}
+	d.failures++
+	if maxFailures > 0 && !d.downgraded && d.failures >= maxFailures {
+		d.downgraded = true
+		return true, false
+	}
+	return false, false
+}
+
+func (d *strategyDowngrade) isDowngraded() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.downgraded
+}
+
+// isStakeUnwanted returns whether an act with the given effective strategy should remove an old stake.
+// A downgrade only holds off on posting until the configured strategy works again, so it keeps the stake.
+func isStakeUnwanted(effectiveStrategy StakerStrategy, downgraded bool) bool {
+	return effectiveStrategy < StakeLatestStrategy && !downgraded
+}
+
+// recordActOutcome records the outcome of an act cycle, downgrading the staker to the watchtower
+// strategy after too many consecutive failures, and resuming its configured strategy once one succeeds.
+// Transient errors, such as waiting on the data poster, are no failure to act and don't count either way.
+func (s *Staker) recordActOutcome(err error, cfg *L1ValidatorConfig) {
+	if cfg.StrategyType() == WatchtowerStrategy || s.downgradedAct.Load() || IsTransientActError(err) {
+		return
+	}
+	downgraded, recovered := s.downgrade.record(time.Now(), err, cfg.DowngradeAfterFailures)
+	if downgraded {
+		log.Error(
+			"staker repeatedly failed to act, downgrading to the watchtower strategy",
+			"strategy", cfg.StrategyType(),
+			"failures", cfg.DowngradeAfterFailures,
+			"retryInterval", cfg.DowngradeRetryInterval,
+			"err", err,
+		)
+		s.metrics.UpdateGauge(stakerDowngradedMetric, 1)
+	} else if recovered {
+		log.Info("staker acted successfully again, resuming its configured strategy", "strategy", cfg.StrategyType())
+		s.metrics.UpdateGauge(stakerDowngradedMetric, 0)
+	}
+}
+
+// Downgraded returns true if the staker has been downgraded to the watchtower strategy after repeatedly failing to act.
+func (s *Staker) Downgraded() bool {
+	return s.downgrade.isDowngraded()
+}
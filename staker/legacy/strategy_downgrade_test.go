@@ -0,0 +1,99 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStrategyDowngradeOnPostingFailures(t *testing.T) {
+	config := TestL1ValidatorConfig
+	config.Strategy = "MakeNodes"
+	config.DowngradeAfterFailures = 3
+	config.DowngradeRetryInterval = time.Minute
+	Require(t, config.Validate())
+	sink := newRecordingMetricsSink()
+	s := &Staker{metrics: sink}
+	postingErr := errors.New("insufficient funds for gas")
+
+	// waiting on the data poster or the block validator isn't a failure to act
+	transientErr := fmt.Errorf("%w: data poster nonce 2 is ahead of on-chain nonce 1", ErrDataPosterNotReady)
+	for i := uint64(0); i < 2*config.DowngradeAfterFailures; i++ {
+		s.recordActOutcome(transientErr, &config)
+		s.recordActOutcome(ErrBlockValidationPending, &config)
+	}
+	if s.Downgraded() {
+		Fail(t, "staker downgraded after transient errors")
+	}
+
+	// persistent posting failures downgrade the staker once the threshold is reached
+	for i := uint64(1); i <= config.DowngradeAfterFailures; i++ {
+		if s.downgrade.watchtower(time.Now(), config.DowngradeAfterFailures, config.DowngradeRetryInterval) {
+			Fail(t, "staker downgraded after only", i-1, "failures")
+		}
+		s.recordActOutcome(postingErr, &config)
+		// transient errors in between don't reset the count either
+		s.recordActOutcome(transientErr, &config)
+	}
+	if !s.Downgraded() {
+		Fail(t, "expected staker to be downgraded after", config.DowngradeAfterFailures, "failures")
+	}
+	if sink.gauges[stakerDowngradedMetric] != 1 {
+		Fail(t, "unexpected downgraded metric", sink.gauges[stakerDowngradedMetric])
+	}
+	now := time.Now()
+	if !s.downgrade.watchtower(now, config.DowngradeAfterFailures, config.DowngradeRetryInterval) {
+		Fail(t, "expected downgraded staker to act as a watchtower")
+	}
+
+	// a downgraded staker keeps its stake, unlike one configured not to stake
+	if isStakeUnwanted(WatchtowerStrategy, true) {
+		Fail(t, "downgraded staker would remove its stake")
+	}
+	if !isStakeUnwanted(WatchtowerStrategy, false) || !isStakeUnwanted(DefensiveStrategy, false) {
+		Fail(t, "staker configured not to stake would keep its old stake")
+	}
+
+	// acts downgraded to the watchtower strategy don't count towards recovery
+	s.downgradedAct.Store(true)
+	s.recordActOutcome(nil, &config)
+	if !s.Downgraded() {
+		Fail(t, "downgraded act recovered the staker")
+	}
+
+	// the configured strategy is retried after the retry interval, and a failure keeps the downgrade
+	retry := now.Add(config.DowngradeRetryInterval)
+	if s.downgrade.watchtower(retry, config.DowngradeAfterFailures, config.DowngradeRetryInterval) {
+		Fail(t, "expected the configured strategy to be retried after the retry interval")
+	}
+	s.downgradedAct.Store(false)
+	s.recordActOutcome(postingErr, &config)
+	if !s.Downgraded() || !s.downgrade.watchtower(retry, config.DowngradeAfterFailures, config.DowngradeRetryInterval) {
+		Fail(t, "expected failed retry to keep the staker downgraded")
+	}
+
+	// once posting recovers, the staker upgrades back to its configured strategy
+	s.recordActOutcome(nil, &config)
+	if s.Downgraded() {
+		Fail(t, "expected staker to recover once posting succeeded")
+	}
+	if sink.gauges[stakerDowngradedMetric] != 0 {
+		Fail(t, "unexpected downgraded metric after recovery", sink.gauges[stakerDowngradedMetric])
+	}
+	if s.downgrade.watchtower(time.Now(), config.DowngradeAfterFailures, config.DowngradeRetryInterval) {
+		Fail(t, "recovered staker still acting as a watchtower")
+	}
+
+	// the downgrade is disabled by default
+	config.DowngradeAfterFailures = 0
+	for i := 0; i < 10; i++ {
+		s.recordActOutcome(postingErr, &config)
+	}
+	if s.Downgraded() {
+		Fail(t, "staker downgraded with downgrades disabled")
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package legacystaker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
+)
+
+type zombieReader interface {
+	ZombieCount(opts *bind.CallOpts) (*big.Int, error)
+	ZombieAddress(opts *bind.CallOpts, zombieNum *big.Int) (common.Address, error)
+	ZombieLatestStakedNode(opts *bind.CallOpts, zombieNum *big.Int) (uint64, error)
+	GetNode(opts *bind.CallOpts, nodeNum uint64) (rollup_legacy_gen.Node, error)
+}
+
+// zombieRemoval describes the removeZombie call needed to fully remove a zombie from the rollup.
+type zombieRemoval struct {
+	zombieNum *big.Int
+	// maxNodes is the number of nodes the zombie's stake has to be removed from
+	maxNodes uint64
+}
+
+// findZombieRemoval looks up staker among the rollup's zombies, returning nil if it isn't a zombie,
+// or if its latest staked node is still on the confirmed chain and so might yet be confirmed.
+// Otherwise a node conflicting with the zombie's chain was confirmed, and the zombie can be removed.
+func findZombieRemoval(opts *bind.CallOpts, rollup zombieReader, staker common.Address, latestConfirmed uint64) (*zombieRemoval, error) {
+	zombieCount, err := rollup.ZombieCount(opts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting zombie count: %w", err)
+	}
+	for i := int64(0); i < zombieCount.Int64(); i++ {
+		zombieNum := big.NewInt(i)
+		address, err := rollup.ZombieAddress(opts, zombieNum)
+		if err != nil {
+			return nil, fmt.Errorf("error getting zombie %v address: %w", i, err)
+		}
+		if address != staker {
+			continue
+		}
+		node, err := rollup.ZombieLatestStakedNode(opts, zombieNum)
+		if err != nil {
+			return nil, fmt.Errorf("error getting zombie %v latest staked node: %w", i, err)
+		}
+		// removeZombie walks back from the latest staked node until it's past the latest confirmed node
+		var maxNodes uint64
+		for node > latestConfirmed {
+			info, err := rollup.GetNode(opts, node)
+			if err != nil {
+				return nil, fmt.Errorf("error getting node %v: %w", node, err)
+			}
+			node = info.PrevNum
+			maxNodes++
+		}
+		if node == latestConfirmed {
+			// the zombie's chain includes the latest confirmed node, so it doesn't conflict with it (yet)
+			return nil, nil
+		}
+		return &zombieRemoval{zombieNum: zombieNum, maxNodes: maxNodes}, nil
+	}
+	return nil, nil
+}
+
+// rescueZombieStake removes our staker from the rollup's zombies once a node conflicting with its chain
+// is confirmed, so that it can stake again, and withdraws any funds it has left in the rollup.
+func (s *Staker) rescueZombieStake(ctx context.Context, staker common.Address, latestConfirmed uint64) error {
+	callOpts := s.getCallOpts(ctx)
+	removal, err := findZombieRemoval(callOpts, s.rollup, staker, latestConfirmed)
+	if err != nil || removal == nil {
+		return err
+	}
+	auth := s.builder.Auth(ctx)
+	_, err = s.rollup.RemoveZombie(auth, removal.zombieNum, new(big.Int).SetUint64(removal.maxNodes))
+	if err != nil {
+		return fmt.Errorf("error removing our staker %v from zombies: %w", staker, err)
+	}
+	withdrawable, err := s.rollup.WithdrawableFunds(callOpts, staker)
+	if err != nil {
+		return fmt.Errorf("error checking withdrawable funds of our staker %v: %w", staker, err)
+	}
+	if withdrawable.Sign() > 0 {
+		_, err = s.rollup.WithdrawStakerFunds(auth)
+		if err != nil {
+			return fmt.Errorf("error withdrawing our staker %v funds: %w", staker, err)
+		}
+	}
+	log.Info("rescuing zombie stake", "staker", staker, "zombie", removal.zombieNum, "nodes", removal.maxNodes, "withdrawable", withdrawable)
+	return nil
+}
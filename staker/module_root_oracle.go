@@ -0,0 +1,110 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// WasmModuleRootOracle returns the wasm module root to validate the messages of the given parent chain block with,
+// e.g. following the rollup's history of module root upgrades. Given math.MaxUint64, it returns the latest module root.
+type WasmModuleRootOracle func(l1Block uint64) common.Hash
+
+// WasmModuleRootUpgrade is a wasm module root in effect from the parent chain block L1Block until the next upgrade.
+type WasmModuleRootUpgrade struct {
+	L1Block    uint64
+	ModuleRoot common.Hash
+}
+
+// WasmModuleRootHistory returns an oracle following the given upgrades, which must be strictly ordered by block.
+// The messages of blocks before the first upgrade are validated with its module root.
+func WasmModuleRootHistory(upgrades []WasmModuleRootUpgrade) (WasmModuleRootOracle, error) {
+	if len(upgrades) == 0 {
+		return nil, errors.New("no wasm module root upgrades")
+	}
+	for i, upgrade := range upgrades {
+		if upgrade.ModuleRoot == (common.Hash{}) {
+			return nil, fmt.Errorf("wasm module root upgrade at block %d has no module root", upgrade.L1Block)
+		}
+		if i > 0 && upgrade.L1Block <= upgrades[i-1].L1Block {
+			return nil, fmt.Errorf("wasm module root upgrades not strictly ordered at block %d", upgrade.L1Block)
+		}
+	}
+	upgrades = append([]WasmModuleRootUpgrade(nil), upgrades...)
+	return func(l1Block uint64) common.Hash {
+		next := sort.Search(len(upgrades), func(i int) bool { return upgrades[i].L1Block > l1Block })
+		if next == 0 {
+			return upgrades[0].ModuleRoot
+		}
+		return upgrades[next-1].ModuleRoot
+	}, nil
+}
+
+// ParseWasmModuleRootHistory parses module root upgrades given as <parent chain block>:<module root>.
+func ParseWasmModuleRootHistory(history []string) ([]WasmModuleRootUpgrade, error) {
+	var upgrades []WasmModuleRootUpgrade
+	for _, entry := range history {
+		blockStr, rootStr, found := strings.Cut(entry, ":")
+		if !found {
+			return nil, fmt.Errorf("module root upgrade %q isn't <block>:<module root>", entry)
+		}
+		block, err := strconv.ParseUint(blockStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("module root upgrade %q has invalid block: %w", entry, err)
+		}
+		var root common.Hash
+		if err := root.UnmarshalText([]byte(strings.TrimSpace(rootStr))); err != nil {
+			return nil, fmt.Errorf("module root upgrade %q has invalid module root: %w", entry, err)
+		}
+		upgrades = append(upgrades, WasmModuleRootUpgrade{L1Block: block, ModuleRoot: root})
+	}
+	if len(upgrades) == 0 {
+		return nil, nil
+	}
+	if _, err := WasmModuleRootHistory(upgrades); err != nil {
+		return nil, err
+	}
+	return upgrades, nil
+}
+
+// wasmModuleRootAt returns the wasm module root to validate the message at pos with, as given by the
+// module root oracle for the parent chain block of the message, or the latest module root if there's no oracle.
+func (v *StatelessBlockValidator) wasmModuleRootAt(pos arbutil.MessageIndex) (common.Hash, error) {
+	if v.wasmModuleRootOracle == nil {
+		return v.latestWasmModuleRoot, nil
+	}
+	msg, err := v.streamer.GetMessage(pos)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed getting message %d: %w", pos, err)
+	}
+	if msg.Message == nil || msg.Message.Header == nil {
+		return common.Hash{}, fmt.Errorf("message %d has no header", pos)
+	}
+	moduleRoot := v.wasmModuleRootOracle(msg.Message.Header.BlockNumber)
+	if moduleRoot == (common.Hash{}) {
+		return common.Hash{}, fmt.Errorf("no wasm module root for message %d of parent chain block %d", pos, msg.Message.Header.BlockNumber)
+	}
+	return moduleRoot, nil
+}
+
+// moduleRootsToValidateAt returns the module roots the block validator validates the message at pos with:
+// the one in effect at its parent chain block if there's a module root oracle, or else roots.
+func (v *BlockValidator) moduleRootsToValidateAt(pos arbutil.MessageIndex, roots []common.Hash) ([]common.Hash, error) {
+	if v.wasmModuleRootOracle == nil {
+		return roots, nil
+	}
+	moduleRoot, err := v.wasmModuleRootAt(pos)
+	if err != nil {
+		return nil, err
+	}
+	return []common.Hash{moduleRoot}, nil
+}
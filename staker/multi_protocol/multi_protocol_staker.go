@@ -3,6 +3,7 @@ package multiprotocolstaker
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -35,9 +36,10 @@ $$$$$$$/   $$$$$$/  $$$$$$$$/ $$$$$$$/
 
 type MultiProtocolStaker struct {
 	stopwaiter.StopWaiter
-	bridge                  *bridgegen.IBridge
-	oldStaker               *legacystaker.Staker
-	boldStaker              *boldstaker.BOLDStaker
+	bridge    *bridgegen.IBridge
+	oldStaker *legacystaker.Staker
+	// set once BoLD is active, which may happen while the old staker is being poked
+	boldStaker              atomic.Pointer[boldstaker.BOLDStaker]
 	legacyConfig            legacystaker.L1ValidatorConfigFetcher
 	stakedNotifiers         []legacystaker.LatestStakedNotifier
 	confirmedNotifiers      []legacystaker.LatestConfirmedNotifier
@@ -105,7 +107,6 @@ func NewMultiProtocolStaker(
 	}
 	return &MultiProtocolStaker{
 		oldStaker:               oldStaker,
-		boldStaker:              nil,
 		bridge:                  bridge,
 		legacyConfig:            legacyConfig,
 		stakedNotifiers:         stakedNotifiers,
@@ -132,11 +133,13 @@ func (m *MultiProtocolStaker) Initialize(ctx context.Context) error {
 	if boldActive {
 		log.Info("BoLD protocol is active, initializing BoLD staker")
 		log.Info(boldArt)
-		if err := m.setupBoldStaker(ctx, rollupAddress); err != nil {
+		boldStaker, err := m.setupBoldStaker(ctx, rollupAddress)
+		if err != nil {
 			return err
 		}
+		m.boldStaker.Store(boldStaker)
 		m.oldStaker = nil
-		return m.boldStaker.Initialize(ctx)
+		return boldStaker.Initialize(ctx)
 	}
 	log.Info("BoLD protocol not detected on startup, using old staker until upgrade")
 	return m.oldStaker.Initialize(ctx)
@@ -145,9 +148,9 @@ func (m *MultiProtocolStaker) Initialize(ctx context.Context) error {
 func (m *MultiProtocolStaker) Start(ctxIn context.Context) {
 	m.StopWaiter.Start(ctxIn, m)
 	m.wallet.Start(ctxIn)
-	if m.boldStaker != nil {
+	if boldStaker := m.boldStaker.Load(); boldStaker != nil {
 		log.Info("Starting BOLD staker")
-		m.boldStaker.Start(ctxIn)
+		boldStaker.Start(ctxIn)
 	} else {
 		log.Info("Starting pre-BOLD staker")
 		m.oldStaker.Start(ctxIn)
@@ -172,9 +175,17 @@ func (m *MultiProtocolStaker) Start(ctxIn context.Context) {
 	}
 }
 
+// LegacyStaker returns the pre-BoLD staker, or nil once the BoLD staker has taken over.
+func (m *MultiProtocolStaker) LegacyStaker() *legacystaker.Staker {
+	if m.boldStaker.Load() != nil {
+		return nil
+	}
+	return m.oldStaker
+}
+
 func (m *MultiProtocolStaker) StopAndWait() {
-	if m.boldStaker != nil {
-		m.boldStaker.StopAndWait()
+	if boldStaker := m.boldStaker.Load(); boldStaker != nil {
+		boldStaker.StopAndWait()
 	}
 	if m.oldStaker != nil {
 		m.oldStaker.StopAndWait()
@@ -210,14 +221,16 @@ func (m *MultiProtocolStaker) checkAndSwitchToBoldStaker(ctx context.Context) er
 		log.Info("Bold is not yet active on-chain, will retry switching later")
 		return nil
 	}
-	if err := m.setupBoldStaker(ctx, rollupAddress); err != nil {
+	boldStaker, err := m.setupBoldStaker(ctx, rollupAddress)
+	if err != nil {
 		return err
 	}
-	if err = m.boldStaker.Initialize(ctx); err != nil {
+	if err = boldStaker.Initialize(ctx); err != nil {
 		return err
 	}
 	log.Info("Detected BOLD protocol upgrade, stopping old staker and starting BOLD staker")
-	m.boldStaker.Start(ctx)
+	m.boldStaker.Store(boldStaker)
+	boldStaker.Start(ctx)
 	// Ready to stop the old staker.
 	m.oldStaker.StopOnly()
 	m.StopOnly()
@@ -233,17 +246,17 @@ func (m *MultiProtocolStaker) getCallOpts(ctx context.Context) *bind.CallOpts {
 func (m *MultiProtocolStaker) setupBoldStaker(
 	ctx context.Context,
 	rollupAddress common.Address,
-) error {
+) (*boldstaker.BOLDStaker, error) {
 	stakeTokenContract, err := m.l1Reader.Client().CodeAt(ctx, m.stakeTokenAddress, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(stakeTokenContract) == 0 {
-		return fmt.Errorf("stake token address for BoLD %v does not point to a contract", m.stakeTokenAddress)
+		return nil, fmt.Errorf("stake token address for BoLD %v does not point to a contract", m.stakeTokenAddress)
 	}
 	txBuilder, err := txbuilder.NewBuilder(m.wallet, m.legacyConfig().GasRefunder())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	boldStaker, err := boldstaker.NewBOLDStaker(
 		ctx,
@@ -264,8 +277,7 @@ func (m *MultiProtocolStaker) setupBoldStaker(
 		m.inboxReader,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	m.boldStaker = boldStaker
-	return nil
+	return boldStaker, nil
 }
@@ -0,0 +1,97 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+// SpawnerSelection is a validation spawner validating the messages its predicate selects,
+// e.g. the JIT for messages near the head, and the arbitrator for messages near a challenge.
+type SpawnerSelection struct {
+	Spawner validator.ValidationSpawner
+	// Selects returns whether Spawner validates the message at pos against moduleRoot
+	Selects func(pos arbutil.MessageIndex, moduleRoot common.Hash) bool
+}
+
+// SetSpawnerSelections makes the validator pick the spawner of each validation from the given ordered
+// selections: the first selection selecting the message whose spawner supports the module root is used,
+// and the validation servers of the config are used for messages none of them selects.
+// It must be called before validations start. The selected spawners are started and stopped by the
+// caller, as they may be shared, e.g. with the validator's own execution spawners.
+func (v *StatelessBlockValidator) SetSpawnerSelections(selections []SpawnerSelection) {
+	v.spawnerSelections = selections
+}
+
+// selectedSpawner returns the spawner selected for the message at pos and moduleRoot, or nil if there's none.
+func (v *StatelessBlockValidator) selectedSpawner(pos arbutil.MessageIndex, moduleRoot common.Hash) validator.ValidationSpawner {
+	for _, selection := range v.spawnerSelections {
+		if selection.Selects(pos, moduleRoot) && validator.SpawnerSupportsModule(selection.Spawner, moduleRoot) {
+			log.Trace("validation spawner selected", "pos", pos, "moduleRoot", moduleRoot, "spawner", selection.Spawner.Name())
+			return selection.Spawner
+		}
+	}
+	return nil
+}
+
+// entrySpawners returns the spawners to validate the message at pos against moduleRoot with:
+// the selected spawner if there's one, or else the validation servers supporting moduleRoot.
+func (v *StatelessBlockValidator) entrySpawners(pos arbutil.MessageIndex, moduleRoot common.Hash, useExec bool) []validator.ValidationSpawner {
+	if spawner := v.selectedSpawner(pos, moduleRoot); spawner != nil {
+		return []validator.ValidationSpawner{spawner}
+	}
+	return v.validationSpawners(moduleRoot, useExec)
+}
+
+// allSpawners returns the validator's execution spawners followed by its selected spawners.
+func (v *StatelessBlockValidator) allSpawners() []validator.ValidationSpawner {
+	spawners := make([]validator.ValidationSpawner, 0, len(v.execSpawners)+len(v.spawnerSelections))
+	for _, spawner := range v.execSpawners {
+		spawners = append(spawners, spawner)
+	}
+	for _, selection := range v.spawnerSelections {
+		spawners = append(spawners, selection.Spawner)
+	}
+	return spawners
+}
+
+// WasmModuleRoots returns the union of the module roots supported by the validator's spawners.
+func (v *StatelessBlockValidator) WasmModuleRoots() []common.Hash {
+	var roots []common.Hash
+	seen := make(map[common.Hash]bool)
+	for _, spawner := range v.allSpawners() {
+		supported, err := spawner.WasmModuleRoots()
+		if err != nil {
+			log.Warn("WasmModuleRoots returned error", "spawner", spawner.Name(), "err", err)
+			continue
+		}
+		for _, root := range supported {
+			if !seen[root] {
+				seen[root] = true
+				roots = append(roots, root)
+			}
+		}
+	}
+	return roots
+}
+
+// StylusArchs returns the union of the targets supported by the validator's spawners.
+func (v *StatelessBlockValidator) StylusArchs() []rawdb.WasmTarget {
+	var targets []rawdb.WasmTarget
+	seen := make(map[rawdb.WasmTarget]bool)
+	for _, spawner := range v.allSpawners() {
+		for _, target := range spawner.StylusArchs() {
+			if !seen[target] {
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets
+}
@@ -7,8 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -17,6 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/offchainlabs/nitro/arbos/arbostypes"
 	"github.com/offchainlabs/nitro/arbutil"
@@ -27,16 +32,25 @@ import (
 	validatorclient "github.com/offchainlabs/nitro/validator/client"
 	"github.com/offchainlabs/nitro/validator/client/redis"
 	"github.com/offchainlabs/nitro/validator/server_api"
+	"github.com/offchainlabs/nitro/validator/server_common"
 )
 
+var ErrWasmModuleRootMismatch = errors.New("on-chain wasm module root doesn't match latest machine")
+var ErrValidationQuorumNotReached = errors.New("validation servers didn't reach quorum")
+
 type StatelessBlockValidator struct {
 	config *BlockValidatorConfig
 
 	execSpawners     []validator.ExecutionSpawner
 	boldExecSpawners []validator.BOLDExecutionSpawner
 	redisValidator   *redis.ValidationClient
+	// spawners selected per message, ahead of the execution spawners
+	spawnerSelections []SpawnerSelection
 
 	recorder execution.ExecutionRecorder
+	// records messages whose state the local node lacks, if an archive node is configured
+	archive       archiveInputsReader
+	archiveClient *rpcclient.RpcClient
 
 	inboxReader          InboxReaderInterface
 	inboxTracker         InboxTrackerInterface
@@ -45,8 +59,18 @@ type StatelessBlockValidator struct {
 	dapReaders           []daprovider.Reader
 	stack                *node.Node
 	latestWasmModuleRoot common.Hash
+	wasmModuleRootOracle WasmModuleRootOracle
+
+	validatedHashesMutex sync.Mutex
+	validatedHashes      map[arbutil.MessageIndex]common.Hash
+
+	reportWriter *ValidationReportWriter
+	reportFile   *os.File
 }
 
+// maxTrackedValidatedMessages bounds how many validated messages are remembered for reorg detection
+const maxTrackedValidatedMessages = 10000
+
 type BlockValidatorRegistrer interface {
 	SetBlockValidator(*BlockValidator)
 }
@@ -144,6 +168,8 @@ type validationEntry struct {
 	Preimages  daprovider.PreimagesMap
 	UserWasms  state.UserWasms
 	DelayedMsg []byte
+	// L2 gas used by the recorded block, nil if it wasn't recorded locally
+	GasUsed *uint64
 }
 
 func (e *validationEntry) ToInput(stylusArchs []rawdb.WasmTarget) (*validator.ValidationInput, error) {
@@ -229,6 +255,7 @@ func newValidationEntry(
 	}, nil
 }
 
+// NewStatelessBlockValidator returns a stateless block validator validating every message with latestWasmModuleRoot.
 func NewStatelessBlockValidator(
 	inboxReader InboxReaderInterface,
 	inbox InboxTrackerInterface,
@@ -239,6 +266,43 @@ func NewStatelessBlockValidator(
 	config func() *BlockValidatorConfig,
 	stack *node.Node,
 	latestWasmModuleRoot common.Hash,
+) (*StatelessBlockValidator, error) {
+	return newStatelessBlockValidator(inboxReader, inbox, streamer, recorder, arbdb, dapReaders, config, stack, latestWasmModuleRoot, nil)
+}
+
+// NewStatelessBlockValidatorWithRootOracle returns a stateless block validator validating each message with the
+// wasm module root given by moduleRootOracle for the message's parent chain block, e.g. to use the old module root
+// for the blocks before a scheduled upgrade.
+func NewStatelessBlockValidatorWithRootOracle(
+	inboxReader InboxReaderInterface,
+	inbox InboxTrackerInterface,
+	streamer TransactionStreamerInterface,
+	recorder execution.ExecutionRecorder,
+	arbdb ethdb.Database,
+	dapReaders []daprovider.Reader,
+	config func() *BlockValidatorConfig,
+	stack *node.Node,
+	moduleRootOracle WasmModuleRootOracle,
+) (*StatelessBlockValidator, error) {
+	if moduleRootOracle == nil {
+		return nil, errors.New("wasm module root oracle not set")
+	}
+	return newStatelessBlockValidator(inboxReader, inbox, streamer, recorder, arbdb, dapReaders, config, stack, moduleRootOracle(math.MaxUint64), moduleRootOracle)
+}
+
+// newStatelessBlockValidator returns a stateless block validator following moduleRootOracle, or validating
+// every message with latestWasmModuleRoot if it's nil.
+func newStatelessBlockValidator(
+	inboxReader InboxReaderInterface,
+	inbox InboxTrackerInterface,
+	streamer TransactionStreamerInterface,
+	recorder execution.ExecutionRecorder,
+	arbdb ethdb.Database,
+	dapReaders []daprovider.Reader,
+	config func() *BlockValidatorConfig,
+	stack *node.Node,
+	latestWasmModuleRoot common.Hash,
+	moduleRootOracle WasmModuleRootOracle,
 ) (*StatelessBlockValidator, error) {
 	var executionSpawners []validator.ExecutionSpawner
 	var boldExecutionSpawners []validator.BOLDExecutionSpawner
@@ -256,8 +320,12 @@ func NewStatelessBlockValidator(
 		i := i
 		confFetcher := func() *rpcclient.ClientConfig { return &config().ValidationServerConfigs[i] }
 		executionSpawner := validatorclient.NewExecutionClient(confFetcher, stack)
-		executionSpawners = append(executionSpawners, executionSpawner)
 		boldExecutionSpawners = append(boldExecutionSpawners, validatorclient.NewBOLDExecutionClient(executionSpawner))
+		if cacheSize := config().ValidationCacheSize; cacheSize > 0 {
+			executionSpawners = append(executionSpawners, server_common.NewCachingExecutionSpawner(executionSpawner, server_common.WithCacheCapacity(cacheSize)))
+		} else {
+			executionSpawners = append(executionSpawners, executionSpawner)
+		}
 	}
 
 	if len(executionSpawners) == 0 {
@@ -268,9 +336,18 @@ func NewStatelessBlockValidator(
 		return nil, errors.New("latestWasmModuleRoot not set")
 	}
 
+	var archiveClient *rpcclient.RpcClient
+	var archive archiveInputsReader
+	if config().ArchiveNode.URL != "" {
+		archiveClient = rpcclient.NewRpcClient(func() *rpcclient.ClientConfig { return &config().ArchiveNode }, stack)
+		archive = &archiveNodeClient{client: archiveClient}
+	}
+
 	return &StatelessBlockValidator{
 		config:               config(),
 		recorder:             recorder,
+		archive:              archive,
+		archiveClient:        archiveClient,
 		redisValidator:       redisValClient,
 		inboxReader:          inboxReader,
 		inboxTracker:         inbox,
@@ -281,6 +358,8 @@ func NewStatelessBlockValidator(
 		boldExecSpawners:     boldExecutionSpawners,
 		stack:                stack,
 		latestWasmModuleRoot: latestWasmModuleRoot,
+		wasmModuleRootOracle: moduleRootOracle,
+		validatedHashes:      make(map[arbutil.MessageIndex]common.Hash),
 	}, nil
 }
 
@@ -376,18 +455,27 @@ func (v *StatelessBlockValidator) ValidationEntryRecord(ctx context.Context, e *
 	if e.Pos != 0 {
 		recording, err := v.recorder.RecordBlockCreation(ctx, e.Pos, e.msg)
 		if err != nil {
-			return err
-		}
-		if recording.BlockHash != e.End.BlockHash {
-			return fmt.Errorf("recording failed: pos %d, hash expected %v, got %v", e.Pos, e.End.BlockHash, recording.BlockHash)
-		}
-		if recording.Preimages != nil {
-			recordingPreimages := map[arbutil.PreimageType]map[common.Hash][]byte{
-				arbutil.Keccak256PreimageType: recording.Preimages,
+			if v.archive == nil {
+				return err
+			}
+			log.Warn("failed recording block locally, recording from archive node", "pos", e.Pos, "err", err)
+			if err := recordFromArchive(ctx, v.archive, e, v.StylusArchs()); err != nil {
+				return err
+			}
+		} else {
+			if recording.BlockHash != e.End.BlockHash {
+				return fmt.Errorf("recording failed: pos %d, hash expected %v, got %v", e.Pos, e.End.BlockHash, recording.BlockHash)
+			}
+			if recording.Preimages != nil {
+				recordingPreimages := map[arbutil.PreimageType]map[common.Hash][]byte{
+					arbutil.Keccak256PreimageType: recording.Preimages,
+				}
+				copyPreimagesInto(e.Preimages, recordingPreimages)
 			}
-			copyPreimagesInto(e.Preimages, recordingPreimages)
+			e.UserWasms = recording.UserWasms
+			gasUsed := recording.GasUsed
+			e.GasUsed = &gasUsed
 		}
-		e.UserWasms = recording.UserWasms
 	}
 	if e.HasDelayedMsg {
 		delayedMsg, err := v.inboxTracker.GetDelayedMessageBytes(ctx, e.DelayedMsgNr)
@@ -496,46 +584,612 @@ func (v *StatelessBlockValidator) CreateReadyValidationEntry(ctx context.Context
 	return entry, nil
 }
 
+// ValidateResult validates the message at pos against moduleRoot, or if it's zero, the module root
+// in effect at the message's parent chain block.
 func (v *StatelessBlockValidator) ValidateResult(
 	ctx context.Context, pos arbutil.MessageIndex, useExec bool, moduleRoot common.Hash,
 ) (bool, *validator.GoGlobalState, error) {
+	if moduleRoot == (common.Hash{}) {
+		var err error
+		moduleRoot, err = v.wasmModuleRootAt(pos)
+		if err != nil {
+			return false, nil, err
+		}
+	}
 	entry, err := v.CreateReadyValidationEntry(ctx, pos)
 	if err != nil {
 		return false, nil, err
 	}
-	var run validator.ValidationRun
-	if !useExec {
-		if v.redisValidator != nil {
-			if validator.SpawnerSupportsModule(v.redisValidator, moduleRoot) {
-				input, err := entry.ToInput(v.redisValidator.StylusArchs())
-				if err != nil {
-					return false, nil, err
-				}
-				run = v.redisValidator.Launch(input, moduleRoot)
+	return v.validateEntry(ctx, entry, useExec, moduleRoot)
+}
+
+func (v *StatelessBlockValidator) validateEntry(
+	ctx context.Context, entry *validationEntry, useExec bool, moduleRoot common.Hash,
+) (bool, *validator.GoGlobalState, error) {
+	if v.config.ValidationQuorum > 0 {
+		result, err := v.validateEntryWithQuorum(ctx, entry, moduleRoot, v.config.ValidationQuorum)
+		if result == nil {
+			return false, nil, err
+		}
+		return result.Valid, result.AgreedState, err
+	}
+	pos := entry.Pos
+	var err error
+	spawners := v.entrySpawners(pos, moduleRoot, useExec)
+	if len(spawners) == 0 {
+		return false, nil, fmt.Errorf("validation with WasmModuleRoot %v not supported by node", moduleRoot)
+	}
+	spawners = v.config.InputSizeDispatch.dispatchBySize(entry, spawners)
+	var gsEnd validator.GoGlobalState
+	for attempt := uint64(0); ; attempt++ {
+		// spread retries across the spawners supporting the module root
+		spawner := spawners[attempt%uint64(len(spawners))]
+		gsEnd, err = v.runValidation(ctx, entry, spawner, moduleRoot)
+		if err == nil || ctx.Err() != nil || attempt >= v.config.ValidationRetries {
+			break
+		}
+		log.Warn("validation failed, retrying", "pos", pos, "server", spawner.Name(), "attempt", attempt+1, "retries", v.config.ValidationRetries, "err", err)
+	}
+	if err != nil || gsEnd != entry.End {
+		return false, &gsEnd, err
+	}
+	v.trackValidated(entry.Pos, entry.End.BlockHash)
+	return true, &entry.End, nil
+}
+
+// validationSpawners returns the validation servers supporting moduleRoot: the redis validator
+// if it supports it and useExec isn't set, or else the supporting execution spawners.
+func (v *StatelessBlockValidator) validationSpawners(moduleRoot common.Hash, useExec bool) []validator.ValidationSpawner {
+	if !useExec && v.redisValidator != nil && validator.SpawnerSupportsModule(v.redisValidator, moduleRoot) {
+		return []validator.ValidationSpawner{v.redisValidator}
+	}
+	var spawners []validator.ValidationSpawner
+	for _, spawner := range v.execSpawners {
+		if validator.SpawnerSupportsModule(spawner, moduleRoot) {
+			spawners = append(spawners, spawner)
+		}
+	}
+	return spawners
+}
+
+func (v *StatelessBlockValidator) runValidation(
+	ctx context.Context, entry *validationEntry, spawner validator.ValidationSpawner, moduleRoot common.Hash,
+) (validator.GoGlobalState, error) {
+	input, err := entry.ToInput(spawner.StylusArchs())
+	if err != nil {
+		return validator.GoGlobalState{}, err
+	}
+	run := spawner.Launch(input, moduleRoot)
+	defer run.Cancel()
+	return run.Await(ctx)
+}
+
+// inputHasStylusArchs returns whether input holds the user wasms compiled for every given target,
+// which is trivially the case if it doesn't call any user wasm.
+func inputHasStylusArchs(input *validator.ValidationInput, stylusArchs []rawdb.WasmTarget) bool {
+	if len(input.UserWasms) == 0 {
+		return true
+	}
+	for _, arch := range stylusArchs {
+		if _, ok := input.UserWasms[arch]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateInput validates a caller-supplied validation input with moduleRoot, instead of building
+// the input from the inbox, and returns the resulting global state. This allows replaying inputs
+// saved with ValidationInputsAt, e.g. when investigating an incident. The input must hold the
+// user wasms for the targets of a validation server supporting moduleRoot.
+func (v *StatelessBlockValidator) ValidateInput(
+	ctx context.Context, input *validator.ValidationInput, moduleRoot common.Hash,
+) (validator.GoGlobalState, error) {
+	var spawners []validator.ValidationSpawner
+	for _, spawner := range v.validationSpawners(moduleRoot, false) {
+		if inputHasStylusArchs(input, spawner.StylusArchs()) {
+			spawners = append(spawners, spawner)
+		}
+	}
+	if len(spawners) == 0 {
+		return validator.GoGlobalState{}, fmt.Errorf("validation of input with WasmModuleRoot %v not supported by node", moduleRoot)
+	}
+	var gsEnd validator.GoGlobalState
+	var err error
+	for attempt := uint64(0); ; attempt++ {
+		spawner := spawners[attempt%uint64(len(spawners))]
+		run := spawner.Launch(input, moduleRoot)
+		gsEnd, err = run.Await(ctx)
+		run.Cancel()
+		if err == nil || ctx.Err() != nil || attempt >= v.config.ValidationRetries {
+			break
+		}
+		log.Warn("input validation failed, retrying", "id", input.Id, "server", spawner.Name(), "attempt", attempt+1, "retries", v.config.ValidationRetries, "err", err)
+	}
+	return gsEnd, err
+}
+
+// StylusTargetResult is the result of validating a message using the user wasms compiled for one target.
+type StylusTargetResult struct {
+	Target      rawdb.WasmTarget
+	Server      string
+	Valid       bool
+	GlobalState validator.GoGlobalState
+	Err         error
+}
+
+type StylusValidationResult struct {
+	Pos arbutil.MessageIndex
+	// HasStylus is false if the message didn't execute any Stylus program, in which case no target is validated
+	HasStylus bool
+	Targets   []StylusTargetResult
+}
+
+// Valid returns true if the message was validated by every target.
+func (r *StylusValidationResult) Valid() bool {
+	for _, target := range r.Targets {
+		if !target.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateStylusTargets validates the message at pos once per Stylus target advertised by the validation
+// servers supporting moduleRoot, each time providing only the user wasms compiled for that target,
+// so that a divergence in the execution of Stylus programs can be attributed to a target.
+// Targets failing to validate are reported in the result rather than as an error.
+func (v *StatelessBlockValidator) ValidateStylusTargets(
+	ctx context.Context, pos arbutil.MessageIndex, moduleRoot common.Hash,
+) (*StylusValidationResult, error) {
+	entry, err := v.CreateReadyValidationEntry(ctx, pos)
+	if err != nil {
+		return nil, err
+	}
+	result := &StylusValidationResult{
+		Pos:       pos,
+		HasStylus: len(entry.UserWasms) > 0,
+	}
+	if !result.HasStylus {
+		return result, nil
+	}
+	spawners := v.validationSpawners(moduleRoot, false)
+	if len(spawners) == 0 {
+		return nil, fmt.Errorf("validation with WasmModuleRoot %v not supported by node", moduleRoot)
+	}
+	validated := make(map[rawdb.WasmTarget]bool)
+	for _, spawner := range spawners {
+		for _, target := range spawner.StylusArchs() {
+			if validated[target] {
+				continue
+			}
+			validated[target] = true
+			targetResult := StylusTargetResult{
+				Target: target,
+				Server: spawner.Name(),
 			}
+			input, err := entry.ToInput([]rawdb.WasmTarget{target})
+			if err != nil {
+				targetResult.Err = err
+			} else {
+				run := spawner.Launch(input, moduleRoot)
+				targetResult.GlobalState, targetResult.Err = run.Await(ctx)
+				run.Cancel()
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			targetResult.Valid = targetResult.Err == nil && targetResult.GlobalState == entry.End
+			if !targetResult.Valid {
+				log.Error("stylus target failed validation", "pos", pos, "target", target, "server", spawner.Name(), "globalState", targetResult.GlobalState, "expected", entry.End, "err", targetResult.Err)
+			}
+			result.Targets = append(result.Targets, targetResult)
 		}
 	}
-	if run == nil {
-		for _, spawner := range v.execSpawners {
-			if validator.SpawnerSupportsModule(spawner, moduleRoot) {
-				input, err := entry.ToInput(spawner.StylusArchs())
-				if err != nil {
-					return false, nil, err
-				}
-				run = spawner.Launch(input, moduleRoot)
+	return result, nil
+}
+
+// QuorumDisagreement describes a validation server which didn't produce the agreed global state.
+type QuorumDisagreement struct {
+	Server      string
+	GlobalState validator.GoGlobalState
+	Err         error
+}
+
+type QuorumValidationResult struct {
+	// Valid is true if the agreed global state matches the expected one
+	Valid bool
+	// AgreedState is nil if no global state was produced by a quorum of servers
+	AgreedState   *validator.GoGlobalState
+	Agreeing      uint64
+	Disagreements []QuorumDisagreement
+}
+
+// ValidateResultWithQuorum dispatches the validation of the message at pos to every validation
+// server supporting moduleRoot, and accepts the global state produced by at least quorum of them.
+// Servers which errored or produced a different global state are returned as disagreements.
+// If no global state reaches quorum, the result is returned along with ErrValidationQuorumNotReached.
+func (v *StatelessBlockValidator) ValidateResultWithQuorum(
+	ctx context.Context, pos arbutil.MessageIndex, moduleRoot common.Hash, quorum uint64,
+) (*QuorumValidationResult, error) {
+	entry, err := v.CreateReadyValidationEntry(ctx, pos)
+	if err != nil {
+		return nil, err
+	}
+	return v.validateEntryWithQuorum(ctx, entry, moduleRoot, quorum)
+}
+
+func (v *StatelessBlockValidator) validateEntryWithQuorum(
+	ctx context.Context, entry *validationEntry, moduleRoot common.Hash, quorum uint64,
+) (*QuorumValidationResult, error) {
+	pos := entry.Pos
+	var runs []validator.ValidationRun
+	var servers []string
+	defer func() {
+		for _, run := range runs {
+			run.Cancel()
+		}
+	}()
+	for _, spawner := range v.execSpawners {
+		if !validator.SpawnerSupportsModule(spawner, moduleRoot) {
+			continue
+		}
+		input, err := entry.ToInput(spawner.StylusArchs())
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, spawner.Launch(input, moduleRoot))
+		servers = append(servers, spawner.Name())
+	}
+	if uint64(len(runs)) < quorum {
+		return nil, fmt.Errorf("only %d validation servers support WasmModuleRoot %v, fewer than quorum %d", len(runs), moduleRoot, quorum)
+	}
+	states := make([]validator.GoGlobalState, len(runs))
+	errs := make([]error, len(runs))
+	counts := make(map[validator.GoGlobalState]uint64)
+	for i, run := range runs {
+		states[i], errs[i] = run.Await(ctx)
+		if errs[i] == nil {
+			counts[states[i]]++
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	result := &QuorumValidationResult{}
+	for state, count := range counts {
+		if count < quorum {
+			continue
+		}
+		if result.AgreedState != nil {
+			// with a quorum of at most half the servers, conflicting states may both reach it
+			result.AgreedState = nil
+			result.Agreeing = 0
+			break
+		}
+		agreed := state
+		result.AgreedState = &agreed
+		result.Agreeing = count
+	}
+	for i := range runs {
+		if errs[i] == nil && result.AgreedState != nil && states[i] == *result.AgreedState {
+			continue
+		}
+		result.Disagreements = append(result.Disagreements, QuorumDisagreement{
+			Server:      servers[i],
+			GlobalState: states[i],
+			Err:         errs[i],
+		})
+		log.Error("validation server disagrees with validation quorum", "pos", pos, "server", servers[i], "globalState", states[i], "agreedState", result.AgreedState, "err", errs[i])
+	}
+	if result.AgreedState == nil {
+		return result, fmt.Errorf("%w: %d servers required to agree on validating message %d", ErrValidationQuorumNotReached, quorum, pos)
+	}
+	result.Valid = *result.AgreedState == entry.End
+	if result.Valid {
+		v.trackValidated(entry.Pos, entry.End.BlockHash)
+	}
+	return result, nil
+}
+
+type BlockValidationResult struct {
+	Pos         arbutil.MessageIndex
+	Valid       bool
+	GlobalState *validator.GoGlobalState
+	// GasUsed is the L2 gas used by the block, as committed to by its validated block hash.
+	// It's nil if the block is invalid, or if it wasn't recorded locally.
+	GasUsed *uint64
+}
+
+// ValidateBatch validates every message derived from the given batch against the wasm module
+// root the module root oracle reports for the message's parent chain block, returning one
+// result per message in the batch.
+// If stopOnFirstMismatch is set, validation stops after the first invalid message,
+// which is then the last of the returned results.
+func (v *StatelessBlockValidator) ValidateBatch(ctx context.Context, batchNum uint64, stopOnFirstMismatch bool) ([]BlockValidationResult, error) {
+	batchCount, err := v.inboxTracker.GetBatchCount()
+	if err != nil {
+		return nil, err
+	}
+	if batchNum >= batchCount {
+		return nil, fmt.Errorf("batch not found: %d", batchNum)
+	}
+	var start arbutil.MessageIndex
+	if batchNum > 0 {
+		start, err = v.inboxTracker.GetBatchMessageCount(batchNum - 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting message count of batch %d: %w", batchNum-1, err)
+		}
+	}
+	end, err := v.inboxTracker.GetBatchMessageCount(batchNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting message count of batch %d: %w", batchNum, err)
+	}
+	results, err := v.ValidateRange(ctx, start, end, stopOnFirstMismatch)
+	if err != nil {
+		return results, fmt.Errorf("failed validating batch %d: %w", batchNum, err)
+	}
+	return results, nil
+}
+
+// ValidateRange validates messages in [start, end) against the wasm module root of each message's parent chain block,
+// returning one result per validated message. If stopOnFirstMismatch is set, validation
+// stops after the first invalid message, which is then the last of the returned results.
+// The delayed messages sequenced in the range are checked against the delayed inbox first.
+func (v *StatelessBlockValidator) ValidateRange(ctx context.Context, start, end arbutil.MessageIndex, stopOnFirstMismatch bool) ([]BlockValidationResult, error) {
+	if end < start {
+		return nil, fmt.Errorf("invalid validation range [%d, %d)", start, end)
+	}
+	if err := v.CheckDelayedSequencing(ctx, start, end); err != nil {
+		return nil, err
+	}
+	results := make([]BlockValidationResult, 0, end-start)
+	for pos := start; pos < end; pos++ {
+		moduleRoot, err := v.wasmModuleRootAt(pos)
+		if err != nil {
+			return results, err
+		}
+		result, err := v.validateAndReport(ctx, pos, moduleRoot)
+		if err != nil {
+			return results, fmt.Errorf("failed validating message %d: %w", pos, err)
+		}
+		results = append(results, result)
+		if !result.Valid && stopOnFirstMismatch {
+			log.Warn("stopping range validation at first mismatch", "start", start, "end", end, "pos", pos)
+			break
+		}
+	}
+	return results, nil
+}
+
+// SetValidationReportWriter makes range and batch validations emit their results to the given
+// report writer. It must be called before validations start, and overrides the configured report file.
+func (v *StatelessBlockValidator) SetValidationReportWriter(writer *ValidationReportWriter) {
+	v.reportWriter = writer
+}
+
+// validateAndReport validates the message at pos, emitting the result to the validation report if there's one.
+func (v *StatelessBlockValidator) validateAndReport(ctx context.Context, pos arbutil.MessageIndex, moduleRoot common.Hash) (BlockValidationResult, error) {
+	start := time.Now()
+	result, err := v.validateBlock(ctx, pos, moduleRoot)
+	if v.reportWriter == nil {
+		return result, err
+	}
+	duration := time.Since(start)
+	expected, expectedErr := v.expectedGlobalState(pos)
+	if expectedErr != nil {
+		log.Warn("failed computing expected global state for validation report", "pos", pos, "err", expectedErr)
+	}
+	reportErr := v.reportWriter.Write(newValidationReportEntry(pos, moduleRoot, expected, result.GlobalState, result.Valid, result.GasUsed, err, duration))
+	if reportErr != nil {
+		if err != nil {
+			log.Error("failed writing validation report", "pos", pos, "err", reportErr)
+			return result, err
+		}
+		return result, reportErr
+	}
+	return result, err
+}
+
+// validateBlock validates the message at pos, reporting the gas used by its block if it's valid.
+func (v *StatelessBlockValidator) validateBlock(ctx context.Context, pos arbutil.MessageIndex, moduleRoot common.Hash) (BlockValidationResult, error) {
+	result := BlockValidationResult{Pos: pos}
+	entry, err := v.CreateReadyValidationEntry(ctx, pos)
+	if err != nil {
+		return result, err
+	}
+	result.Valid, result.GlobalState, err = v.validateEntry(ctx, entry, false, moduleRoot)
+	if result.Valid {
+		// the validated block hash commits to the recorded block's header, including its gas used
+		result.GasUsed = entry.GasUsed
+	}
+	return result, err
+}
+
+func (v *StatelessBlockValidator) expectedGlobalState(pos arbutil.MessageIndex) (*validator.GoGlobalState, error) {
+	result, err := v.streamer.ResultAtMessageIndex(pos)
+	if err != nil {
+		return nil, err
+	}
+	_, endPos, err := v.GlobalStatePositionsAtCount(pos + 1)
+	if err != nil {
+		return nil, err
+	}
+	gs := BuildGlobalState(*result, endPos)
+	return &gs, nil
+}
+
+// ValidateRangeWithCheckpoint is like ValidateRange, but persists its progress to the database
+// after every valid message. If a checkpoint of the same range and latest module root exists, validation
+// resumes from it, and only the messages validated by this call are returned.
+// The checkpoint stops advancing at the first invalid message, so that resuming re-validates it,
+// and it's removed once the whole range has been validated.
+func (v *StatelessBlockValidator) ValidateRangeWithCheckpoint(ctx context.Context, start, end arbutil.MessageIndex, stopOnFirstMismatch bool) ([]BlockValidationResult, error) {
+	if end < start {
+		return nil, fmt.Errorf("invalid validation range [%d, %d)", start, end)
+	}
+	moduleRoot := v.latestWasmModuleRoot
+	progress := RangeValidationProgress{
+		Start:      uint64(start),
+		End:        uint64(end),
+		ModuleRoot: moduleRoot,
+		NextPos:    uint64(start),
+	}
+	checkpoint, err := v.ReadRangeValidationProgress()
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil && checkpoint.Start == progress.Start && checkpoint.End == progress.End && checkpoint.ModuleRoot == moduleRoot {
+		log.Info("resuming range validation from checkpoint", "start", start, "end", end, "nextPos", checkpoint.NextPos)
+		progress.NextPos = checkpoint.NextPos
+	}
+	if err := v.CheckDelayedSequencing(ctx, arbutil.MessageIndex(progress.NextPos), end); err != nil {
+		return nil, err
+	}
+	results := make([]BlockValidationResult, 0, end-arbutil.MessageIndex(progress.NextPos))
+	checkpointing := true
+	for pos := arbutil.MessageIndex(progress.NextPos); pos < end; pos++ {
+		msgModuleRoot, err := v.wasmModuleRootAt(pos)
+		if err != nil {
+			return results, err
+		}
+		result, err := v.validateAndReport(ctx, pos, msgModuleRoot)
+		if err != nil {
+			return results, fmt.Errorf("failed validating message %d: %w", pos, err)
+		}
+		results = append(results, result)
+		if !result.Valid {
+			checkpointing = false
+			if stopOnFirstMismatch {
+				log.Warn("stopping range validation at first mismatch", "start", start, "end", end, "pos", pos)
 				break
 			}
 		}
+		if checkpointing {
+			progress.NextPos = uint64(pos) + 1
+			if err := v.writeRangeValidationProgress(&progress); err != nil {
+				return results, err
+			}
+		}
 	}
-	if run == nil {
-		return false, nil, fmt.Errorf("validation with WasmModuleRoot %v not supported by node", moduleRoot)
+	if checkpointing && progress.NextPos == progress.End {
+		if err := v.db.Delete(rangeValidationProgressKey); err != nil {
+			return results, fmt.Errorf("failed deleting range validation checkpoint: %w", err)
+		}
 	}
-	defer run.Cancel()
-	gsEnd, err := run.Await(ctx)
-	if err != nil || gsEnd != entry.End {
-		return false, &gsEnd, err
+	return results, nil
+}
+
+// ReadRangeValidationProgress returns the persisted range validation checkpoint, or nil if there's none.
+func (v *StatelessBlockValidator) ReadRangeValidationProgress() (*RangeValidationProgress, error) {
+	exists, err := v.db.Has(rangeValidationProgressKey)
+	if err != nil || !exists {
+		return nil, err
+	}
+	progressBytes, err := v.db.Get(rangeValidationProgressKey)
+	if err != nil {
+		return nil, err
+	}
+	var progress RangeValidationProgress
+	if err := rlp.DecodeBytes(progressBytes, &progress); err != nil {
+		return nil, fmt.Errorf("failed decoding range validation checkpoint: %w", err)
+	}
+	return &progress, nil
+}
+
+func (v *StatelessBlockValidator) writeRangeValidationProgress(progress *RangeValidationProgress) error {
+	encoded, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		return err
+	}
+	if err := v.db.Put(rangeValidationProgressKey, encoded); err != nil {
+		return fmt.Errorf("failed writing range validation checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (v *StatelessBlockValidator) trackValidated(pos arbutil.MessageIndex, blockHash common.Hash) {
+	v.validatedHashesMutex.Lock()
+	defer v.validatedHashesMutex.Unlock()
+	v.validatedHashes[pos] = blockHash
+	if len(v.validatedHashes) > maxTrackedValidatedMessages {
+		for tracked := range v.validatedHashes {
+			if tracked+maxTrackedValidatedMessages <= pos {
+				delete(v.validatedHashes, tracked)
+			}
+		}
 	}
-	return true, &entry.End, nil
+}
+
+// DetectReorg returns the first previously validated message which has since been
+// reorged, i.e. is no longer in the chain or now results in a different block.
+func (v *StatelessBlockValidator) DetectReorg() (arbutil.MessageIndex, bool, error) {
+	v.validatedHashesMutex.Lock()
+	defer v.validatedHashesMutex.Unlock()
+	msgCount, err := v.streamer.GetProcessedMessageCount()
+	if err != nil {
+		return 0, false, err
+	}
+	var firstReorged arbutil.MessageIndex
+	found := false
+	for pos, blockHash := range v.validatedHashes {
+		reorged := pos >= msgCount
+		if reorged {
+			// forget the block hash, so the message is still found reorged once it's back in the chain
+			v.validatedHashes[pos] = common.Hash{}
+		} else {
+			result, err := v.streamer.ResultAtMessageIndex(pos)
+			if err != nil {
+				return 0, false, err
+			}
+			reorged = result.BlockHash != blockHash
+		}
+		if reorged && (!found || pos < firstReorged) {
+			firstReorged = pos
+			found = true
+		}
+	}
+	return firstReorged, found, nil
+}
+
+// RevalidateReorged detects a reorg of previously validated messages and re-validates
+// the affected range, up to the last previously validated message back in the chain.
+// Reorged messages not yet back in the chain are revalidated by a later call.
+// Post-reorg validation failures are logged and reported as invalid results.
+func (v *StatelessBlockValidator) RevalidateReorged(ctx context.Context, stopOnFirstMismatch bool) ([]BlockValidationResult, error) {
+	firstReorged, found, err := v.DetectReorg()
+	if err != nil {
+		return nil, fmt.Errorf("error detecting reorg: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	msgCount, err := v.streamer.GetProcessedMessageCount()
+	if err != nil {
+		return nil, err
+	}
+	if msgCount <= firstReorged {
+		log.Info("reorged validated messages not back in the chain yet", "firstReorged", firstReorged, "msgCount", msgCount)
+		return nil, nil
+	}
+	v.validatedHashesMutex.Lock()
+	end := firstReorged
+	for pos := range v.validatedHashes {
+		if pos >= firstReorged && pos < msgCount {
+			end = max(end, pos+1)
+			delete(v.validatedHashes, pos)
+		}
+	}
+	v.validatedHashesMutex.Unlock()
+	log.Warn("detected reorg of validated messages, revalidating affected range", "start", firstReorged, "end", end)
+	results, err := v.ValidateRange(ctx, firstReorged, end, stopOnFirstMismatch)
+	for _, result := range results {
+		if !result.Valid {
+			validatorPostReorgFailuresCounter.Inc(1)
+			log.Error("post-reorg validation failed", "pos", result.Pos)
+		}
+	}
+	return results, err
 }
 
 func (v *StatelessBlockValidator) ValidationInputsAt(ctx context.Context, pos arbutil.MessageIndex, targets ...rawdb.WasmTarget) (server_api.InputJSON, error) {
@@ -558,6 +1212,14 @@ func (v *StatelessBlockValidator) GetLatestWasmModuleRoot() common.Hash {
 	return v.latestWasmModuleRoot
 }
 
+// CheckOnChainWasmModuleRoot returns ErrWasmModuleRootMismatch if onChainRoot isn't the latest machine's root
+func (v *StatelessBlockValidator) CheckOnChainWasmModuleRoot(onChainRoot common.Hash) error {
+	if onChainRoot == v.latestWasmModuleRoot {
+		return nil
+	}
+	return fmt.Errorf("%w: on-chain %v, latest machine %v", ErrWasmModuleRootMismatch, onChainRoot, v.latestWasmModuleRoot)
+}
+
 func (v *StatelessBlockValidator) Start(ctx_in context.Context) error {
 	if v.redisValidator != nil {
 		if err := v.redisValidator.Start(ctx_in); err != nil {
@@ -569,6 +1231,19 @@ func (v *StatelessBlockValidator) Start(ctx_in context.Context) error {
 			return err
 		}
 	}
+	if v.archiveClient != nil {
+		if err := v.archiveClient.Start(ctx_in); err != nil {
+			return fmt.Errorf("starting archive node client: %w", err)
+		}
+	}
+	if v.reportWriter == nil && v.config.ValidationReportFile != "" {
+		reportFile, err := os.OpenFile(v.config.ValidationReportFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("opening validation report file: %w", err)
+		}
+		v.reportFile = reportFile
+		v.reportWriter = NewValidationReportWriter(reportFile)
+	}
 	return nil
 }
 
@@ -579,4 +1254,12 @@ func (v *StatelessBlockValidator) Stop() {
 	if v.redisValidator != nil {
 		v.redisValidator.Stop()
 	}
+	if v.archiveClient != nil {
+		v.archiveClient.Close()
+	}
+	if v.reportFile != nil {
+		if err := v.reportFile.Close(); err != nil {
+			log.Error("error closing validation report file", "err", err)
+		}
+	}
 }
@@ -33,6 +33,11 @@ type Builder struct {
 	authMutex    sync.Mutex
 	wallet       ValidatorWalletInterface
 	gasRefunder  common.Address
+
+	executedMutex sync.Mutex
+	// recording is set while RecordExecutedTransactions runs, which collects the transactions posted in executed
+	recording bool
+	executed  []*types.Transaction
 }
 
 func NewBuilder(wallet ValidatorWalletInterface, gasRefunder common.Address) (*Builder, error) {
@@ -93,6 +98,7 @@ func NewBuilder(wallet ValidatorWalletInterface, gasRefunder common.Address) (*B
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute builder transaction: %w", err)
 		}
+		builder.recordExecuted(signedTx)
 		return signedTx, nil
 	}
 	return builder, nil
@@ -102,6 +108,11 @@ func (b *Builder) BuildingTransactionCount() int {
 	return len(b.transactions)
 }
 
+// BuildingTransactions returns the transactions batched so far.
+func (b *Builder) BuildingTransactions() []*types.Transaction {
+	return b.transactions
+}
+
 func (b *Builder) ClearTransactions() {
 	b.transactions = nil
 }
@@ -144,7 +155,46 @@ func (b *Builder) WalletAddress() *common.Address {
 }
 
 func (b *Builder) ExecuteTransactions(ctx context.Context) (*types.Transaction, error) {
+	return b.ExecuteTransactionsRetrying(ctx, func(error) bool { return false })
+}
+
+// ExecuteTransactionsRetrying is like ExecuteTransactions, except that if sending the transactions fails and
+// retry returns true for the error, having dealt with its cause, sending them is attempted once more.
+func (b *Builder) ExecuteTransactionsRetrying(ctx context.Context, retry func(error) bool) (*types.Transaction, error) {
 	tx, err := b.wallet.ExecuteTransactions(ctx, b.transactions, b.gasRefunder)
+	if err != nil && retry(err) {
+		tx, err = b.wallet.ExecuteTransactions(ctx, b.transactions, b.gasRefunder)
+	}
 	b.ClearTransactions()
+	b.recordExecuted(tx)
 	return tx, err
 }
+
+func (b *Builder) recordExecuted(tx *types.Transaction) {
+	if tx == nil {
+		return
+	}
+	b.executedMutex.Lock()
+	defer b.executedMutex.Unlock()
+	if b.recording {
+		b.executed = append(b.executed, tx)
+	}
+}
+
+func (b *Builder) setRecording(recording bool) {
+	b.executedMutex.Lock()
+	defer b.executedMutex.Unlock()
+	b.recording = recording
+	b.executed = nil
+}
+
+// RecordExecutedTransactions runs fn, returning the transactions posted via the builder while it ran.
+// Transactions posted outside of it aren't kept.
+func (b *Builder) RecordExecutedTransactions(fn func()) []*types.Transaction {
+	b.setRecording(true)
+	defer b.setRecording(false)
+	fn()
+	b.executedMutex.Lock()
+	defer b.executedMutex.Unlock()
+	return b.executed
+}
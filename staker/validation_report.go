@@ -0,0 +1,95 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+// ValidationReportVersion is the version of the validation report schema.
+// It must be bumped whenever a field of ValidationReportEntry is changed or removed.
+const ValidationReportVersion = 1
+
+type ValidationReportGlobalState struct {
+	BlockHash  common.Hash `json:"blockHash"`
+	SendRoot   common.Hash `json:"sendRoot"`
+	Batch      uint64      `json:"batch"`
+	PosInBatch uint64      `json:"posInBatch"`
+}
+
+func newValidationReportGlobalState(gs *validator.GoGlobalState) *ValidationReportGlobalState {
+	if gs == nil {
+		return nil
+	}
+	return &ValidationReportGlobalState{
+		BlockHash:  gs.BlockHash,
+		SendRoot:   gs.SendRoot,
+		Batch:      gs.Batch,
+		PosInBatch: gs.PosInBatch,
+	}
+}
+
+// ValidationReportEntry is the result of validating a single message, as written to a validation report.
+// Expected is nil if the expected global state couldn't be computed, and Actual is nil if
+// validation failed without producing a global state, in which case Error is set.
+type ValidationReportEntry struct {
+	Version       uint64                       `json:"version"`
+	MessageNumber uint64                       `json:"messageNumber"`
+	ModuleRoot    common.Hash                  `json:"moduleRoot"`
+	Expected      *ValidationReportGlobalState `json:"expected"`
+	Actual        *ValidationReportGlobalState `json:"actual"`
+	Valid         bool                         `json:"valid"`
+	GasUsed       *uint64                      `json:"gasUsed,omitempty"`
+	Error         string                       `json:"error,omitempty"`
+	DurationMs    int64                        `json:"durationMs"`
+}
+
+// ValidationReportWriter writes validation results as JSON lines, one ValidationReportEntry per line.
+type ValidationReportWriter struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewValidationReportWriter(w io.Writer) *ValidationReportWriter {
+	return &ValidationReportWriter{encoder: json.NewEncoder(w)}
+}
+
+func (w *ValidationReportWriter) Write(entry *ValidationReportEntry) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if err := w.encoder.Encode(entry); err != nil {
+		return fmt.Errorf("failed writing validation report entry for message %d: %w", entry.MessageNumber, err)
+	}
+	return nil
+}
+
+func newValidationReportEntry(
+	pos arbutil.MessageIndex, moduleRoot common.Hash, expected, actual *validator.GoGlobalState, valid bool, gasUsed *uint64, validationErr error, duration time.Duration,
+) *ValidationReportEntry {
+	entry := &ValidationReportEntry{
+		Version:       ValidationReportVersion,
+		MessageNumber: uint64(pos),
+		ModuleRoot:    moduleRoot,
+		Expected:      newValidationReportGlobalState(expected),
+		Actual:        newValidationReportGlobalState(actual),
+		Valid:         valid,
+		GasUsed:       gasUsed,
+		DurationMs:    duration.Milliseconds(),
+	}
+	if validationErr != nil {
+		entry.Error = validationErr.Error()
+		// a failed validation doesn't produce a meaningful global state
+		entry.Actual = nil
+	}
+	return entry
+}
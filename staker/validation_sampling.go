@@ -0,0 +1,115 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+type ValidationSamplingConfig struct {
+	Rate          float64 `koanf:"rate" reload:"hot"`
+	AnomalyWindow uint64  `koanf:"anomaly-window" reload:"hot"`
+}
+
+var DefaultValidationSamplingConfig = ValidationSamplingConfig{
+	Rate:          1,
+	AnomalyWindow: 1000,
+}
+
+func ValidationSamplingConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Float64(prefix+".rate", DefaultValidationSamplingConfig.Rate, "fraction of blocks the block validator validates, trusting local execution for the rest without recording them as validated (1 validates every block). Blocks around failed validations and incorrect assertions are always validated")
+	f.Uint64(prefix+".anomaly-window", DefaultValidationSamplingConfig.AnomalyWindow, "when sampling, number of blocks fully validated starting at a failed validation")
+}
+
+func (c *ValidationSamplingConfig) Validate() error {
+	if c.Rate <= 0 || c.Rate > 1 {
+		return errors.New("block validator sampling rate must be in (0, 1]")
+	}
+	return nil
+}
+
+type messageRange struct {
+	start, end arbutil.MessageIndex
+}
+
+// ValidationSampler decides which messages get validated in sampling mode.
+// Sampled messages are spread evenly, so that out of any n consecutive messages
+// n*rate (rounded) are validated. Messages in forced ranges are always validated.
+type ValidationSampler struct {
+	mutex sync.Mutex
+	// sorted, non-overlapping and non-adjacent
+	forced []messageRange
+}
+
+func NewValidationSampler() *ValidationSampler {
+	return &ValidationSampler{}
+}
+
+// ForceFullValidation makes messages in [start, end) always be validated.
+func (s *ValidationSampler) ForceFullValidation(start, end arbutil.MessageIndex) {
+	s.force(start, end)
+}
+
+// force adds [start, end) to the forced ranges, and returns the parts of it which weren't forced before.
+func (s *ValidationSampler) force(start, end arbutil.MessageIndex) []messageRange {
+	if end <= start {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var added []messageRange
+	merged := messageRange{start: start, end: end}
+	next := start
+	remaining := make([]messageRange, 0, len(s.forced)+1)
+	inserted := false
+	for _, r := range s.forced {
+		if r.end < start {
+			remaining = append(remaining, r)
+			continue
+		}
+		if r.start > end {
+			if !inserted {
+				remaining = append(remaining, merged)
+				inserted = true
+			}
+			remaining = append(remaining, r)
+			continue
+		}
+		if r.start > next {
+			added = append(added, messageRange{start: next, end: r.start})
+		}
+		next = max(next, r.end)
+		merged.start = min(merged.start, r.start)
+		merged.end = max(merged.end, r.end)
+	}
+	if next < end {
+		added = append(added, messageRange{start: next, end: end})
+	}
+	if !inserted {
+		remaining = append(remaining, merged)
+	}
+	s.forced = remaining
+	return added
+}
+
+// ShouldValidate returns whether the message at pos should be validated with the given sampling rate.
+func (s *ValidationSampler) ShouldValidate(pos arbutil.MessageIndex, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	i := sort.Search(len(s.forced), func(i int) bool { return s.forced[i].end > pos })
+	if i < len(s.forced) && s.forced[i].start <= pos {
+		return true
+	}
+	return math.Floor(float64(pos+1)*rate) > math.Floor(float64(pos)*rate)
+}
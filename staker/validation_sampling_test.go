@@ -0,0 +1,62 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package staker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+func TestValidationSampler(t *testing.T) {
+	sampler := NewValidationSampler()
+	rate := 0.25
+	validated := 0
+	for pos := arbutil.MessageIndex(0); pos < 1000; pos++ {
+		if sampler.ShouldValidate(pos, rate) {
+			validated++
+		}
+	}
+	if validated != 250 {
+		t.Fatal("expected 250 sampled validations at rate", rate, "got", validated)
+	}
+
+	sampler.ForceFullValidation(100, 120)
+	for pos := arbutil.MessageIndex(100); pos < 120; pos++ {
+		if !sampler.ShouldValidate(pos, rate) {
+			t.Fatal("message", pos, "in forced range wasn't validated")
+		}
+	}
+	for pos := arbutil.MessageIndex(0); pos < 100; pos++ {
+		if !sampler.ShouldValidate(pos, 1) {
+			t.Fatal("message", pos, "not validated at rate 1")
+		}
+	}
+}
+
+func TestValidationSamplerForceDedupes(t *testing.T) {
+	sampler := NewValidationSampler()
+	steps := []struct {
+		start, end arbutil.MessageIndex
+		added      []messageRange
+	}{
+		{100, 120, []messageRange{{100, 120}}},
+		{100, 120, nil},
+		{110, 115, nil},
+		{90, 130, []messageRange{{90, 100}, {120, 130}}},
+		{200, 210, []messageRange{{200, 210}}},
+		{125, 205, []messageRange{{130, 200}}},
+		{210, 210, nil},
+	}
+	for _, step := range steps {
+		added := sampler.force(step.start, step.end)
+		if !reflect.DeepEqual(added, step.added) {
+			t.Fatal("forcing", step.start, step.end, "added", added, "expected", step.added)
+		}
+	}
+	if !reflect.DeepEqual(sampler.forced, []messageRange{{90, 210}}) {
+		t.Fatal("forced ranges weren't merged", sampler.forced)
+	}
+}
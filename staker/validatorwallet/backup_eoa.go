@@ -0,0 +1,89 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package validatorwallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbnode/dataposter"
+)
+
+// ErrBackupEOACannotSendValue is returned when the wallet's sender can't afford a wallet transaction
+// sending value, which the backup EOA never pays for, so that stakes are only ever funded by the wallet's sender.
+var ErrBackupEOACannotSendValue = errors.New("backup EOA doesn't fund transactions sending value")
+
+// WithBackupEOA makes the wallet send its transactions through dataPoster's EOA when the wallet's own sender
+// can't afford their gas. The backup EOA must be an executor of the wallet, allowed to call the wallet's
+// destinations. It only ever pays for gas: transactions sending value aren't sent through it.
+func WithBackupEOA(dataPoster *dataposter.DataPoster) ContractOption {
+	return func(v *Contract) {
+		v.backupDataPoster = dataPoster
+	}
+}
+
+// BackupEOA returns the address of the backup EOA paying for the wallet's gas if its sender can't, if any.
+func (v *Contract) BackupEOA() *common.Address {
+	if v.backupDataPoster == nil {
+		return nil
+	}
+	sender := v.backupDataPoster.Sender()
+	return &sender
+}
+
+// canAfford returns whether from's balance covers the value and maximum gas cost of a transaction.
+func (v *Contract) canAfford(ctx context.Context, from common.Address, gas uint64, value *big.Int) (bool, error) {
+	gasFeeCap, _, err := gasFees(ctx, v.l1Reader)
+	if err != nil {
+		return false, err
+	}
+	cost := new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(gas))
+	cost.Add(cost, value)
+	balance, err := v.l1Reader.Client().BalanceAt(ctx, from, nil)
+	if err != nil {
+		return false, fmt.Errorf("getting balance of %v: %w", from, err)
+	}
+	return balance.Cmp(cost) >= 0, nil
+}
+
+// postWalletTransaction posts a call of the wallet with data and value, paying for its gas from the wallet's
+// sender, or from the backup EOA if the sender can't afford it.
+func (v *Contract) postWalletTransaction(ctx context.Context, data []byte, value *big.Int) (*types.Transaction, error) {
+	gas, err := v.gasForTxData(ctx, data, value)
+	if v.backupDataPoster == nil {
+		if err != nil {
+			return nil, fmt.Errorf("getting gas for tx data: %w", err)
+		}
+		return v.dataPoster.PostSimpleTransaction(ctx, *v.Address(), data, gas, value)
+	}
+	// the gas estimation fails too if the sender can't afford the transaction
+	senderErr := err
+	if senderErr == nil {
+		affordable, err := v.canAfford(ctx, v.From(), gas, value)
+		if err != nil {
+			return nil, err
+		}
+		if affordable {
+			log.Debug("validator wallet transaction paid by wallet sender", "wallet", *v.Address(), "sender", v.From(), "gas", gas)
+			return v.dataPoster.PostSimpleTransaction(ctx, *v.Address(), data, gas, value)
+		}
+		senderErr = fmt.Errorf("balance of %v doesn't cover the transaction", v.From())
+	}
+	if value.Sign() > 0 {
+		return nil, fmt.Errorf("%w: sending %v: %w", ErrBackupEOACannotSendValue, value, senderErr)
+	}
+	backup := v.backupDataPoster.Sender()
+	backupGas, err := gasForTxData(ctx, v.l1Reader, backup, v.Address(), data, value, v.getExtraGas)
+	if err != nil {
+		return nil, fmt.Errorf("getting gas for tx data from wallet sender (%w) and backup EOA %v: %w", senderErr, backup, err)
+	}
+	log.Warn("validator wallet sender can't afford transaction, paying its gas from backup EOA", "wallet", *v.Address(), "sender", v.From(), "backup", backup, "gas", backupGas, "err", senderErr)
+	return v.backupDataPoster.PostSimpleTransaction(ctx, *v.Address(), data, backupGas, value)
+}
@@ -59,10 +59,17 @@ type Contract struct {
 	dataPoster          *dataposter.DataPoster
 	getExtraGas         func() uint64
 	populateWalletMutex sync.Mutex
+	// ERC-20 token the wallet stakes with, and the rollup it's staked on, if not the native currency
+	stakeToken        common.Address
+	stakeTokenSpender common.Address
+	// data poster of the EOA paying for the wallet's gas if its sender can't, if any
+	backupDataPoster *dataposter.DataPoster
+	// extra gas to create the wallet with, if it differs from getExtraGas
+	getCreationGas func() uint64
 }
 
 func NewContract(dp *dataposter.DataPoster, address *common.Address, walletFactoryAddr common.Address, l1Reader *headerreader.HeaderReader, auth *bind.TransactOpts, rollupFromBlock int64, onWalletCreated func(common.Address),
-	getExtraGas func() uint64) (*Contract, error) {
+	getExtraGas func() uint64, opts ...ContractOption) (*Contract, error) {
 	var con *rollup_legacy_gen.ValidatorWallet
 	if address != nil {
 		var err error
@@ -81,11 +88,22 @@ func NewContract(dp *dataposter.DataPoster, address *common.Address, walletFacto
 		dataPoster:        dp,
 		getExtraGas:       getExtraGas,
 	}
+	for _, opt := range opts {
+		opt(wallet)
+	}
 	// Go complains if we make an address variable before wallet and copy it in
 	wallet.address.Store(address)
 	return wallet, nil
 }
 
+// WithCreationGas makes the wallet create its contract with getCreationGas extra gas, instead of the extra gas
+// of its other transactions, as creating the contract is a one-time large transaction.
+func WithCreationGas(getCreationGas func() uint64) ContractOption {
+	return func(v *Contract) {
+		v.getCreationGas = getCreationGas
+	}
+}
+
 func (v *Contract) validateWallet(ctx context.Context) error {
 	if v.con == nil || v.auth == nil {
 		return nil
@@ -102,6 +120,15 @@ func (v *Contract) validateWallet(ctx context.Context) error {
 	if v.auth.From != owner && !isExecutor {
 		return errors.New("specified unauthorized smart contract wallet")
 	}
+	if backup := v.BackupEOA(); backup != nil {
+		isExecutor, err := v.con.Executors(callOpts, *backup)
+		if err != nil {
+			return err
+		}
+		if *backup != owner && !isExecutor {
+			return fmt.Errorf("backup EOA %v isn't authorized by the smart contract wallet", *backup)
+		}
+	}
 	return nil
 }
 
@@ -147,11 +174,7 @@ func (v *Contract) executeTransaction(ctx context.Context, tx *types.Transaction
 	if err != nil {
 		return nil, fmt.Errorf("packing arguments for executeTransactionWithGasRefunder: %w", err)
 	}
-	gas, err := v.gasForTxData(ctx, data, tx.Value())
-	if err != nil {
-		return nil, fmt.Errorf("getting gas for tx data: %w", err)
-	}
-	return v.dataPoster.PostSimpleTransaction(ctx, *v.Address(), data, gas, tx.Value())
+	return v.postWalletTransaction(ctx, data, tx.Value())
 }
 
 func createWalletContract(
@@ -200,7 +223,7 @@ func (v *Contract) populateWallet(ctx context.Context, createIfMissing bool) err
 		// By passing v.dataPoster as a parameter to GetValidatorWalletContract we force to create a validator wallet through the Staker's DataPoster object.
 		// DataPoster keeps in its internal state information related to the transactions sent through it, which is used to infer the expected nonce in a transaction for example.
 		// If a transaction is sent using the Staker's DataPoster key, but not through the Staker's DataPoster object, DataPoster's internal state will be outdated, which can compromise the expected nonce inference.
-		addr, err := GetValidatorWalletContract(ctx, v.walletFactoryAddr, v.rollupFromBlock, v.l1Reader, createIfMissing, v.dataPoster, v.getExtraGas)
+		addr, err := GetValidatorWalletContract(ctx, v.walletFactoryAddr, v.rollupFromBlock, v.l1Reader, createIfMissing, v.dataPoster, v.getExtraGas, v.getCreationGas)
 		if err != nil {
 			return err
 		}
@@ -248,6 +271,10 @@ func (v *Contract) ExecuteTransactions(ctx context.Context, txes []*types.Transa
 	if err != nil {
 		return nil, err
 	}
+	txes, err = v.withStakeTokenAllowance(ctx, txes)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(txes) == 1 {
 		arbTx, err := v.executeTransaction(ctx, txes[0], gasRefunder)
@@ -282,30 +309,35 @@ func (v *Contract) ExecuteTransactions(ctx context.Context, txes []*types.Transa
 	if err != nil {
 		return nil, fmt.Errorf("packing arguments for executeTransactionWithGasRefunder: %w", err)
 	}
-	gas, err := v.gasForTxData(ctx, txData, callValue)
-	if err != nil {
-		return nil, fmt.Errorf("getting gas for tx data: %w", err)
-	}
-	arbTx, err := v.dataPoster.PostSimpleTransaction(ctx, *v.Address(), txData, gas, callValue)
+	arbTx, err := v.postWalletTransaction(ctx, txData, callValue)
 	if err != nil {
 		return nil, err
 	}
 	return arbTx, nil
 }
 
-func gasForTxData(ctx context.Context, l1Reader *headerreader.HeaderReader, from common.Address, to *common.Address, data []byte, value *big.Int, getExtraGas func() uint64) (uint64, error) {
+// gasFees returns the fee cap and tip cap to estimate transactions' gas with.
+func gasFees(ctx context.Context, l1Reader *headerreader.HeaderReader) (*big.Int, *big.Int, error) {
 	h, err := l1Reader.LastHeader(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("getting the last header: %w", err)
+		return nil, nil, fmt.Errorf("getting the last header: %w", err)
 	}
 	gasFeeCap := new(big.Int).Mul(h.BaseFee, big.NewInt(2))
 	gasFeeCap = arbmath.BigMax(gasFeeCap, arbmath.FloatToBig(params.GWei))
 
 	gasTipCap, err := l1Reader.Client().SuggestGasTipCap(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("getting suggested gas tip cap: %w", err)
+		return nil, nil, fmt.Errorf("getting suggested gas tip cap: %w", err)
 	}
 	gasFeeCap.Add(gasFeeCap, gasTipCap)
+	return gasFeeCap, gasTipCap, nil
+}
+
+func gasForTxData(ctx context.Context, l1Reader *headerreader.HeaderReader, from common.Address, to *common.Address, data []byte, value *big.Int, getExtraGas func() uint64) (uint64, error) {
+	gasFeeCap, gasTipCap, err := gasFees(ctx, l1Reader)
+	if err != nil {
+		return 0, err
+	}
 	g, err := l1Reader.Client().EstimateGas(
 		ctx,
 		ethereum.CallMsg{
@@ -332,11 +364,7 @@ func (v *Contract) TimeoutChallenges(ctx context.Context, challenges []uint64, c
 	if err != nil {
 		return nil, fmt.Errorf("packing arguments for timeoutChallenges: %w", err)
 	}
-	gas, err := v.gasForTxData(ctx, data, common.Big0)
-	if err != nil {
-		return nil, fmt.Errorf("getting gas for tx data: %w", err)
-	}
-	return v.dataPoster.PostSimpleTransaction(ctx, *v.Address(), data, gas, common.Big0)
+	return v.postWalletTransaction(ctx, data, common.Big0)
 }
 
 func (v *Contract) L1Client() *ethclient.Client {
@@ -347,6 +375,10 @@ func (v *Contract) TestTransactions(ctx context.Context, txs []*types.Transactio
 	if v.Address() == nil {
 		return nil
 	}
+	txs, err := v.withStakeTokenAllowance(ctx, txs)
+	if err != nil {
+		return err
+	}
 	data, dest, amount, totalAmount := combineTxes(txs)
 	realData, err := validatorABI.Pack("executeTransactions", data, dest, amount)
 	if err != nil {
@@ -362,6 +394,23 @@ func (v *Contract) TestTransactions(ctx context.Context, txs []*types.Transactio
 	return err
 }
 
+// EstimateTransactionsGas returns the gas executing txs through the wallet in one transaction is estimated to need.
+func (v *Contract) EstimateTransactionsGas(ctx context.Context, txs []*types.Transaction) (uint64, error) {
+	if v.Address() == nil {
+		return 0, errors.New("validator wallet contract doesn't exist yet")
+	}
+	txs, err := v.withStakeTokenAllowance(ctx, txs)
+	if err != nil {
+		return 0, err
+	}
+	data, dest, amount, totalAmount := combineTxes(txs)
+	realData, err := validatorABI.Pack("executeTransactions", data, dest, amount)
+	if err != nil {
+		return 0, err
+	}
+	return v.gasForTxData(ctx, realData, totalAmount)
+}
+
 func (v *Contract) CanBatchTxs() bool {
 	return true
 }
@@ -372,21 +421,41 @@ func (v *Contract) AuthIfEoa() *bind.TransactOpts {
 
 func (w *Contract) Start(ctx context.Context) {
 	w.dataPoster.Start(ctx)
+	if w.backupDataPoster != nil {
+		w.backupDataPoster.Start(ctx)
+	}
 }
 
 func (b *Contract) StopAndWait() {
 	b.dataPoster.StopAndWait()
+	if b.backupDataPoster != nil {
+		b.backupDataPoster.StopAndWait()
+	}
 }
 
 func (b *Contract) DataPoster() *dataposter.DataPoster {
 	return b.dataPoster
 }
 
+func (b *Contract) BackupDataPoster() *dataposter.DataPoster {
+	return b.backupDataPoster
+}
+
 // Exported for testing
 func (b *Contract) GetExtraGas() func() uint64 {
 	return b.getExtraGas
 }
 
+// GetCreationGas returns the extra gas the wallet is created with, which may be nil to use GetExtraGas.
+func (b *Contract) GetCreationGas() func() uint64 {
+	return b.getCreationGas
+}
+
+// ErrWalletNotDeployed is returned by CheckValidatorWalletContract when the owner has no validator wallet.
+var ErrWalletNotDeployed = errors.New("validator wallet not deployed")
+
+// GetValidatorWalletContract returns the address of the data poster's validator wallet, creating it if missing and
+// createIfMissing is set. The wallet is created with getCreationGas extra gas, or with getExtraGas if that's nil.
 func GetValidatorWalletContract(
 	ctx context.Context,
 	validatorWalletFactoryAddr common.Address,
@@ -395,44 +464,26 @@ func GetValidatorWalletContract(
 	createIfMissing bool,
 	dataPoster *dataposter.DataPoster,
 	getExtraGas func() uint64,
+	getCreationGas func() uint64,
 ) (*common.Address, error) {
-	client := l1Reader.Client()
 	transactAuth := dataPoster.Auth()
-
-	// TODO: If we just save a mapping in the wallet creator we won't need log search
-	walletCreator, err := rollup_legacy_gen.NewValidatorWalletCreator(validatorWalletFactoryAddr, client)
-	if err != nil {
-		return nil, err
-	}
-	query := ethereum.FilterQuery{
-		BlockHash: nil,
-		FromBlock: big.NewInt(fromBlock),
-		ToBlock:   nil,
-		Addresses: []common.Address{validatorWalletFactoryAddr},
-		Topics:    [][]common.Hash{{walletCreatedID}, nil, {common.BytesToHash(transactAuth.From.Bytes())}},
-	}
-	logs, err := client.FilterLogs(ctx, query)
+	walletCreator, err := rollup_legacy_gen.NewValidatorWalletCreator(validatorWalletFactoryAddr, l1Reader.Client())
 	if err != nil {
 		return nil, err
 	}
-	if len(logs) > 1 {
-		return nil, errors.New("more than one validator wallet created for address")
-	}
-	if len(logs) == 1 {
-		rawLog := logs[0]
-		parsed, err := walletCreator.ParseWalletCreated(rawLog)
-		if err != nil {
-			return nil, err
-		}
-		log.Info("found validator smart contract wallet", "address", parsed.WalletAddress)
-		return &parsed.WalletAddress, err
+	walletAddr, err := findValidatorWalletContract(ctx, walletCreator, validatorWalletFactoryAddr, fromBlock, l1Reader, transactAuth.From)
+	if err != nil || walletAddr != nil {
+		return walletAddr, err
 	}
 
 	if !createIfMissing {
 		return nil, nil
 	}
 
-	tx, err := createWalletContract(ctx, l1Reader, transactAuth.From, dataPoster, getExtraGas, validatorWalletFactoryAddr)
+	if getCreationGas == nil {
+		getCreationGas = getExtraGas
+	}
+	tx, err := createWalletContract(ctx, l1Reader, transactAuth.From, dataPoster, getCreationGas, validatorWalletFactoryAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -448,3 +499,62 @@ func GetValidatorWalletContract(
 	log.Info("created validator smart contract wallet", "address", ev.WalletAddress)
 	return &ev.WalletAddress, nil
 }
+
+// CheckValidatorWalletContract returns the address of owner's validator wallet, or ErrWalletNotDeployed if it
+// has none. Unlike GetValidatorWalletContract, it never creates the wallet, and so doesn't need a data poster,
+// e.g. to verify the configured chain and owner before funding them.
+func CheckValidatorWalletContract(
+	ctx context.Context,
+	validatorWalletFactoryAddr common.Address,
+	fromBlock int64,
+	l1Reader *headerreader.HeaderReader,
+	owner common.Address,
+) (common.Address, error) {
+	walletCreator, err := rollup_legacy_gen.NewValidatorWalletCreator(validatorWalletFactoryAddr, l1Reader.Client())
+	if err != nil {
+		return common.Address{}, err
+	}
+	walletAddr, err := findValidatorWalletContract(ctx, walletCreator, validatorWalletFactoryAddr, fromBlock, l1Reader, owner)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if walletAddr == nil {
+		return common.Address{}, fmt.Errorf("%w for owner %v by factory %v", ErrWalletNotDeployed, owner, validatorWalletFactoryAddr)
+	}
+	return *walletAddr, nil
+}
+
+// findValidatorWalletContract returns the address of owner's validator wallet, or nil if it has none.
+func findValidatorWalletContract(
+	ctx context.Context,
+	walletCreator *rollup_legacy_gen.ValidatorWalletCreator,
+	validatorWalletFactoryAddr common.Address,
+	fromBlock int64,
+	l1Reader *headerreader.HeaderReader,
+	owner common.Address,
+) (*common.Address, error) {
+	// TODO: If we just save a mapping in the wallet creator we won't need log search
+	query := ethereum.FilterQuery{
+		BlockHash: nil,
+		FromBlock: big.NewInt(fromBlock),
+		ToBlock:   nil,
+		Addresses: []common.Address{validatorWalletFactoryAddr},
+		Topics:    [][]common.Hash{{walletCreatedID}, nil, {common.BytesToHash(owner.Bytes())}},
+	}
+	logs, err := l1Reader.Client().FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) > 1 {
+		return nil, errors.New("more than one validator wallet created for address")
+	}
+	if len(logs) == 0 {
+		return nil, nil
+	}
+	parsed, err := walletCreator.ParseWalletCreated(logs[0])
+	if err != nil {
+		return nil, err
+	}
+	log.Info("found validator smart contract wallet", "address", parsed.WalletAddress)
+	return &parsed.WalletAddress, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package validatorwallet
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/solgen/go/challenge_legacy_gen"
+	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
+)
+
+// ABIs the calls of a dry run wallet are decoded against
+var dryRunABIs []abi.ABI
+
+func init() {
+	for _, abiJSON := range []string{rollup_legacy_gen.RollupUserLogicABI, challenge_legacy_gen.ChallengeManagerABI, erc20ABIJSON} {
+		parsed, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			panic(err)
+		}
+		dryRunABIs = append(dryRunABIs, parsed)
+	}
+}
+
+// DryRun validator wallet is a read-only wallet like NoOp, but instead of discarding the
+// transactions it's asked to make, it logs the methods they'd call. It lets operators audit
+// what a staker would do before promoting a watchtower to an active staker.
+type DryRun struct {
+	NoOp
+}
+
+func NewDryRun(l1Client *ethclient.Client) *DryRun {
+	return &DryRun{
+		NoOp: NoOp{l1Client: l1Client},
+	}
+}
+
+func (*DryRun) ExecuteTransactions(_ context.Context, txs []*types.Transaction, gasRefunder common.Address) (*types.Transaction, error) {
+	for i, tx := range txs {
+		method, args := decodeCall(tx.Data())
+		if method == "" {
+			log.Info("dry run validator wallet would send transaction", "index", i, "count", len(txs), "to", tx.To(), "value", tx.Value(), "data", hexutil.Bytes(tx.Data()), "gasRefunder", gasRefunder)
+			continue
+		}
+		log.Info("dry run validator wallet would call", "index", i, "count", len(txs), "to", tx.To(), "value", tx.Value(), "method", method, "args", args, "gasRefunder", gasRefunder)
+	}
+	return nil, nil
+}
+
+func (*DryRun) TimeoutChallenges(_ context.Context, challenges []uint64, challengeManagerAddress common.Address) (*types.Transaction, error) {
+	log.Info("dry run validator wallet would time out challenges", "challengeManager", challengeManagerAddress, "challenges", challenges)
+	return nil, nil
+}
+
+// decodeCall returns the name and arguments of the method called with data,
+// or an empty name if it isn't a call of any known method.
+func decodeCall(data []byte) (string, []interface{}) {
+	if len(data) < 4 {
+		return "", nil
+	}
+	for _, contractABI := range dryRunABIs {
+		method, err := contractABI.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			continue
+		}
+		return method.Name, args
+	}
+	return "", nil
+}
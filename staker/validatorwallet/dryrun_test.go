@@ -0,0 +1,49 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package validatorwallet
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
+)
+
+func TestDryRunDecodesRollupCalls(t *testing.T) {
+	rollupABI, err := abi.JSON(strings.NewReader(rollup_legacy_gen.RollupUserLogicABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeHash := common.HexToHash("0x1234")
+	data, err := rollupABI.Pack("stakeOnExistingNode", uint64(7), nodeHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, args := decodeCall(data)
+	if method != "stakeOnExistingNode" {
+		t.Fatal("unexpected decoded method", method)
+	}
+	if len(args) != 2 || args[0] != uint64(7) || args[1] != [32]byte(nodeHash) {
+		t.Fatal("unexpected decoded arguments", args)
+	}
+	if method, _ := decodeCall([]byte{1, 2, 3, 4, 5}); method != "" {
+		t.Fatal("decoded unknown call as", method)
+	}
+
+	rollup := common.HexToAddress("0xabcd")
+	tx := types.NewTx(&types.LegacyTx{To: &rollup, Data: data})
+	wallet := NewDryRun(nil)
+	executed, err := wallet.ExecuteTransactions(context.Background(), []*types.Transaction{tx}, common.Address{})
+	if err != nil || executed != nil {
+		t.Fatal("expected dry run to execute nothing, got", executed, err)
+	}
+	if wallet.Address() != nil {
+		t.Fatal("expected dry run wallet to have no address")
+	}
+}
@@ -68,6 +68,14 @@ func (w *EOA) ExecuteTransactions(ctx context.Context, txes []*types.Transaction
 	return w.postTransaction(ctx, tx)
 }
 
+// EstimateTransactionsGas returns the gas of the first of txs, the only one the wallet executes.
+func (w *EOA) EstimateTransactionsGas(_ context.Context, txes []*types.Transaction) (uint64, error) {
+	if len(txes) == 0 {
+		return 0, nil
+	}
+	return txes[0].Gas() + w.getExtraGas(), nil
+}
+
 func (w *EOA) postTransaction(ctx context.Context, baseTx *types.Transaction) (*types.Transaction, error) {
 	gas := baseTx.Gas() + w.getExtraGas()
 	newTx, err := w.dataPoster.PostSimpleTransaction(ctx, *baseTx.To(), baseTx.Data(), gas, baseTx.Value())
@@ -114,3 +122,7 @@ func (b *EOA) StopAndWait() {
 func (b *EOA) DataPoster() *dataposter.DataPoster {
 	return b.dataPoster
 }
+
+func (b *EOA) BackupDataPoster() *dataposter.DataPoster {
+	return nil
+}
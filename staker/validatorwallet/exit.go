@@ -0,0 +1,283 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package validatorwallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbnode/dataposter"
+	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
+)
+
+// ErrExitPending is returned by WithdrawStakeAndExit while a transaction of the wallet's sender is pending,
+// as the next step of the exit can only be planned once the previous one is included.
+var ErrExitPending = errors.New("exit transaction still pending")
+
+// ErrExitDestinationUnsupported is returned by an EOA's WithdrawStakeAndExit given a destination other than
+// the EOA, as its funds are withdrawn to it and it can't forward them in the same transaction.
+var ErrExitDestinationUnsupported = errors.New("EOA wallet can only withdraw to itself")
+
+var rollupUserLogicABI abi.ABI
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(rollup_legacy_gen.RollupUserLogicABI))
+	if err != nil {
+		panic(err)
+	}
+	rollupUserLogicABI = parsed
+}
+
+type exitRollupReader interface {
+	IsStaked(opts *bind.CallOpts, staker common.Address) (bool, error)
+	LatestStakedNode(opts *bind.CallOpts, staker common.Address) (uint64, error)
+	CurrentChallenge(opts *bind.CallOpts, staker common.Address) (uint64, error)
+	LatestConfirmed(opts *bind.CallOpts) (uint64, error)
+	WithdrawableFunds(opts *bind.CallOpts, owner common.Address) (*big.Int, error)
+	IsZombie(opts *bind.CallOpts, staker common.Address) (bool, error)
+	ZombieCount(opts *bind.CallOpts) (*big.Int, error)
+	ZombieAddress(opts *bind.CallOpts, zombieNum *big.Int) (common.Address, error)
+	ZombieLatestStakedNode(opts *bind.CallOpts, zombieNum *big.Int) (uint64, error)
+	GetNode(opts *bind.CallOpts, nodeNum uint64) (rollup_legacy_gen.Node, error)
+}
+
+// planExit returns the rollup calls unwinding staker's position in the rollup, in order: returning its stake
+// once the node it's staked on is confirmed, removing it from the zombies after it lost a challenge, and
+// withdrawing its funds to it. It returns no calls if there's nothing to recover, or nothing which can be yet.
+func planExit(opts *bind.CallOpts, rollup exitRollupReader, rollupAddress common.Address, staker common.Address) ([]*types.Transaction, error) {
+	var calls []*types.Transaction
+	addCall := func(method string, args ...interface{}) error {
+		data, err := rollupUserLogicABI.Pack(method, args...)
+		if err != nil {
+			return fmt.Errorf("packing arguments for %v: %w", method, err)
+		}
+		calls = append(calls, types.NewTx(&types.LegacyTx{
+			To:    &rollupAddress,
+			Value: common.Big0,
+			Data:  data,
+		}))
+		return nil
+	}
+	latestConfirmed, err := rollup.LatestConfirmed(opts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest confirmed node: %w", err)
+	}
+	returningStake := false
+	staked, err := rollup.IsStaked(opts, staker)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if %v is staked: %w", staker, err)
+	}
+	if staked {
+		latestStaked, err := rollup.LatestStakedNode(opts, staker)
+		if err != nil {
+			return nil, fmt.Errorf("error getting latest staked node of %v: %w", staker, err)
+		}
+		challenge, err := rollup.CurrentChallenge(opts, staker)
+		if err != nil {
+			return nil, fmt.Errorf("error getting current challenge of %v: %w", staker, err)
+		}
+		if challenge == 0 && latestStaked <= latestConfirmed {
+			if err := addCall("returnOldDeposit", staker); err != nil {
+				return nil, err
+			}
+			returningStake = true
+		} else {
+			log.Info("stake can't be returned yet", "staker", staker, "latestStaked", latestStaked, "latestConfirmed", latestConfirmed, "challenge", challenge)
+		}
+	}
+	zombie, err := rollup.IsZombie(opts, staker)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if %v is a zombie: %w", staker, err)
+	}
+	if zombie {
+		zombieNum, maxNodes, err := findZombie(opts, rollup, staker, latestConfirmed)
+		if err != nil {
+			return nil, err
+		}
+		if zombieNum != nil {
+			if err := addCall("removeZombie", zombieNum, new(big.Int).SetUint64(maxNodes)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	withdrawable, err := rollup.WithdrawableFunds(opts, staker)
+	if err != nil {
+		return nil, fmt.Errorf("error getting withdrawable funds of %v: %w", staker, err)
+	}
+	if returningStake || withdrawable.Sign() > 0 {
+		if err := addCall("withdrawStakerFunds"); err != nil {
+			return nil, err
+		}
+	}
+	return calls, nil
+}
+
+// findZombie returns the number of staker among the rollup's zombies, or nil if it isn't one, and the number
+// of nodes its stake has to be removed from for removeZombie to remove it.
+func findZombie(opts *bind.CallOpts, rollup exitRollupReader, staker common.Address, latestConfirmed uint64) (*big.Int, uint64, error) {
+	zombieCount, err := rollup.ZombieCount(opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting zombie count: %w", err)
+	}
+	for i := int64(0); i < zombieCount.Int64(); i++ {
+		zombieNum := big.NewInt(i)
+		address, err := rollup.ZombieAddress(opts, zombieNum)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error getting zombie %v address: %w", i, err)
+		}
+		if address != staker {
+			continue
+		}
+		node, err := rollup.ZombieLatestStakedNode(opts, zombieNum)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error getting zombie %v latest staked node: %w", i, err)
+		}
+		// removeZombie walks back from the latest staked node until it's before the latest confirmed node
+		var maxNodes uint64
+		for node >= latestConfirmed {
+			maxNodes++
+			if node == 0 {
+				break
+			}
+			info, err := rollup.GetNode(opts, node)
+			if err != nil {
+				return nil, 0, fmt.Errorf("error getting node %v: %w", node, err)
+			}
+			node = info.PrevNum
+		}
+		return zombieNum, maxNodes, nil
+	}
+	return nil, 0, nil
+}
+
+// checkExitNotPending returns ErrExitPending if dataPoster has a transaction which isn't included yet.
+func checkExitNotPending(ctx context.Context, dataPoster *dataposter.DataPoster, client *ethclient.Client) error {
+	if dataPoster == nil {
+		return nil
+	}
+	nextNonce, _, err := dataPoster.GetNextNonceAndMeta(ctx)
+	if err != nil {
+		return err
+	}
+	latestNonce, err := client.NonceAt(ctx, dataPoster.Sender(), nil)
+	if err != nil {
+		return fmt.Errorf("getting nonce of %v: %w", dataPoster.Sender(), err)
+	}
+	if nextNonce > latestNonce {
+		return fmt.Errorf("%w: nonce %v of %v is ahead of on-chain nonce %v", ErrExitPending, nextNonce, dataPoster.Sender(), latestNonce)
+	}
+	return nil
+}
+
+// WithdrawStakeAndExit unwinds the wallet's position in the rollup, e.g. after it lost a challenge: it returns
+// the wallet's stake once the node it's staked on is confirmed, removes the wallet from the zombies, and withdraws
+// its funds from the rollup to the wallet, all in one transaction. Once that's included, the next call sends the
+// wallet's whole balance of the stake currency on to destination, unless it's zero or the wallet itself, which
+// requires the wallet's sender to be its owner. It's safe to call repeatedly, returning ErrExitPending while a
+// transaction of the wallet is pending and a nil transaction once there's nothing left to recover.
+func (v *Contract) WithdrawStakeAndExit(ctx context.Context, rollupAddress common.Address, destination common.Address) (*types.Transaction, error) {
+	if v.Address() == nil {
+		return nil, nil
+	}
+	client := v.l1Reader.Client()
+	for _, dataPoster := range []*dataposter.DataPoster{v.dataPoster, v.backupDataPoster} {
+		if err := checkExitNotPending(ctx, dataPoster, client); err != nil {
+			return nil, err
+		}
+	}
+	rollup, err := rollup_legacy_gen.NewRollupUserLogic(rollupAddress, client)
+	if err != nil {
+		return nil, err
+	}
+	calls, err := planExit(&bind.CallOpts{Context: ctx}, rollup, rollupAddress, *v.Address())
+	if err != nil {
+		return nil, err
+	}
+	if len(calls) > 0 {
+		return v.ExecuteTransactions(ctx, calls, common.Address{})
+	}
+	if destination == (common.Address{}) || destination == *v.Address() {
+		return nil, nil
+	}
+	return v.forwardExitFunds(ctx, destination)
+}
+
+// forwardExitFunds sends the wallet's balance of its stake currency to destination.
+func (v *Contract) forwardExitFunds(ctx context.Context, destination common.Address) (*types.Transaction, error) {
+	wallet := *v.Address()
+	if v.stakeToken != (common.Address{}) {
+		balance, err := v.callStakeToken(ctx, "balanceOf", wallet)
+		if err != nil || balance.Sign() == 0 {
+			return nil, err
+		}
+		data, err := erc20ABI.Pack("transfer", destination, balance)
+		if err != nil {
+			return nil, fmt.Errorf("packing arguments for stake token transfer: %w", err)
+		}
+		log.Info("forwarding exited stake tokens", "wallet", wallet, "destination", destination, "amount", balance)
+		return v.ExecuteTransactions(ctx, []*types.Transaction{types.NewTx(&types.LegacyTx{
+			To:    &v.stakeToken,
+			Value: common.Big0,
+			Data:  data,
+		})}, common.Address{})
+	}
+	balance, err := v.l1Reader.Client().BalanceAt(ctx, wallet, nil)
+	if err != nil || balance.Sign() == 0 {
+		return nil, err
+	}
+	data, err := validatorABI.Pack("withdrawEth", balance, destination)
+	if err != nil {
+		return nil, fmt.Errorf("packing arguments for withdrawEth: %w", err)
+	}
+	log.Info("forwarding exited funds", "wallet", wallet, "destination", destination, "amount", balance)
+	return v.postWalletTransaction(ctx, data, common.Big0)
+}
+
+// WithdrawStakeAndExit unwinds the account's position in the rollup as the contract wallet's WithdrawStakeAndExit
+// does, withdrawing its funds to the account itself, so destination must be zero or the account. As an EOA can't
+// batch transactions, each call takes a single step once the previous one is included, so it should be called
+// until it returns a nil transaction.
+func (w *EOA) WithdrawStakeAndExit(ctx context.Context, rollupAddress common.Address, destination common.Address) (*types.Transaction, error) {
+	if destination != (common.Address{}) && destination != w.auth.From {
+		return nil, fmt.Errorf("%w: destination %v", ErrExitDestinationUnsupported, destination)
+	}
+	if err := checkExitNotPending(ctx, w.dataPoster, w.client); err != nil {
+		return nil, err
+	}
+	rollup, err := rollup_legacy_gen.NewRollupUserLogic(rollupAddress, w.client)
+	if err != nil {
+		return nil, err
+	}
+	calls, err := planExit(&bind.CallOpts{Context: ctx}, rollup, rollupAddress, w.auth.From)
+	if err != nil || len(calls) == 0 {
+		return nil, err
+	}
+	call := calls[0]
+	gas, err := w.client.EstimateGas(ctx, ethereum.CallMsg{
+		From:  w.auth.From,
+		To:    call.To(),
+		Value: call.Value(),
+		Data:  call.Data(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("estimating gas: %w", err)
+	}
+	return w.postTransaction(ctx, types.NewTx(&types.LegacyTx{
+		To:    call.To(),
+		Value: call.Value(),
+		Gas:   gas,
+		Data:  call.Data(),
+	}))
+}
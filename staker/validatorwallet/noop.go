@@ -50,6 +50,10 @@ func (*NoOp) TestTransactions(ctx context.Context, txs []*types.Transaction) err
 	return nil
 }
 
+func (*NoOp) EstimateTransactionsGas(context.Context, []*types.Transaction) (uint64, error) {
+	return 0, errors.New("no op validator wallet cannot execute transactions")
+}
+
 func (*NoOp) CanBatchTxs() bool { return false }
 
 func (*NoOp) AuthIfEoa() *bind.TransactOpts { return nil }
@@ -59,3 +63,9 @@ func (w *NoOp) Start(ctx context.Context) {}
 func (b *NoOp) StopAndWait() {}
 
 func (b *NoOp) DataPoster() *dataposter.DataPoster { return nil }
+
+func (b *NoOp) BackupDataPoster() *dataposter.DataPoster { return nil }
+
+func (*NoOp) WithdrawStakeAndExit(context.Context, common.Address, common.Address) (*types.Transaction, error) {
+	return nil, nil
+}
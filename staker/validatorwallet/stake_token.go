@@ -0,0 +1,101 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package validatorwallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const erc20ABIJSON = `[
+	{"inputs":[{"internalType":"address","name":"owner","type":"address"},{"internalType":"address","name":"spender","type":"address"}],"name":"allowance","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"name":"approve","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+	{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"balanceOf","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"name":"transfer","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}
+]`
+
+var erc20ABI abi.ABI
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(erc20ABIJSON))
+	if err != nil {
+		panic(err)
+	}
+	erc20ABI = parsed
+}
+
+type ContractOption func(*Contract)
+
+// WithStakeToken makes the wallet stake with the given ERC-20 token, ensuring the rollup is allowed
+// to spend the wallet's token balance before it sends the rollup any transaction, e.g. to stake.
+// A zero token address leaves the wallet staking with the parent chain's native currency.
+func WithStakeToken(token common.Address, rollup common.Address) ContractOption {
+	return func(v *Contract) {
+		v.stakeToken = token
+		v.stakeTokenSpender = rollup
+	}
+}
+
+func (v *Contract) callStakeToken(ctx context.Context, method string, args ...interface{}) (*big.Int, error) {
+	var out []interface{}
+	token := bind.NewBoundContract(v.stakeToken, erc20ABI, v.l1Reader.Client(), nil, nil)
+	if err := token.Call(&bind.CallOpts{Context: ctx}, &out, method, args...); err != nil {
+		return nil, fmt.Errorf("error calling stake token %v %v: %w", v.stakeToken, method, err)
+	}
+	if len(out) != 1 {
+		return nil, fmt.Errorf("unexpected stake token %v output length %d", method, len(out))
+	}
+	value, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected stake token %v output type %T", method, out[0])
+	}
+	return value, nil
+}
+
+// withStakeTokenAllowance prepends an approval of the wallet's stake token balance to txes if they
+// call the rollup and the rollup's allowance doesn't cover that balance, so that stakes can be placed.
+func (v *Contract) withStakeTokenAllowance(ctx context.Context, txes []*types.Transaction) ([]*types.Transaction, error) {
+	if v.stakeToken == (common.Address{}) || v.Address() == nil {
+		return txes, nil
+	}
+	callsRollup := false
+	for _, tx := range txes {
+		if tx.To() != nil && *tx.To() == v.stakeTokenSpender {
+			callsRollup = true
+			break
+		}
+	}
+	if !callsRollup {
+		return txes, nil
+	}
+	wallet := *v.Address()
+	balance, err := v.callStakeToken(ctx, "balanceOf", wallet)
+	if err != nil {
+		return nil, err
+	}
+	allowance, err := v.callStakeToken(ctx, "allowance", wallet, v.stakeTokenSpender)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(balance) >= 0 {
+		return txes, nil
+	}
+	data, err := erc20ABI.Pack("approve", v.stakeTokenSpender, balance)
+	if err != nil {
+		return nil, fmt.Errorf("packing arguments for stake token approve: %w", err)
+	}
+	approval := types.NewTx(&types.LegacyTx{
+		To:    &v.stakeToken,
+		Value: common.Big0,
+		Data:  data,
+	})
+	return append([]*types.Transaction{approval}, txes...), nil
+}
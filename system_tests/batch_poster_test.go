@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/offchainlabs/nitro/arbnode"
 	"github.com/offchainlabs/nitro/arbnode/dataposter"
 	"github.com/offchainlabs/nitro/arbnode/dataposter/externalsignertest"
+	"github.com/offchainlabs/nitro/arbnode/dataposter/storage"
 	"github.com/offchainlabs/nitro/solgen/go/upgrade_executorgen"
 	"github.com/offchainlabs/nitro/util/redisutil"
 )
@@ -567,3 +569,67 @@ func TestBatchPosterWithDelayProofsAndBacklog(t *testing.T) {
 	builder.L1.SendWaitTestTransactions(t, batchPosterTxs)
 	CheckBatchCount(t, builder, initialBatchCount+numBatches)
 }
+
+func TestDataPostersSharingNonces(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L1Info.GenerateAccount("Poster")
+	builder.L1.TransferBalance(t, "Faucet", "Poster", big.NewInt(1e18), builder.L1Info)
+	auth := builder.L1Info.GetDefaultTransactOpts("Poster", ctx)
+	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	Require(t, err)
+
+	// a staker and a batch poster posting from the same account, each with its own queue
+	nodeConfig := arbnode.ConfigDefaultL1NonSequencerTest()
+	nodeConfig.Staker.DataPoster.ShareNonces = true
+	nodeConfig.Staker.DataPoster.UseDBStorage = true
+	var posters []*dataposter.DataPoster
+	for _, prefix := range []string{storage.StakerPrefix, storage.BatchPosterPrefix} {
+		dp, err := arbnode.StakerDataposter(
+			ctx,
+			rawdb.NewTable(builder.L2.ConsensusNode.ArbDB, prefix),
+			builder.L2.ConsensusNode.L1Reader,
+			&auth,
+			NewFetcherFromConfig(nodeConfig),
+			builder.L2.ConsensusNode.SyncMonitor,
+			parentChainID,
+		)
+		Require(t, err)
+		dp.Start(ctx)
+		defer dp.StopAndWait()
+		posters = append(posters, dp)
+	}
+	// let the data posters fetch their balance
+	time.Sleep(time.Second)
+
+	startNonce, err := builder.L1.Client.NonceAt(ctx, auth.From, nil)
+	Require(t, err)
+	faucetAddr := builder.L1Info.GetAddress("Faucet")
+	for i := uint64(0); i < 4; i++ {
+		// posting alternately leaves gaps in both queues, which they must accept
+		tx, err := posters[i%2].PostSimpleTransaction(ctx, faucetAddr, nil, builder.L1Info.TransferGas, common.Big1)
+		Require(t, err)
+		if tx.Nonce() != startNonce+i {
+			Fatal(t, "data poster", i%2, "posted nonce", tx.Nonce(), "instead of", startNonce+i)
+		}
+		_, err = builder.L1.EnsureTxSucceeded(tx)
+		Require(t, err)
+	}
+	nonce, err := builder.L1.Client.NonceAt(ctx, auth.From, nil)
+	Require(t, err)
+	if nonce != startNonce+4 {
+		Fatal(t, "unexpected nonce of the shared account", nonce, "want", startNonce+4)
+	}
+
+	// the in-memory queue can't hold gaps, so sharing nonces with it is rejected
+	nodeConfig.Staker.DataPoster.UseDBStorage = false
+	_, err = arbnode.StakerDataposter(ctx, rawdb.NewMemoryDatabase(), builder.L2.ConsensusNode.L1Reader, &auth, NewFetcherFromConfig(nodeConfig), builder.L2.ConsensusNode.SyncMonitor, parentChainID)
+	if err == nil {
+		Fatal(t, "expected sharing nonces with the in-memory queue to be rejected")
+	}
+}
@@ -4,7 +4,18 @@
 
 package arbtest
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/staker"
+	legacystaker "github.com/offchainlabs/nitro/staker/legacy"
+	"github.com/offchainlabs/nitro/validator"
+)
 
 func TestMockChallengeManagerAsserterIncorrect(t *testing.T) {
 	defaultWasmRootDir := ""
@@ -19,3 +30,56 @@ func TestMockChallengeManagerAsserterCorrect(t *testing.T) {
 		RunChallengeTest(t, true, true, i, defaultWasmRootDir)
 	}
 }
+
+func TestMockChallengeManagerModuleRootUnavailable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true).DontParalellise()
+	initialBalance := new(big.Int).Lsh(big.NewInt(1), 200)
+	l1Info := builder.L1Info
+	l1Info.GenerateGenesisAccount("deployer", initialBalance)
+	l1Info.GenerateGenesisAccount("asserter", initialBalance)
+	l1Info.GenerateGenesisAccount("challenger", initialBalance)
+	l1Info.GenerateGenesisAccount("sequencer", initialBalance)
+
+	conf := builder.nodeConfig
+	_, valStack := createMockValidationNode(t, ctx, &builder.valnodeConfig.Arbitrator)
+	configByValidationNode(conf, valStack)
+
+	builder.BuildL1(t)
+	l1Backend := builder.L1.Client
+	deployerTxOpts := l1Info.GetDefaultTransactOpts("deployer", ctx)
+	challengerTxOpts := l1Info.GetDefaultTransactOpts("challenger", ctx)
+
+	bridgeAddr, _, seqInboxAddr := setupSequencerInboxStub(ctx, t, l1Info, l1Backend, builder.chainConfig)
+	ospEntry := DeployOneStepProofEntry(t, ctx, &deployerTxOpts, l1Backend)
+	// a module root none of the mock spawner's machines has, e.g. one upgraded away from
+	unavailableRoot := common.HexToHash("0xdead")
+	_, challengeManagerAddr := CreateChallenge(
+		t,
+		ctx,
+		&deployerTxOpts,
+		l1Backend,
+		ospEntry,
+		seqInboxAddr,
+		bridgeAddr,
+		unavailableRoot,
+		validator.GoGlobalState{Batch: 1},
+		validator.GoGlobalState{Batch: 2},
+		1,
+		l1Info.GetAddress("asserter"),
+		l1Info.GetAddress("challenger"),
+	)
+	confirmLatestBlock(ctx, t, l1Info, l1Backend)
+
+	val, err := staker.NewStatelessBlockValidator(nil, nil, nil, nil, nil, nil, StaticFetcherFrom(t, &conf.BlockValidator), valStack, mockWasmModuleRoots[0])
+	Require(t, err)
+	Require(t, val.Start(ctx))
+	defer val.Stop()
+
+	_, err = legacystaker.NewChallengeManager(ctx, l1Backend, &challengerTxOpts, challengerTxOpts.From, challengeManagerAddr, 1, val, 0, 0, nil, nil)
+	if !errors.Is(err, legacystaker.ErrChallengeModuleRootUnavailable) {
+		Fatal(t, "expected", legacystaker.ErrChallengeModuleRootUnavailable, "creating challenge manager, got", err)
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"math/big"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 
@@ -23,7 +25,9 @@ import (
 	"github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/colors"
+	"github.com/offchainlabs/nitro/util/rpcclient"
 	"github.com/offchainlabs/nitro/util/testhelpers"
+	"github.com/offchainlabs/nitro/validator/valnode"
 )
 
 func blockIsEmpty(block *types.Block) bool {
@@ -105,6 +109,65 @@ func validateBlockRange(
 	}
 }
 
+func TestProgramValidateStylusTargets(t *testing.T) {
+	// validate with a jit server for the local target, and an arbitrator server for wavm
+	builder, auth, cleanup := setupProgramTest(t, true, func(builder *NodeBuilder) {
+		valConf := valnode.TestValidationConfig
+		valConf.UseJit = false
+		_, valStack := createTestValidationNode(t, builder.ctx, &valConf)
+		serverConfig := rpcclient.TestClientConfig
+		serverConfig.URL = valStack.WSEndpoint()
+		serverConfig.JWTSecret = ""
+		valConfig := &builder.nodeConfig.BlockValidator
+		valConfig.ValidationServerConfigs = append(slices.Clone(valConfig.ValidationServerConfigs), serverConfig)
+	})
+	ctx := builder.ctx
+	l2client := builder.L2.Client
+	defer cleanup()
+
+	programAddress := deployWasm(t, ctx, auth, l2client, rustFile("keccak"))
+	_, tx, mock, err := localgen.DeployProgramTest(&auth, l2client)
+	Require(t, err)
+	evmReceipt, err := EnsureTxSucceeded(ctx, l2client, tx)
+	Require(t, err)
+	args := append([]byte{0x01}, []byte("validate me on every target")...)
+	tx, err = mock.CallKeccak(&auth, programAddress, args)
+	Require(t, err)
+	stylusReceipt, err := EnsureTxSucceeded(ctx, l2client, tx)
+	Require(t, err)
+	waitForSequencer(t, builder, stylusReceipt.BlockNumber.Uint64())
+
+	validator := builder.L2.ConsensusNode.StatelessBlockValidator
+	wasmModuleRoot := currentRootModule(t)
+	result, err := validator.ValidateStylusTargets(ctx, arbutil.MessageIndex(stylusReceipt.BlockNumber.Uint64()), wasmModuleRoot)
+	Require(t, err)
+	if !result.HasStylus {
+		Fatal(t, "expected block", stylusReceipt.BlockNumber, "to execute a stylus program")
+	}
+	validatedTargets := make(map[rawdb.WasmTarget]bool)
+	for _, target := range result.Targets {
+		if !target.Valid {
+			Fatal(t, "target", target.Target, "of server", target.Server, "failed validation", target.GlobalState, target.Err)
+		}
+		validatedTargets[target.Target] = true
+	}
+	for _, target := range []rawdb.WasmTarget{rawdb.LocalTarget(), rawdb.TargetWavm} {
+		if !validatedTargets[target] {
+			Fatal(t, "expected target", target, "to be validated, got", result.Targets)
+		}
+	}
+	if len(result.Targets) != len(validatedTargets) || !result.Valid() {
+		Fatal(t, "expected each target to be validated once, got", result.Targets)
+	}
+
+	// blocks without stylus execution have no target to validate
+	result, err = validator.ValidateStylusTargets(ctx, arbutil.MessageIndex(evmReceipt.BlockNumber.Uint64()), wasmModuleRoot)
+	Require(t, err)
+	if result.HasStylus || len(result.Targets) != 0 {
+		Fatal(t, "expected no stylus targets validated for block", evmReceipt.BlockNumber, "got", result.Targets)
+	}
+}
+
 func TestProgramEvmData(t *testing.T) {
 	testEvmData(t, true)
 }
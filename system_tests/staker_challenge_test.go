@@ -10,9 +10,17 @@ package arbtest
 import "testing"
 
 func TestChallengeStakersFaultyHonestActive(t *testing.T) {
-	stakerTestImpl(t, true, false)
+	stakerTestImpl(t, stakerTestOptions{faultyStaker: true})
 }
 
 func TestChallengeStakersFaultyHonestInactive(t *testing.T) {
-	stakerTestImpl(t, true, true)
+	stakerTestImpl(t, stakerTestOptions{faultyStaker: true, honestStakerInactive: true})
+}
+
+func TestChallengeStakersFaultyExits(t *testing.T) {
+	stakerTestImpl(t, stakerTestOptions{faultyStaker: true, faultyStakerExits: true})
+}
+
+func TestChallengeStakersFaultyChallengeOnly(t *testing.T) {
+	stakerTestImpl(t, stakerTestOptions{faultyStaker: true, challengeOnlyStaker: true})
 }
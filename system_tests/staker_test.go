@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -21,15 +22,16 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 
+	"github.com/offchainlabs/bold/solgen/go/mocksgen"
 	"github.com/offchainlabs/nitro/arbnode"
 	"github.com/offchainlabs/nitro/arbnode/dataposter"
 	"github.com/offchainlabs/nitro/arbnode/dataposter/externalsignertest"
 	"github.com/offchainlabs/nitro/arbnode/dataposter/storage"
 	"github.com/offchainlabs/nitro/arbos/l2pricing"
+	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/solgen/go/mocks_legacy_gen"
 	"github.com/offchainlabs/nitro/solgen/go/rollup_legacy_gen"
 	"github.com/offchainlabs/nitro/solgen/go/upgrade_executorgen"
@@ -61,7 +63,21 @@ func makeBackgroundTxs(ctx context.Context, builder *NodeBuilder) error {
 	return nil
 }
 
-func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool) {
+// stakerTestOptions selects the scenario stakerTestImpl runs
+type stakerTestOptions struct {
+	// staker B creates incorrect nodes, which staker A challenges
+	faultyStaker bool
+	// staker A is defensive, only staking once it sees an incorrect node
+	honestStakerInactive bool
+	// staker A makes nodes aggressively
+	honestStakerAggressive bool
+	// staker B unwinds its zombie stake through its wallet instead of having it rescued
+	faultyStakerExits bool
+	// a challenge-only staker D joins in challenging staker B
+	challengeOnlyStaker bool
+}
+
+func stakerTestImpl(t *testing.T, opts stakerTestOptions) {
 	logHandler := testhelpers.InitTestLog(t, log.LvlTrace)
 
 	ctx, cancelCtx := context.WithCancel(context.Background())
@@ -97,7 +113,7 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	l2nodeA := builder.L2.ConsensusNode
 	execNodeA := builder.L2.ExecNode
 
-	if faultyStaker {
+	if opts.faultyStaker {
 		builder.L2Info.GenerateGenesisAccount("FaultyAddr", common.Big1)
 	}
 
@@ -114,7 +130,7 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 
 	nodeAGenesis := execNodeA.Backend.APIBackend().CurrentHeader().Hash()
 	nodeBGenesis := execNodeB.Backend.APIBackend().CurrentHeader().Hash()
-	if faultyStaker {
+	if opts.faultyStaker {
 		if nodeAGenesis == nodeBGenesis {
 			Fatal(t, "node A L2 genesis hash", nodeAGenesis, "== node B L2 genesis hash", nodeBGenesis)
 		}
@@ -138,6 +154,10 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	builder.L1.TransferBalance(t, "Faucet", "ValidatorB", balance, builder.L1Info)
 	l1authB := builder.L1Info.GetDefaultTransactOpts("ValidatorB", ctx)
 
+	builder.L1Info.GenerateAccount("ValidatorD")
+	builder.L1.TransferBalance(t, "Faucet", "ValidatorD", balance, builder.L1Info)
+	l1authD := builder.L1Info.GetDefaultTransactOpts("ValidatorD", ctx)
+
 	rollup, err := rollup_legacy_gen.NewRollupAdminLogic(l2nodeA.DeployInfo.Rollup, builder.L1.Client)
 	Require(t, err)
 
@@ -146,7 +166,12 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	rollupABI, err := abi.JSON(strings.NewReader(rollup_legacy_gen.RollupAdminLogicABI))
 	Require(t, err, "unable to parse rollup ABI")
 
-	setMinAssertPeriodCalldata, err := rollupABI.Pack("setMinimumAssertionPeriod", big.NewInt(1))
+	minAssertPeriod := big.NewInt(1)
+	if opts.honestStakerAggressive {
+		// let the aggressive staker create several nodes on top of each other in one act
+		minAssertPeriod = common.Big0
+	}
+	setMinAssertPeriodCalldata, err := rollupABI.Pack("setMinimumAssertionPeriod", minAssertPeriod)
 	Require(t, err, "unable to generate setMinimumAssertionPeriod calldata")
 	tx, err := upgradeExecutor.ExecuteCall(&deployAuth, l2nodeA.DeployInfo.Rollup, setMinAssertPeriodCalldata)
 	Require(t, err, "unable to set minimum assertion period")
@@ -172,24 +197,39 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	if err != nil {
 		t.Fatalf("Error creating validator dataposter: %v", err)
 	}
-	valWalletA, err := validatorwallet.NewContract(dpA, nil, l2nodeA.DeployInfo.ValidatorWalletCreator, l2nodeA.L1Reader, &l1authA, 0, func(common.Address) {}, func() uint64 { return valConfigA.ExtraGas })
+	// the wallet is created with more padding than its routine transactions
+	valConfigA.WalletCreationExtraGas = 4 * valConfigA.ExtraGas
+	creationGasA := validatorwallet.WithCreationGas(func() uint64 { return valConfigA.WalletCreationGas() })
+	valWalletA, err := validatorwallet.NewContract(dpA, nil, l2nodeA.DeployInfo.ValidatorWalletCreator, l2nodeA.L1Reader, &l1authA, 0, func(common.Address) {}, func() uint64 { return valConfigA.ExtraGas }, creationGasA)
 	Require(t, err)
-	if honestStakerInactive {
+	if opts.honestStakerInactive {
 		valConfigA.Strategy = "Defensive"
+	} else if opts.honestStakerAggressive {
+		valConfigA.Strategy = "MakeNodesAggressive"
 	} else {
 		valConfigA.Strategy = "MakeNodes"
 	}
+	valConfigA.VerifyConfirmedSendRoots = true
 
-	valWalletAddrAPtr, err := validatorwallet.GetValidatorWalletContract(ctx, l2nodeA.DeployInfo.ValidatorWalletCreator, 0, l2nodeA.L1Reader, true, valWalletA.DataPoster(), valWalletA.GetExtraGas())
+	_, err = validatorwallet.CheckValidatorWalletContract(ctx, l2nodeA.DeployInfo.ValidatorWalletCreator, 0, l2nodeA.L1Reader, l1authA.From)
+	if !errors.Is(err, validatorwallet.ErrWalletNotDeployed) {
+		Fatal(t, "expected validator wallet not to be deployed yet, got", err)
+	}
+	valWalletAddrAPtr, err := validatorwallet.GetValidatorWalletContract(ctx, l2nodeA.DeployInfo.ValidatorWalletCreator, 0, l2nodeA.L1Reader, true, valWalletA.DataPoster(), valWalletA.GetExtraGas(), valWalletA.GetCreationGas())
 	Require(t, err)
 	valWalletAddrA := *valWalletAddrAPtr
-	valWalletAddrCheck, err := validatorwallet.GetValidatorWalletContract(ctx, l2nodeA.DeployInfo.ValidatorWalletCreator, 0, l2nodeA.L1Reader, true, valWalletA.DataPoster(), valWalletA.GetExtraGas())
+	checkedWalletAddrA, err := validatorwallet.CheckValidatorWalletContract(ctx, l2nodeA.DeployInfo.ValidatorWalletCreator, 0, l2nodeA.L1Reader, l1authA.From)
+	Require(t, err)
+	if checkedWalletAddrA != valWalletAddrA {
+		Fatal(t, "checked validator wallet", checkedWalletAddrA, "doesn't match created wallet", valWalletAddrA)
+	}
+	valWalletAddrCheck, err := validatorwallet.GetValidatorWalletContract(ctx, l2nodeA.DeployInfo.ValidatorWalletCreator, 0, l2nodeA.L1Reader, true, valWalletA.DataPoster(), valWalletA.GetExtraGas(), valWalletA.GetCreationGas())
 	Require(t, err)
 	if valWalletAddrA == *valWalletAddrCheck {
 		Require(t, err, "didn't cache validator wallet address", valWalletAddrA.String(), "vs", valWalletAddrCheck.String())
 	}
 
-	setValidatorCalldata, err := rollupABI.Pack("setValidator", []common.Address{valWalletAddrA, l1authB.From, srv.Address}, []bool{true, true, true})
+	setValidatorCalldata, err := rollupABI.Pack("setValidator", []common.Address{valWalletAddrA, l1authB.From, srv.Address, l1authD.From}, []bool{true, true, true, true})
 	Require(t, err, "unable to generate setValidator calldata")
 	tx, err = upgradeExecutor.ExecuteCall(&deployAuth, l2nodeA.DeployInfo.Rollup, setValidatorCalldata)
 	Require(t, err, "unable to set validators")
@@ -198,6 +238,8 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 
 	_, valStack := createTestValidationNode(t, ctx, &valnode.TestValidationConfig)
 	blockValidatorConfig := staker.TestBlockValidatorConfig
+	// the stakers revalidate the same nodes while challenging, which the cache returns without rerunning
+	blockValidatorConfig.ValidationCacheSize = server_common.DefaultValidationCacheCapacity
 
 	locator, err := server_common.NewMachineLocator(valnode.TestValidationConfig.Wasm.RootPath)
 	Require(t, err)
@@ -259,6 +301,8 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	Require(t, err)
 	valConfigB := legacystaker.TestL1ValidatorConfig
 	valConfigB.Strategy = "MakeNodes"
+	// a faulty staker exiting unwinds its zombie stake through its wallet instead
+	valConfigB.RescueZombieStake = opts.faultyStaker && !opts.faultyStakerExits
 	statelessB, err := staker.NewStatelessBlockValidator(
 		l2nodeB.InboxReader,
 		l2nodeB.InboxTracker,
@@ -323,6 +367,45 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	err = stakerC.Initialize(ctx)
 	Require(t, err)
 
+	// an honest challenge-only staker, only ever staking on staker A's nodes to challenge staker B
+	var stakerD *legacystaker.Staker
+	if opts.challengeOnlyStaker {
+		dpD, err := arbnode.StakerDataposter(
+			ctx,
+			rawdb.NewTable(l2nodeA.ArbDB, storage.StakerPrefix+"D"),
+			l2nodeA.L1Reader,
+			&l1authD, NewFetcherFromConfig(arbnode.ConfigDefaultL1NonSequencerTest()),
+			nil,
+			parentChainID,
+		)
+		Require(t, err)
+		valWalletD, err := validatorwallet.NewEOA(dpD, l2nodeA.L1Reader.Client(), func() uint64 { return 0 })
+		Require(t, err)
+		valConfigD := legacystaker.TestL1ValidatorConfig
+		valConfigD.Strategy = "ChallengeOnly"
+		stakerD, err = legacystaker.NewStaker(
+			l2nodeA.L1Reader,
+			valWalletD,
+			bind.CallOpts{},
+			func() *legacystaker.L1ValidatorConfig { return &valConfigD },
+			nil,
+			statelessA,
+			nil,
+			nil,
+			l2nodeA.DeployInfo.ValidatorUtils,
+			l2nodeA.DeployInfo.Rollup,
+			l2nodeA.InboxTracker,
+			l2nodeA.TxStreamer,
+			l2nodeA.InboxReader,
+			nil,
+		)
+		Require(t, err)
+		Require(t, stakerD.Initialize(ctx))
+		Require(t, valWalletD.Initialize(ctx))
+	}
+	challengeOnlyStates := make(map[legacystaker.StakerState]bool)
+	challengeOnlyNodesCreated := 0
+
 	builder.L2Info.GenerateAccount("BackgroundUser")
 	tx = builder.L2Info.PrepareTx("Faucet", "BackgroundUser", builder.L2Info.TransferGas, balance, nil)
 	err = builder.L2.Client.SendTransaction(ctx, tx)
@@ -346,38 +429,101 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	})()
 
 	stakerATxs := 0
+	// the most nodes created by one act of staker A
+	stakerAMostNodesCreated := uint64(0)
 	stakerAWasStaked := false
 	stakerBTxs := 0
 	stakerBWasStaked := false
 	sawStakerZombie := false
+	sawStakerZombieRescued := false
+	sawWatchtowerSuppressedChallenge := false
 	challengeMangerTimedOut := false
+	stakerStates := make(map[legacystaker.StakerState]bool)
+	// an act of staker A which advanced its stake, to be simulated afterwards
+	type stakerAAdvance struct {
+		parentChainBlock    uint64
+		stakedBefore        uint64
+		stakedAfter         uint64
+		latestCreatedBefore uint64
+		created             bool
+	}
+	var advanceA *stakerAAdvance
+	watchtowerStates := make(map[legacystaker.StakerState]bool)
 	for i := 0; i < 100; i++ {
 		var stakerName string
+		var pendingAdvanceA *stakerAAdvance
+		// transactions posted before tx in the same act
+		var earlierTxs []*types.Transaction
+		if i%2 == 0 && stakerD != nil {
+			// staker D acts before staker A, and twice as it can only stake or challenge in an act,
+			// so that it challenges staker B before staker A does
+			for j := 0; j < 2; j++ {
+				latestCreated, err := rollup.LatestNodeCreated(&bind.CallOpts{})
+				Require(t, err)
+				txD, err := stakerD.Act(ctx)
+				if legacystaker.IsTransientActError(err) {
+					continue
+				}
+				Require(t, err, "challenge-only staker failed to act")
+				challengeOnlyStates[stakerD.State()] = true
+				if txD == nil {
+					continue
+				}
+				_, err = builder.L1.EnsureTxSucceeded(txD)
+				Require(t, err)
+				latestCreatedAfter, err := rollup.LatestNodeCreated(&bind.CallOpts{})
+				Require(t, err)
+				if latestCreatedAfter > latestCreated {
+					challengeOnlyNodesCreated++
+				}
+			}
+		}
+		var latestCreatedBeforeA uint64
 		if i%2 == 0 {
 			stakerName = "A"
+			latestCreatedBeforeA, err = rollup.LatestNodeCreated(&bind.CallOpts{})
+			Require(t, err)
+			if advanceA == nil {
+				parentChainBlock, err := builder.L1.Client.BlockNumber(ctx)
+				Require(t, err)
+				stakedBefore, _, err := validatorUtils.LatestStaked(&bind.CallOpts{}, l2nodeA.DeployInfo.Rollup, valWalletAddrA)
+				Require(t, err)
+				latestCreated, err := rollup.LatestNodeCreated(&bind.CallOpts{})
+				Require(t, err)
+				pendingAdvanceA = &stakerAAdvance{
+					parentChainBlock:    parentChainBlock,
+					stakedBefore:        stakedBefore,
+					latestCreatedBefore: latestCreated,
+				}
+			}
 			fmt.Printf("staker A acting:\n")
 			tx, err = stakerA.Act(ctx)
 			if tx != nil {
 				stakerATxs++
 			}
+			stakerStates[stakerA.State()] = true
 		} else {
 			stakerName = "B"
 			fmt.Printf("staker B acting:\n")
-			tx, err = stakerB.Act(ctx)
-			if tx != nil {
+			var txs []*types.Transaction
+			txs, err = stakerB.ActMany(ctx)
+			tx = nil
+			if len(txs) > 0 {
+				earlierTxs, tx = txs[:len(txs)-1], txs[len(txs)-1]
 				stakerBTxs++
 			}
+			stakerStates[stakerB.State()] = true
 		}
 
-		if err != nil && strings.Contains(err.Error(), "waiting") {
+		if legacystaker.IsTransientActError(err) {
 			colors.PrintRed("retrying ", err.Error(), i)
 			time.Sleep(20 * time.Millisecond)
 			i--
 			continue
 		}
-		if err != nil && faultyStaker && i%2 == 1 {
+		if err != nil && opts.faultyStaker && i%2 == 1 {
 			// Check if this is an expected error from the faulty staker.
-			if strings.Contains(err.Error(), "agreed with entire challenge") || strings.Contains(err.Error(), "after msg 0 expected global state") {
+			if errors.Is(err, legacystaker.ErrAgreedWithEntireChallenge) || strings.Contains(err.Error(), "after msg 0 expected global state") {
 				// Expected error upon realizing you're losing the challenge. Get ready for a timeout.
 				if !challengeMangerTimedOut {
 					// Upgrade the ChallengeManager contract to an implementation which says challenges are always timed out
@@ -389,9 +535,7 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 
 					managerAddr, err := stakerA.Rollup().ChallengeManager(&bind.CallOpts{Context: ctx})
 					Require(t, err)
-					// 0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103
-					proxyAdminSlot := common.BigToHash(arbmath.BigSub(crypto.Keccak256Hash([]byte("eip1967.proxy.admin")).Big(), common.Big1))
-					proxyAdminBytes, err := builder.L1.Client.StorageAt(ctx, managerAddr, proxyAdminSlot, nil)
+					proxyAdminBytes, err := builder.L1.Client.StorageAt(ctx, managerAddr, arbutil.ProxyAdminSlot, nil)
 					Require(t, err)
 					proxyAdminAddr := common.BytesToAddress(proxyAdminBytes)
 					if proxyAdminAddr == (common.Address{}) {
@@ -421,20 +565,69 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 			t.Log("got expected faulty staker error", err)
 			err = nil
 			tx = nil
+			earlierTxs = nil
 		}
 		Require(t, err, "Staker", stakerName, "failed to act")
+		for _, earlierTx := range earlierTxs {
+			_, err = builder.L1.EnsureTxSucceeded(earlierTx)
+			Require(t, err, "EnsureTxSucceeded failed for staker", stakerName, "earlier tx")
+		}
 		if tx != nil {
 			_, err = builder.L1.EnsureTxSucceeded(tx)
 			Require(t, err, "EnsureTxSucceeded failed for staker", stakerName, "tx")
+			if stakerName == "A" && !opts.faultyStaker && !opts.honestStakerInactive {
+				// staker A batches its txs, so each act moves its stake all the way to the latest node it agrees with
+				latestCreated, err := rollup.LatestNodeCreated(&bind.CallOpts{})
+				Require(t, err)
+				stakedA, _, err := validatorUtils.LatestStaked(&bind.CallOpts{}, l2nodeA.DeployInfo.Rollup, valWalletAddrA)
+				Require(t, err)
+				if stakedA != latestCreated {
+					Fatal(t, "staker A left its stake on node", stakedA, "after acting, while the latest node is", latestCreated)
+				}
+				stakerAMostNodesCreated = max(stakerAMostNodesCreated, latestCreated-latestCreatedBeforeA)
+			}
+			if pendingAdvanceA != nil {
+				latestCreated, err := rollup.LatestNodeCreated(&bind.CallOpts{})
+				Require(t, err)
+				pendingAdvanceA.created = latestCreated > pendingAdvanceA.latestCreatedBefore
+				pendingAdvanceA.stakedAfter, _, err = validatorUtils.LatestStaked(&bind.CallOpts{}, l2nodeA.DeployInfo.Rollup, valWalletAddrA)
+				Require(t, err)
+				if pendingAdvanceA.stakedAfter > pendingAdvanceA.stakedBefore {
+					advanceA = pendingAdvanceA
+				}
+			}
 		}
-		if faultyStaker {
+		if opts.faultyStaker {
 			conflictInfo, err := validatorUtils.FindStakerConflict(&bind.CallOpts{}, l2nodeA.DeployInfo.Rollup, l1authA.From, srv.Address, big.NewInt(1024))
 			Require(t, err)
 			if legacystaker.ConflictType(conflictInfo.Ty) == legacystaker.CONFLICT_TYPE_FOUND {
 				cancelBackgroundTxs()
 			}
 		}
-		if faultyStaker && !sawStakerZombie {
+		if opts.faultyStakerExits && sawStakerZombie && !sawStakerZombieRescued {
+			// staker B's EOA wallet takes a step per call, until there's nothing left to recover
+			for steps := 0; ; steps++ {
+				exitTx, err := stakerB.WithdrawStakeAndExit(ctx)
+				Require(t, err)
+				if exitTx == nil {
+					if steps == 0 {
+						Fatal(t, "staker B had nothing to recover as a zombie")
+					}
+					break
+				}
+				if steps == 3 {
+					Fatal(t, "staker B didn't finish exiting after", steps, "steps")
+				}
+				_, err = builder.L1.EnsureTxSucceeded(exitTx)
+				Require(t, err)
+			}
+		}
+		if opts.faultyStaker && sawStakerZombie && !sawStakerZombieRescued {
+			isZombie, err := rollup.IsZombie(&bind.CallOpts{}, srv.Address)
+			Require(t, err)
+			sawStakerZombieRescued = !isZombie
+		}
+		if opts.faultyStaker && !sawStakerZombie {
 			sawStakerZombie, err = rollup.IsZombie(&bind.CallOpts{}, srv.Address)
 			Require(t, err)
 		}
@@ -445,12 +638,21 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 		}
 		fmt.Printf("watchtower staker acting:\n")
 		watchTx, err := stakerC.Act(ctx)
-		if err != nil && !strings.Contains(err.Error(), "catch up") {
+		if err != nil && !legacystaker.IsTransientActError(err) {
 			Require(t, err, "watchtower staker failed to act")
 		}
 		if watchTx != nil {
 			Fatal(t, "watchtower staker made a transaction")
 		}
+		watchtowerStates[stakerC.State()] = true
+		if suppressed := stakerC.SuppressedAction(); suppressed != nil {
+			if !opts.faultyStaker {
+				Fatal(t, "watchtower staker would have acted in cooperative scenario", suppressed.Action, "node", suppressed.Node)
+			}
+			if suppressed.Action == legacystaker.WatchtowerActionChallenge && suppressed.Node > 0 {
+				sawWatchtowerSuppressedChallenge = true
+			}
+		}
 		if !stakerAWasStaked {
 			stakerAWasStaked, err = rollup.IsStaked(&bind.CallOpts{}, valWalletAddrA)
 			Require(t, err)
@@ -467,34 +669,114 @@ func stakerTestImpl(t *testing.T, faultyStaker bool, honestStakerInactive bool)
 	if stakerATxs == 0 || stakerBTxs == 0 {
 		Fatal(t, "staker didn't make txs: staker A made", stakerATxs, "staker B made", stakerBTxs)
 	}
+	if opts.honestStakerAggressive && stakerAMostNodesCreated < 2 {
+		Fatal(t, "aggressive staker A never created several nodes in one act, at most", stakerAMostNodesCreated)
+	}
 
 	latestConfirmedNode, err := rollup.LatestConfirmed(&bind.CallOpts{})
 	Require(t, err)
 
-	if latestConfirmedNode <= 1 && !honestStakerInactive {
+	if latestConfirmedNode <= 1 && !opts.honestStakerInactive {
 		latestCreatedNode, err := rollup.LatestNodeCreated(&bind.CallOpts{})
 		Require(t, err)
 		Fatal(t, "latest confirmed node didn't advance:", latestConfirmedNode, latestCreatedNode)
 	}
 
-	if faultyStaker && !sawStakerZombie {
+	if latestConfirmedNode > 1 && !opts.faultyStaker {
+		verification, err := stakerA.VerifyConfirmedNodeSendRoot(ctx, latestConfirmedNode)
+		Require(t, err)
+		if !verification.Match {
+			Fatal(t, "confirmed node send root mismatch: on-chain", verification.OnChainSendRoot, "computed", verification.ComputedSendRoot)
+		}
+		// staker A also verified the send roots of the nodes confirmed while it acted
+		if verified := stakerA.Status().LatestVerifiedConfirmedNode; verified == 0 {
+			Fatal(t, "staker A didn't verify the send root of any confirmed node")
+		}
+		if divergence := stakerA.ConfirmedDivergence(); divergence != nil {
+			Fatal(t, "staker A found confirmed node", divergence.Node, "diverging from local execution")
+		}
+	}
+
+	if opts.faultyStaker && !sawStakerZombie {
 		Fatal(t, "staker B didn't become a zombie despite being faulty")
 	}
 
+	if !opts.faultyStaker && !opts.honestStakerInactive {
+		if advanceA == nil {
+			Fatal(t, "staker A never advanced its stake")
+		}
+		simulated, err := stakerA.SimulateActAt(ctx, advanceA.parentChainBlock)
+		Require(t, err)
+		if simulated.LatestStakedNode != advanceA.stakedBefore {
+			Fatal(t, "simulated act at block", advanceA.parentChainBlock, "saw latest staked node", simulated.LatestStakedNode, "expected", advanceA.stakedBefore)
+		}
+		if advanceA.created {
+			if simulated.Action != legacystaker.SimulatedNodeActionCreate {
+				Fatal(t, "staker A created a node at block", advanceA.parentChainBlock, "but the simulated act decided to", simulated.Action)
+			}
+		} else if simulated.Action != legacystaker.SimulatedNodeActionStakeOnExisting || simulated.Node <= advanceA.stakedBefore || simulated.Node > advanceA.stakedAfter {
+			Fatal(t, "staker A staked from node", advanceA.stakedBefore, "to", advanceA.stakedAfter, "at block", advanceA.parentChainBlock, "but the simulated act decided to", simulated.Action, "node", simulated.Node)
+		}
+	}
+
+	if opts.faultyStaker {
+		if !sawStakerZombieRescued {
+			Fatal(t, "staker B didn't rescue its stake after becoming a zombie")
+		}
+		withdrawable, err := rollup.WithdrawableFunds(&bind.CallOpts{}, srv.Address)
+		Require(t, err)
+		if withdrawable.Sign() != 0 {
+			Fatal(t, "staker B left", withdrawable, "withdrawable funds in the rollup after rescuing its stake")
+		}
+	}
+
+	if opts.faultyStaker && !sawWatchtowerSuppressedChallenge {
+		Fatal(t, "watchtower staker didn't report it would have challenged the incorrect node")
+	}
+
 	if !stakerAWasStaked {
 		Fatal(t, "staker A was never staked")
 	}
+	if !opts.faultyStaker && !opts.honestStakerInactive {
+		if !stakerStates[legacystaker.StakerStateCreating] {
+			Fatal(t, "stakers never reported creating a node, states:", stakerStates)
+		}
+		if !stakerStates[legacystaker.StakerStateStaking] && !stakerStates[legacystaker.StakerStateConfirming] {
+			Fatal(t, "stakers never reported staking on or confirming a node, states:", stakerStates)
+		}
+		if stakerStates[legacystaker.StakerStateChallenging] || stakerStates[legacystaker.StakerStateZombie] {
+			Fatal(t, "cooperative stakers reported a challenge, states:", stakerStates)
+		}
+	}
+	for state := range watchtowerStates {
+		if state != legacystaker.StakerStateIdle && state != legacystaker.StakerStateSyncing && state != legacystaker.StakerStatePaused {
+			Fatal(t, "watchtower staker reported state", state)
+		}
+	}
 	if !stakerBWasStaked {
 		Fatal(t, "staker B was never staked")
 	}
 
+	if opts.challengeOnlyStaker {
+		if !challengeOnlyStates[legacystaker.StakerStateChallenging] {
+			Fatal(t, "challenge-only staker never challenged the faulty staker, states:", challengeOnlyStates)
+		}
+		if challengeOnlyNodesCreated > 0 || challengeOnlyStates[legacystaker.StakerStateCreating] {
+			Fatal(t, "challenge-only staker created", challengeOnlyNodesCreated, "nodes, states:", challengeOnlyStates)
+		}
+	}
+
 	if logHandler.WasLogged("data poster expected next transaction to have nonce \\d+ but was requested to post transaction with nonce \\d+") {
 		Fatal(t, "Staker's DataPoster inferred nonce incorrectly")
 	}
 }
 
 func TestStakersCooperative(t *testing.T) {
-	stakerTestImpl(t, false, false)
+	stakerTestImpl(t, stakerTestOptions{})
+}
+
+func TestStakersCooperativeAggressive(t *testing.T) {
+	stakerTestImpl(t, stakerTestOptions{honestStakerAggressive: true})
 }
 
 func TestGetValidatorWalletContractWithDataposterOnlyUsedToCreateValidatorWalletContract(t *testing.T) {
@@ -526,12 +808,1042 @@ func TestGetValidatorWalletContractWithDataposterOnlyUsedToCreateValidatorWallet
 	}
 	getExtraGas := func() uint64 { return builder.nodeConfig.Staker.ExtraGas }
 
-	valWalletAddrAPtr, err := validatorwallet.GetValidatorWalletContract(ctx, builder.L2.ConsensusNode.DeployInfo.ValidatorWalletCreator, 0, builder.L2.ConsensusNode.L1Reader, true, dataPoster, getExtraGas)
+	valWalletAddrAPtr, err := validatorwallet.GetValidatorWalletContract(ctx, builder.L2.ConsensusNode.DeployInfo.ValidatorWalletCreator, 0, builder.L2.ConsensusNode.L1Reader, true, dataPoster, getExtraGas, nil)
 	Require(t, err)
 	valWalletAddrA := *valWalletAddrAPtr
-	valWalletAddrCheck, err := validatorwallet.GetValidatorWalletContract(ctx, builder.L2.ConsensusNode.DeployInfo.ValidatorWalletCreator, 0, builder.L2.ConsensusNode.L1Reader, true, dataPoster, getExtraGas)
+	valWalletAddrCheck, err := validatorwallet.GetValidatorWalletContract(ctx, builder.L2.ConsensusNode.DeployInfo.ValidatorWalletCreator, 0, builder.L2.ConsensusNode.L1Reader, true, dataPoster, getExtraGas, nil)
 	Require(t, err)
 	if valWalletAddrA == *valWalletAddrCheck {
 		Require(t, err, "didn't cache validator wallet address", valWalletAddrA.String(), "vs", valWalletAddrCheck.String())
 	}
 }
+
+func TestValidatorWalletHeartbeat(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	balance := big.NewInt(params.Ether)
+	balance.Mul(balance, big.NewInt(100))
+	builder.L1Info.GenerateAccount("ValidatorA")
+	builder.L1.TransferBalance(t, "Faucet", "ValidatorA", balance, builder.L1Info)
+	l1auth := builder.L1Info.GetDefaultTransactOpts("ValidatorA", ctx)
+
+	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	Require(t, err)
+	dataPoster, err := arbnode.StakerDataposter(
+		ctx,
+		rawdb.NewTable(builder.L2.ConsensusNode.ArbDB, storage.StakerPrefix),
+		builder.L2.ConsensusNode.L1Reader,
+		&l1auth, NewFetcherFromConfig(arbnode.ConfigDefaultL1NonSequencerTest()),
+		nil,
+		parentChainID,
+	)
+	Require(t, err)
+	dataPoster.Start(ctx)
+	defer dataPoster.StopAndWait()
+
+	idleInterval := 500 * time.Millisecond
+	heartbeat := legacystaker.NewWalletHeartbeat(dataPoster, builder.L1.Client)
+	tx, err := heartbeat.MaybeSend(ctx, idleInterval, nil)
+	Require(t, err)
+	if tx != nil {
+		Fatal(t, "posted heartbeat before the wallet was idle")
+	}
+
+	time.Sleep(idleInterval)
+	tx, err = heartbeat.MaybeSend(ctx, idleInterval, big.NewInt(1))
+	Require(t, err)
+	if tx != nil {
+		Fatal(t, "posted heartbeat costing more than the max cost")
+	}
+	tx, err = heartbeat.MaybeSend(ctx, idleInterval, nil)
+	Require(t, err)
+	if tx == nil {
+		Fatal(t, "didn't post heartbeat after the idle interval")
+	}
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+	if tx.To() == nil || *tx.To() != l1auth.From || tx.Value().Sign() != 0 {
+		Fatal(t, "heartbeat isn't a zero value self-transfer", tx.To(), tx.Value())
+	}
+
+	tx, err = heartbeat.MaybeSend(ctx, idleInterval, nil)
+	Require(t, err)
+	if tx != nil {
+		Fatal(t, "posted heartbeat right after the previous heartbeat")
+	}
+}
+
+func TestStakerVerifiesChallengeManager(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	l2node := builder.L2.ConsensusNode
+	rollup, err := rollup_legacy_gen.NewRollupUserLogic(l2node.DeployInfo.Rollup, builder.L1.Client)
+	Require(t, err)
+	challengeManager, err := rollup.ChallengeManager(&bind.CallOpts{Context: ctx})
+	Require(t, err)
+
+	newWatchtower := func(challengeManagerAddress string) *legacystaker.Staker {
+		valConfig := legacystaker.TestL1ValidatorConfig
+		valConfig.Strategy = "Watchtower"
+		valConfig.ChallengeManagerAddress = challengeManagerAddress
+		watchtower, err := legacystaker.NewStaker(
+			l2node.L1Reader,
+			validatorwallet.NewNoOp(builder.L1.Client),
+			bind.CallOpts{},
+			func() *legacystaker.L1ValidatorConfig { return &valConfig },
+			nil,
+			nil,
+			nil,
+			nil,
+			l2node.DeployInfo.ValidatorUtils,
+			l2node.DeployInfo.Rollup,
+			l2node.InboxTracker,
+			l2node.TxStreamer,
+			l2node.InboxReader,
+			nil,
+		)
+		Require(t, err)
+		return watchtower
+	}
+
+	err = newWatchtower(common.HexToAddress("0x1234").Hex()).Initialize(ctx)
+	if !errors.Is(err, legacystaker.ErrChallengeManagerMismatch) {
+		Fatal(t, "expected initialization to fail with a challenge manager mismatch, got", err)
+	}
+	Require(t, newWatchtower(challengeManager.Hex()).Initialize(ctx))
+	Require(t, newWatchtower("").Initialize(ctx))
+}
+
+func TestContractWalletApprovesStakeToken(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+	l2node := builder.L2.ConsensusNode
+
+	balance := big.NewInt(params.Ether)
+	balance.Mul(balance, big.NewInt(100))
+	builder.L1Info.GenerateAccount("ValidatorA")
+	builder.L1.TransferBalance(t, "Faucet", "ValidatorA", balance, builder.L1Info)
+	l1auth := builder.L1Info.GetDefaultTransactOpts("ValidatorA", ctx)
+	// stands in for the rollup, pulling the stake from the wallet
+	builder.L1Info.GenerateAccount("StakeSpender")
+	builder.L1.TransferBalance(t, "Faucet", "StakeSpender", balance, builder.L1Info)
+	spender := builder.L1Info.GetAddress("StakeSpender")
+
+	faucetAuth := builder.L1Info.GetDefaultTransactOpts("Faucet", ctx)
+	stakeTokenAddr, tx, stakeToken, err := mocksgen.DeployTestWETH9(&faucetAuth, builder.L1.Client, "Weth", "WETH")
+	Require(t, err)
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	Require(t, err)
+	dataPoster, err := arbnode.StakerDataposter(
+		ctx,
+		rawdb.NewTable(l2node.ArbDB, storage.StakerPrefix),
+		l2node.L1Reader,
+		&l1auth, NewFetcherFromConfig(arbnode.ConfigDefaultL1NonSequencerTest()),
+		nil,
+		parentChainID,
+	)
+	Require(t, err)
+	dataPoster.Start(ctx)
+	defer dataPoster.StopAndWait()
+	getExtraGas := func() uint64 { return 0 }
+	walletAddrPtr, err := validatorwallet.GetValidatorWalletContract(ctx, l2node.DeployInfo.ValidatorWalletCreator, 0, l2node.L1Reader, true, dataPoster, getExtraGas, nil)
+	Require(t, err)
+	walletAddr := *walletAddrPtr
+	wallet, err := validatorwallet.NewContract(dataPoster, &walletAddr, l2node.DeployInfo.ValidatorWalletCreator, l2node.L1Reader, &l1auth, 0, func(common.Address) {}, getExtraGas, validatorwallet.WithStakeToken(stakeTokenAddr, spender))
+	Require(t, err)
+	Require(t, wallet.Initialize(ctx))
+
+	stake := big.NewInt(params.Ether)
+	faucetAuth.Value = stake
+	tx, err = stakeToken.Deposit(&faucetAuth)
+	Require(t, err)
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+	faucetAuth.Value = nil
+	tx, err = stakeToken.Transfer(&faucetAuth, walletAddr, stake)
+	Require(t, err)
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	// a transaction to the rollup makes the wallet approve its stake token balance first
+	stakeTx := types.NewTx(&types.LegacyTx{To: &spender, Value: common.Big0})
+	Require(t, wallet.TestTransactions(ctx, []*types.Transaction{stakeTx}))
+	tx, err = wallet.ExecuteTransactions(ctx, []*types.Transaction{stakeTx}, common.Address{})
+	Require(t, err)
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+	allowance, err := stakeToken.Allowance(&bind.CallOpts{Context: ctx}, walletAddr, spender)
+	Require(t, err)
+	if allowance.Cmp(stake) != 0 {
+		Fatal(t, "expected the wallet to approve its stake token balance", stake, "got", allowance)
+	}
+
+	// the rollup can then take the stake
+	spenderAuth := builder.L1Info.GetDefaultTransactOpts("StakeSpender", ctx)
+	tx, err = stakeToken.TransferFrom(&spenderAuth, walletAddr, spender, stake)
+	Require(t, err)
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+	staked, err := stakeToken.BalanceOf(&bind.CallOpts{Context: ctx}, spender)
+	Require(t, err)
+	if staked.Cmp(stake) != 0 {
+		Fatal(t, "expected the stake to be taken from the wallet, got", staked)
+	}
+}
+
+func TestContractWalletBackupEOAPaysGas(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	cleanup := builder.Build(t)
+	defer cleanup()
+	l2node := builder.L2.ConsensusNode
+
+	balance := big.NewInt(params.Ether)
+	balance.Mul(balance, big.NewInt(100))
+	builder.L1Info.GenerateAccount("WalletOwner")
+	builder.L1.TransferBalance(t, "Faucet", "WalletOwner", balance, builder.L1Info)
+	builder.L1Info.GenerateAccount("BackupEOA")
+	builder.L1.TransferBalance(t, "Faucet", "BackupEOA", balance, builder.L1Info)
+	// the wallet's sender is never funded
+	builder.L1Info.GenerateAccount("DrainedExecutor")
+	builder.L1Info.GenerateAccount("Destination")
+	destination := builder.L1Info.GetAddress("Destination")
+
+	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	Require(t, err)
+	newDataPoster := func(account string) (*dataposter.DataPoster, bind.TransactOpts) {
+		auth := builder.L1Info.GetDefaultTransactOpts(account, ctx)
+		dataPoster, err := arbnode.StakerDataposter(
+			ctx,
+			rawdb.NewTable(l2node.ArbDB, storage.StakerPrefix+account),
+			l2node.L1Reader,
+			&auth, NewFetcherFromConfig(arbnode.ConfigDefaultL1NonSequencerTest()),
+			nil,
+			parentChainID,
+		)
+		Require(t, err)
+		dataPoster.Start(ctx)
+		return dataPoster, auth
+	}
+	ownerDataPoster, ownerAuth := newDataPoster("WalletOwner")
+	defer ownerDataPoster.StopAndWait()
+	senderDataPoster, senderAuth := newDataPoster("DrainedExecutor")
+	defer senderDataPoster.StopAndWait()
+	backupDataPoster, _ := newDataPoster("BackupEOA")
+	defer backupDataPoster.StopAndWait()
+
+	getExtraGas := func() uint64 { return 0 }
+	walletAddrPtr, err := validatorwallet.GetValidatorWalletContract(ctx, l2node.DeployInfo.ValidatorWalletCreator, 0, l2node.L1Reader, true, ownerDataPoster, getExtraGas, nil)
+	Require(t, err)
+	walletAddr := *walletAddrPtr
+	walletCon, err := rollup_legacy_gen.NewValidatorWallet(walletAddr, builder.L1.Client)
+	Require(t, err)
+	backup := builder.L1Info.GetAddress("BackupEOA")
+	tx, err := walletCon.SetExecutor(&ownerAuth, []common.Address{senderAuth.From, backup}, []bool{true, true})
+	Require(t, err)
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+	tx, err = walletCon.SetAllowedExecutorDestinations(&ownerAuth, []common.Address{destination}, []bool{true})
+	Require(t, err)
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	wallet, err := validatorwallet.NewContract(senderDataPoster, &walletAddr, l2node.DeployInfo.ValidatorWalletCreator, l2node.L1Reader, &senderAuth, 0, func(common.Address) {}, getExtraGas, validatorwallet.WithBackupEOA(backupDataPoster))
+	Require(t, err)
+	Require(t, wallet.Initialize(ctx))
+
+	// the backup EOA pays for the gas of a transaction the drained sender can't afford
+	call := types.NewTx(&types.LegacyTx{To: &destination, Value: common.Big0})
+	tx, err = wallet.ExecuteTransactions(ctx, []*types.Transaction{call}, common.Address{})
+	Require(t, err)
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+	sender, err := types.Sender(types.LatestSignerForChainID(parentChainID), tx)
+	Require(t, err)
+	if sender != backup {
+		Fatal(t, "expected the backup EOA", backup, "to send the wallet transaction, got", sender)
+	}
+
+	// but never funds a transaction's value
+	transfer := types.NewTx(&types.LegacyTx{To: &destination, Value: big.NewInt(params.GWei)})
+	_, err = wallet.ExecuteTransactions(ctx, []*types.Transaction{transfer}, common.Address{})
+	if !errors.Is(err, validatorwallet.ErrBackupEOACannotSendValue) {
+		Fatal(t, "expected the backup EOA to refuse funding the transaction value, got", err)
+	}
+
+	// exiting through the owner sends the wallet's funds on to the destination, once
+	ownerWallet, err := validatorwallet.NewContract(ownerDataPoster, &walletAddr, l2node.DeployInfo.ValidatorWalletCreator, l2node.L1Reader, &ownerAuth, 0, func(common.Address) {}, getExtraGas)
+	Require(t, err)
+	Require(t, ownerWallet.Initialize(ctx))
+	builder.L1.TransferBalanceTo(t, "Faucet", walletAddr, big.NewInt(params.Ether), builder.L1Info)
+	exitTx, err := ownerWallet.WithdrawStakeAndExit(ctx, l2node.DeployInfo.Rollup, destination)
+	Require(t, err)
+	if exitTx == nil {
+		Fatal(t, "expected the wallet's funds to be sent to the destination")
+	}
+	_, err = builder.L1.EnsureTxSucceeded(exitTx)
+	Require(t, err)
+	received, err := builder.L1.Client.BalanceAt(ctx, destination, nil)
+	Require(t, err)
+	if received.Cmp(big.NewInt(params.Ether)) != 0 {
+		Fatal(t, "expected the destination to receive the wallet's funds, got", received)
+	}
+	exitTx, err = ownerWallet.WithdrawStakeAndExit(ctx, l2node.DeployInfo.Rollup, destination)
+	Require(t, err)
+	if exitTx != nil {
+		Fatal(t, "exit repeated after the funds were sent")
+	}
+}
+
+func TestStakerRecoveryModePacesStakeAdvances(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	// nodes aren't confirmed during the test, so they pile up into a backlog
+	env, cleanup := newLegacyStakerTestEnv(t, ctx, NewNodeBuilder(ctx).DefaultConfig(t, true).WithProdConfirmPeriodBlocks().DontParalellise())
+	defer cleanup()
+	cancelBackgroundTxs := env.startBackgroundTxs()
+	defer cancelBackgroundTxs()
+
+	// staker A creates a backlog of unconfirmed nodes
+	valConfigA := legacystaker.TestL1ValidatorConfig
+	valConfigA.Strategy = "MakeNodes"
+	stakerA, _ := env.newStaker("ValidatorA", &valConfigA)
+	backlog := uint64(6)
+	for i := 0; ; i++ {
+		latestCreated, err := env.rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+		if latestCreated >= backlog {
+			backlog = latestCreated
+			break
+		}
+		if i == 100 {
+			Fatal(t, "staker A only created", latestCreated, "nodes")
+		}
+		env.act(stakerA)
+	}
+	cancelBackgroundTxs()
+
+	// staker B comes online to the backlog, and catches up on it a few stake advances at a time
+	valConfigB := legacystaker.TestL1ValidatorConfig
+	valConfigB.Strategy = "StakeLatest"
+	valConfigB.RecoveryBacklogNodes = 3
+	valConfigB.RecoveryStakeAdvances = 2
+	stakerB, walletAddrB := env.newStaker("ValidatorB", &valConfigB)
+	latestConfirmed, err := env.rollup.LatestConfirmed(&bind.CallOpts{})
+	Require(t, err)
+	if backlog-latestConfirmed < valConfigB.RecoveryBacklogNodes {
+		Fatal(t, "backlog of", backlog-latestConfirmed, "nodes too small for recovery mode")
+	}
+	staked := latestConfirmed
+	acts := uint64(0)
+	for staked < backlog {
+		acts++
+		if acts > backlog {
+			Fatal(t, "staker B didn't catch up after", acts-1, "acts, staked on node", staked, "of", backlog)
+		}
+		env.act(stakerB)
+		stakedAfter, err := env.rollup.LatestStakedNode(&bind.CallOpts{}, walletAddrB)
+		Require(t, err)
+		if stakedAfter <= staked || stakedAfter-staked > valConfigB.RecoveryStakeAdvances {
+			Fatal(t, "staker B advanced its stake from node", staked, "to", stakedAfter, "in one act in recovery mode, expected at most", valConfigB.RecoveryStakeAdvances, "advances")
+		}
+		staked = stakedAfter
+	}
+	if minActs := arbmath.DivCeil(backlog-latestConfirmed, valConfigB.RecoveryStakeAdvances); acts != minActs {
+		Fatal(t, "staker B took", acts, "acts to catch up on", backlog-latestConfirmed, "nodes, expected", minActs)
+	}
+}
+
+func TestStakerAdvancesStakeToLatestAgreedNode(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	env, cleanup := newLegacyStakerTestEnv(t, ctx, NewNodeBuilder(ctx).DefaultConfig(t, true).WithProdConfirmPeriodBlocks().DontParalellise())
+	defer cleanup()
+	cancelBackgroundTxs := env.startBackgroundTxs()
+	defer cancelBackgroundTxs()
+
+	valConfigA := legacystaker.TestL1ValidatorConfig
+	valConfigA.Strategy = "MakeNodes"
+	stakerA, _ := env.newStaker("ValidatorA", &valConfigA)
+	valConfigB := legacystaker.TestL1ValidatorConfig
+	valConfigB.Strategy = "MakeNodes"
+	stakerB, walletAddrB := env.newStaker("ValidatorB", &valConfigB)
+	senderB := env.builder.L1Info.GetAddress("ValidatorB")
+
+	// staker A moves the chain ahead by a few nodes at a time, which staker B follows
+	for round := 0; round < 3; round++ {
+		latestCreated, err := env.rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+		target := latestCreated + 3
+		for i := 0; latestCreated < target; i++ {
+			if i == 100 {
+				Fatal(t, "staker A only created", latestCreated, "nodes")
+			}
+			env.act(stakerA)
+			latestCreated, err = env.rollup.LatestNodeCreated(&bind.CallOpts{})
+			Require(t, err)
+		}
+
+		nonceBefore, err := env.builder.L1.Client.NonceAt(ctx, senderB, nil)
+		Require(t, err)
+		env.act(stakerB)
+		nonceAfter, err := env.builder.L1.Client.NonceAt(ctx, senderB, nil)
+		Require(t, err)
+		// staker B may have created a newer node on top of those of staker A
+		latestCreated, err = env.rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+		stakedB, err := env.rollup.LatestStakedNode(&bind.CallOpts{}, walletAddrB)
+		Require(t, err)
+		if stakedB != latestCreated {
+			Fatal(t, "staker B left its stake on node", stakedB, "after acting, while the latest node is", latestCreated)
+		}
+		if nonceAfter-nonceBefore != 1 {
+			Fatal(t, "staker B sent", nonceAfter-nonceBefore, "transactions advancing its stake, expected 1")
+		}
+	}
+}
+
+func TestStakerMinPostIntervalStillConfirms(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	var transferGas = util.NormalizeL2GasForL1GasInitial(800_000, params.GWei) // include room for aggregator L1 costs
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true).DontParalellise()
+	builder.L2Info = NewBlockChainTestInfo(
+		t,
+		types.NewArbitrumSigner(types.NewLondonSigner(builder.chainConfig.ChainID)), big.NewInt(l2pricing.InitialBaseFeeWei*2),
+		transferGas,
+	)
+	// For now validation only works with HashScheme set
+	builder.RequireScheme(t, rawdb.HashScheme)
+	builder.nodeConfig.BatchPoster.MaxDelay = -1000 * time.Hour
+	cleanup := builder.Build(t)
+	defer cleanup()
+	l2node := builder.L2.ConsensusNode
+
+	builder.BridgeBalance(t, "Faucet", big.NewInt(1).Mul(big.NewInt(params.Ether), big.NewInt(10000)))
+	deployAuth := builder.L1Info.GetDefaultTransactOpts("RollupOwner", ctx)
+	rollup, err := rollup_legacy_gen.NewRollupAdminLogic(l2node.DeployInfo.Rollup, builder.L1.Client)
+	Require(t, err)
+	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(l2node.DeployInfo.UpgradeExecutor, builder.L1.Client)
+	Require(t, err, "unable to bind upgrade executor")
+	rollupABI, err := abi.JSON(strings.NewReader(rollup_legacy_gen.RollupAdminLogicABI))
+	Require(t, err, "unable to parse rollup ABI")
+	setMinAssertPeriodCalldata, err := rollupABI.Pack("setMinimumAssertionPeriod", big.NewInt(1))
+	Require(t, err, "unable to generate setMinimumAssertionPeriod calldata")
+	tx, err := upgradeExecutor.ExecuteCall(&deployAuth, l2node.DeployInfo.Rollup, setMinAssertPeriodCalldata)
+	Require(t, err, "unable to set minimum assertion period")
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	_, valStack := createTestValidationNode(t, ctx, &valnode.TestValidationConfig)
+	blockValidatorConfig := staker.TestBlockValidatorConfig
+	locator, err := server_common.NewMachineLocator(valnode.TestValidationConfig.Wasm.RootPath)
+	Require(t, err)
+	stateless, err := staker.NewStatelessBlockValidator(
+		l2node.InboxReader,
+		l2node.InboxTracker,
+		l2node.TxStreamer,
+		builder.L2.ExecNode,
+		l2node.ArbDB,
+		nil,
+		StaticFetcherFrom(t, &blockValidatorConfig),
+		valStack,
+		locator.LatestWasmModuleRoot(),
+	)
+	Require(t, err)
+	Require(t, stateless.Start(ctx))
+
+	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	Require(t, err)
+	balance := big.NewInt(params.Ether)
+	balance.Mul(balance, big.NewInt(100))
+	builder.L1Info.GenerateAccount("Validator")
+	builder.L1.TransferBalance(t, "Faucet", "Validator", balance, builder.L1Info)
+	auth := builder.L1Info.GetDefaultTransactOpts("Validator", ctx)
+	setValidatorCalldata, err := rollupABI.Pack("setValidator", []common.Address{auth.From}, []bool{true})
+	Require(t, err, "unable to generate setValidator calldata")
+	tx, err = upgradeExecutor.ExecuteCall(&deployAuth, l2node.DeployInfo.Rollup, setValidatorCalldata)
+	Require(t, err, "unable to set validator")
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+	dataPoster, err := arbnode.StakerDataposter(
+		ctx,
+		rawdb.NewTable(l2node.ArbDB, storage.StakerPrefix),
+		l2node.L1Reader,
+		&auth, NewFetcherFromConfig(arbnode.ConfigDefaultL1NonSequencerTest()),
+		nil,
+		parentChainID,
+	)
+	Require(t, err)
+	wallet, err := validatorwallet.NewEOA(dataPoster, l2node.L1Reader.Client(), func() uint64 { return 0 })
+	Require(t, err)
+
+	builder.L2Info.GenerateAccount("BackgroundUser")
+	tx = builder.L2Info.PrepareTx("Faucet", "BackgroundUser", builder.L2Info.TransferGas, balance, nil)
+	Require(t, builder.L2.Client.SendTransaction(ctx, tx))
+	_, err = builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	backgroundTxsCtx, cancelBackgroundTxs := context.WithCancel(ctx)
+	backgroundTxsShutdownChan := make(chan struct{})
+	defer (func() {
+		cancelBackgroundTxs()
+		<-backgroundTxsShutdownChan
+	})()
+	go (func() {
+		defer close(backgroundTxsShutdownChan)
+		err := makeBackgroundTxs(backgroundTxsCtx, builder)
+		if !errors.Is(err, context.Canceled) {
+			log.Warn("error making background txs", "err", err)
+		}
+	})()
+
+	// the staker's loop polls rarely, so its acts are driven by pokes, which set the time a node was
+	// posted once its transaction succeeds
+	valConfig := legacystaker.TestL1ValidatorConfig
+	valConfig.Strategy = "MakeNodes"
+	valConfig.StakerInterval = time.Hour
+	valConfig.MinPostInterval = time.Hour
+	stakerInstance, err := legacystaker.NewStaker(
+		l2node.L1Reader,
+		wallet,
+		bind.CallOpts{},
+		func() *legacystaker.L1ValidatorConfig { return &valConfig },
+		nil,
+		stateless,
+		nil,
+		nil,
+		l2node.DeployInfo.ValidatorUtils,
+		l2node.DeployInfo.Rollup,
+		l2node.InboxTracker,
+		l2node.TxStreamer,
+		l2node.InboxReader,
+		nil,
+	)
+	Require(t, err)
+	Require(t, stakerInstance.Initialize(ctx))
+	Require(t, wallet.Initialize(ctx))
+	stakerInstance.Start(ctx)
+	defer stakerInstance.StopAndWait()
+	poke := func() {
+		for attempt := 0; ; attempt++ {
+			_, err := stakerInstance.Poke(ctx)
+			if legacystaker.IsTransientActError(err) && attempt < 100 {
+				time.Sleep(20 * time.Millisecond)
+				continue
+			}
+			Require(t, err)
+			break
+		}
+		for j := 0; j < 5; j++ {
+			builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big0, builder.L1Info)
+		}
+	}
+
+	var created uint64
+	for i := 0; created == 0; i++ {
+		if i == 100 {
+			Fatal(t, "staker didn't create a node")
+		}
+		poke()
+		created, err = rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+	}
+
+	// within the min post interval, the staker keeps confirming without creating more nodes
+	for i := 0; ; i++ {
+		confirmed, err := rollup.LatestConfirmed(&bind.CallOpts{})
+		Require(t, err)
+		if confirmed >= created {
+			break
+		}
+		if i == 100 {
+			Fatal(t, "staker didn't confirm node", created, "latest confirmed is", confirmed)
+		}
+		poke()
+		latestCreated, err := rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+		if latestCreated != created {
+			Fatal(t, "staker created node", latestCreated, "within the min post interval of node", created)
+		}
+	}
+}
+
+func TestStakerWaitsForConfirmationMaturity(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	env, cleanup := newLegacyStakerTestEnv(t, ctx, NewNodeBuilder(ctx).DefaultConfig(t, true).DontParalellise())
+	defer cleanup()
+	cancelBackgroundTxs := env.startBackgroundTxs()
+	defer cancelBackgroundTxs()
+
+	// while nodes keep being created, the staker only confirms nodes with enough successors
+	valConfig := legacystaker.TestL1ValidatorConfig
+	valConfig.Strategy = "MakeNodes"
+	valConfig.ConfirmationMaturityNodes = 2
+	stakerInstance, _ := env.newStaker("Validator", &valConfig)
+	var confirmed uint64
+	for i := 0; confirmed == 0; i++ {
+		if i == 100 {
+			Fatal(t, "staker didn't confirm a node")
+		}
+		env.act(stakerInstance)
+		var err error
+		confirmed, err = env.rollup.LatestConfirmed(&bind.CallOpts{})
+		Require(t, err)
+		latestCreated, err := env.rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+		if confirmed > 0 && latestCreated < confirmed+valConfig.ConfirmationMaturityNodes {
+			Fatal(t, "staker confirmed node", confirmed, "with only", latestCreated-confirmed, "successors")
+		}
+	}
+	cancelBackgroundTxs()
+
+	// once no more nodes are created, the remaining nodes mature by L1 blocks instead
+	valConfig.ConfirmationMaturityBlocks = 40
+	latestCreated, err := env.rollup.LatestNodeCreated(&bind.CallOpts{})
+	Require(t, err)
+	for i := 0; confirmed < latestCreated; i++ {
+		if i == 100 {
+			Fatal(t, "staker didn't confirm node", latestCreated, "latest confirmed is", confirmed)
+		}
+		env.act(stakerInstance)
+		confirmedAfter, err := env.rollup.LatestConfirmed(&bind.CallOpts{})
+		Require(t, err)
+		createdAfter, err := env.rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+		currentBlock, err := env.builder.L1.Client.BlockNumber(ctx)
+		Require(t, err)
+		for node := confirmed + 1; node <= confirmedAfter; node++ {
+			nodeInfo, err := env.rollup.GetNode(&bind.CallOpts{}, node)
+			Require(t, err)
+			if createdAfter < node+valConfig.ConfirmationMaturityNodes && currentBlock < nodeInfo.CreatedAtBlock+valConfig.ConfirmationMaturityBlocks {
+				Fatal(t, "staker confirmed node", node, "created at block", nodeInfo.CreatedAtBlock, "before block", nodeInfo.CreatedAtBlock+valConfig.ConfirmationMaturityBlocks)
+			}
+		}
+		confirmed = confirmedAfter
+	}
+}
+
+func TestConfirmerWaitsForConfirmationSafetyDelay(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	env, cleanup := newLegacyStakerTestEnv(t, ctx, NewNodeBuilder(ctx).DefaultConfig(t, true).DontParalellise())
+	defer cleanup()
+	cancelBackgroundTxs := env.startBackgroundTxs()
+	defer cancelBackgroundTxs()
+
+	// the node maker never confirms nodes itself, leaving them to the confirmer
+	makerConfig := legacystaker.TestL1ValidatorConfig
+	makerConfig.Strategy = "MakeNodes"
+	makerConfig.ConfirmationSafetyDelayBlocks = 1 << 30
+	maker, _ := env.newStaker("Maker", &makerConfig)
+	for i := 0; ; i++ {
+		latestCreated, err := env.rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+		if latestCreated > 0 {
+			break
+		}
+		if i == 100 {
+			Fatal(t, "staker didn't create a node")
+		}
+		env.act(maker)
+	}
+	cancelBackgroundTxs()
+
+	confirmerConfig := legacystaker.TestL1ValidatorConfig
+	confirmerConfig.Strategy = "Watchtower"
+	confirmerConfig.Confirmer = true
+	confirmerConfig.ConfirmationSafetyDelayBlocks = 100
+	confirmer, _ := env.newStaker("Confirmer", &confirmerConfig)
+	nodeNum, err := env.rollup.FirstUnresolvedNode(&bind.CallOpts{})
+	Require(t, err)
+	node, err := env.rollup.GetNode(&bind.CallOpts{}, nodeNum)
+	Require(t, err)
+	confirmableBlock := node.DeadlineBlock + confirmerConfig.ConfirmationSafetyDelayBlocks
+
+	// actConfirmerAt runs an act of the confirmer once the parent chain reaches block,
+	// returning the latest confirmed node after it
+	actConfirmerAt := func(block uint64) uint64 {
+		for {
+			currentBlock, err := env.builder.L1.Client.BlockNumber(ctx)
+			Require(t, err)
+			if currentBlock > block {
+				Fatal(t, "parent chain is at block", currentBlock, "past block", block)
+			}
+			if currentBlock == block {
+				break
+			}
+			env.builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big0, env.builder.L1Info)
+		}
+		for attempt := 0; ; attempt++ {
+			tx, err := confirmer.Act(ctx)
+			if legacystaker.IsTransientActError(err) && attempt < 100 {
+				time.Sleep(20 * time.Millisecond)
+				continue
+			}
+			Require(t, err)
+			if tx != nil {
+				_, err = env.builder.L1.EnsureTxSucceeded(tx)
+				Require(t, err)
+			}
+			break
+		}
+		confirmed, err := env.rollup.LatestConfirmed(&bind.CallOpts{})
+		Require(t, err)
+		return confirmed
+	}
+
+	// the confirmer validates the node, but holds off confirming it until the safety delay has passed
+	currentBlock, err := env.builder.L1.Client.BlockNumber(ctx)
+	Require(t, err)
+	if confirmed := actConfirmerAt(currentBlock); confirmed >= nodeNum {
+		Fatal(t, "confirmer confirmed node", nodeNum, "at block", currentBlock, "before", confirmableBlock)
+	}
+	if confirmed := actConfirmerAt(confirmableBlock - 1); confirmed >= nodeNum {
+		Fatal(t, "confirmer confirmed node", nodeNum, "a block before", confirmableBlock)
+	}
+	if confirmed := actConfirmerAt(confirmableBlock); confirmed < nodeNum {
+		Fatal(t, "confirmer didn't confirm node", nodeNum, "at block", confirmableBlock, "latest confirmed is", confirmed)
+	}
+}
+
+func TestStakerConfirmationsBoundedPerAct(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	env, cleanup := newLegacyStakerTestEnv(t, ctx, NewNodeBuilder(ctx).DefaultConfig(t, true).DontParalellise())
+	defer cleanup()
+	cancelBackgroundTxs := env.startBackgroundTxs()
+	defer cancelBackgroundTxs()
+
+	// the staker holds back its confirmations until it has created a backlog of nodes
+	valConfig := legacystaker.TestL1ValidatorConfig
+	valConfig.Strategy = "MakeNodes"
+	valConfig.ConfirmationSafetyDelayBlocks = 1 << 30
+	stakerInstance, _ := env.newStaker("Validator", &valConfig)
+	backlog := uint64(7)
+	for i := 0; ; i++ {
+		latestCreated, err := env.rollup.LatestNodeCreated(&bind.CallOpts{})
+		Require(t, err)
+		if latestCreated >= backlog {
+			backlog = latestCreated
+			break
+		}
+		if i == 100 {
+			Fatal(t, "staker only created", latestCreated, "nodes")
+		}
+		env.act(stakerInstance)
+	}
+	cancelBackgroundTxs()
+	lastNode, err := env.rollup.GetNode(&bind.CallOpts{}, backlog)
+	Require(t, err)
+	for {
+		currentBlock, err := env.builder.L1.Client.BlockNumber(ctx)
+		Require(t, err)
+		if currentBlock >= lastNode.DeadlineBlock {
+			break
+		}
+		env.builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big0, env.builder.L1Info)
+	}
+
+	// the whole backlog can be confirmed now, but only a few nodes per act
+	valConfig.ConfirmationSafetyDelayBlocks = 0
+	valConfig.MaxConfirmationsPerAct = 3
+	confirmed, err := env.rollup.LatestConfirmed(&bind.CallOpts{})
+	Require(t, err)
+	unconfirmed := backlog - confirmed
+	acts := uint64(0)
+	for confirmed < backlog {
+		acts++
+		if acts > unconfirmed {
+			Fatal(t, "staker didn't confirm the backlog after", acts-1, "acts, confirmed node", confirmed, "of", backlog)
+		}
+		env.act(stakerInstance)
+		confirmedAfter, err := env.rollup.LatestConfirmed(&bind.CallOpts{})
+		Require(t, err)
+		if confirmedAfter-confirmed > valConfig.MaxConfirmationsPerAct {
+			Fatal(t, "staker confirmed", confirmedAfter-confirmed, "nodes in one act, expected at most", valConfig.MaxConfirmationsPerAct)
+		}
+		confirmed = confirmedAfter
+	}
+	if minActs := arbmath.DivCeil(unconfirmed, valConfig.MaxConfirmationsPerAct); acts != minActs {
+		Fatal(t, "staker took", acts, "acts to confirm", unconfirmed, "nodes, expected", minActs)
+	}
+}
+
+func TestConfirmedOnlyWatchtower(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	env, cleanup := newLegacyStakerTestEnv(t, ctx, NewNodeBuilder(ctx).DefaultConfig(t, true).DontParalellise())
+	defer cleanup()
+	cancelBackgroundTxs := env.startBackgroundTxs()
+	defer cancelBackgroundTxs()
+
+	valConfigA := legacystaker.TestL1ValidatorConfig
+	valConfigA.Strategy = "MakeNodes"
+	stakerA, _ := env.newStaker("ValidatorA", &valConfigA)
+	watchtowerConfig := legacystaker.TestL1ValidatorConfig
+	watchtowerConfig.Strategy = "Watchtower"
+	watchtowerConfig.ConfirmedOnlyWatchtower = true
+	watchtower, _ := env.newStaker("Watchtower", &watchtowerConfig)
+
+	for i := 0; ; i++ {
+		if i == 100 {
+			Fatal(t, "staker A didn't confirm enough nodes")
+		}
+		env.act(stakerA)
+		tx, err := watchtower.Act(ctx)
+		Require(t, err)
+		if tx != nil {
+			Fatal(t, "confirmed-only watchtower made a transaction")
+		}
+		confirmed, err := env.rollup.LatestConfirmed(&bind.CallOpts{})
+		Require(t, err)
+		if confirmed == 0 {
+			continue
+		}
+		// every act verifies the nodes confirmed since the last one, up to the latest confirmed node
+		if verified := watchtower.Status().LatestVerifiedConfirmedNode; verified != confirmed {
+			Fatal(t, "watchtower verified confirmed node", verified, "expected", confirmed)
+		}
+		if confirmed >= 3 {
+			break
+		}
+	}
+	if divergence := watchtower.ConfirmedDivergence(); divergence != nil {
+		Fatal(t, "watchtower found confirmed node", divergence.Node, "diverging from local execution")
+	}
+}
+
+// legacyStakerTestEnv is a chain with a legacy rollup and a stateless block validator, on which the behaviour
+// of stakers is tested through real acts.
+type legacyStakerTestEnv struct {
+	t               *testing.T
+	ctx             context.Context
+	builder         *NodeBuilder
+	rollup          *rollup_legacy_gen.RollupAdminLogic
+	upgradeExecutor *upgrade_executorgen.UpgradeExecutor
+	rollupABI       abi.ABI
+	deployAuth      bind.TransactOpts
+	stateless       *staker.StatelessBlockValidator
+	parentChainID   *big.Int
+}
+
+// newLegacyStakerTestEnv builds the chain of builder, with a minimum assertion period of a block so that
+// stakers can create nodes on every act.
+func newLegacyStakerTestEnv(t *testing.T, ctx context.Context, builder *NodeBuilder) (*legacyStakerTestEnv, func()) {
+	var transferGas = util.NormalizeL2GasForL1GasInitial(800_000, params.GWei) // include room for aggregator L1 costs
+	builder.L2Info = NewBlockChainTestInfo(
+		t,
+		types.NewArbitrumSigner(types.NewLondonSigner(builder.chainConfig.ChainID)), big.NewInt(l2pricing.InitialBaseFeeWei*2),
+		transferGas,
+	)
+	// For now validation only works with HashScheme set
+	builder.RequireScheme(t, rawdb.HashScheme)
+	builder.nodeConfig.BatchPoster.MaxDelay = -1000 * time.Hour
+	cleanup := builder.Build(t)
+	l2node := builder.L2.ConsensusNode
+
+	builder.BridgeBalance(t, "Faucet", big.NewInt(1).Mul(big.NewInt(params.Ether), big.NewInt(10000)))
+	deployAuth := builder.L1Info.GetDefaultTransactOpts("RollupOwner", ctx)
+	rollup, err := rollup_legacy_gen.NewRollupAdminLogic(l2node.DeployInfo.Rollup, builder.L1.Client)
+	Require(t, err)
+	upgradeExecutor, err := upgrade_executorgen.NewUpgradeExecutor(l2node.DeployInfo.UpgradeExecutor, builder.L1.Client)
+	Require(t, err, "unable to bind upgrade executor")
+	rollupABI, err := abi.JSON(strings.NewReader(rollup_legacy_gen.RollupAdminLogicABI))
+	Require(t, err, "unable to parse rollup ABI")
+	setMinAssertPeriodCalldata, err := rollupABI.Pack("setMinimumAssertionPeriod", big.NewInt(1))
+	Require(t, err, "unable to generate setMinimumAssertionPeriod calldata")
+	tx, err := upgradeExecutor.ExecuteCall(&deployAuth, l2node.DeployInfo.Rollup, setMinAssertPeriodCalldata)
+	Require(t, err, "unable to set minimum assertion period")
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+
+	_, valStack := createTestValidationNode(t, ctx, &valnode.TestValidationConfig)
+	blockValidatorConfig := staker.TestBlockValidatorConfig
+	locator, err := server_common.NewMachineLocator(valnode.TestValidationConfig.Wasm.RootPath)
+	Require(t, err)
+	stateless, err := staker.NewStatelessBlockValidator(
+		l2node.InboxReader,
+		l2node.InboxTracker,
+		l2node.TxStreamer,
+		builder.L2.ExecNode,
+		l2node.ArbDB,
+		nil,
+		StaticFetcherFrom(t, &blockValidatorConfig),
+		valStack,
+		locator.LatestWasmModuleRoot(),
+	)
+	Require(t, err)
+	Require(t, stateless.Start(ctx))
+
+	parentChainID, err := builder.L1.Client.ChainID(ctx)
+	Require(t, err)
+	return &legacyStakerTestEnv{
+		t:               t,
+		ctx:             ctx,
+		builder:         builder,
+		rollup:          rollup,
+		upgradeExecutor: upgradeExecutor,
+		rollupABI:       rollupABI,
+		deployAuth:      deployAuth,
+		stateless:       stateless,
+		parentChainID:   parentChainID,
+	}, cleanup
+}
+
+// newStaker creates a whitelisted staker with a contract wallet, which batches its stake advances
+func (e *legacyStakerTestEnv) newStaker(name string, valConfig *legacystaker.L1ValidatorConfig) (*legacystaker.Staker, common.Address) {
+	t, ctx, builder := e.t, e.ctx, e.builder
+	l2node := builder.L2.ConsensusNode
+	balance := big.NewInt(params.Ether)
+	balance.Mul(balance, big.NewInt(100))
+	builder.L1Info.GenerateAccount(name)
+	builder.L1.TransferBalance(t, "Faucet", name, balance, builder.L1Info)
+	auth := builder.L1Info.GetDefaultTransactOpts(name, ctx)
+	dataPoster, err := arbnode.StakerDataposter(
+		ctx,
+		rawdb.NewTable(l2node.ArbDB, storage.StakerPrefix+name),
+		l2node.L1Reader,
+		&auth, NewFetcherFromConfig(arbnode.ConfigDefaultL1NonSequencerTest()),
+		nil,
+		e.parentChainID,
+	)
+	Require(t, err)
+	wallet, err := validatorwallet.NewContract(dataPoster, nil, l2node.DeployInfo.ValidatorWalletCreator, l2node.L1Reader, &auth, 0, func(common.Address) {}, func() uint64 { return valConfig.ExtraGas })
+	Require(t, err)
+	walletAddr, err := validatorwallet.GetValidatorWalletContract(ctx, l2node.DeployInfo.ValidatorWalletCreator, 0, l2node.L1Reader, true, wallet.DataPoster(), wallet.GetExtraGas(), wallet.GetCreationGas())
+	Require(t, err)
+	setValidatorCalldata, err := e.rollupABI.Pack("setValidator", []common.Address{*walletAddr}, []bool{true})
+	Require(t, err, "unable to generate setValidator calldata")
+	tx, err := e.upgradeExecutor.ExecuteCall(&e.deployAuth, l2node.DeployInfo.Rollup, setValidatorCalldata)
+	Require(t, err, "unable to set validator")
+	_, err = builder.L1.EnsureTxSucceeded(tx)
+	Require(t, err)
+	stakerInstance, err := legacystaker.NewStaker(
+		l2node.L1Reader,
+		wallet,
+		bind.CallOpts{},
+		func() *legacystaker.L1ValidatorConfig { return valConfig },
+		nil,
+		e.stateless,
+		nil,
+		nil,
+		l2node.DeployInfo.ValidatorUtils,
+		l2node.DeployInfo.Rollup,
+		l2node.InboxTracker,
+		l2node.TxStreamer,
+		l2node.InboxReader,
+		nil,
+	)
+	Require(t, err)
+	Require(t, stakerInstance.Initialize(ctx))
+	Require(t, wallet.Initialize(ctx))
+	return stakerInstance, *walletAddr
+}
+
+// act runs an act of the staker to completion, retrying it on transient errors
+func (e *legacyStakerTestEnv) act(stakerInstance *legacystaker.Staker) {
+	t, builder := e.t, e.builder
+	for attempt := 0; ; attempt++ {
+		tx, err := stakerInstance.Act(e.ctx)
+		if legacystaker.IsTransientActError(err) && attempt < 100 {
+			time.Sleep(20 * time.Millisecond)
+			continue
+		}
+		Require(t, err)
+		if tx != nil {
+			_, err = builder.L1.EnsureTxSucceeded(tx)
+			Require(t, err)
+		}
+		break
+	}
+	for j := 0; j < 5; j++ {
+		builder.L1.TransferBalance(t, "Faucet", "Faucet", common.Big0, builder.L1Info)
+	}
+}
+
+// startBackgroundTxs keeps making L2 transactions, so that batches and nodes keep being posted,
+// until the returned function is called.
+func (e *legacyStakerTestEnv) startBackgroundTxs() func() {
+	t, builder := e.t, e.builder
+	balance := big.NewInt(params.Ether)
+	balance.Mul(balance, big.NewInt(100))
+	builder.L2Info.GenerateAccount("BackgroundUser")
+	tx := builder.L2Info.PrepareTx("Faucet", "BackgroundUser", builder.L2Info.TransferGas, balance, nil)
+	Require(t, builder.L2.Client.SendTransaction(e.ctx, tx))
+	_, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	backgroundTxsCtx, cancelBackgroundTxs := context.WithCancel(e.ctx)
+	backgroundTxsShutdownChan := make(chan struct{})
+	go (func() {
+		defer close(backgroundTxsShutdownChan)
+		err := makeBackgroundTxs(backgroundTxsCtx, builder)
+		if !errors.Is(err, context.Canceled) {
+			log.Warn("error making background txs", "err", err)
+		}
+	})()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancelBackgroundTxs()
+			<-backgroundTxsShutdownChan
+		})
+	}
+}
+
+func TestStakerStateAdminRPC(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	// For now validation only works with HashScheme set
+	builder.RequireScheme(t, rawdb.HashScheme)
+	builder.nodeConfig.BlockValidator.Enable = false
+	builder.nodeConfig.Staker.Enable = true
+	builder.nodeConfig.ParentChainReader.Enable = true
+	_, valStack := createTestValidationNode(t, ctx, &valnode.TestValidationConfig)
+	configByValidationNode(builder.nodeConfig, valStack)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	legacyStaker := builder.L2.ConsensusNode.Staker.LegacyStaker()
+	if legacyStaker == nil {
+		Fatal(t, "expected the node to run the legacy staker")
+	}
+	client := builder.L2.Stack.Attach()
+	var poked arbnode.PokeStakerResult
+	Require(t, client.CallContext(ctx, &poked, "staker_poke"))
+	if poked.Tx != nil {
+		Fatal(t, "watchtower staker posted transaction", poked.Tx)
+	}
+	var state string
+	Require(t, client.CallContext(ctx, &state, "staker_state"))
+	if state != legacyStaker.State().String() {
+		Fatal(t, "staker state reported as", state, "expected", legacyStaker.State())
+	}
+}
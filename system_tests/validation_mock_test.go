@@ -3,15 +3,25 @@ package arbtest
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"math/big"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/offchainlabs/nitro/arbnode"
@@ -32,8 +42,30 @@ import (
 type mockSpawner struct {
 	ExecSpawned []uint64
 	LaunchDelay time.Duration
+	// Diverge makes validations produce a different block hash than the expected one
+	Diverge atomic.Bool
+	// FailLaunches is the number of upcoming validations to fail with an error
+	FailLaunches atomic.Int32
+	// Launched is the number of validations launched
+	Launched atomic.Int32
+	// SpawnerName overrides the name reported by the spawner
+	SpawnerName string
+	// Archs overrides the stylus targets supported by the spawner
+	Archs []rawdb.WasmTarget
+
+	launchedRootsMutex sync.Mutex
+	// launchedRoots holds the module root of each validation launched, in order
+	launchedRoots []common.Hash
+}
+
+func (s *mockSpawner) LaunchedRoots() []common.Hash {
+	s.launchedRootsMutex.Lock()
+	defer s.launchedRootsMutex.Unlock()
+	return append([]common.Hash{}, s.launchedRoots...)
 }
 
+var errMockValidationFailed = errors.New("mock validation failed")
+
 var blockHashKey = common.HexToHash("0x11223344")
 var sendRootKey = common.HexToHash("0x55667788")
 var batchNumKey = common.HexToHash("0x99aabbcc")
@@ -63,6 +95,9 @@ func (s *mockSpawner) WasmModuleRoots() ([]common.Hash, error) {
 }
 
 func (s *mockSpawner) StylusArchs() []rawdb.WasmTarget {
+	if s.Archs != nil {
+		return s.Archs
+	}
 	return []rawdb.WasmTarget{"mock"}
 }
 
@@ -72,7 +107,20 @@ func (s *mockSpawner) Launch(entry *validator.ValidationInput, moduleRoot common
 		root:    moduleRoot,
 	}
 	<-time.After(s.LaunchDelay)
-	run.Produce(globalstateFromTestPreimages(entry.Preimages))
+	s.Launched.Add(1)
+	s.launchedRootsMutex.Lock()
+	s.launchedRoots = append(s.launchedRoots, moduleRoot)
+	s.launchedRootsMutex.Unlock()
+	if s.FailLaunches.Load() > 0 {
+		s.FailLaunches.Add(-1)
+		run.ProduceError(errMockValidationFailed)
+		return run
+	}
+	gs := globalstateFromTestPreimages(entry.Preimages)
+	if s.Diverge.Load() {
+		gs.BlockHash = crypto.Keccak256Hash(gs.BlockHash[:])
+	}
+	run.Produce(gs)
 	return run
 }
 
@@ -81,9 +129,16 @@ var mockWasmModuleRoots []common.Hash = []common.Hash{common.HexToHash("0xa5a5a5
 func (s *mockSpawner) Start(context.Context) error {
 	return nil
 }
-func (s *mockSpawner) Stop()        {}
-func (s *mockSpawner) Name() string { return "mock" }
-func (s *mockSpawner) Room() int    { return 4 }
+
+func (s *mockSpawner) Stop()     {}
+func (s *mockSpawner) Room() int { return 4 }
+
+func (s *mockSpawner) Name() string {
+	if s.SpawnerName != "" {
+		return s.SpawnerName
+	}
+	return "mock"
+}
 
 func (s *mockSpawner) CreateExecutionRun(wasmModuleRoot common.Hash, input *validator.ValidationInput, _ bool) containers.PromiseInterface[validator.ExecutionRun] {
 	s.ExecSpawned = append(s.ExecSpawned, input.Id)
@@ -176,7 +231,7 @@ func createMockValidationNode(t *testing.T, ctx context.Context, config *server_
 	}
 	configFetcher := func() *server_arb.ArbitratorSpawnerConfig { return config }
 	spawner := &mockSpawner{}
-	serverAPI := valnode.NewExecutionServerAPI(spawner, spawner, configFetcher)
+	serverAPI := valnode.NewExecutionServerAPI(spawner, spawner, configFetcher, func() *valnode.ValidateInputConfig { return &valnode.DefaultValidateInputConfig })
 
 	valAPIs := []rpc.API{{
 		Namespace:     server_api.Namespace,
@@ -384,7 +439,7 @@ func TestExecutionKeepAlive(t *testing.T) {
 
 type mockBlockRecorder struct {
 	validator *staker.StatelessBlockValidator
-	streamer  *arbnode.TransactionStreamer
+	streamer  staker.TransactionStreamerInterface
 }
 
 func (m *mockBlockRecorder) RecordBlockCreation(
@@ -419,6 +474,1129 @@ func (m *mockBlockRecorder) PrepareForRecord(ctx context.Context, start, end arb
 	return nil
 }
 
-func newMockRecorder(validator *staker.StatelessBlockValidator, streamer *arbnode.TransactionStreamer) *mockBlockRecorder {
+func newMockRecorder(validator *staker.StatelessBlockValidator, streamer staker.TransactionStreamerInterface) *mockBlockRecorder {
 	return &mockBlockRecorder{validator, streamer}
 }
+
+type corruptingMockRecorder struct {
+	*mockBlockRecorder
+	badPositions map[arbutil.MessageIndex]bool
+	// failPositions fail recording, interrupting validation
+	failPositions map[arbutil.MessageIndex]bool
+	recorded      []arbutil.MessageIndex
+}
+
+var errMockRecordingInterrupted = errors.New("mock recording interrupted")
+
+func (m *corruptingMockRecorder) RecordBlockCreation(
+	ctx context.Context,
+	pos arbutil.MessageIndex,
+	msg *arbostypes.MessageWithMetadata,
+) (*execution.RecordResult, error) {
+	if m.failPositions[pos] {
+		return nil, errMockRecordingInterrupted
+	}
+	m.recorded = append(m.recorded, pos)
+	res, err := m.mockBlockRecorder.RecordBlockCreation(ctx, pos, msg)
+	if err != nil {
+		return nil, err
+	}
+	if m.badPositions[pos] {
+		res.Preimages[blockHashKey] = common.HexToHash("0xbad").Bytes()
+	}
+	return res, nil
+}
+
+// reorgingStreamer simulates reorged messages by overriding their results, or the messages themselves
+type reorgingStreamer struct {
+	*arbnode.TransactionStreamer
+	reorgedResults  map[arbutil.MessageIndex]*execution.MessageResult
+	reorgedMessages map[arbutil.MessageIndex]*arbostypes.MessageWithMetadata
+}
+
+func (s *reorgingStreamer) GetMessage(msgIdx arbutil.MessageIndex) (*arbostypes.MessageWithMetadata, error) {
+	if msg, ok := s.reorgedMessages[msgIdx]; ok {
+		return msg, nil
+	}
+	return s.TransactionStreamer.GetMessage(msgIdx)
+}
+
+func (s *reorgingStreamer) ResultAtMessageIndex(msgIdx arbutil.MessageIndex) (*execution.MessageResult, error) {
+	if result, ok := s.reorgedResults[msgIdx]; ok {
+		return result, nil
+	}
+	return s.TransactionStreamer.ResultAtMessageIndex(msgIdx)
+}
+
+// setupMockBatchValidation builds a node posting batches and a stateless validator using
+// the mock validation spawner, with at least two batches posted.
+func setupMockBatchValidation(t *testing.T, ctx context.Context) (*NodeBuilder, *staker.StatelessBlockValidator, *corruptingMockRecorder, *reorgingStreamer, func()) {
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BlockValidator.Enable = false
+	_, valStack := createMockValidationNode(t, ctx, nil)
+	configByValidationNode(builder.nodeConfig, valStack)
+	cleanup := builder.Build(t)
+
+	builder.L2Info.GenerateAccount("BackgroundUser")
+	createTransactionTillBatchCount(ctx, t, builder, 2)
+
+	l2 := builder.L2.ConsensusNode
+	streamer := &reorgingStreamer{
+		TransactionStreamer: l2.TxStreamer,
+		reorgedResults:      make(map[arbutil.MessageIndex]*execution.MessageResult),
+		reorgedMessages:     make(map[arbutil.MessageIndex]*arbostypes.MessageWithMetadata),
+	}
+	statelessValidator, err := staker.NewStatelessBlockValidator(l2.InboxReader, l2.InboxTracker, streamer, builder.L2.ExecNode.Recorder, l2.ArbDB, nil, StaticFetcherFrom(t, &builder.nodeConfig.BlockValidator), valStack, mockWasmModuleRoots[0])
+	Require(t, err)
+	recorder := &corruptingMockRecorder{
+		mockBlockRecorder: newMockRecorder(statelessValidator, streamer),
+		badPositions:      make(map[arbutil.MessageIndex]bool),
+		failPositions:     make(map[arbutil.MessageIndex]bool),
+	}
+	statelessValidator.OverrideRecorder(t, recorder)
+	Require(t, statelessValidator.Start(ctx))
+	return builder, statelessValidator, recorder, streamer, func() {
+		statelessValidator.Stop()
+		cleanup()
+	}
+}
+
+func TestValidateBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, recorder, _, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	batchNum := uint64(1)
+	prevMsgCount, err := l2.InboxTracker.GetBatchMessageCount(batchNum - 1)
+	Require(t, err)
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(batchNum)
+	Require(t, err)
+
+	results, err := statelessValidator.ValidateBatch(ctx, batchNum, false)
+	Require(t, err)
+	if len(results) != int(msgCount-prevMsgCount) {
+		Fatal(t, "unexpected number of results", len(results), "expected", msgCount-prevMsgCount)
+	}
+	for i, res := range results {
+		if res.Pos != prevMsgCount+arbutil.MessageIndex(i) {
+			Fatal(t, "unexpected result position", res.Pos)
+		}
+		if !res.Valid {
+			Fatal(t, "known-good message failed validation", res.Pos)
+		}
+	}
+
+	badPos := msgCount - 1
+	recorder.badPositions[badPos] = true
+	results, err = statelessValidator.ValidateBatch(ctx, batchNum, false)
+	Require(t, err)
+	for _, res := range results {
+		if res.Valid == (res.Pos == badPos) {
+			Fatal(t, "unexpected validation result", res.Pos, "valid", res.Valid)
+		}
+	}
+
+	if msgCount-prevMsgCount > 1 {
+		// an early mismatch stops validation before the rest of the batch is processed
+		delete(recorder.badPositions, badPos)
+		recorder.badPositions[prevMsgCount] = true
+		results, err = statelessValidator.ValidateBatch(ctx, batchNum, true)
+		Require(t, err)
+		if len(results) != 1 {
+			Fatal(t, "expected validation to stop at first mismatch, got", len(results), "results")
+		}
+		if results[0].Pos != prevMsgCount || results[0].Valid {
+			Fatal(t, "expected first message to be reported invalid", results[0].Pos, "valid", results[0].Valid)
+		}
+	}
+
+	_, err = statelessValidator.ValidateBatch(ctx, 1<<32, false)
+	if err == nil {
+		Fatal(t, "expected error validating nonexistent batch")
+	}
+}
+
+func TestCheckDelayedSequencing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, _, streamer, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	builder.BridgeBalance(t, "Faucet", big.NewInt(params.Ether))
+	builder.BridgeBalance(t, "Faucet", big.NewInt(params.Ether))
+
+	msgCount, err := streamer.GetMessageCount()
+	Require(t, err)
+	var delayedPositions []arbutil.MessageIndex
+	var prevDelayed uint64
+	for pos := arbutil.MessageIndex(0); pos < msgCount; pos++ {
+		msg, err := streamer.GetMessage(pos)
+		Require(t, err)
+		if msg.DelayedMessagesRead > prevDelayed {
+			delayedPositions = append(delayedPositions, pos)
+		}
+		prevDelayed = msg.DelayedMessagesRead
+	}
+	if len(delayedPositions) < 2 {
+		Fatal(t, "expected at least two delayed messages, got", len(delayedPositions))
+	}
+	Require(t, statelessValidator.CheckDelayedSequencing(ctx, 0, msgCount))
+
+	// swap the contents of the last two delayed messages sequenced
+	first, second := delayedPositions[len(delayedPositions)-2], delayedPositions[len(delayedPositions)-1]
+	firstMsg, err := streamer.GetMessage(first)
+	Require(t, err)
+	secondMsg, err := streamer.GetMessage(second)
+	Require(t, err)
+	reorderedFirst, reorderedSecond := *firstMsg, *secondMsg
+	reorderedFirst.Message, reorderedSecond.Message = secondMsg.Message, firstMsg.Message
+	streamer.reorgedMessages[first] = &reorderedFirst
+	streamer.reorgedMessages[second] = &reorderedSecond
+
+	err = statelessValidator.CheckDelayedSequencing(ctx, 0, msgCount)
+	if !errors.Is(err, staker.ErrDelayedSequencing) {
+		Fatal(t, "expected reordered delayed messages to be flagged, got", err)
+	}
+	_, err = statelessValidator.ValidateRange(ctx, first, msgCount, false)
+	if !errors.Is(err, staker.ErrDelayedSequencing) {
+		Fatal(t, "expected range validation to flag reordered delayed messages, got", err)
+	}
+	// the range before the reordering is still correct
+	Require(t, statelessValidator.CheckDelayedSequencing(ctx, 0, first))
+}
+
+func TestValidateBlockRange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, recorder, streamer, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(1)
+	Require(t, err)
+	genesis := streamer.ChainConfig().ArbitrumChainParams.GenesisBlockNum
+	start := genesis + 1
+	end := genesis + uint64(msgCount)
+	if end < start+2 {
+		Fatal(t, "expected at least two blocks to validate, got range", start, end)
+	}
+
+	states, err := statelessValidator.ValidateBlockRange(ctx, start, end, common.Hash{})
+	Require(t, err)
+	if uint64(len(states)) != end-start {
+		Fatal(t, "expected", end-start, "global states, got", len(states))
+	}
+	for i, state := range states {
+		header, err := builder.L2.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(start+uint64(i)))
+		Require(t, err)
+		if state.BlockHash != header.Hash() {
+			Fatal(t, "unexpected global state block hash for block", header.Number, "got", state.BlockHash, "expected", header.Hash())
+		}
+	}
+
+	// results are streamed as blocks are validated, and validation stops when the context is cancelled
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	defer cancelStream()
+	var streamed []uint64
+	err = statelessValidator.StreamBlockRangeValidation(streamCtx, start, end, common.Hash{}, func(block uint64, result staker.BlockValidationResult) error {
+		if !result.Valid {
+			Fatal(t, "known-good block failed validation", block)
+		}
+		streamed = append(streamed, block)
+		cancelStream()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		Fatal(t, "expected cancelled validation to stop, got", err)
+	}
+	if len(streamed) != 1 || streamed[0] != start {
+		Fatal(t, "expected only the first block to be validated before cancelling, got", streamed)
+	}
+
+	// a block which doesn't reproduce its global state stops the validation
+	badBlock := start + 1
+	recorder.badPositions[arbutil.MessageIndex(badBlock-genesis)] = true
+	states, err = statelessValidator.ValidateBlockRange(ctx, start, end, common.Hash{})
+	if !errors.Is(err, staker.ErrBlockNotReproduced) {
+		Fatal(t, "expected block", badBlock, "not to reproduce, got", err)
+	}
+	if uint64(len(states)) != badBlock-start+1 {
+		Fatal(t, "expected validation to stop at block", badBlock, "got", len(states), "global states")
+	}
+
+	_, err = statelessValidator.ValidateBlockRange(ctx, start, end, common.HexToHash("0x1234"))
+	if err == nil {
+		Fatal(t, "expected error validating against an unsupported module root")
+	}
+
+	// an on-chain module root other than the latest machine's is flagged before it's validated against
+	for _, tc := range []struct {
+		onChainRoot common.Hash
+		mismatch    bool
+	}{
+		{mockWasmModuleRoots[0], false},
+		{mockWasmModuleRoots[1], true},
+		{common.HexToHash("0x1234"), true},
+	} {
+		err := statelessValidator.CheckOnChainWasmModuleRoot(tc.onChainRoot)
+		if errors.Is(err, staker.ErrWasmModuleRootMismatch) != tc.mismatch {
+			Fatal(t, "on-chain module root", tc.onChainRoot, "expected mismatch", tc.mismatch, "got", err)
+		}
+	}
+}
+
+func TestRevalidateReorged(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, recorder, streamer, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	batchNum := uint64(1)
+	prevMsgCount, err := l2.InboxTracker.GetBatchMessageCount(batchNum - 1)
+	Require(t, err)
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(batchNum)
+	Require(t, err)
+	results, err := statelessValidator.ValidateBatch(ctx, batchNum, false)
+	Require(t, err)
+	for _, res := range results {
+		if !res.Valid {
+			Fatal(t, "known-good message failed validation", res.Pos)
+		}
+	}
+
+	_, found, err := statelessValidator.DetectReorg()
+	Require(t, err)
+	if found {
+		Fatal(t, "detected reorg without any reorg")
+	}
+
+	// reorg the last message of the batch, which now results in a different block
+	reorgedPos := msgCount - 1
+	result, err := l2.TxStreamer.ResultAtMessageIndex(reorgedPos)
+	Require(t, err)
+	reorgedResult := *result
+	reorgedResult.BlockHash = common.HexToHash("0x1234")
+	streamer.reorgedResults[reorgedPos] = &reorgedResult
+
+	firstReorged, found, err := statelessValidator.DetectReorg()
+	Require(t, err)
+	if !found || firstReorged != reorgedPos {
+		Fatal(t, "expected reorg detected at", reorgedPos, "got", firstReorged, "found", found)
+	}
+	results, err = statelessValidator.RevalidateReorged(ctx, false)
+	Require(t, err)
+	if len(results) != 1 || results[0].Pos != reorgedPos || !results[0].Valid {
+		Fatal(t, "expected re-validated reorged message to pass", results)
+	}
+	_, found, err = statelessValidator.DetectReorg()
+	Require(t, err)
+	if found {
+		Fatal(t, "reorg still detected after re-validation")
+	}
+
+	// a reorg where the re-validated message doesn't validate is flagged
+	if reorgedPos > prevMsgCount {
+		reorgedPos = prevMsgCount
+		result, err = l2.TxStreamer.ResultAtMessageIndex(reorgedPos)
+		Require(t, err)
+		reorgedResult := *result
+		reorgedResult.BlockHash = common.HexToHash("0x5678")
+		streamer.reorgedResults[reorgedPos] = &reorgedResult
+		recorder.badPositions[reorgedPos] = true
+		results, err = statelessValidator.RevalidateReorged(ctx, false)
+		Require(t, err)
+		if len(results) == 0 || results[0].Pos != reorgedPos || results[0].Valid {
+			Fatal(t, "expected post-reorg validation failure to be flagged", results)
+		}
+	}
+}
+
+func TestRevalidateAfterL2Reorg(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, _, _, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	batchNum := uint64(1)
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(batchNum)
+	Require(t, err)
+	results, err := statelessValidator.ValidateBatch(ctx, batchNum, false)
+	Require(t, err)
+	for _, res := range results {
+		if !res.Valid {
+			Fatal(t, "known-good message failed validation", res.Pos)
+		}
+	}
+
+	// reorg out the last validated message
+	reorgedPos := msgCount - 1
+	Require(t, l2.TxStreamer.ReorgAt(reorgedPos))
+	firstReorged, found, err := statelessValidator.DetectReorg()
+	Require(t, err)
+	if !found || firstReorged != reorgedPos {
+		Fatal(t, "expected reorg detected at", reorgedPos, "got", firstReorged, "found", found)
+	}
+	results, err = statelessValidator.RevalidateReorged(ctx, false)
+	Require(t, err)
+	if len(results) != 0 {
+		Fatal(t, "revalidated messages not back in the chain", results)
+	}
+
+	// the message is revalidated once it's back in the chain, whether resequenced or re-read from its batch
+	for i := 0; ; i++ {
+		processed, err := l2.TxStreamer.GetProcessedMessageCount()
+		Require(t, err)
+		if processed > reorgedPos {
+			break
+		}
+		if i >= 300 {
+			Fatal(t, "reorged message not back in the chain")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	results, err = statelessValidator.RevalidateReorged(ctx, false)
+	Require(t, err)
+	if len(results) != 1 || results[0].Pos != reorgedPos || !results[0].Valid {
+		Fatal(t, "expected the reorged message to be revalidated", results)
+	}
+}
+
+func TestValidateWithQuorum(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BlockValidator.Enable = false
+	_, valStackA := createMockValidationNode(t, ctx, nil)
+	_, valStackB := createMockValidationNode(t, ctx, nil)
+	divergingSpawner, valStackC := createMockValidationNode(t, ctx, nil)
+	divergingSpawner.Diverge.Store(true)
+	configByValidationNode(builder.nodeConfig, valStackA)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("BackgroundUser")
+	createTransactionTillBatchCount(ctx, t, builder, 2)
+
+	valConfig := builder.nodeConfig.BlockValidator
+	valConfig.ValidationServerConfigs = nil
+	for _, valStack := range []*node.Node{valStackA, valStackB, valStackC} {
+		serverConfig := rpcclient.TestClientConfig
+		serverConfig.URL = valStack.WSEndpoint()
+		serverConfig.JWTSecret = ""
+		valConfig.ValidationServerConfigs = append(valConfig.ValidationServerConfigs, serverConfig)
+	}
+	valConfig.ValidationQuorum = 2
+
+	l2 := builder.L2.ConsensusNode
+	statelessValidator, err := staker.NewStatelessBlockValidator(l2.InboxReader, l2.InboxTracker, l2.TxStreamer, builder.L2.ExecNode.Recorder, l2.ArbDB, nil, StaticFetcherFrom(t, &valConfig), valStackA, mockWasmModuleRoots[0])
+	Require(t, err)
+	statelessValidator.OverrideRecorder(t, newMockRecorder(statelessValidator, l2.TxStreamer))
+	Require(t, statelessValidator.Start(ctx))
+	defer statelessValidator.Stop()
+
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(1)
+	Require(t, err)
+	pos := msgCount - 1
+	expected, err := l2.TxStreamer.ResultAtMessageIndex(pos)
+	Require(t, err)
+
+	result, err := statelessValidator.ValidateResultWithQuorum(ctx, pos, mockWasmModuleRoots[0], 2)
+	Require(t, err)
+	if !result.Valid || result.AgreedState == nil || result.AgreedState.BlockHash != expected.BlockHash {
+		Fatal(t, "expected two agreeing servers to validate message", pos, "result", result)
+	}
+	if result.Agreeing != 2 || len(result.Disagreements) != 1 {
+		Fatal(t, "expected two agreeing servers and one disagreement, got", result.Agreeing, "agreeing and", len(result.Disagreements), "disagreeing")
+	}
+	disagreement := result.Disagreements[0]
+	if disagreement.Err != nil || disagreement.GlobalState.BlockHash == expected.BlockHash {
+		Fatal(t, "expected diverging server to be flagged with its global state", disagreement)
+	}
+
+	// the configured quorum is used by ValidateResult
+	valid, gs, err := statelessValidator.ValidateResult(ctx, pos, false, mockWasmModuleRoots[0])
+	Require(t, err)
+	if !valid || gs == nil || gs.BlockHash != expected.BlockHash {
+		Fatal(t, "expected message", pos, "to be valid with a quorum of two, got", gs)
+	}
+
+	// requiring all three servers to agree fails as one diverges
+	result, err = statelessValidator.ValidateResultWithQuorum(ctx, pos, mockWasmModuleRoots[0], 3)
+	if !errors.Is(err, staker.ErrValidationQuorumNotReached) {
+		Fatal(t, "expected quorum of three to not be reached, got", err)
+	}
+	if result == nil || result.AgreedState != nil || len(result.Disagreements) != 3 {
+		Fatal(t, "expected all servers flagged without an agreed state", result)
+	}
+}
+
+func TestValidationReport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, recorder, _, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	var report bytes.Buffer
+	statelessValidator.SetValidationReportWriter(staker.NewValidationReportWriter(&report))
+
+	batchNum := uint64(1)
+	prevMsgCount, err := l2.InboxTracker.GetBatchMessageCount(batchNum - 1)
+	Require(t, err)
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(batchNum)
+	Require(t, err)
+	badPos := msgCount - 1
+	recorder.badPositions[badPos] = true
+	_, err = statelessValidator.ValidateBatch(ctx, batchNum, false)
+	Require(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(report.Bytes()), []byte("\n"))
+	if len(lines) != int(msgCount-prevMsgCount) {
+		Fatal(t, "expected one report line per message, got", len(lines), "lines for", msgCount-prevMsgCount, "messages")
+	}
+	expectedFields := []string{"version", "messageNumber", "moduleRoot", "expected", "actual", "valid", "durationMs"}
+	for i, line := range lines {
+		var fields map[string]json.RawMessage
+		Require(t, json.Unmarshal(line, &fields))
+		for _, field := range expectedFields {
+			if _, ok := fields[field]; !ok {
+				Fatal(t, "validation report entry missing field", field, string(line))
+			}
+		}
+		var entry staker.ValidationReportEntry
+		Require(t, json.Unmarshal(line, &entry))
+		pos := prevMsgCount + arbutil.MessageIndex(i)
+		if entry.Version != staker.ValidationReportVersion || entry.MessageNumber != uint64(pos) || entry.ModuleRoot != mockWasmModuleRoots[0] {
+			Fatal(t, "unexpected validation report entry", string(line))
+		}
+		if entry.Expected == nil || entry.Actual == nil || entry.Error != "" {
+			Fatal(t, "expected both global states without error in report entry", string(line))
+		}
+		expected, err := l2.TxStreamer.ResultAtMessageIndex(pos)
+		Require(t, err)
+		if entry.Expected.BlockHash != expected.BlockHash {
+			Fatal(t, "unexpected expected block hash in report entry", string(line))
+		}
+		shouldBeValid := pos != badPos
+		if entry.Valid != shouldBeValid || (entry.Actual.BlockHash == entry.Expected.BlockHash) != shouldBeValid {
+			Fatal(t, "unexpected validation result in report entry", string(line))
+		}
+	}
+}
+
+func TestValidateRetriesFailedValidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BlockValidator.Enable = false
+	failingSpawner, valStackA := createMockValidationNode(t, ctx, nil)
+	otherSpawner, valStackB := createMockValidationNode(t, ctx, nil)
+	configByValidationNode(builder.nodeConfig, valStackA)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("BackgroundUser")
+	createTransactionTillBatchCount(ctx, t, builder, 2)
+
+	valConfig := builder.nodeConfig.BlockValidator
+	valConfig.ValidationRetries = 1
+	valConfig.ValidationServerConfigs = nil
+	for _, valStack := range []*node.Node{valStackA, valStackB} {
+		serverConfig := rpcclient.TestClientConfig
+		serverConfig.URL = valStack.WSEndpoint()
+		serverConfig.JWTSecret = ""
+		valConfig.ValidationServerConfigs = append(valConfig.ValidationServerConfigs, serverConfig)
+	}
+
+	l2 := builder.L2.ConsensusNode
+	statelessValidator, err := staker.NewStatelessBlockValidator(l2.InboxReader, l2.InboxTracker, l2.TxStreamer, builder.L2.ExecNode.Recorder, l2.ArbDB, nil, StaticFetcherFrom(t, &valConfig), valStackA, mockWasmModuleRoots[0])
+	Require(t, err)
+	statelessValidator.OverrideRecorder(t, newMockRecorder(statelessValidator, l2.TxStreamer))
+	Require(t, statelessValidator.Start(ctx))
+	defer statelessValidator.Stop()
+
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(1)
+	Require(t, err)
+	pos := msgCount - 1
+	expected, err := l2.TxStreamer.ResultAtMessageIndex(pos)
+	Require(t, err)
+
+	// once retries are exhausted the failure is reported
+	failingSpawner.FailLaunches.Store(1)
+	otherSpawner.FailLaunches.Store(1)
+	valid, _, err := statelessValidator.ValidateResult(ctx, pos, false, mockWasmModuleRoots[0])
+	if err == nil || valid {
+		Fatal(t, "expected validation failure to be reported after exhausting retries, got valid", valid)
+	}
+
+	// the retry on the next server recovers from the failure
+	failingSpawner.FailLaunches.Store(1)
+	valid, gs, err := statelessValidator.ValidateResult(ctx, pos, false, mockWasmModuleRoots[0])
+	Require(t, err)
+	if !valid || gs == nil || gs.BlockHash != expected.BlockHash {
+		Fatal(t, "expected message", pos, "to be valid after retrying, got", gs)
+	}
+	if failingSpawner.FailLaunches.Load() != 0 {
+		Fatal(t, "expected failing server to have been tried first")
+	}
+}
+
+func TestValidateRangeResumesFromCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, recorder, _, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	end, err := l2.InboxTracker.GetBatchMessageCount(1)
+	Require(t, err)
+	start := arbutil.MessageIndex(1)
+	if end < start+3 {
+		Fatal(t, "expected at least three messages to validate, got range", start, end)
+	}
+	interruptAt := start + (end-start)/2
+
+	// interrupt the validation halfway through the range
+	recorder.failPositions[interruptAt] = true
+	results, err := statelessValidator.ValidateRangeWithCheckpoint(ctx, start, end, false)
+	if !errors.Is(err, errMockRecordingInterrupted) {
+		Fatal(t, "expected range validation to be interrupted, got", err)
+	}
+	if arbutil.MessageIndex(len(results)) != interruptAt-start {
+		Fatal(t, "expected", interruptAt-start, "messages validated before the interruption, got", len(results))
+	}
+	progress, err := statelessValidator.ReadRangeValidationProgress()
+	Require(t, err)
+	if progress == nil || arbutil.MessageIndex(progress.NextPos) != interruptAt {
+		Fatal(t, "expected checkpoint at", interruptAt, "got", progress)
+	}
+
+	// resuming only validates the remaining messages
+	delete(recorder.failPositions, interruptAt)
+	recorder.recorded = nil
+	results, err = statelessValidator.ValidateRangeWithCheckpoint(ctx, start, end, false)
+	Require(t, err)
+	if arbutil.MessageIndex(len(results)) != end-interruptAt || results[0].Pos != interruptAt {
+		Fatal(t, "expected resumed validation to cover", interruptAt, "to", end, "got", results)
+	}
+	for _, res := range results {
+		if !res.Valid {
+			Fatal(t, "known-good message failed validation", res.Pos)
+		}
+	}
+	for _, pos := range recorder.recorded {
+		if pos < interruptAt {
+			Fatal(t, "resumed validation re-validated message", pos)
+		}
+	}
+	progress, err = statelessValidator.ReadRangeValidationProgress()
+	Require(t, err)
+	if progress != nil {
+		Fatal(t, "checkpoint wasn't removed after completing the range", progress)
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, _, _, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	// a precomputed input, as the mock spawner produces the global state held in its preimages
+	want := validator.GoGlobalState{
+		BlockHash:  common.HexToHash("0x1234"),
+		SendRoot:   common.HexToHash("0x5678"),
+		Batch:      3,
+		PosInBatch: 2,
+	}
+	precomputed := &validator.ValidationInput{
+		Preimages: daprovider.PreimagesMap{
+			arbutil.Keccak256PreimageType: globalstateToTestPreimages(want),
+		},
+	}
+	gs, err := statelessValidator.ValidateInput(ctx, precomputed, mockWasmModuleRoots[0])
+	Require(t, err)
+	if gs != want {
+		Fatal(t, "validating precomputed input produced", gs, "expected", want)
+	}
+
+	// replaying an input saved from the node validates to the message's result
+	pos := arbutil.MessageIndex(1)
+	saved, err := statelessValidator.ValidationInputsAt(ctx, pos, "mock")
+	Require(t, err)
+	replayed, err := server_api.ValidationInputFromJson(&saved)
+	Require(t, err)
+	gs, err = statelessValidator.ValidateInput(ctx, replayed, mockWasmModuleRoots[0])
+	Require(t, err)
+	expected, err := l2.TxStreamer.ResultAtMessageIndex(pos)
+	Require(t, err)
+	if gs.BlockHash != expected.BlockHash || gs.SendRoot != expected.SendRoot {
+		Fatal(t, "replayed input of message", pos, "produced", gs, "expected block hash", expected.BlockHash)
+	}
+
+	_, err = statelessValidator.ValidateInput(ctx, precomputed, common.HexToHash("0xdead"))
+	if err == nil {
+		Fatal(t, "expected validating with an unsupported module root to fail")
+	}
+}
+
+// mockArchiveAPI serves validation inputs recorded by a node holding the historical state
+type mockArchiveAPI struct {
+	validator *staker.StatelessBlockValidator
+	requested atomic.Int32
+}
+
+func (a *mockArchiveAPI) ValidationInputsAt(ctx context.Context, msgNum hexutil.Uint64, target rawdb.WasmTarget) (server_api.InputJSON, error) {
+	a.requested.Add(1)
+	return a.validator.ValidationInputsAt(ctx, arbutil.MessageIndex(msgNum), target)
+}
+
+func TestValidateFromArchiveNode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, archiveValidator, _, streamer, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	archiveAPI := &mockArchiveAPI{validator: archiveValidator}
+	archiveServer := rpc.NewServer()
+	Require(t, archiveServer.RegisterName("arbdebug", archiveAPI))
+	archiveHttp := httptest.NewServer(archiveServer)
+	defer archiveHttp.Close()
+
+	// a validator whose local state for the message was pruned, so it can't record it itself
+	pos := arbutil.MessageIndex(1)
+	config := builder.nodeConfig.BlockValidator
+	config.ArchiveNode.URL = archiveHttp.URL
+	_, valStack := createMockValidationNode(t, ctx, nil)
+	prunedValidator, err := staker.NewStatelessBlockValidator(l2.InboxReader, l2.InboxTracker, streamer, builder.L2.ExecNode.Recorder, l2.ArbDB, nil, StaticFetcherFrom(t, &config), valStack, mockWasmModuleRoots[0])
+	Require(t, err)
+	prunedRecorder := &corruptingMockRecorder{
+		mockBlockRecorder: newMockRecorder(prunedValidator, streamer),
+		failPositions:     map[arbutil.MessageIndex]bool{pos: true},
+	}
+	prunedValidator.OverrideRecorder(t, prunedRecorder)
+	Require(t, prunedValidator.Start(ctx))
+	defer prunedValidator.Stop()
+
+	valid, gs, err := prunedValidator.ValidateResult(ctx, pos, false, mockWasmModuleRoots[0])
+	Require(t, err)
+	if !valid {
+		Fatal(t, "message", pos, "recorded from archive node failed validation, got", gs)
+	}
+	if archiveAPI.requested.Load() == 0 {
+		Fatal(t, "expected the archive node to be asked for the pruned message's inputs")
+	}
+	expected, err := l2.TxStreamer.ResultAtMessageIndex(pos)
+	Require(t, err)
+	if gs.BlockHash != expected.BlockHash || gs.SendRoot != expected.SendRoot {
+		Fatal(t, "message", pos, "validated from archive node produced", gs, "expected block hash", expected.BlockHash)
+	}
+
+	// messages the validator still holds state for are recorded locally
+	requested := archiveAPI.requested.Load()
+	valid, _, err = prunedValidator.ValidateResult(ctx, pos+1, false, mockWasmModuleRoots[0])
+	Require(t, err)
+	if !valid || archiveAPI.requested.Load() != requested {
+		Fatal(t, "expected message", pos+1, "to be recorded and validated locally")
+	}
+}
+
+// gasRecordingMockRecorder takes the gas used by recorded blocks from the node's actual block recorder
+type gasRecordingMockRecorder struct {
+	*corruptingMockRecorder
+	execRecorder execution.ExecutionRecorder
+}
+
+func (m *gasRecordingMockRecorder) RecordBlockCreation(
+	ctx context.Context,
+	pos arbutil.MessageIndex,
+	msg *arbostypes.MessageWithMetadata,
+) (*execution.RecordResult, error) {
+	res, err := m.corruptingMockRecorder.RecordBlockCreation(ctx, pos, msg)
+	if err != nil {
+		return nil, err
+	}
+	recording, err := m.execRecorder.RecordBlockCreation(ctx, pos, msg)
+	if err != nil {
+		return nil, err
+	}
+	if recording.BlockHash != res.BlockHash {
+		return nil, fmt.Errorf("recorded block hash %v doesn't match %v", recording.BlockHash, res.BlockHash)
+	}
+	res.GasUsed = recording.GasUsed
+	return res, nil
+}
+
+func TestValidateBatchReportsGasUsed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, recorder, _, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	statelessValidator.OverrideRecorder(t, &gasRecordingMockRecorder{
+		corruptingMockRecorder: recorder,
+		execRecorder:           builder.L2.ExecNode.Recorder,
+	})
+	l2 := builder.L2.ConsensusNode
+
+	batchNum := uint64(1)
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(batchNum)
+	Require(t, err)
+	badPos := msgCount - 1
+	recorder.badPositions[badPos] = true
+
+	var report bytes.Buffer
+	statelessValidator.SetValidationReportWriter(staker.NewValidationReportWriter(&report))
+	results, err := statelessValidator.ValidateBatch(ctx, batchNum, false)
+	Require(t, err)
+	var totalGasUsed uint64
+	for _, res := range results {
+		if res.Pos == badPos {
+			if res.Valid || res.GasUsed != nil {
+				Fatal(t, "expected no gas reported for invalid message", res.Pos, "got", res.GasUsed)
+			}
+			continue
+		}
+		if !res.Valid {
+			Fatal(t, "known-good message failed validation", res.Pos)
+		}
+		header, err := builder.L2.Client.HeaderByHash(ctx, res.GlobalState.BlockHash)
+		Require(t, err)
+		if res.GasUsed == nil || *res.GasUsed != header.GasUsed {
+			Fatal(t, "message", res.Pos, "reported gas used", res.GasUsed, "expected", header.GasUsed)
+		}
+		totalGasUsed += header.GasUsed
+	}
+	if totalGasUsed == 0 {
+		Fatal(t, "expected the validated blocks to use gas")
+	}
+
+	decoder := json.NewDecoder(&report)
+	for _, res := range results {
+		var entry staker.ValidationReportEntry
+		Require(t, decoder.Decode(&entry))
+		if entry.MessageNumber != uint64(res.Pos) {
+			Fatal(t, "unexpected report entry", entry.MessageNumber, "expected", res.Pos)
+		}
+		if (entry.GasUsed == nil) != (res.GasUsed == nil) || (entry.GasUsed != nil && *entry.GasUsed != *res.GasUsed) {
+			Fatal(t, "report entry", entry.MessageNumber, "gas used", entry.GasUsed, "doesn't match result", res.GasUsed)
+		}
+	}
+}
+
+func TestValidateDispatchesByInputSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BlockValidator.Enable = false
+	smallSpawner, smallValStack := createMockValidationNode(t, ctx, nil)
+	largeSpawner, largeValStack := createMockValidationNode(t, ctx, nil)
+	smallSpawner.SpawnerName = "mock-arbitrator"
+	largeSpawner.SpawnerName = "mock-jit"
+	configByValidationNode(builder.nodeConfig, smallValStack)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("BackgroundUser")
+	createTransactionTillBatchCount(ctx, t, builder, 2)
+
+	valConfig := builder.nodeConfig.BlockValidator
+	valConfig.ValidationServerConfigs = nil
+	for _, valStack := range []*node.Node{smallValStack, largeValStack} {
+		serverConfig := rpcclient.TestClientConfig
+		serverConfig.URL = valStack.WSEndpoint()
+		serverConfig.JWTSecret = ""
+		valConfig.ValidationServerConfigs = append(valConfig.ValidationServerConfigs, serverConfig)
+	}
+	valConfig.InputSizeDispatch.SmallInputSpawner = "mock-arbitrator"
+	valConfig.InputSizeDispatch.LargeInputSpawner = "mock-jit"
+
+	l2 := builder.L2.ConsensusNode
+	msgCount, err := l2.InboxTracker.GetBatchMessageCount(1)
+	Require(t, err)
+	pos := msgCount - 1
+
+	for _, tc := range []struct {
+		name          string
+		limit         uint64
+		expected      *mockSpawner
+		notDispatched *mockSpawner
+	}{
+		// every input fits under the limit
+		{"small", math.MaxUint64, smallSpawner, largeSpawner},
+		// no input fits under the limit
+		{"large", 1, largeSpawner, smallSpawner},
+	} {
+		config := valConfig
+		config.InputSizeDispatch.SmallInputLimit = tc.limit
+		statelessValidator, err := staker.NewStatelessBlockValidator(l2.InboxReader, l2.InboxTracker, l2.TxStreamer, builder.L2.ExecNode.Recorder, l2.ArbDB, nil, StaticFetcherFrom(t, &config), smallValStack, mockWasmModuleRoots[0])
+		Require(t, err)
+		statelessValidator.OverrideRecorder(t, newMockRecorder(statelessValidator, l2.TxStreamer))
+		Require(t, statelessValidator.Start(ctx))
+
+		expectedLaunched := tc.expected.Launched.Load()
+		notDispatchedLaunched := tc.notDispatched.Launched.Load()
+		valid, _, err := statelessValidator.ValidateResult(ctx, pos, false, mockWasmModuleRoots[0])
+		Require(t, err)
+		statelessValidator.Stop()
+		if !valid {
+			Fatal(t, tc.name, "input failed validation")
+		}
+		if tc.expected.Launched.Load() != expectedLaunched+1 {
+			Fatal(t, tc.name, "input wasn't validated by", tc.expected.SpawnerName)
+		}
+		if tc.notDispatched.Launched.Load() != notDispatchedLaunched {
+			Fatal(t, tc.name, "input was validated by", tc.notDispatched.SpawnerName)
+		}
+	}
+}
+
+func TestBackfillValidatesModuleRootsInParallel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BlockValidator.Enable = false
+	spawner, valStack := createMockValidationNode(t, ctx, nil)
+	configByValidationNode(builder.nodeConfig, valStack)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("BackgroundUser")
+	createTransactionTillBatchCount(ctx, t, builder, 2)
+	spawner.LaunchDelay = 20 * time.Millisecond
+
+	l2 := builder.L2.ConsensusNode
+	end, err := l2.InboxTracker.GetBatchMessageCount(1)
+	Require(t, err)
+	start := arbutil.MessageIndex(1)
+	if end < start+4 {
+		Fatal(t, "not enough messages to backfill, got", end)
+	}
+	// the range spans an upgrade from the first to the second mock module root halfway through
+	upgradePos := start + (end-start)/2
+	activations := []staker.ModuleRootActivation{
+		{Pos: 0, ModuleRoot: mockWasmModuleRoots[0]},
+		{Pos: upgradePos, ModuleRoot: mockWasmModuleRoots[1]},
+	}
+
+	// backfill checks the results of both module roots, returning whether their validations interleaved
+	backfill := func(parallel bool) bool {
+		config := builder.nodeConfig.BlockValidator
+		config.ParallelBackfill.Enable = parallel
+		statelessValidator, err := staker.NewStatelessBlockValidator(l2.InboxReader, l2.InboxTracker, l2.TxStreamer, builder.L2.ExecNode.Recorder, l2.ArbDB, nil, StaticFetcherFrom(t, &config), valStack, mockWasmModuleRoots[0])
+		Require(t, err)
+		statelessValidator.OverrideRecorder(t, newMockRecorder(statelessValidator, l2.TxStreamer))
+		Require(t, statelessValidator.Start(ctx))
+		defer statelessValidator.Stop()
+
+		launchedBefore := len(spawner.LaunchedRoots())
+		results, err := statelessValidator.Backfill(ctx, start, end, activations, false)
+		Require(t, err)
+		if len(results) != int(end-start) {
+			Fatal(t, "expected", end-start, "results, got", len(results))
+		}
+		for i, result := range results {
+			if result.Pos != start+arbutil.MessageIndex(i) {
+				Fatal(t, "result", i, "is for message", result.Pos)
+			}
+			if !result.Valid {
+				Fatal(t, "message", result.Pos, "failed validation")
+			}
+		}
+		launched := spawner.LaunchedRoots()[launchedBefore:]
+		counts := make(map[common.Hash]int)
+		lastFirstRoot, firstSecondRoot := -1, -1
+		for i, root := range launched {
+			counts[root]++
+			if root == mockWasmModuleRoots[0] {
+				lastFirstRoot = i
+			} else if firstSecondRoot < 0 {
+				firstSecondRoot = i
+			}
+		}
+		if counts[mockWasmModuleRoots[0]] != int(upgradePos-start) || counts[mockWasmModuleRoots[1]] != int(end-upgradePos) {
+			Fatal(t, "unexpected validations per module root", counts)
+		}
+		return firstSecondRoot < lastFirstRoot
+	}
+
+	if backfill(false) {
+		Fatal(t, "sequential backfill validated the module roots concurrently")
+	}
+	if !backfill(true) {
+		Fatal(t, "parallel backfill didn't validate the module roots concurrently")
+	}
+}
+
+func TestValidateWithSpawnerSelections(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder, statelessValidator, _, _, cleanup := setupMockBatchValidation(t, ctx)
+	defer cleanup()
+	l2 := builder.L2.ConsensusNode
+
+	end, err := l2.InboxTracker.GetBatchMessageCount(1)
+	Require(t, err)
+	start := arbutil.MessageIndex(1)
+	if end < start+2 {
+		Fatal(t, "expected at least two messages to validate, got", end)
+	}
+	// messages up to the boundary are near a challenge and validated by the arbitrator, the rest by the jit
+	boundary := start + (end-start)/2
+	arbitrator := &mockSpawner{SpawnerName: "mock-arbitrator", Archs: []rawdb.WasmTarget{rawdb.TargetWavm}}
+	jit := &mockSpawner{SpawnerName: "mock-jit"}
+	// the jit only supports the latest module root, so older module roots fall back to the validation servers
+	statelessValidator.SetSpawnerSelections([]staker.SpawnerSelection{
+		{Spawner: arbitrator, Selects: func(pos arbutil.MessageIndex, _ common.Hash) bool { return pos < boundary }},
+		{Spawner: jit, Selects: func(_ arbutil.MessageIndex, moduleRoot common.Hash) bool { return moduleRoot == mockWasmModuleRoots[0] }},
+	})
+
+	results, err := statelessValidator.ValidateRange(ctx, start, end, false)
+	Require(t, err)
+	for _, result := range results {
+		if !result.Valid {
+			Fatal(t, "message", result.Pos, "failed validation")
+		}
+	}
+	if arbitrator.Launched.Load() != int32(boundary-start) {
+		Fatal(t, "expected the arbitrator to validate", boundary-start, "messages, got", arbitrator.Launched.Load())
+	}
+	if jit.Launched.Load() != int32(end-boundary) {
+		Fatal(t, "expected the jit to validate", end-boundary, "messages, got", jit.Launched.Load())
+	}
+
+	valid, _, err := statelessValidator.ValidateResult(ctx, end-1, false, mockWasmModuleRoots[1])
+	Require(t, err)
+	if !valid {
+		Fatal(t, "message failed validation with the older module root")
+	}
+	if jit.Launched.Load() != int32(end-boundary) {
+		Fatal(t, "jit validated a message with a module root it isn't selected for")
+	}
+
+	archs := statelessValidator.StylusArchs()
+	for _, arch := range []rawdb.WasmTarget{"mock", rawdb.TargetWavm} {
+		if !slices.Contains(archs, arch) {
+			Fatal(t, "expected stylus archs", archs, "to include", arch)
+		}
+	}
+	if len(archs) != 2 {
+		Fatal(t, "expected the union of stylus archs without duplicates, got", archs)
+	}
+	if roots := statelessValidator.WasmModuleRoots(); len(roots) != len(mockWasmModuleRoots) {
+		Fatal(t, "expected the union of module roots without duplicates, got", roots)
+	}
+}
+
+func TestValidateRangeWithModuleRootOracle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, true)
+	builder.nodeConfig.BlockValidator.Enable = false
+	spawner, valStack := createMockValidationNode(t, ctx, nil)
+	configByValidationNode(builder.nodeConfig, valStack)
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("BackgroundUser")
+	createTransactionTillBatchCount(ctx, t, builder, 2)
+
+	l2 := builder.L2.ConsensusNode
+	end, err := l2.InboxTracker.GetBatchMessageCount(1)
+	Require(t, err)
+	start := arbutil.MessageIndex(1)
+	l1Block := func(pos arbutil.MessageIndex) uint64 {
+		msg, err := l2.TxStreamer.GetMessage(pos)
+		Require(t, err)
+		return msg.Message.Header.BlockNumber
+	}
+	// the module root is upgraded at the parent chain block of the range's last message
+	upgradeBlock := l1Block(end - 1)
+	if l1Block(start) >= upgradeBlock {
+		Fatal(t, "expected the range's messages to span several parent chain blocks")
+	}
+	config := builder.nodeConfig.BlockValidator
+	config.ModuleRootHistory = []string{
+		fmt.Sprintf("0:%v", mockWasmModuleRoots[0]),
+		fmt.Sprintf("%d:%v", upgradeBlock, mockWasmModuleRoots[1]),
+	}
+	Require(t, config.Validate())
+	oracle, err := config.ModuleRootOracle()
+	Require(t, err)
+	statelessValidator, err := staker.NewStatelessBlockValidatorWithRootOracle(l2.InboxReader, l2.InboxTracker, l2.TxStreamer, builder.L2.ExecNode.Recorder, l2.ArbDB, nil, StaticFetcherFrom(t, &builder.nodeConfig.BlockValidator), valStack, oracle)
+	Require(t, err)
+	if statelessValidator.GetLatestWasmModuleRoot() != mockWasmModuleRoots[1] {
+		Fatal(t, "unexpected latest module root", statelessValidator.GetLatestWasmModuleRoot())
+	}
+	statelessValidator.OverrideRecorder(t, newMockRecorder(statelessValidator, l2.TxStreamer))
+	Require(t, statelessValidator.Start(ctx))
+	defer statelessValidator.Stop()
+
+	launchedBefore := len(spawner.LaunchedRoots())
+	results, err := statelessValidator.ValidateRange(ctx, start, end, false)
+	Require(t, err)
+	for _, result := range results {
+		if !result.Valid {
+			Fatal(t, "message", result.Pos, "failed validation")
+		}
+	}
+	launched := spawner.LaunchedRoots()[launchedBefore:]
+	if len(launched) != int(end-start) {
+		Fatal(t, "expected", end-start, "validations, got", len(launched))
+	}
+	for i, root := range launched {
+		pos := start + arbutil.MessageIndex(i)
+		expected := mockWasmModuleRoots[0]
+		if l1Block(pos) >= upgradeBlock {
+			expected = mockWasmModuleRoots[1]
+		}
+		if root != expected {
+			Fatal(t, "message", pos, "of parent chain block", l1Block(pos), "validated with module root", root, "expected", expected)
+		}
+	}
+
+	// validating a single message without a module root uses the one in effect for it
+	for _, pos := range []arbutil.MessageIndex{start, end - 1} {
+		launchedBefore = len(spawner.LaunchedRoots())
+		valid, _, err := statelessValidator.ValidateResult(ctx, pos, false, common.Hash{})
+		Require(t, err)
+		if !valid {
+			Fatal(t, "message", pos, "failed validation")
+		}
+		expected := mockWasmModuleRoots[0]
+		if l1Block(pos) >= upgradeBlock {
+			expected = mockWasmModuleRoots[1]
+		}
+		if launched := spawner.LaunchedRoots()[launchedBefore:]; len(launched) != 1 || launched[0] != expected {
+			Fatal(t, "message", pos, "validated with module roots", launched, "expected", expected)
+		}
+	}
+
+	_, err = staker.WasmModuleRootHistory([]staker.WasmModuleRootUpgrade{
+		{L1Block: upgradeBlock, ModuleRoot: mockWasmModuleRoots[1]},
+		{L1Block: upgradeBlock, ModuleRoot: mockWasmModuleRoots[0]},
+	})
+	if err == nil {
+		Fatal(t, "expected unordered module root upgrades to be rejected")
+	}
+}
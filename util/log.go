@@ -1,7 +1,10 @@
 package util
 
 import (
+	"context"
+	"log/slog"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -65,3 +68,68 @@ func (h *EphemeralErrorHandler) LogLevel(err error, currentLogLevel func(msg str
 func (h *EphemeralErrorHandler) Reset() {
 	*h.FirstOccurrence = time.Time{}
 }
+
+var logOutputHandler atomic.Pointer[slog.Handler]
+
+// SetLogOutputHandler sets the handler subsystem loggers with their own log level write to.
+// It should be the handler the root logger wraps, without the root logger's level filtering.
+func SetLogOutputHandler(handler slog.Handler) {
+	logOutputHandler.Store(&handler)
+}
+
+// SubsystemLogLevel returns the log level of a subsystem, or false if it uses the root logger's level.
+type SubsystemLogLevel func() (slog.Level, bool)
+
+// NewSubsystemLogger returns a logger for the named subsystem, whose records carry a subsystem attribute.
+// If level returns a level, records are filtered by it instead of the root logger's level and written
+// to the handler set with SetLogOutputHandler, otherwise they go through the root logger.
+func NewSubsystemLogger(subsystem string, level SubsystemLogLevel) log.Logger {
+	return log.NewLogger(&subsystemHandler{
+		level: level,
+		attrs: []slog.Attr{slog.String("subsystem", subsystem)},
+	})
+}
+
+type subsystemHandler struct {
+	level SubsystemLogLevel
+	attrs []slog.Attr
+}
+
+// target returns the handler records are written to, and whether it's filtered by the subsystem's level
+func (h *subsystemHandler) target() (slog.Handler, *slog.Level) {
+	if level, ok := h.level(); ok {
+		if output := logOutputHandler.Load(); output != nil {
+			return *output, &level
+		}
+		return log.Root().Handler(), &level
+	}
+	return log.Root().Handler(), nil
+}
+
+func (h *subsystemHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	handler, subsystemLevel := h.target()
+	if subsystemLevel != nil {
+		return level >= *subsystemLevel
+	}
+	return handler.Enabled(ctx, level)
+}
+
+func (h *subsystemHandler) Handle(ctx context.Context, record slog.Record) error {
+	handler, subsystemLevel := h.target()
+	if subsystemLevel != nil && record.Level < *subsystemLevel {
+		return nil
+	}
+	return handler.WithAttrs(h.attrs).Handle(ctx, record)
+}
+
+func (h *subsystemHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &subsystemHandler{
+		level: h.level,
+		attrs: append(append([]slog.Attr{}, h.attrs...), attrs...),
+	}
+}
+
+func (h *subsystemHandler) WithGroup(string) slog.Handler {
+	// groups aren't used by geth style logging
+	return h
+}
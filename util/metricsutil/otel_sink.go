@@ -0,0 +1,132 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package metricsutil
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// OtelSink is a Sink reporting to an OpenTelemetry meter. Gauges are observable instruments
+// reporting the latest value they were updated to whenever the meter provider collects them.
+type OtelSink struct {
+	meter metric.Meter
+
+	mutex       sync.Mutex
+	counters    map[string]metric.Int64Counter
+	histograms  map[string]metric.Int64Histogram
+	gauges      map[string]*atomic.Int64
+	floatGauges map[string]*atomic.Uint64 // float64 bits
+	// instruments the meter failed to create, only logged once
+	failed map[string]bool
+}
+
+// NewOtelSink creates a Sink reporting to a meter of the given provider, named after scope.
+func NewOtelSink(provider metric.MeterProvider, scope string) *OtelSink {
+	return &OtelSink{
+		meter:       provider.Meter(scope),
+		counters:    make(map[string]metric.Int64Counter),
+		histograms:  make(map[string]metric.Int64Histogram),
+		gauges:      make(map[string]*atomic.Int64),
+		floatGauges: make(map[string]*atomic.Uint64),
+		failed:      make(map[string]bool),
+	}
+}
+
+// creationFailed logs the failure to create the instrument of a metric the first time, and reports
+// whether it failed. The mutex must be held.
+func (s *OtelSink) creationFailed(name string, err error) bool {
+	if err == nil {
+		return false
+	}
+	if !s.failed[name] {
+		s.failed[name] = true
+		log.Warn("failed to create OpenTelemetry instrument, dropping its updates", "metric", name, "err", err)
+	}
+	return true
+}
+
+func (s *OtelSink) UpdateGauge(name string, value int64) {
+	s.mutex.Lock()
+	gauge, ok := s.gauges[name]
+	if !ok && !s.failed[name] {
+		gauge = new(atomic.Int64)
+		_, err := s.meter.Int64ObservableGauge(name, metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(gauge.Load())
+			return nil
+		}))
+		if s.creationFailed(name, err) {
+			gauge = nil
+		} else {
+			s.gauges[name] = gauge
+		}
+	}
+	s.mutex.Unlock()
+	if gauge != nil {
+		gauge.Store(value)
+	}
+}
+
+func (s *OtelSink) UpdateGaugeFloat64(name string, value float64) {
+	s.mutex.Lock()
+	gauge, ok := s.floatGauges[name]
+	if !ok && !s.failed[name] {
+		gauge = new(atomic.Uint64)
+		_, err := s.meter.Float64ObservableGauge(name, metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			o.Observe(math.Float64frombits(gauge.Load()))
+			return nil
+		}))
+		if s.creationFailed(name, err) {
+			gauge = nil
+		} else {
+			s.floatGauges[name] = gauge
+		}
+	}
+	s.mutex.Unlock()
+	if gauge != nil {
+		gauge.Store(math.Float64bits(value))
+	}
+}
+
+func (s *OtelSink) IncCounter(name string, delta int64) {
+	s.mutex.Lock()
+	counter, ok := s.counters[name]
+	if !ok && !s.failed[name] {
+		var err error
+		counter, err = s.meter.Int64Counter(name)
+		if s.creationFailed(name, err) {
+			counter = nil
+		} else {
+			s.counters[name] = counter
+		}
+	}
+	s.mutex.Unlock()
+	if counter != nil {
+		counter.Add(context.Background(), delta)
+	}
+}
+
+func (s *OtelSink) UpdateHistogram(name string, value int64) {
+	s.mutex.Lock()
+	histogram, ok := s.histograms[name]
+	if !ok && !s.failed[name] {
+		var err error
+		histogram, err = s.meter.Int64Histogram(name)
+		if s.creationFailed(name, err) {
+			histogram = nil
+		} else {
+			s.histograms[name] = histogram
+		}
+	}
+	s.mutex.Unlock()
+	if histogram != nil {
+		histogram.Record(context.Background(), value)
+	}
+}
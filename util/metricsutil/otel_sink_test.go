@@ -0,0 +1,105 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package metricsutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// recordingMeter records the values added to its counters and histograms, and the
+// callbacks of its observable gauges. It fails to create instruments named "invalid".
+type recordingMeter struct {
+	noop.Meter
+	values         map[string][]int64
+	gaugeCallbacks map[string]metric.Int64Callback
+}
+
+type recordingMeterProvider struct {
+	noop.MeterProvider
+	meter *recordingMeter
+}
+
+func (p *recordingMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return p.meter
+}
+
+type recordingInstrument struct {
+	noop.Int64Counter
+	noop.Int64Histogram
+	meter *recordingMeter
+	name  string
+}
+
+func (i *recordingInstrument) Add(_ context.Context, incr int64, _ ...metric.AddOption) {
+	i.meter.values[i.name] = append(i.meter.values[i.name], incr)
+}
+
+func (i *recordingInstrument) Record(_ context.Context, value int64, _ ...metric.RecordOption) {
+	i.meter.values[i.name] = append(i.meter.values[i.name], value)
+}
+
+var errInvalidInstrument = errors.New("invalid instrument name")
+
+func (m *recordingMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	if name == "invalid" {
+		return nil, errInvalidInstrument
+	}
+	return &recordingInstrument{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) Int64Histogram(name string, _ ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	return &recordingInstrument{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) Int64ObservableGauge(name string, opts ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	m.gaugeCallbacks[name] = metric.NewInt64ObservableGaugeConfig(opts...).Callbacks()[0]
+	return noop.Int64ObservableGauge{}, nil
+}
+
+type recordingObserver struct {
+	noop.Int64Observer
+	observed []int64
+}
+
+func (o *recordingObserver) Observe(value int64, _ ...metric.ObserveOption) {
+	o.observed = append(o.observed, value)
+}
+
+func TestOtelSink(t *testing.T) {
+	meter := &recordingMeter{values: make(map[string][]int64), gaugeCallbacks: make(map[string]metric.Int64Callback)}
+	sink := NewOtelSink(&recordingMeterProvider{meter: meter}, "test")
+
+	sink.IncCounter("arb/test/counter", 2)
+	sink.IncCounter("arb/test/counter", 3)
+	sink.UpdateHistogram("arb/test/histogram", 7)
+	if got := meter.values["arb/test/counter"]; len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatal("unexpected counter increments", got)
+	}
+	if got := meter.values["arb/test/histogram"]; len(got) != 1 || got[0] != 7 {
+		t.Fatal("unexpected histogram values", got)
+	}
+
+	// a gauge reports the latest value it was updated to when collected
+	sink.UpdateGauge("arb/test/gauge", 4)
+	sink.UpdateGauge("arb/test/gauge", 5)
+	observer := &recordingObserver{}
+	if err := meter.gaugeCallbacks["arb/test/gauge"](context.Background(), observer); err != nil {
+		t.Fatal(err)
+	}
+	if len(observer.observed) != 1 || observer.observed[0] != 5 {
+		t.Fatal("unexpected observed gauge values", observer.observed)
+	}
+
+	// updates of a metric whose instrument couldn't be created are dropped
+	sink.IncCounter("invalid", 1)
+	sink.IncCounter("invalid", 1)
+	if len(meter.values["invalid"]) != 0 || !sink.failed["invalid"] {
+		t.Fatal("expected updates of an invalid counter to be dropped")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package metricsutil
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Sink receives metric updates by name, allowing embedders to route metrics
+// to a backend of their choice instead of the go-ethereum metrics registry.
+type Sink interface {
+	UpdateGauge(name string, value int64)
+	UpdateGaugeFloat64(name string, value float64)
+	IncCounter(name string, delta int64)
+	UpdateHistogram(name string, value int64)
+}
+
+// RegistrySink is a Sink backed by a go-ethereum metrics registry.
+type RegistrySink struct {
+	registry metrics.Registry
+}
+
+// NewRegistrySink creates a Sink registering metrics in the given registry,
+// or in the default registry if nil.
+func NewRegistrySink(registry metrics.Registry) *RegistrySink {
+	return &RegistrySink{registry: registry}
+}
+
+// DefaultSink reports to the default go-ethereum metrics registry.
+var DefaultSink Sink = NewRegistrySink(nil)
+
+func (s *RegistrySink) UpdateGauge(name string, value int64) {
+	metrics.GetOrRegisterGauge(name, s.registry).Update(value)
+}
+
+func (s *RegistrySink) UpdateGaugeFloat64(name string, value float64) {
+	metrics.GetOrRegisterGaugeFloat64(name, s.registry).Update(value)
+}
+
+func (s *RegistrySink) IncCounter(name string, delta int64) {
+	metrics.GetOrRegisterCounter(name, s.registry).Inc(delta)
+}
+
+func (s *RegistrySink) UpdateHistogram(name string, value int64) {
+	metrics.GetOrRegisterHistogram(name, s.registry, metrics.NewBoundedHistogramSample()).Update(value)
+}
+
+// Flusher is implemented by sinks which buffer metric updates before reporting them.
+type Flusher interface {
+	Flush()
+}
+
+// Flush reports the metric updates buffered by sink, if it buffers them.
+func Flush(sink Sink) {
+	if flusher, ok := sink.(Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -54,7 +54,7 @@ func (c *ValidationClient) Launch(entry *validator.ValidationInput, moduleRoot c
 		c.room.Add(1)
 		return res, err
 	})
-	return server_common.NewValRun(promise, moduleRoot)
+	return server_common.NewValRun(promise, moduleRoot, server_common.WithSpawnerName(c.Name()))
 }
 
 func (c *ValidationClient) Start(ctx context.Context) error {
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -19,11 +20,75 @@ type ValidationSpawner interface {
 	Room() int
 }
 
+// ValidationHandle describes a validation in flight on a spawner, through which it can be cancelled.
+type ValidationHandle struct {
+	// Id is unique among the spawner's validations
+	Id         uint64      `json:"id"`
+	ModuleRoot common.Hash `json:"moduleRoot"`
+	// Block is the id of the validation input, i.e. the position of the validated message
+	Block   uint64    `json:"block"`
+	Started time.Time `json:"started"`
+	cancel  func()
+}
+
+func NewValidationHandle(id uint64, moduleRoot common.Hash, block uint64, started time.Time, cancel func()) ValidationHandle {
+	return ValidationHandle{Id: id, ModuleRoot: moduleRoot, Block: block, Started: started, cancel: cancel}
+}
+
+// Cancel aborts the validation, failing it with a context cancellation error.
+func (h ValidationHandle) Cancel() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+}
+
+// InFlightLister is implemented by spawners able to list their in-flight validations,
+// e.g. to surface and abort a wedged validation without restarting the process.
+type InFlightLister interface {
+	InFlight() []ValidationHandle
+}
+
 type ValidationRun interface {
 	containers.PromiseInterface[GoGlobalState]
 	WasmModuleRoot() common.Hash
 }
 
+// SpawnerNamedRun is implemented by validation runs which know the name of the spawner which ran them,
+// e.g. "jit-cranelift", to attribute results when aggregating them across spawners.
+type SpawnerNamedRun interface {
+	SpawnerName() string
+}
+
+// RunSpawnerName returns the name of the spawner which ran run, or "" if unknown.
+func RunSpawnerName(run ValidationRun) string {
+	if named, ok := run.(SpawnerNamedRun); ok {
+		return named.SpawnerName()
+	}
+	return ""
+}
+
+// ResourceUsage is the resources a validation used, e.g. to size validation hosts.
+type ResourceUsage struct {
+	// PeakWasmMemory is the size in bytes of the validation's wasm memory, which only grows
+	PeakWasmMemory uint64
+	// CPUTime is the user and system CPU time spent on the validation
+	CPUTime time.Duration
+}
+
+// ResourceUsageRun is implemented by validation runs able to report the resources they used,
+// which are known once the validation succeeded.
+type ResourceUsageRun interface {
+	ResourceUsage() (ResourceUsage, bool)
+}
+
+// RunResourceUsage returns the resources run used, if it's completed successfully and reports them.
+func RunResourceUsage(run ValidationRun) (ResourceUsage, bool) {
+	if reporter, ok := run.(ResourceUsageRun); ok {
+		return reporter.ResourceUsage()
+	}
+	return ResourceUsage{}, false
+}
+
 type ExecutionSpawner interface {
 	ValidationSpawner
 	CreateExecutionRun(wasmModuleRoot common.Hash, input *ValidationInput, useBoldMachine bool) containers.PromiseInterface[ExecutionRun]
@@ -0,0 +1,286 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/daprovider"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+// InputFormat is an encoding of a validation input, e.g. to dump it to disk and replay it elsewhere.
+type InputFormat uint8
+
+const (
+	// InputFormatJSON is the InputJSON encoding, as sent to validation servers.
+	InputFormatJSON InputFormat = iota
+	// InputFormatBinary is a compact binary encoding, with the preimages and wasms stored raw.
+	InputFormatBinary
+)
+
+// binaryInputMagic prefixes binary encoded validation inputs, followed by the format version.
+var binaryInputMagic = []byte("NITROVAL")
+
+const binaryInputVersion = 1
+
+var ErrInvalidBinaryInput = errors.New("invalid binary validation input")
+
+// MarshalValidationInput encodes input in the given format, round-tripping through UnmarshalValidationInput.
+func MarshalValidationInput(input *validator.ValidationInput, format InputFormat) ([]byte, error) {
+	switch format {
+	case InputFormatJSON:
+		return ValidationInputToJson(input).Marshal()
+	case InputFormatBinary:
+		return marshalBinaryInput(input), nil
+	default:
+		return nil, fmt.Errorf("unknown validation input format %d", format)
+	}
+}
+
+// UnmarshalValidationInput decodes a validation input encoded by MarshalValidationInput, in either format.
+func UnmarshalValidationInput(data []byte) (*validator.ValidationInput, error) {
+	if bytes.HasPrefix(data, binaryInputMagic) {
+		return unmarshalBinaryInput(data[len(binaryInputMagic):])
+	}
+	var inputJSON InputJSON
+	if err := json.Unmarshal(data, &inputJSON); err != nil {
+		return nil, fmt.Errorf("error decoding validation input json: %w", err)
+	}
+	return ValidationInputFromJson(&inputJSON)
+}
+
+// HashValidationInput returns a hash of the content of input, e.g. to cache validation results by.
+func HashValidationInput(input *validator.ValidationInput) common.Hash {
+	hasher := crypto.NewKeccakState()
+	writeBinaryInput(hasher, input)
+	var hash common.Hash
+	_, _ = hasher.Read(hash[:])
+	return hash
+}
+
+// binaryInputWriter writes the binary encoding of a validation input to a writer which can't fail.
+type binaryInputWriter struct {
+	io.Writer
+}
+
+func (w binaryInputWriter) byte(v byte) {
+	_, _ = w.Write([]byte{v})
+}
+
+func (w binaryInputWriter) uint(v uint64) {
+	_, _ = w.Write(binary.AppendUvarint(nil, v))
+}
+
+func (w binaryInputWriter) bool(v bool) {
+	if v {
+		w.byte(1)
+	} else {
+		w.byte(0)
+	}
+}
+
+func (w binaryInputWriter) bytes(v []byte) {
+	w.uint(uint64(len(v)))
+	_, _ = w.Write(v)
+}
+
+func (w binaryInputWriter) hash(v common.Hash) {
+	_, _ = w.Write(v[:])
+}
+
+func marshalBinaryInput(input *validator.ValidationInput) []byte {
+	var buf bytes.Buffer
+	buf.Write(binaryInputMagic)
+	buf.WriteByte(binaryInputVersion)
+	writeBinaryInput(&buf, input)
+	return buf.Bytes()
+}
+
+// writeBinaryInput encodes input deterministically, with map entries sorted by key.
+func writeBinaryInput(out io.Writer, input *validator.ValidationInput) {
+	w := binaryInputWriter{out}
+	w.uint(input.Id)
+	w.bool(input.HasDelayedMsg)
+	w.uint(input.DelayedMsgNr)
+	w.bytes(input.DelayedMsg)
+	w.hash(input.StartState.BlockHash)
+	w.hash(input.StartState.SendRoot)
+	w.uint(input.StartState.Batch)
+	w.uint(input.StartState.PosInBatch)
+	w.bool(input.DebugChain)
+	w.uint(uint64(len(input.BatchInfo)))
+	for _, batch := range input.BatchInfo {
+		w.uint(batch.Number)
+		w.bytes(batch.Data)
+	}
+	writeHashes := func(entries map[common.Hash][]byte) {
+		hashes := make([]common.Hash, 0, len(entries))
+		for hash := range entries {
+			hashes = append(hashes, hash)
+		}
+		slices.SortFunc(hashes, func(a, b common.Hash) int { return a.Cmp(b) })
+		w.uint(uint64(len(hashes)))
+		for _, hash := range hashes {
+			w.hash(hash)
+			w.bytes(entries[hash])
+		}
+	}
+	preimageTypes := make([]arbutil.PreimageType, 0, len(input.Preimages))
+	for ty := range input.Preimages {
+		preimageTypes = append(preimageTypes, ty)
+	}
+	slices.Sort(preimageTypes)
+	w.uint(uint64(len(preimageTypes)))
+	for _, ty := range preimageTypes {
+		w.byte(byte(ty))
+		writeHashes(input.Preimages[ty])
+	}
+	targets := make([]rawdb.WasmTarget, 0, len(input.UserWasms))
+	for target := range input.UserWasms {
+		targets = append(targets, target)
+	}
+	slices.Sort(targets)
+	w.uint(uint64(len(targets)))
+	for _, target := range targets {
+		w.bytes([]byte(target))
+		writeHashes(input.UserWasms[target])
+	}
+}
+
+type binaryInputReader struct {
+	*bytes.Reader
+	err error
+}
+
+func (r *binaryInputReader) fail(err error) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: %w", ErrInvalidBinaryInput, err)
+	}
+}
+
+func (r *binaryInputReader) uint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(r)
+	if err != nil {
+		r.fail(err)
+	}
+	return v
+}
+
+func (r *binaryInputReader) bool() bool {
+	if r.err != nil {
+		return false
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		r.fail(err)
+	} else if b > 1 {
+		r.fail(fmt.Errorf("bad bool %d", b))
+	}
+	return b == 1
+}
+
+func (r *binaryInputReader) fixed(out []byte) {
+	if r.err != nil {
+		return
+	}
+	if _, err := io.ReadFull(r, out); err != nil {
+		r.fail(err)
+	}
+}
+
+// count reads a length, which can't exceed the remaining bytes as every entry takes at least one.
+func (r *binaryInputReader) count() uint64 {
+	n := r.uint()
+	if n > uint64(r.Len()) {
+		r.fail(fmt.Errorf("length %d exceeds the remaining %d bytes", n, r.Len()))
+		return 0
+	}
+	return n
+}
+
+func (r *binaryInputReader) bytes() []byte {
+	n := r.count()
+	if r.err != nil {
+		return nil
+	}
+	out := make([]byte, n)
+	r.fixed(out)
+	return out
+}
+
+func (r *binaryInputReader) hash() common.Hash {
+	var hash common.Hash
+	r.fixed(hash[:])
+	return hash
+}
+
+func unmarshalBinaryInput(data []byte) (*validator.ValidationInput, error) {
+	r := &binaryInputReader{Reader: bytes.NewReader(data)}
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBinaryInput, err)
+	}
+	if version != binaryInputVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidBinaryInput, version)
+	}
+	input := &validator.ValidationInput{
+		Preimages: make(daprovider.PreimagesMap),
+		UserWasms: make(map[rawdb.WasmTarget]map[common.Hash][]byte),
+	}
+	input.Id = r.uint()
+	input.HasDelayedMsg = r.bool()
+	input.DelayedMsgNr = r.uint()
+	input.DelayedMsg = r.bytes()
+	input.StartState.BlockHash = r.hash()
+	input.StartState.SendRoot = r.hash()
+	input.StartState.Batch = r.uint()
+	input.StartState.PosInBatch = r.uint()
+	input.DebugChain = r.bool()
+	for i := r.count(); i > 0 && r.err == nil; i-- {
+		number := r.uint()
+		input.BatchInfo = append(input.BatchInfo, validator.BatchInfo{Number: number, Data: r.bytes()})
+	}
+	readHashes := func() map[common.Hash][]byte {
+		entries := make(map[common.Hash][]byte)
+		for i := r.count(); i > 0 && r.err == nil; i-- {
+			hash := r.hash()
+			entries[hash] = r.bytes()
+		}
+		return entries
+	}
+	for i := r.count(); i > 0 && r.err == nil; i-- {
+		ty, err := r.ReadByte()
+		if err != nil {
+			r.fail(err)
+			break
+		}
+		input.Preimages[arbutil.PreimageType(ty)] = readHashes()
+	}
+	for i := r.count(); i > 0 && r.err == nil; i-- {
+		target := rawdb.WasmTarget(r.bytes())
+		input.UserWasms[target] = readHashes()
+	}
+	if r.err == nil && r.Len() > 0 {
+		r.fail(fmt.Errorf("%d trailing bytes", r.Len()))
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return input, nil
+}
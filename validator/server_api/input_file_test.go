@@ -0,0 +1,113 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_api
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/daprovider"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+func testValidationInput() *validator.ValidationInput {
+	return &validator.ValidationInput{
+		Id:            42,
+		HasDelayedMsg: true,
+		DelayedMsgNr:  7,
+		DelayedMsg:    []byte("delayed message"),
+		Preimages: daprovider.PreimagesMap{
+			arbutil.Keccak256PreimageType: {
+				common.HexToHash("0x01"): []byte("first preimage"),
+				common.HexToHash("0x02"): []byte("second preimage"),
+			},
+			arbutil.Sha2_256PreimageType: {
+				common.HexToHash("0x03"): []byte("sha preimage"),
+			},
+		},
+		UserWasms: map[rawdb.WasmTarget]map[common.Hash][]byte{
+			rawdb.TargetWavm: {
+				common.HexToHash("0x04"): []byte("wasm module"),
+			},
+		},
+		BatchInfo: []validator.BatchInfo{
+			{Number: 3, Data: []byte("batch 3")},
+			{Number: 4, Data: []byte("batch 4")},
+		},
+		StartState: validator.GoGlobalState{
+			BlockHash:  common.HexToHash("0x05"),
+			SendRoot:   common.HexToHash("0x06"),
+			Batch:      3,
+			PosInBatch: 9,
+		},
+		DebugChain: true,
+	}
+}
+
+func TestValidationInputRoundTrip(t *testing.T) {
+	input := testValidationInput()
+	for _, format := range []InputFormat{InputFormatJSON, InputFormatBinary} {
+		data, err := MarshalValidationInput(input, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := UnmarshalValidationInput(data)
+		if err != nil {
+			t.Fatalf("format %d: %v", format, err)
+		}
+		if !reflect.DeepEqual(decoded, input) {
+			t.Fatalf("format %d: decoded input %+v doesn't match %+v", format, decoded, input)
+		}
+	}
+}
+
+func TestBinaryValidationInputDeterministic(t *testing.T) {
+	first, err := MarshalValidationInput(testValidationInput(), InputFormatBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := MarshalValidationInput(testValidationInput(), InputFormatBinary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatal("binary encoding isn't deterministic")
+		}
+	}
+}
+
+func TestBinaryValidationInputCorrupt(t *testing.T) {
+	data, err := MarshalValidationInput(testValidationInput(), InputFormatBinary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, corrupt := range [][]byte{
+		data[:len(data)-1],
+		append(append([]byte{}, data...), 0),
+		binaryInputMagic,
+	} {
+		if _, err := UnmarshalValidationInput(corrupt); !errors.Is(err, ErrInvalidBinaryInput) {
+			t.Fatalf("expected invalid input error for %d bytes, got %v", len(corrupt), err)
+		}
+	}
+}
+
+func TestHashValidationInput(t *testing.T) {
+	hash := HashValidationInput(testValidationInput())
+	if HashValidationInput(testValidationInput()) != hash {
+		t.Fatal("validation input hash isn't deterministic")
+	}
+	changed := testValidationInput()
+	changed.Preimages[arbutil.Keccak256PreimageType][common.HexToHash("0x01")] = []byte("other preimage")
+	if HashValidationInput(changed) == hash {
+		t.Fatal("validation input hash doesn't depend on the preimages")
+	}
+}
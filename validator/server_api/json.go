@@ -20,6 +20,28 @@ import (
 
 const Namespace string = "validation"
 
+// Error codes of typed errors returned by the validateInput method
+const (
+	UnknownModuleRootErrorCode = -38001
+	InputTooLargeErrorCode     = -38002
+	ValidationTimeoutErrorCode = -38003
+)
+
+// ValidationError is an error carrying a JSON-RPC error code, letting clients tell apart
+// why validating a submitted input failed.
+type ValidationError struct {
+	Code    int
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) ErrorCode() int {
+	return e.Code
+}
+
 type MachineStepResultJson struct {
 	Hash        common.Hash
 	Position    uint64
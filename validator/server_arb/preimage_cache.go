@@ -0,0 +1,126 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_arb
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/util/containers"
+)
+
+var (
+	preimageCacheHitCounter  = metrics.NewRegisteredCounter("arbitrator/preimagecache/hit", nil)
+	preimageCacheMissCounter = metrics.NewRegisteredCounter("arbitrator/preimagecache/miss", nil)
+)
+
+type PreimageCacheConfig struct {
+	Enable  bool          `koanf:"enable"`
+	MaxSize uint64        `koanf:"max-size"`
+	Expiry  time.Duration `koanf:"expiry"`
+}
+
+var DefaultPreimageCacheConfig = PreimageCacheConfig{
+	Enable:  false,
+	MaxSize: 256 * 1024 * 1024,
+	Expiry:  time.Hour,
+}
+
+func PreimageCacheConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultPreimageCacheConfig.Enable, "cache the preimages read by validations, sharing them across validations")
+	f.Uint64(prefix+".max-size", DefaultPreimageCacheConfig.MaxSize, "maximum total size in bytes of the preimages kept in the cache, evicting the least recently used")
+	f.Duration(prefix+".expiry", DefaultPreimageCacheConfig.Expiry, "how long a preimage is kept in the cache after being resolved (0 = until evicted by size)")
+}
+
+type cachedPreimage struct {
+	preimage []byte
+	resolved time.Time
+}
+
+// preimageCache is a size-bounded cache of resolved preimages shared across validations,
+// evicting the least recently used preimages, and preimages resolved longer ago than its expiry.
+type preimageCache struct {
+	mutex   sync.Mutex
+	cache   *containers.LruCache[preimageKey, cachedPreimage]
+	size    uint64
+	maxSize uint64
+	expiry  time.Duration
+	hits    uint64
+	misses  uint64
+}
+
+func newPreimageCache(config *PreimageCacheConfig) *preimageCache {
+	c := &preimageCache{
+		maxSize: config.MaxSize,
+		expiry:  config.Expiry,
+	}
+	// bounded by the total size of the preimages rather than by their number
+	c.cache = containers.NewLruCacheWithOnEvict(math.MaxInt32, func(_ preimageKey, cached cachedPreimage) {
+		c.size -= uint64(len(cached.preimage))
+	})
+	return c
+}
+
+func (c *preimageCache) get(key preimageKey, now time.Time) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cached, ok := c.cache.Get(key)
+	if ok && c.expiry > 0 && now.Sub(cached.resolved) >= c.expiry {
+		c.cache.Remove(key)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		preimageCacheMissCounter.Inc(1)
+		return nil, false
+	}
+	c.hits++
+	preimageCacheHitCounter.Inc(1)
+	return cached.preimage, true
+}
+
+// stats returns the number of cache hits and misses so far
+func (c *preimageCache) stats() (uint64, uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.hits, c.misses
+}
+
+func (c *preimageCache) add(key preimageKey, preimage []byte, now time.Time) {
+	if uint64(len(preimage)) > c.maxSize {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.cache.Remove(key)
+	c.cache.Add(key, cachedPreimage{preimage: preimage, resolved: now})
+	c.size += uint64(len(preimage))
+	for c.size > c.maxSize {
+		c.cache.RemoveOldest()
+	}
+}
+
+// cachePreimageResolver wraps resolver so that the preimages it resolves are kept in cache,
+// and requests of a cached preimage are answered without calling resolver again.
+func cachePreimageResolver(resolver GoPreimageResolver, cache *preimageCache) GoPreimageResolver {
+	return func(ty arbutil.PreimageType, hash common.Hash) ([]byte, error) {
+		key := preimageKey{ty, hash}
+		if preimage, ok := cache.get(key, time.Now()); ok {
+			return preimage, nil
+		}
+		preimage, err := resolver(ty, hash)
+		if err != nil {
+			return nil, err
+		}
+		cache.add(key, preimage, time.Now())
+		return preimage, nil
+	}
+}
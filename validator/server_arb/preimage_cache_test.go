@@ -0,0 +1,117 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_arb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+func TestPreimageCacheSharedAcrossValidations(t *testing.T) {
+	preimages := make(map[common.Hash][]byte)
+	var shared, firstOnly, secondOnly []common.Hash
+	addPreimage := func(hashes *[]common.Hash, preimage string) {
+		hash := crypto.Keccak256Hash([]byte(preimage))
+		preimages[hash] = []byte(preimage)
+		*hashes = append(*hashes, hash)
+	}
+	addPreimage(&shared, "shared code")
+	addPreimage(&shared, "shared account")
+	addPreimage(&firstOnly, "first block transaction")
+	addPreimage(&secondOnly, "second block transaction")
+
+	config := DefaultArbitratorSpawnerConfig
+	config.PreimageCache.Enable = true
+	spawner, err := NewArbitratorSpawner(nil, func() *ArbitratorSpawnerConfig { return &config })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validate := func(id uint64, hashes ...[]common.Hash) {
+		entry := &validator.ValidationInput{
+			Id:        id,
+			Preimages: map[arbutil.PreimageType]map[common.Hash][]byte{arbutil.Keccak256PreimageType: {}},
+		}
+		for _, blockHashes := range hashes {
+			for _, hash := range blockHashes {
+				entry.Preimages[arbutil.Keccak256PreimageType][hash] = preimages[hash]
+			}
+		}
+		resolver := spawner.entryPreimageResolver(entry)
+		for _, blockHashes := range hashes {
+			for _, hash := range blockHashes {
+				preimage, err := resolver(arbutil.Keccak256PreimageType, hash)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(preimage, preimages[hash]) {
+					t.Fatal("unexpected preimage", preimage, "for hash", hash)
+				}
+			}
+		}
+	}
+	validate(1, shared, firstOnly)
+	if hits, _ := spawner.preimageCache.stats(); hits != 0 {
+		t.Fatal("expected no cache hits validating the first block, got", hits)
+	}
+	validate(2, shared, secondOnly)
+	hits, misses := spawner.preimageCache.stats()
+	if hits != uint64(len(shared)) {
+		t.Fatal("expected", len(shared), "cache hits validating the second block, got", hits)
+	}
+	if misses != uint64(len(shared)+len(firstOnly)+len(secondOnly)) {
+		t.Fatal("unexpected number of cache misses", misses)
+	}
+}
+
+func TestPreimageCacheEviction(t *testing.T) {
+	cache := newPreimageCache(&PreimageCacheConfig{Enable: true, MaxSize: 2, Expiry: time.Minute})
+	keyA := preimageKey{arbutil.Keccak256PreimageType, common.HexToHash("0xa")}
+	keyB := preimageKey{arbutil.Keccak256PreimageType, common.HexToHash("0xb")}
+	keyC := preimageKey{arbutil.Keccak256PreimageType, common.HexToHash("0xc")}
+	now := time.Now()
+	cache.add(keyA, []byte{0xa}, now)
+	cache.add(keyB, []byte{0xb}, now)
+	// A is more recently used than B, so adding C evicts B
+	if _, ok := cache.get(keyA, now); !ok {
+		t.Fatal("expected preimage A to be cached")
+	}
+	cache.add(keyC, []byte{0xc}, now)
+	if _, ok := cache.get(keyB, now); ok {
+		t.Fatal("expected least recently used preimage B to be evicted")
+	}
+	if _, ok := cache.get(keyC, now.Add(time.Minute-time.Second)); !ok {
+		t.Fatal("expected preimage C to be cached before its expiry")
+	}
+	if _, ok := cache.get(keyC, now.Add(time.Minute)); ok {
+		t.Fatal("expected preimage C to expire")
+	}
+	// the cache is bounded by the size of its preimages, so a larger preimage evicts several
+	cache.add(keyB, []byte{0xb}, now)
+	cache.add(keyC, []byte{0xc, 0xc}, now)
+	if _, ok := cache.get(keyA, now); ok {
+		t.Fatal("expected preimage A to be evicted by a larger preimage")
+	}
+	if _, ok := cache.get(keyB, now); ok {
+		t.Fatal("expected preimage B to be evicted by a larger preimage")
+	}
+	if _, ok := cache.get(keyC, now); !ok {
+		t.Fatal("expected preimage C to be cached")
+	}
+	if cache.size != 2 {
+		t.Fatal("expected the cache to hold 2 bytes, got", cache.size)
+	}
+	// preimages larger than the cache aren't cached
+	cache.add(keyA, []byte{0xa, 0xa, 0xa}, now)
+	if _, ok := cache.get(keyA, now); ok {
+		t.Fatal("expected preimage larger than the cache not to be cached")
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_arb
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+type preimageKey struct {
+	ty   arbutil.PreimageType
+	hash common.Hash
+}
+
+// inflightPreimage is a preimage being resolved, shared by every request for it until it's resolved
+type inflightPreimage struct {
+	done     chan struct{}
+	preimage []byte
+	err      error
+}
+
+// limitPreimageResolver wraps resolver so that at most concurrency preimages are resolved at once,
+// with excess requests waiting for a slot, and so that concurrent requests for the same preimage
+// are coalesced into a single call. A concurrency of 0 means unlimited.
+func limitPreimageResolver(resolver GoPreimageResolver, concurrency int) GoPreimageResolver {
+	var slots chan struct{}
+	if concurrency > 0 {
+		slots = make(chan struct{}, concurrency)
+	}
+	var mutex sync.Mutex
+	inflight := make(map[preimageKey]*inflightPreimage)
+	return func(ty arbutil.PreimageType, hash common.Hash) ([]byte, error) {
+		key := preimageKey{ty, hash}
+		mutex.Lock()
+		if request, ok := inflight[key]; ok {
+			mutex.Unlock()
+			<-request.done
+			return request.preimage, request.err
+		}
+		request := &inflightPreimage{done: make(chan struct{})}
+		inflight[key] = request
+		mutex.Unlock()
+
+		if slots != nil {
+			slots <- struct{}{}
+		}
+		request.preimage, request.err = resolver(ty, hash)
+		if slots != nil {
+			<-slots
+		}
+
+		mutex.Lock()
+		delete(inflight, key)
+		mutex.Unlock()
+		close(request.done)
+		return request.preimage, request.err
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_arb
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+type trackingPreimageResolver struct {
+	mutex   sync.Mutex
+	calls   map[common.Hash]int
+	running atomic.Int32
+	maxSeen atomic.Int32
+	release chan struct{}
+}
+
+func (r *trackingPreimageResolver) resolve(ty arbutil.PreimageType, hash common.Hash) ([]byte, error) {
+	r.mutex.Lock()
+	r.calls[hash]++
+	r.mutex.Unlock()
+	running := r.running.Add(1)
+	defer r.running.Add(-1)
+	for {
+		seen := r.maxSeen.Load()
+		if running <= seen || r.maxSeen.CompareAndSwap(seen, running) {
+			break
+		}
+	}
+	<-r.release
+	return hash.Bytes(), nil
+}
+
+func TestLimitPreimageResolver(t *testing.T) {
+	tracker := &trackingPreimageResolver{
+		calls:   make(map[common.Hash]int),
+		release: make(chan struct{}),
+	}
+	concurrency := 2
+	resolver := limitPreimageResolver(tracker.resolve, concurrency)
+
+	var wg sync.WaitGroup
+	request := func(hash common.Hash) {
+		defer wg.Done()
+		preimage, err := resolver(arbutil.Keccak256PreimageType, hash)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if common.BytesToHash(preimage) != hash {
+			t.Error("unexpected preimage", preimage, "for hash", hash)
+		}
+	}
+	duplicated := common.HexToHash("0xd0")
+	for i := 0; i < 6; i++ {
+		wg.Add(2)
+		go request(common.BigToHash(big.NewInt(int64(i))))
+		go request(duplicated)
+	}
+	// let every request reach the limiter before any resolution completes
+	time.Sleep(100 * time.Millisecond)
+	if running := tracker.running.Load(); running != int32(concurrency) {
+		t.Fatal("expected", concurrency, "resolutions running, got", running)
+	}
+	close(tracker.release)
+	wg.Wait()
+
+	if maxSeen := tracker.maxSeen.Load(); maxSeen > int32(concurrency) {
+		t.Fatal("concurrency limit", concurrency, "exceeded, saw", maxSeen, "resolutions at once")
+	}
+	if calls := tracker.calls[duplicated]; calls != 1 {
+		t.Fatal("expected concurrent requests of the same preimage to be coalesced, got", calls, "calls")
+	}
+	if len(tracker.calls) != 7 {
+		t.Fatal("expected each distinct preimage to be resolved, got", tracker.calls)
+	}
+}
@@ -31,6 +31,8 @@ type ArbitratorSpawnerConfig struct {
 	Execution                   MachineCacheConfig           `koanf:"execution" reload:"hot"` // hot reloading for new executions only
 	ExecutionRunTimeout         time.Duration                `koanf:"execution-run-timeout" reload:"hot"`
 	RedisValidationServerConfig redis.ValidationServerConfig `koanf:"redis-validation-server-config"`
+	PreimageResolverConcurrency int                          `koanf:"preimage-resolver-concurrency"`
+	PreimageCache               PreimageCacheConfig          `koanf:"preimage-cache"`
 }
 
 type ArbitratorSpawnerConfigFecher func() *ArbitratorSpawnerConfig
@@ -41,6 +43,8 @@ var DefaultArbitratorSpawnerConfig = ArbitratorSpawnerConfig{
 	Execution:                   DefaultMachineCacheConfig,
 	ExecutionRunTimeout:         time.Minute * 15,
 	RedisValidationServerConfig: redis.DefaultValidationServerConfig,
+	PreimageResolverConcurrency: 16,
+	PreimageCache:               DefaultPreimageCacheConfig,
 }
 
 func ArbitratorSpawnerConfigAddOptions(prefix string, f *pflag.FlagSet) {
@@ -49,6 +53,8 @@ func ArbitratorSpawnerConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	f.String(prefix+".output-path", DefaultArbitratorSpawnerConfig.OutputPath, "path to write machines to")
 	MachineCacheConfigConfigAddOptions(prefix+".execution", f)
 	redis.ValidationServerConfigAddOptions(prefix+".redis-validation-server-config", f)
+	f.Int(prefix+".preimage-resolver-concurrency", DefaultArbitratorSpawnerConfig.PreimageResolverConcurrency, "maximum number of preimages missing from validation inputs to resolve at once with the preimage resolver, if one is set (0 = unlimited)")
+	PreimageCacheConfigAddOptions(prefix+".preimage-cache", f)
 }
 
 func DefaultArbitratorSpawnerConfigFetcher() *ArbitratorSpawnerConfig {
@@ -73,6 +79,10 @@ type ArbitratorSpawner struct {
 	// Oreder of wrappers is important. The first wrapper is the innermost.
 	machineWrappers []MachineWrapper
 	config          ArbitratorSpawnerConfigFecher
+	// resolves preimages missing from validation inputs, if set
+	preimageResolver GoPreimageResolver
+	// caches the preimages read by validations, if enabled
+	preimageCache *preimageCache
 }
 
 func WithWrapper(wrapper MachineWrapper) SpawnerOption {
@@ -81,6 +91,15 @@ func WithWrapper(wrapper MachineWrapper) SpawnerOption {
 	}
 }
 
+// WithPreimageResolver makes the spawner resolve preimages missing from validation inputs
+// with the given resolver, instead of failing the validation. Resolutions are limited to
+// the configured preimage resolver concurrency, and concurrent requests of a preimage are coalesced.
+func WithPreimageResolver(resolver GoPreimageResolver) SpawnerOption {
+	return func(s *ArbitratorSpawner) {
+		s.preimageResolver = resolver
+	}
+}
+
 func NewArbitratorSpawner(locator *server_common.MachineLocator, config ArbitratorSpawnerConfigFecher, opts ...SpawnerOption) (*ArbitratorSpawner, error) {
 	// TODO: preload machines
 	spawner := &ArbitratorSpawner{
@@ -92,6 +111,12 @@ func NewArbitratorSpawner(locator *server_common.MachineLocator, config Arbitrat
 	for _, opt := range opts {
 		opt(spawner)
 	}
+	if spawner.preimageResolver != nil {
+		spawner.preimageResolver = limitPreimageResolver(spawner.preimageResolver, config().PreimageResolverConcurrency)
+	}
+	if cacheConfig := &config().PreimageCache; cacheConfig.Enable {
+		spawner.preimageCache = newPreimageCache(cacheConfig)
+	}
 	return spawner, nil
 }
 
@@ -112,15 +137,28 @@ func (s *ArbitratorSpawner) Name() string {
 	return "arbitrator"
 }
 
-func (v *ArbitratorSpawner) loadEntryToMachine(_ context.Context, entry *validator.ValidationInput, mach *ArbitratorMachine) error {
+// entryPreimageResolver resolves the preimages of the validation entry, falling back to
+// the spawner's preimage resolver, if set, for preimages missing from it.
+// With the preimage cache enabled, the preimages are looked up in it first.
+func (v *ArbitratorSpawner) entryPreimageResolver(entry *validator.ValidationInput) GoPreimageResolver {
 	resolver := func(ty arbutil.PreimageType, hash common.Hash) ([]byte, error) {
 		// Check if it's a known preimage
 		if preimage, ok := entry.Preimages[ty][hash]; ok {
 			return preimage, nil
 		}
+		if v.preimageResolver != nil {
+			return v.preimageResolver(ty, hash)
+		}
 		return nil, errors.New("preimage not found")
 	}
-	if err := mach.SetPreimageResolver(resolver); err != nil {
+	if v.preimageCache != nil {
+		return cachePreimageResolver(resolver, v.preimageCache)
+	}
+	return resolver
+}
+
+func (v *ArbitratorSpawner) loadEntryToMachine(_ context.Context, entry *validator.ValidationInput, mach *ArbitratorMachine) error {
+	if err := mach.SetPreimageResolver(v.entryPreimageResolver(entry)); err != nil {
 		return err
 	}
 	err := mach.SetGlobalState(entry.StartState)
@@ -215,7 +253,7 @@ func (v *ArbitratorSpawner) Launch(entry *validator.ValidationInput, moduleRoot
 		defer v.count.Add(-1)
 		return v.execute(ctx, entry, moduleRoot)
 	})
-	return server_common.NewValRun(promise, moduleRoot)
+	return server_common.NewValRun(promise, moduleRoot, server_common.WithSpawnerName(v.Name()))
 }
 
 func (v *ArbitratorSpawner) Room() int {
@@ -0,0 +1,134 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_common
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/util/containers"
+	"github.com/offchainlabs/nitro/util/stopwaiter"
+	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/server_api"
+)
+
+const DefaultValidationCacheCapacity = 1024
+
+type validationCacheKey struct {
+	moduleRoot common.Hash
+	inputHash  common.Hash
+}
+
+// CachingSpawner wraps a validation spawner, memoizing the results of successful validations by module root
+// and validation input content, so validating the same input again returns the cached result instantly.
+type CachingSpawner struct {
+	stopwaiter.StopWaiter
+	validator.ValidationSpawner
+
+	cacheMutex sync.Mutex
+	cache      *containers.LruCache[validationCacheKey, validator.GoGlobalState]
+}
+
+type CachingSpawnerOption func(*cachingSpawnerConfig)
+
+type cachingSpawnerConfig struct {
+	capacity int
+}
+
+// WithCacheCapacity sets the maximum number of validation results cached, evicting the least recently used.
+func WithCacheCapacity(capacity int) CachingSpawnerOption {
+	return func(c *cachingSpawnerConfig) {
+		c.capacity = capacity
+	}
+}
+
+func NewCachingSpawner(inner validator.ValidationSpawner, opts ...CachingSpawnerOption) *CachingSpawner {
+	config := cachingSpawnerConfig{capacity: DefaultValidationCacheCapacity}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &CachingSpawner{
+		ValidationSpawner: inner,
+		cache:             containers.NewLruCache[validationCacheKey, validator.GoGlobalState](config.capacity),
+	}
+}
+
+func (s *CachingSpawner) Start(ctx context.Context) error {
+	s.StopWaiter.Start(ctx, s)
+	return s.ValidationSpawner.Start(ctx)
+}
+
+func (s *CachingSpawner) Stop() {
+	s.StopWaiter.StopAndWait()
+	s.ValidationSpawner.Stop()
+}
+
+// Launch looks the input up in the cache in the background, as hashing a large input takes a while,
+// and launches the validation on the inner spawner if it isn't cached.
+func (s *CachingSpawner) Launch(entry *validator.ValidationInput, moduleRoot common.Hash) validator.ValidationRun {
+	run := &cachingValRun{}
+	promise := stopwaiter.LaunchPromiseThread[validator.GoGlobalState](s, func(ctx context.Context) (validator.GoGlobalState, error) {
+		key := validationCacheKey{moduleRoot: moduleRoot, inputHash: server_api.HashValidationInput(entry)}
+		s.cacheMutex.Lock()
+		state, cached := s.cache.Get(key)
+		s.cacheMutex.Unlock()
+		if cached {
+			return state, nil
+		}
+		inner := s.ValidationSpawner.Launch(entry, moduleRoot)
+		defer inner.Cancel()
+		run.inner.Store(&inner)
+		state, err := inner.Await(ctx)
+		if err != nil {
+			return validator.GoGlobalState{}, err
+		}
+		s.cacheMutex.Lock()
+		s.cache.Add(key, state)
+		s.cacheMutex.Unlock()
+		return state, nil
+	})
+	run.ValRun = NewValRun(promise, moduleRoot)
+	return run
+}
+
+// cachingValRun reports the spawner name and resources used of the inner spawner's validation, if it wasn't cached.
+type cachingValRun struct {
+	*ValRun
+	inner atomic.Pointer[validator.ValidationRun]
+}
+
+func (r *cachingValRun) SpawnerName() string {
+	if inner := r.inner.Load(); inner != nil {
+		return validator.RunSpawnerName(*inner)
+	}
+	return ""
+}
+
+func (r *cachingValRun) ResourceUsage() (validator.ResourceUsage, bool) {
+	if inner := r.inner.Load(); inner != nil && r.Ready() {
+		return validator.RunResourceUsage(*inner)
+	}
+	return validator.ResourceUsage{}, false
+}
+
+// CachingExecutionSpawner caches the validations of an execution spawner as CachingSpawner does, passing
+// its execution runs through.
+type CachingExecutionSpawner struct {
+	*CachingSpawner
+	execution validator.ExecutionSpawner
+}
+
+func NewCachingExecutionSpawner(inner validator.ExecutionSpawner, opts ...CachingSpawnerOption) *CachingExecutionSpawner {
+	return &CachingExecutionSpawner{
+		CachingSpawner: NewCachingSpawner(inner, opts...),
+		execution:      inner,
+	}
+}
+
+func (s *CachingExecutionSpawner) CreateExecutionRun(wasmModuleRoot common.Hash, input *validator.ValidationInput, useBoldMachine bool) containers.PromiseInterface[validator.ExecutionRun] {
+	return s.execution.CreateExecutionRun(wasmModuleRoot, input, useBoldMachine)
+}
@@ -0,0 +1,89 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+
+	"github.com/offchainlabs/nitro/util/containers"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+// countingSpawner validates an input to a state derived from its batch, failing inputs without batches.
+type countingSpawner struct {
+	launches int
+}
+
+func (s *countingSpawner) Launch(entry *validator.ValidationInput, moduleRoot common.Hash) validator.ValidationRun {
+	s.launches++
+	if len(entry.BatchInfo) == 0 {
+		return NewValRun(containers.NewReadyPromise(validator.GoGlobalState{}, errors.New("no batches")), moduleRoot)
+	}
+	state := validator.GoGlobalState{BlockHash: common.BytesToHash(entry.BatchInfo[0].Data), Batch: entry.BatchInfo[0].Number}
+	return NewValRun(containers.NewReadyPromise(state, nil), moduleRoot)
+}
+
+func (s *countingSpawner) WasmModuleRoots() ([]common.Hash, error) { return nil, nil }
+func (s *countingSpawner) Start(context.Context) error             { return nil }
+func (s *countingSpawner) Stop()                                   {}
+func (s *countingSpawner) Name() string                            { return "counting" }
+func (s *countingSpawner) StylusArchs() []rawdb.WasmTarget         { return nil }
+func (s *countingSpawner) Room() int                               { return 1 }
+
+func TestCachingSpawner(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inner := &countingSpawner{}
+	spawner := NewCachingSpawner(inner, WithCacheCapacity(2))
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer spawner.Stop()
+	rootA := common.HexToHash("0xa")
+	rootB := common.HexToHash("0xb")
+	input := func(data string) *validator.ValidationInput {
+		return &validator.ValidationInput{Id: 1, BatchInfo: []validator.BatchInfo{{Number: 1, Data: []byte(data)}}}
+	}
+	validate := func(entry *validator.ValidationInput, moduleRoot common.Hash, expectedLaunches int) validator.GoGlobalState {
+		t.Helper()
+		run := spawner.Launch(entry, moduleRoot)
+		if run.WasmModuleRoot() != moduleRoot {
+			t.Fatalf("run has module root %v, expected %v", run.WasmModuleRoot(), moduleRoot)
+		}
+		state, err := run.Await(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inner.launches != expectedLaunches {
+			t.Fatalf("inner spawner launched %d validations, expected %d", inner.launches, expectedLaunches)
+		}
+		return state
+	}
+
+	first := validate(input("first"), rootA, 1)
+	// an input with the same content is served from the cache
+	if cached := validate(input("first"), rootA, 1); cached != first {
+		t.Fatalf("cached state %v doesn't match %v", cached, first)
+	}
+	// a different module root or input content isn't
+	validate(input("first"), rootB, 2)
+	validate(input("second"), rootA, 3)
+	// the least recently used result was evicted
+	validate(input("first"), rootA, 4)
+
+	// failed validations aren't cached
+	for i := 0; i < 2; i++ {
+		if _, err := spawner.Launch(&validator.ValidationInput{Id: 2}, rootA).Await(ctx); err == nil {
+			t.Fatal("expected validation without batches to fail")
+		}
+	}
+	if inner.launches != 6 {
+		t.Fatalf("inner spawner launched %d validations, expected 6", inner.launches)
+	}
+}
@@ -63,15 +63,56 @@ func (l *MachineLoader[M]) GetMachine(ctx context.Context, moduleRoot common.Has
 	return status.Await(ctx)
 }
 
+// Preload loads the machine of moduleRoot ahead of its first use. A failed load isn't kept,
+// so that the machine is loaded again on its first use.
+func (l *MachineLoader[M]) Preload(ctx context.Context, moduleRoot common.Hash) error {
+	_, err := l.GetMachine(ctx, moduleRoot)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	l.mapMutex.Lock()
+	defer l.mapMutex.Unlock()
+	if status := l.machines[moduleRoot]; status != nil && status.Ready() {
+		if _, statusErr := status.Current(); statusErr != nil {
+			delete(l.machines, moduleRoot)
+		}
+	}
+	return err
+}
+
+// ForEachReadyMachine runs runme on every machine that has been loaded, skipping machines still loading
+// and machines that failed to load.
 func (l *MachineLoader[M]) ForEachReadyMachine(runme func(*M)) {
 	l.mapMutex.Lock()
 	defer l.mapMutex.Unlock()
 	for _, stat := range l.machines {
 		if stat.Ready() {
 			machine, err := stat.Current()
-			if err != nil {
+			if err == nil {
 				runme(machine)
 			}
 		}
 	}
 }
+
+// ReadyModuleRoots returns the subset of moduleRoots that can be validated right now:
+// roots whose machine has been loaded, and roots not yet requested for which canLoad reports true.
+// Roots whose machine failed to load are excluded, as are roots still loading that canLoad rejects.
+func (l *MachineLoader[M]) ReadyModuleRoots(moduleRoots []common.Hash, canLoad func(common.Hash) bool) []common.Hash {
+	l.mapMutex.Lock()
+	defer l.mapMutex.Unlock()
+	var ready []common.Hash
+	for _, moduleRoot := range moduleRoots {
+		status := l.machines[moduleRoot]
+		if status != nil && status.Ready() {
+			if _, err := status.Current(); err == nil {
+				ready = append(ready, moduleRoot)
+			}
+			continue
+		}
+		if canLoad(moduleRoot) {
+			ready = append(ready, moduleRoot)
+		}
+	}
+	return ready
+}
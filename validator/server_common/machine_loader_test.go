@@ -0,0 +1,50 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestForEachReadyMachine(t *testing.T) {
+	loaded, failed, loading := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+	release := make(chan struct{})
+	defer close(release)
+	loader := NewMachineLoader(nil, func(ctx context.Context, moduleRoot common.Hash) (*common.Hash, error) {
+		switch moduleRoot {
+		case failed:
+			return nil, errors.New("machine failed to load")
+		case loading:
+			<-release
+		}
+		return &moduleRoot, nil
+	})
+	ctx := context.Background()
+	if _, err := loader.GetMachine(ctx, loaded); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loader.GetMachine(ctx, failed); err == nil {
+		t.Fatal("expected machine to fail to load")
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := loader.GetMachine(cancelled, loading); err == nil {
+		t.Fatal("expected machine to still be loading")
+	}
+
+	var visited []common.Hash
+	loader.ForEachReadyMachine(func(machine *common.Hash) {
+		if machine == nil {
+			t.Fatal("ran on a machine that failed to load")
+		}
+		visited = append(visited, *machine)
+	})
+	if len(visited) != 1 || visited[0] != loaded {
+		t.Fatal("expected to run on the loaded machine only, ran on", visited)
+	}
+}
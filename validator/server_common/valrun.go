@@ -1,6 +1,8 @@
 package server_common
 
 import (
+	"sync/atomic"
+
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/offchainlabs/nitro/util/containers"
@@ -10,15 +12,56 @@ import (
 type ValRun struct {
 	containers.PromiseInterface[validator.GoGlobalState]
 	root common.Hash
+	// name of the spawner which ran the validation, e.g. identifying its compiler backend, if known
+	spawnerName string
+	// resources used by the validation, stored by it before completing, if reported
+	usage *atomic.Pointer[validator.ResourceUsage]
 }
 
 func (r *ValRun) WasmModuleRoot() common.Hash {
 	return r.root
 }
 
-func NewValRun(promise containers.PromiseInterface[validator.GoGlobalState], root common.Hash) *ValRun {
-	return &ValRun{
+// SpawnerName returns the name of the spawner which ran the validation, or "" if unknown.
+func (r *ValRun) SpawnerName() string {
+	return r.spawnerName
+}
+
+// ResourceUsage returns the resources used by the validation, if it completed and they were reported.
+func (r *ValRun) ResourceUsage() (validator.ResourceUsage, bool) {
+	if r.usage == nil || !r.Ready() {
+		return validator.ResourceUsage{}, false
+	}
+	usage := r.usage.Load()
+	if usage == nil {
+		return validator.ResourceUsage{}, false
+	}
+	return *usage, true
+}
+
+type ValRunOption func(*ValRun)
+
+// WithSpawnerName attributes the validation run to the spawner with the given name.
+func WithSpawnerName(name string) ValRunOption {
+	return func(r *ValRun) {
+		r.spawnerName = name
+	}
+}
+
+// WithResourceUsage makes the run report the resources used stored in usage by the validation before completing.
+func WithResourceUsage(usage *atomic.Pointer[validator.ResourceUsage]) ValRunOption {
+	return func(r *ValRun) {
+		r.usage = usage
+	}
+}
+
+func NewValRun(promise containers.PromiseInterface[validator.GoGlobalState], root common.Hash, opts ...ValRunOption) *ValRun {
+	run := &ValRun{
 		PromiseInterface: promise,
 		root:             root,
 	}
+	for _, opt := range opts {
+		opt(run)
+	}
+	return run
 }
@@ -0,0 +1,39 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_common
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/util/containers"
+	"github.com/offchainlabs/nitro/validator"
+)
+
+func TestValRunResourceUsage(t *testing.T) {
+	root := common.HexToHash("0x1")
+	if _, ok := validator.RunResourceUsage(NewValRun(containers.NewReadyPromise(validator.GoGlobalState{}, nil), root)); ok {
+		t.Fatal("run without resource usage reported some")
+	}
+
+	var usage atomic.Pointer[validator.ResourceUsage]
+	promise := containers.NewPromise[validator.GoGlobalState](nil)
+	run := NewValRun(&promise, root, WithResourceUsage(&usage))
+	expected := validator.ResourceUsage{PeakWasmMemory: 1 << 20, CPUTime: 3 * time.Second}
+	usage.Store(&expected)
+	if _, ok := validator.RunResourceUsage(run); ok {
+		t.Fatal("pending run reported resource usage")
+	}
+	promise.Produce(validator.GoGlobalState{})
+	reported, ok := validator.RunResourceUsage(run)
+	if !ok {
+		t.Fatal("completed run didn't report resource usage")
+	}
+	if reported != expected {
+		t.Fatalf("run reported resource usage %+v, expected %+v", reported, expected)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrJitSpawnerCircuitOpen is returned instead of launching a validation against a module root whose
+// validations failed too many times in a row, until the circuit breaker's cooldown elapses.
+var ErrJitSpawnerCircuitOpen = errors.New("jit spawner circuit open")
+
+const (
+	// number of module roots whose circuit is open or half-open
+	jitCircuitOpenMetric     = "arb/validator/jit/circuit/open"
+	jitCircuitRejectedMetric = "arb/validator/jit/circuit/rejected"
+)
+
+// CircuitBreakerState is the circuit breaker state of a module root.
+type CircuitBreakerState struct {
+	ModuleRoot common.Hash
+	// ConsecutiveFailures is the number of validations which failed since the last which succeeded
+	ConsecutiveFailures int
+	// OpenUntil is when launching validations is allowed again, if the circuit is open
+	OpenUntil time.Time
+}
+
+// Open returns whether validations against the module root are refused at the given time.
+func (s CircuitBreakerState) Open(now time.Time) bool {
+	return now.Before(s.OpenUntil)
+}
+
+type circuitBreaker struct {
+	consecutiveFailures int
+	// zero until the circuit first opens
+	openUntil time.Time
+	// while half-open, when the probe in flight is given up on, letting another one through
+	probeUntil time.Time
+}
+
+// checkCircuit returns an ErrJitSpawnerCircuitOpen error if the circuit of moduleRoot is open. Once the cooldown
+// elapses the circuit is half-open, and a single validation is let through as a probe, in which case probe is true.
+func (v *JitSpawner) checkCircuit(moduleRoot common.Hash, now time.Time) (probe bool, err error) {
+	config := v.config()
+	if config.CircuitBreakerFailures <= 0 {
+		return false, nil
+	}
+	v.circuitsMutex.Lock()
+	defer v.circuitsMutex.Unlock()
+	breaker, ok := v.circuits[moduleRoot]
+	if !ok || breaker.openUntil.IsZero() {
+		return false, nil
+	}
+	if now.Before(breaker.openUntil) {
+		v.metrics.IncCounter(jitCircuitRejectedMetric, 1)
+		return false, fmt.Errorf("%w for module root %v after %d consecutive failures, retrying in %v", ErrJitSpawnerCircuitOpen, moduleRoot, breaker.consecutiveFailures, breaker.openUntil.Sub(now))
+	}
+	if now.Before(breaker.probeUntil) {
+		v.metrics.IncCounter(jitCircuitRejectedMetric, 1)
+		return false, fmt.Errorf("%w for module root %v while a probe validation is in flight", ErrJitSpawnerCircuitOpen, moduleRoot)
+	}
+	// a probe which never reports back, e.g. as it failed before running, stops blocking others after the cooldown
+	breaker.probeUntil = now.Add(config.CircuitBreakerCooldown)
+	return true, nil
+}
+
+// countsAgainstCircuit returns whether a validation error says something about the module root, i.e. its
+// machine couldn't be loaded or its compiler backends disagree, as opposed to the validation timing out,
+// exceeding its memory limit, failing on its input, or being cancelled or refused.
+func countsAgainstCircuit(err error) bool {
+	return errors.Is(err, errMachineUnavailable) || errors.Is(err, ErrJitBackendMismatch)
+}
+
+// updateCircuitOpenMetric reports the number of module roots whose circuit is open or half-open.
+// The circuits mutex must be held.
+func (v *JitSpawner) updateCircuitOpenMetric() {
+	open := 0
+	for _, breaker := range v.circuits {
+		if !breaker.openUntil.IsZero() {
+			open++
+		}
+	}
+	v.metrics.UpdateGauge(jitCircuitOpenMetric, int64(open))
+}
+
+// recordCircuitResult counts a completed validation against moduleRoot, opening its circuit for the cooldown
+// once the configured number of consecutive validations failed. While the circuit is half-open after the
+// cooldown, a single failure opens it again, and a success closes it. If the validation was let through as
+// the half-open circuit's probe, another probe is let through if it failed for a reason not counted.
+func (v *JitSpawner) recordCircuitResult(moduleRoot common.Hash, err error, now time.Time, probe bool) {
+	config := v.config()
+	if config.CircuitBreakerFailures <= 0 {
+		return
+	}
+	v.circuitsMutex.Lock()
+	defer v.circuitsMutex.Unlock()
+	breaker, ok := v.circuits[moduleRoot]
+	if ok && probe {
+		breaker.probeUntil = time.Time{}
+	}
+	if err != nil && !countsAgainstCircuit(err) {
+		return
+	}
+	if err == nil {
+		if ok {
+			if !breaker.openUntil.IsZero() {
+				log.Info("jit spawner circuit closed after a successful validation", "moduleRoot", moduleRoot)
+			}
+			delete(v.circuits, moduleRoot)
+			v.updateCircuitOpenMetric()
+		}
+		return
+	}
+	if v.circuits == nil {
+		v.circuits = make(map[common.Hash]*circuitBreaker)
+	}
+	if !ok {
+		breaker = &circuitBreaker{}
+		v.circuits[moduleRoot] = breaker
+	}
+	breaker.consecutiveFailures++
+	if breaker.consecutiveFailures >= config.CircuitBreakerFailures && !now.Before(breaker.openUntil) {
+		breaker.openUntil = now.Add(config.CircuitBreakerCooldown)
+		v.updateCircuitOpenMetric()
+		log.Error("jit spawner circuit opened, refusing validations of module root", "moduleRoot", moduleRoot, "consecutiveFailures", breaker.consecutiveFailures, "cooldown", config.CircuitBreakerCooldown, "err", err)
+	}
+}
+
+// CircuitBreakerStates returns the circuit breaker state of each module root with failed validations
+// since its last successful one, ordered by module root.
+func (v *JitSpawner) CircuitBreakerStates() []CircuitBreakerState {
+	v.circuitsMutex.Lock()
+	defer v.circuitsMutex.Unlock()
+	states := make([]CircuitBreakerState, 0, len(v.circuits))
+	for moduleRoot, breaker := range v.circuits {
+		states = append(states, CircuitBreakerState{
+			ModuleRoot:          moduleRoot,
+			ConsecutiveFailures: breaker.consecutiveFailures,
+			OpenUntil:           breaker.openUntil,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].ModuleRoot.Cmp(states[j].ModuleRoot) < 0 })
+	return states
+}
@@ -0,0 +1,121 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/validator"
+)
+
+func TestJitSpawnerCircuitBreaker(t *testing.T) {
+	config := DefaultJitSpawnerConfig
+	config.CircuitBreakerFailures = 3
+	config.CircuitBreakerCooldown = time.Minute
+	sink := newBufferingSink()
+	spawner := &JitSpawner{config: func() *JitSpawnerConfig { return &config }, metrics: sink}
+	broken := common.HexToHash("0x01")
+	healthy := common.HexToHash("0x02")
+	failure := fmt.Errorf("%w: %w", errMachineUnavailable, errors.New("missing wasm target"))
+	now := time.Now()
+
+	expectOpen := func(at time.Time, open bool) {
+		t.Helper()
+		_, err := spawner.checkCircuit(broken, at)
+		if open && !errors.Is(err, ErrJitSpawnerCircuitOpen) {
+			t.Fatalf("expected the circuit to be open, got %v", err)
+		}
+		if !open && err != nil {
+			t.Fatalf("expected the circuit to be closed, got %v", err)
+		}
+	}
+
+	// only errors of the module root's machine count, not cancelled, timed out or oversized validations
+	for _, err := range []error{context.Canceled, ErrValidationTimeout, context.DeadlineExceeded, ErrWasmMemoryHardLimit, errors.New("execution failed")} {
+		for i := 0; i < config.CircuitBreakerFailures; i++ {
+			spawner.recordCircuitResult(broken, err, now, false)
+		}
+		expectOpen(now, false)
+	}
+	for i := 0; i < config.CircuitBreakerFailures-1; i++ {
+		spawner.recordCircuitResult(broken, failure, now, false)
+		spawner.recordCircuitResult(healthy, failure, now, false)
+		expectOpen(now, false)
+	}
+	spawner.recordCircuitResult(healthy, nil, now, false)
+	spawner.recordCircuitResult(broken, failure, now, false)
+	expectOpen(now, true)
+	if sink.gauges[jitCircuitOpenMetric] != 1 {
+		t.Fatalf("expected one open circuit, got %d", sink.gauges[jitCircuitOpenMetric])
+	}
+	if _, err := spawner.checkCircuit(healthy, now); err != nil {
+		t.Fatalf("failures against one module root opened the circuit of another: %v", err)
+	}
+	states := spawner.CircuitBreakerStates()
+	if len(states) != 1 || states[0].ModuleRoot != broken || !states[0].Open(now) || states[0].ConsecutiveFailures != config.CircuitBreakerFailures {
+		t.Fatalf("unexpected circuit breaker states %+v", states)
+	}
+
+	// launches are refused without running
+	rejected := sink.buffered[jitCircuitRejectedMetric]
+	run := spawner.Launch(&validator.ValidationInput{Id: 1}, broken)
+	if _, err := run.Await(context.Background()); !errors.Is(err, ErrJitSpawnerCircuitOpen) {
+		t.Fatalf("expected launch to be refused with an open circuit, got %v", err)
+	}
+	if sink.buffered[jitCircuitRejectedMetric] != rejected+1 {
+		t.Fatalf("expected the launch to be counted as rejected, got %d rejections", sink.buffered[jitCircuitRejectedMetric])
+	}
+
+	// once the cooldown elapses, a single probe is let through the half-open circuit
+	halfOpen := now.Add(config.CircuitBreakerCooldown)
+	probe, err := spawner.checkCircuit(broken, halfOpen)
+	if err != nil || !probe {
+		t.Fatalf("expected a probe through the half-open circuit, got %v, %v", probe, err)
+	}
+	expectOpen(halfOpen, true)
+	// a probe failing for a reason which doesn't count lets another one through
+	spawner.recordCircuitResult(broken, context.Canceled, halfOpen, true)
+	if probe, err = spawner.checkCircuit(broken, halfOpen); err != nil || !probe {
+		t.Fatalf("expected another probe after the first was cancelled, got %v, %v", probe, err)
+	}
+	// and a failed probe opens the circuit again
+	spawner.recordCircuitResult(broken, failure, halfOpen, true)
+	expectOpen(halfOpen, true)
+	expectOpen(halfOpen.Add(config.CircuitBreakerCooldown-time.Second), true)
+
+	// a probe which never reports back stops blocking others after the cooldown
+	reopened := halfOpen.Add(config.CircuitBreakerCooldown)
+	if probe, err = spawner.checkCircuit(broken, reopened); err != nil || !probe {
+		t.Fatalf("expected a probe through the half-open circuit, got %v, %v", probe, err)
+	}
+	expectOpen(reopened.Add(config.CircuitBreakerCooldown-time.Second), true)
+	lostProbe := reopened.Add(config.CircuitBreakerCooldown)
+	if probe, err = spawner.checkCircuit(broken, lostProbe); err != nil || !probe {
+		t.Fatalf("expected another probe after the first was lost, got %v, %v", probe, err)
+	}
+
+	// and a successful probe closes it
+	spawner.recordCircuitResult(broken, nil, lostProbe, true)
+	expectOpen(lostProbe, false)
+	if sink.gauges[jitCircuitOpenMetric] != 0 {
+		t.Fatalf("expected no open circuits, got %d", sink.gauges[jitCircuitOpenMetric])
+	}
+	spawner.recordCircuitResult(broken, failure, lostProbe, false)
+	expectOpen(lostProbe, false)
+
+	// the breaker is disabled by default
+	config.CircuitBreakerFailures = 0
+	for i := 0; i < 10; i++ {
+		spawner.recordCircuitResult(healthy, failure, lostProbe, false)
+	}
+	if _, err := spawner.checkCircuit(healthy, lostProbe); err != nil {
+		t.Fatalf("disabled circuit breaker opened: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/validator"
+)
+
+var _ validator.InFlightLister = (*JitSpawner)(nil)
+
+// trackValidation registers a validation until untrackValidation, returning its id. Cancelling
+// it through its handle calls cancel, which should cancel the validation's context.
+func (v *JitSpawner) trackValidation(entry *validator.ValidationInput, moduleRoot common.Hash, cancel func()) uint64 {
+	v.trackedMutex.Lock()
+	defer v.trackedMutex.Unlock()
+	if v.tracked == nil {
+		v.tracked = make(map[uint64]validator.ValidationHandle)
+	}
+	id := v.nextTrackedId
+	v.nextTrackedId++
+	v.tracked[id] = validator.NewValidationHandle(id, moduleRoot, entry.Id, time.Now(), cancel)
+	return id
+}
+
+func (v *JitSpawner) untrackValidation(id uint64) {
+	v.trackedMutex.Lock()
+	defer v.trackedMutex.Unlock()
+	delete(v.tracked, id)
+}
+
+// InFlight returns the validations launched and not yet completed, including those waiting
+// for a worker, in launch order.
+func (v *JitSpawner) InFlight() []validator.ValidationHandle {
+	v.trackedMutex.Lock()
+	defer v.trackedMutex.Unlock()
+	handles := make([]validator.ValidationHandle, 0, len(v.tracked))
+	for _, handle := range v.tracked {
+		handles = append(handles, handle)
+	}
+	sort.Slice(handles, func(i, j int) bool { return handles[i].Id < handles[j].Id })
+	return handles
+}
@@ -13,18 +13,34 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/metrics"
 
 	"github.com/offchainlabs/nitro/util/arbmath"
+	"github.com/offchainlabs/nitro/util/metricsutil"
 	"github.com/offchainlabs/nitro/validator"
 )
 
-var jitWasmMemoryUsage = metrics.NewRegisteredHistogram("jit/wasm/memoryusage", nil, metrics.NewBoundedHistogramSample())
+const (
+	jitWasmMemoryUsageMetric = "jit/wasm/memoryusage"
+	jitCPUTimeMetric         = "jit/cputime"
+)
+
+var ErrWasmMemoryHardLimit = errors.New("jit wasm exceeded memory hard limit")
+
+// memoryWatchInterval is how often a running validation's memory is checked against the hard limit
+const memoryWatchInterval = 100 * time.Millisecond
+
+// ErrValidationTimeout is returned when a validation doesn't complete within the max execution time,
+// which says nothing about the validated block's correctness, e.g. so that it can be retried elsewhere.
+var ErrValidationTimeout = errors.New("jit validation exceeded max execution time")
 
 type JitMachine struct {
 	binary               string
@@ -32,9 +48,10 @@ type JitMachine struct {
 	stdin                io.WriteCloser
 	wasmMemoryUsageLimit int
 	maxExecutionTime     time.Duration
+	metrics              metricsutil.Sink
 }
 
-func createJitMachine(jitBinary string, binaryPath string, cranelift bool, wasmMemoryUsageLimit int, maxExecutionTime time.Duration, _ common.Hash, fatalErrChan chan error) (*JitMachine, error) {
+func createJitMachine(jitBinary string, binaryPath string, cranelift bool, wasmMemoryUsageLimit int, maxExecutionTime time.Duration, _ common.Hash, fatalErrChan chan error, metricsSink metricsutil.Sink) (*JitMachine, error) {
 	invocation := []string{"--binary", binaryPath, "--forks"}
 	if cranelift {
 		invocation = append(invocation, "--cranelift")
@@ -58,6 +75,7 @@ func createJitMachine(jitBinary string, binaryPath string, cranelift bool, wasmM
 		stdin:                stdin,
 		wasmMemoryUsageLimit: wasmMemoryUsageLimit,
 		maxExecutionTime:     maxExecutionTime,
+		metrics:              metricsSink,
 	}
 	return machine, nil
 }
@@ -69,22 +87,94 @@ func (machine *JitMachine) close() {
 	}
 }
 
+// checkWasmMemoryUsage logs a warning if memoryUsed exceeds the soft limit, and fails if it exceeds
+// the hard limit. A hard limit of 0 disables it.
+func (machine *JitMachine) checkWasmMemoryUsage(memoryUsed uint64, wasmMemoryHardLimit int) error {
+	// #nosec G115
+	if wasmMemoryHardLimit > 0 && memoryUsed > uint64(wasmMemoryHardLimit) {
+		log.Error("memory used by jit wasm exceeds the wasm memory hard limit", "limit", wasmMemoryHardLimit, "memoryUsed", memoryUsed)
+		return fmt.Errorf("%w: used %v bytes, limit %v bytes", ErrWasmMemoryHardLimit, memoryUsed, wasmMemoryHardLimit)
+	}
+	// #nosec G115
+	if memoryUsed > uint64(machine.wasmMemoryUsageLimit) {
+		log.Warn("memory used by jit wasm exceeds the wasm memory usage limit", "limit", machine.wasmMemoryUsageLimit, "memoryUsed", memoryUsed)
+	}
+	return nil
+}
+
+// residentMemory returns the resident memory of process pid in bytes, as reported by Linux's procfs.
+func residentMemory(pid int) (uint64, error) {
+	statm, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected statm contents %q", statm)
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	// #nosec G115
+	return pages * uint64(os.Getpagesize()), nil
+}
+
+// watchMemory kills the process running a validation once its resident memory exceeds the hard limit,
+// and returns whether it did. It stops watching when ctx is done, or if the memory can't be read.
+func watchMemory(ctx context.Context, pid int, wasmMemoryHardLimit int) bool {
+	ticker := time.NewTicker(memoryWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+		memoryUsed, err := residentMemory(pid)
+		if err != nil {
+			log.Debug("stopped watching jit validation memory", "pid", pid, "err", err)
+			return false
+		}
+		// #nosec G115
+		if memoryUsed > uint64(wasmMemoryHardLimit) {
+			log.Error("jit validation exceeds the wasm memory hard limit, killing it", "pid", pid, "limit", wasmMemoryHardLimit, "memoryUsed", memoryUsed)
+			if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
+				log.Warn("error killing jit validation process", "pid", pid, "err", err)
+			}
+			return true
+		}
+	}
+}
+
+// prove validates entry, returning an ErrValidationTimeout error if it takes longer than the max execution time.
+// On success, it also returns the resources the validation used.
 func (machine *JitMachine) prove(
-	ctxIn context.Context, entry *validator.ValidationInput,
-) (validator.GoGlobalState, error) {
+	ctx context.Context, entry *validator.ValidationInput, wasmMemoryHardLimit int,
+) (validator.GoGlobalState, validator.ResourceUsage, error) {
+	state, usage, err := machine.proveUntil(ctx, entry, wasmMemoryHardLimit, time.Now().Add(machine.maxExecutionTime))
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return state, usage, fmt.Errorf("%w of %v: %w", ErrValidationTimeout, machine.maxExecutionTime, err)
+	}
+	return state, usage, err
+}
+
+func (machine *JitMachine) proveUntil(
+	ctxIn context.Context, entry *validator.ValidationInput, wasmMemoryHardLimit int, timeout time.Time,
+) (validator.GoGlobalState, validator.ResourceUsage, error) {
 	ctx, cancel := context.WithCancel(ctxIn)
 	defer cancel() // ensure our cleanup functions run when we're done
 	state := validator.GoGlobalState{}
+	usage := validator.ResourceUsage{}
 
-	timeout := time.Now().Add(machine.maxExecutionTime)
 	tcp, err := net.ListenTCP("tcp4", &net.TCPAddr{
 		IP: []byte{127, 0, 0, 1},
 	})
 	if err != nil {
-		return state, err
+		return state, usage, err
 	}
 	if err := tcp.SetDeadline(timeout); err != nil {
-		return state, err
+		return state, usage, err
 	}
 	go func() {
 		<-ctx.Done()
@@ -97,13 +187,13 @@ func (machine *JitMachine) prove(
 
 	// Tell the spawner process about the new tcp port
 	if _, err := machine.stdin.Write([]byte(address)); err != nil {
-		return state, err
+		return state, usage, err
 	}
 
 	// Wait for the forked process to connect
 	conn, err := tcp.Accept()
 	if err != nil {
-		return state, fmt.Errorf("error waiting for jit machine to connect back to validator: %w", err)
+		return state, usage, fmt.Errorf("error waiting for jit machine to connect back to validator: %w", err)
 	}
 	go func() {
 		<-ctx.Done()
@@ -113,10 +203,10 @@ func (machine *JitMachine) prove(
 		}
 	}()
 	if err := conn.SetReadDeadline(timeout); err != nil {
-		return state, err
+		return state, usage, err
 	}
 	if err := conn.SetWriteDeadline(timeout); err != nil {
-		return state, err
+		return state, usage, err
 	}
 
 	writeExact := func(data []byte) error {
@@ -148,16 +238,16 @@ func (machine *JitMachine) prove(
 
 	// send global state
 	if err := writeUint64(entry.StartState.Batch); err != nil {
-		return state, err
+		return state, usage, err
 	}
 	if err := writeUint64(entry.StartState.PosInBatch); err != nil {
-		return state, err
+		return state, usage, err
 	}
 	if err := writeExact(entry.StartState.BlockHash[:]); err != nil {
-		return state, err
+		return state, usage, err
 	}
 	if err := writeExact(entry.StartState.SendRoot[:]); err != nil {
-		return state, err
+		return state, usage, err
 	}
 
 	const successByte = 0x0
@@ -172,53 +262,53 @@ func (machine *JitMachine) prove(
 	// send inbox
 	for _, batch := range entry.BatchInfo {
 		if err := writeExact(another); err != nil {
-			return state, err
+			return state, usage, err
 		}
 		if err := writeUint64(batch.Number); err != nil {
-			return state, err
+			return state, usage, err
 		}
 		if err := writeBytes(batch.Data); err != nil {
-			return state, err
+			return state, usage, err
 		}
 	}
 	if err := writeExact(success); err != nil {
-		return state, err
+		return state, usage, err
 	}
 
 	// send delayed inbox
 	if entry.HasDelayedMsg {
 		if err := writeExact(another); err != nil {
-			return state, err
+			return state, usage, err
 		}
 		if err := writeUint64(entry.DelayedMsgNr); err != nil {
-			return state, err
+			return state, usage, err
 		}
 		if err := writeBytes(entry.DelayedMsg); err != nil {
-			return state, err
+			return state, usage, err
 		}
 	}
 	if err := writeExact(success); err != nil {
-		return state, err
+		return state, usage, err
 	}
 
 	// send known preimages
 	preimageTypes := entry.Preimages
 	if err := writeIntAsUint32(len(preimageTypes)); err != nil {
-		return state, err
+		return state, usage, err
 	}
 	for ty, preimages := range preimageTypes {
 		if err := writeUint8(uint8(ty)); err != nil {
-			return state, err
+			return state, usage, err
 		}
 		if err := writeIntAsUint32(len(preimages)); err != nil {
-			return state, err
+			return state, usage, err
 		}
 		for hash, preimage := range preimages {
 			if err := writeExact(hash[:]); err != nil {
-				return state, err
+				return state, usage, err
 			}
 			if err := writeBytes(preimage); err != nil {
-				return state, err
+				return state, usage, err
 			}
 		}
 	}
@@ -230,26 +320,26 @@ func (machine *JitMachine) prove(
 	if len(userWasms) == 0 {
 		for arch, userWasms := range entry.UserWasms {
 			if len(userWasms) != 0 {
-				return state, fmt.Errorf("bad stylus arch for validation input. got: %v, expected: %v", arch, localTarget)
+				return state, usage, fmt.Errorf("bad stylus arch for validation input. got: %v, expected: %v", arch, localTarget)
 			}
 		}
 	}
 
 	if err := writeIntAsUint32(len(userWasms)); err != nil {
-		return state, err
+		return state, usage, err
 	}
 	for moduleHash, program := range userWasms {
 		if err := writeExact(moduleHash[:]); err != nil {
-			return state, err
+			return state, usage, err
 		}
 		if err := writeBytes(program); err != nil {
-			return state, err
+			return state, usage, err
 		}
 	}
 
 	// signal that we are done sending global state
 	if err := writeExact(ready); err != nil {
-		return state, err
+		return state, usage, err
 	}
 
 	read := func(count uint64) ([]byte, error) {
@@ -275,51 +365,78 @@ func (machine *JitMachine) prove(
 		return common.BytesToHash(slice), nil
 	}
 
+	// the forked process running the validation reports its pid, so that its memory can be
+	// watched while it steps rather than only once it's done
+	pid, err := readUint64()
+	if err != nil {
+		return state, usage, fmt.Errorf("failed to read pid from Jit machine: %w", err)
+	}
+	var memoryExceeded atomic.Bool
+	if wasmMemoryHardLimit > 0 {
+		go func() {
+			// #nosec G115
+			if watchMemory(ctx, int(pid), wasmMemoryHardLimit) {
+				memoryExceeded.Store(true)
+				cancel()
+			}
+		}()
+	}
+
 	for {
 		kind, err := read(1)
+		if memoryExceeded.Load() {
+			return validator.GoGlobalState{}, usage, fmt.Errorf("%w: killed the validation at more than %v bytes", ErrWasmMemoryHardLimit, wasmMemoryHardLimit)
+		}
 		if err != nil {
-			return state, err
+			return state, usage, err
 		}
 		switch kind[0] {
 		case failureByte:
 			length, err := readUint64()
 			if err != nil {
-				return state, err
+				return state, usage, err
 			}
 			message, err := read(length)
 			if err != nil {
-				return state, err
+				return state, usage, err
 			}
 			log.Error("Jit Machine Failure", "message", string(message))
-			return state, errors.New(string(message))
+			return state, usage, errors.New(string(message))
 		case successByte:
 			if state.Batch, err = readUint64(); err != nil {
-				return state, err
+				return state, usage, err
 			}
 			if state.PosInBatch, err = readUint64(); err != nil {
-				return state, err
+				return state, usage, err
 			}
 			if state.BlockHash, err = readHash(); err != nil {
-				return state, err
+				return state, usage, err
 			}
 			if state.SendRoot, err = readHash(); err != nil {
-				return state, err
+				return state, usage, err
 			}
 			memoryUsed, err := readUint64()
 			if err != nil {
-				return state, fmt.Errorf("failed to read memory usage from Jit machine: %w", err)
+				return state, usage, fmt.Errorf("failed to read memory usage from Jit machine: %w", err)
 			}
 			// #nosec G115
-			if memoryUsed > uint64(machine.wasmMemoryUsageLimit) {
-				log.Warn("memory used by jit wasm exceeds the wasm memory usage limit", "limit", machine.wasmMemoryUsageLimit, "memoryUsed", memoryUsed)
+			machine.metrics.UpdateHistogram(jitWasmMemoryUsageMetric, int64(memoryUsed))
+			if err := machine.checkWasmMemoryUsage(memoryUsed, wasmMemoryHardLimit); err != nil {
+				return validator.GoGlobalState{}, usage, err
+			}
+			cpuTimeMicros, err := readUint64()
+			if err != nil {
+				return state, usage, fmt.Errorf("failed to read cpu time from Jit machine: %w", err)
 			}
 			// #nosec G115
-			jitWasmMemoryUsage.Update(int64(memoryUsed))
-			return state, nil
+			cpuTime := time.Duration(cpuTimeMicros) * time.Microsecond
+			machine.metrics.UpdateHistogram(jitCPUTimeMetric, cpuTime.Microseconds())
+			usage = validator.ResourceUsage{PeakWasmMemory: memoryUsed, CPUTime: cpuTime}
+			return state, usage, nil
 		default:
 			message := "inter-process communication failure"
 			log.Error("Jit Machine Failure", "message", message)
-			return state, errors.New("inter-process communication failure")
+			return state, usage, errors.New("inter-process communication failure")
 		}
 	}
 }
@@ -0,0 +1,96 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/nitro/validator"
+)
+
+func TestCheckWasmMemoryUsage(t *testing.T) {
+	machine := &JitMachine{wasmMemoryUsageLimit: 100}
+
+	// exceeding only the soft limit warns but doesn't fail
+	if err := machine.checkWasmMemoryUsage(150, 0); err != nil {
+		t.Fatal("expected no error with the hard limit disabled, got", err)
+	}
+	if err := machine.checkWasmMemoryUsage(150, 200); err != nil {
+		t.Fatal("expected no error below the hard limit, got", err)
+	}
+	if err := machine.checkWasmMemoryUsage(200, 200); err != nil {
+		t.Fatal("expected no error at the hard limit, got", err)
+	}
+	err := machine.checkWasmMemoryUsage(201, 200)
+	if !errors.Is(err, ErrWasmMemoryHardLimit) {
+		t.Fatal("expected hard limit error, got", err)
+	}
+}
+
+func TestWatchMemoryKillsProcess(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("watching memory requires procfs")
+	}
+	process := exec.Command("sleep", "60")
+	if err := process.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- process.Wait() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if !watchMemory(ctx, process.Process.Pid, 1) {
+		t.Fatal("expected the process to be killed for exceeding the hard limit")
+	}
+	select {
+	case <-exited:
+	case <-time.After(10 * time.Second):
+		t.Fatal("process still running after exceeding the hard limit")
+	}
+
+	// a process within the limit is left alone until the validation is done
+	process = exec.Command("sleep", "60")
+	if err := process.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = process.Process.Kill() }()
+	ctx, cancel = context.WithTimeout(context.Background(), 3*memoryWatchInterval)
+	defer cancel()
+	if watchMemory(ctx, process.Process.Pid, 1<<40) {
+		t.Fatal("expected a process within the hard limit not to be killed")
+	}
+}
+
+type discardWriteCloser struct{}
+
+func (discardWriteCloser) Write(p []byte) (int, error) { return len(p), nil }
+
+func (discardWriteCloser) Close() error { return nil }
+
+func TestProveTimeout(t *testing.T) {
+	// the jit process never connects back, so the validation runs out of time
+	machine := &JitMachine{stdin: discardWriteCloser{}, maxExecutionTime: 50 * time.Millisecond}
+	_, _, err := machine.prove(context.Background(), &validator.ValidationInput{}, 0)
+	if !errors.Is(err, ErrValidationTimeout) {
+		t.Fatal("expected validation timeout error, got", err)
+	}
+	if validationErrorClass(err) != "timeout" {
+		t.Fatal("expected validation timeout to be classified as a timeout, got", validationErrorClass(err))
+	}
+
+	// cancellation isn't a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	machine.maxExecutionTime = time.Minute
+	_, _, err = machine.prove(ctx, &validator.ValidationInput{}, 0)
+	if err == nil || errors.Is(err, ErrValidationTimeout) {
+		t.Fatal("expected cancelled validation to fail without a timeout error, got", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/offchainlabs/nitro/util/metricsutil"
 	"github.com/offchainlabs/nitro/validator/server_common"
 )
 
@@ -18,6 +19,7 @@ type JitMachineConfig struct {
 	ProverBinPath        string
 	JitCranelift         bool
 	WasmMemoryUsageLimit int
+	MaxConcurrentLoads   int
 }
 
 var DefaultJitMachineConfig = JitMachineConfig{
@@ -51,23 +53,59 @@ func getJitPath() (string, error) {
 
 type JitMachineLoader struct {
 	server_common.MachineLoader[JitMachine]
-	stopped bool
+	locator       *server_common.MachineLocator
+	proverBinPath string
+	stopped       bool
 }
 
-func NewJitMachineLoader(config *JitMachineConfig, locator *server_common.MachineLocator, maxExecutionTime time.Duration, fatalErrChan chan error) (*JitMachineLoader, error) {
+func NewJitMachineLoader(config *JitMachineConfig, locator *server_common.MachineLocator, maxExecutionTime time.Duration, fatalErrChan chan error, metricsSink metricsutil.Sink) (*JitMachineLoader, error) {
 	jitPath, err := getJitPath()
 	if err != nil {
 		return nil, err
 	}
 	createMachineThreadFunc := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
-		binPath := filepath.Join(locator.GetMachinePath(moduleRoot), config.ProverBinPath)
-		return createJitMachine(jitPath, binPath, config.JitCranelift, config.WasmMemoryUsageLimit, maxExecutionTime, moduleRoot, fatalErrChan)
+		binPath := proverBinPath(locator, config.ProverBinPath, moduleRoot)
+		return createJitMachine(jitPath, binPath, config.JitCranelift, config.WasmMemoryUsageLimit, maxExecutionTime, moduleRoot, fatalErrChan, metricsSink)
 	}
+	createMachineThreadFunc = limitConcurrentLoads(config.MaxConcurrentLoads, createMachineThreadFunc)
 	return &JitMachineLoader{
 		MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachineThreadFunc),
+		locator:       locator,
+		proverBinPath: config.ProverBinPath,
 	}, nil
 }
 
+func proverBinPath(locator *server_common.MachineLocator, binName string, moduleRoot common.Hash) string {
+	return filepath.Join(locator.GetMachinePath(moduleRoot), binName)
+}
+
+// ReadyModuleRoots returns the advertised module roots that have a loaded machine,
+// or whose prover binary is present so that a machine can be loaded on demand.
+func (j *JitMachineLoader) ReadyModuleRoots() []common.Hash {
+	return j.MachineLoader.ReadyModuleRoots(j.locator.ModuleRoots(), func(moduleRoot common.Hash) bool {
+		_, err := os.Stat(proverBinPath(j.locator, j.proverBinPath, moduleRoot))
+		return err == nil
+	})
+}
+
+// limitConcurrentLoads wraps createMachine so that at most limit machines are
+// loaded at once, with excess loads waiting for a slot. A limit of 0 means unlimited.
+func limitConcurrentLoads[M any](limit int, createMachine func(context.Context, common.Hash) (*M, error)) func(context.Context, common.Hash) (*M, error) {
+	if limit <= 0 {
+		return createMachine
+	}
+	slots := make(chan struct{}, limit)
+	return func(ctx context.Context, moduleRoot common.Hash) (*M, error) {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-slots }()
+		return createMachine(ctx, moduleRoot)
+	}
+}
+
 func (j *JitMachineLoader) Stop() {
 	if j.stopped {
 		return
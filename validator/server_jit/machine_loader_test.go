@@ -0,0 +1,120 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/validator/server_common"
+)
+
+func TestLimitConcurrentLoads(t *testing.T) {
+	const limit = 2
+	const roots = 6
+	var loading, maxLoading atomic.Int32
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		current := loading.Add(1)
+		defer loading.Add(-1)
+		for {
+			prev := maxLoading.Load()
+			if current <= prev || maxLoading.CompareAndSwap(prev, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return &JitMachine{}, nil
+	}
+	limited := limitConcurrentLoads(limit, createMachine)
+
+	var wg sync.WaitGroup
+	for i := 0; i < roots; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := limited(context.Background(), common.Hash{byte(i + 1)}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if maxLoading.Load() > limit {
+		t.Fatalf("%d machines loaded concurrently, limit is %d", maxLoading.Load(), limit)
+	}
+	if maxLoading.Load() == 0 {
+		t.Fatal("no machines loaded")
+	}
+}
+
+func writeTestMachine(t *testing.T, dir string, moduleRoot common.Hash, withProver bool) {
+	t.Helper()
+	machineDir := filepath.Join(dir, moduleRoot.Hex())
+	if err := os.MkdirAll(machineDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(machineDir, "module-root.txt"), []byte(moduleRoot.Hex()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if withProver {
+		if err := os.WriteFile(filepath.Join(machineDir, DefaultJitMachineConfig.ProverBinPath), []byte{}, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadyModuleRoots(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	loadable := common.HexToHash("0x01")
+	missing := common.HexToHash("0x02")
+	loaded := common.HexToHash("0x03")
+	broken := common.HexToHash("0x04")
+
+	dir := t.TempDir()
+	writeTestMachine(t, dir, loadable, true)
+	writeTestMachine(t, dir, missing, false)
+	writeTestMachine(t, dir, loaded, false)
+	writeTestMachine(t, dir, broken, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locator.ModuleRoots()) != 4 {
+		t.Fatalf("expected 4 advertised module roots, got %v", locator.ModuleRoots())
+	}
+
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		if moduleRoot == broken {
+			return nil, errors.New("failed to load machine")
+		}
+		return &JitMachine{}, nil
+	}
+	loader := &JitMachineLoader{
+		MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+		locator:       locator,
+		proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+	}
+	if _, err := loader.GetMachine(ctx, loaded); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loader.GetMachine(ctx, broken); err == nil {
+		t.Fatal("expected error loading broken machine")
+	}
+
+	ready := loader.ReadyModuleRoots()
+	sort.Slice(ready, func(i, j int) bool { return ready[i].Cmp(ready[j]) < 0 })
+	if len(ready) != 2 || ready[0] != loadable || ready[1] != loaded {
+		t.Fatalf("unexpected ready module roots %v, expected [%v %v]", ready, loadable, loaded)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/server_api"
+	"github.com/offchainlabs/nitro/validator/server_common"
+)
+
+// Replay validates the validation input dumped at path, in either format of server_api.MarshalValidationInput,
+// with the jit machine of moduleRoot, found as the default machine locator finds it. It's meant for reproducing
+// validation failures offline, e.g. on a developer's machine.
+func Replay(path string, moduleRoot common.Hash) (validator.GoGlobalState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return validator.GoGlobalState{}, err
+	}
+	input, err := server_api.UnmarshalValidationInput(data)
+	if err != nil {
+		return validator.GoGlobalState{}, fmt.Errorf("error decoding validation input %v: %w", path, err)
+	}
+	locator, err := server_common.NewMachineLocator("")
+	if err != nil {
+		return validator.GoGlobalState{}, err
+	}
+	config := DefaultJitSpawnerConfig
+	config.PreloadMachines = false
+	fatalErrChan := make(chan error, 1)
+	spawner, err := NewJitSpawner(locator, func() *JitSpawnerConfig { return &config }, fatalErrChan)
+	if err != nil {
+		return validator.GoGlobalState{}, err
+	}
+	ctx := context.Background()
+	if err := spawner.Start(ctx); err != nil {
+		return validator.GoGlobalState{}, err
+	}
+	defer spawner.Stop()
+	run := spawner.Launch(input, moduleRoot)
+	select {
+	case <-run.ReadyChan():
+		return run.Await(ctx)
+	case err := <-fatalErrChan:
+		run.Cancel()
+		return validator.GoGlobalState{}, fmt.Errorf("jit machine failed replaying %v: %w", path, err)
+	}
+}
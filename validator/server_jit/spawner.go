@@ -2,95 +2,352 @@ package server_jit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	flag "github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/offchainlabs/nitro/arbnode/resourcemanager"
 	"github.com/offchainlabs/nitro/util"
+	"github.com/offchainlabs/nitro/util/containers"
+	"github.com/offchainlabs/nitro/util/metricsutil"
 	"github.com/offchainlabs/nitro/util/stopwaiter"
 	"github.com/offchainlabs/nitro/validator"
 	"github.com/offchainlabs/nitro/validator/server_common"
 )
 
 type JitSpawnerConfig struct {
-	Workers          int           `koanf:"workers" reload:"hot"`
-	Cranelift        bool          `koanf:"cranelift"`
-	MaxExecutionTime time.Duration `koanf:"max-execution-time" reload:"hot"`
+	Workers          int            `koanf:"workers" reload:"hot"`
+	ModuleWorkers    map[string]int `koanf:"module-workers" reload:"hot"`
+	Cranelift        bool           `koanf:"cranelift"`
+	CrossCheck       bool           `koanf:"crosscheck"`
+	PreloadMachines  bool           `koanf:"preload-machines"`
+	MaxExecutionTime time.Duration  `koanf:"max-execution-time" reload:"hot"`
+	StopTimeout      time.Duration  `koanf:"stop-timeout" reload:"hot"`
 
 	// TODO: change WasmMemoryUsageLimit to a string and use resourcemanager.ParseMemLimit
-	WasmMemoryUsageLimit int `koanf:"wasm-memory-usage-limit"`
+	WasmMemoryUsageLimit      int    `koanf:"wasm-memory-usage-limit"`
+	WasmMemoryHardLimit       int    `koanf:"wasm-memory-hard-limit" reload:"hot"`
+	MemoryFreeLimit           string `koanf:"memory-free-limit"`
+	MaxConcurrentMachineLoads int    `koanf:"max-concurrent-machine-loads"`
+
+	CircuitBreakerFailures int           `koanf:"circuit-breaker-failures" reload:"hot"`
+	CircuitBreakerCooldown time.Duration `koanf:"circuit-breaker-cooldown" reload:"hot"`
+
+	Tracing     bool `koanf:"tracing"`
+	OtelMetrics bool `koanf:"otel-metrics"`
+
+	moduleWorkers map[common.Hash]int
+}
+
+func (c *JitSpawnerConfig) Validate() error {
+	moduleWorkers := make(map[common.Hash]int, len(c.ModuleWorkers))
+	for rootStr, workers := range c.ModuleWorkers {
+		var root common.Hash
+		if err := root.UnmarshalText([]byte(rootStr)); err != nil {
+			return fmt.Errorf("jit spawner config module-workers has invalid module root %q: %w", rootStr, err)
+		}
+		if workers > 0 {
+			moduleWorkers[root] = workers
+		}
+	}
+	c.moduleWorkers = moduleWorkers
+	return nil
 }
 
 type JitSpawnerConfigFecher func() *JitSpawnerConfig
 
 var DefaultJitSpawnerConfig = JitSpawnerConfig{
-	Workers:              0,
-	Cranelift:            true,
-	WasmMemoryUsageLimit: 4294967296, // 2^32 WASM memory limit
-	MaxExecutionTime:     time.Minute * 10,
+	Workers:                   0,
+	ModuleWorkers:             nil,
+	Cranelift:                 true,
+	CrossCheck:                false,
+	PreloadMachines:           true,
+	WasmMemoryUsageLimit:      4294967296, // 2^32 WASM memory limit
+	WasmMemoryHardLimit:       0,
+	MaxExecutionTime:          time.Minute * 10,
+	StopTimeout:               time.Second * 30,
+	MemoryFreeLimit:           "",
+	MaxConcurrentMachineLoads: 0,
+	CircuitBreakerFailures:    0,
+	CircuitBreakerCooldown:    time.Minute,
+	Tracing:                   false,
+	OtelMetrics:               false,
 }
 
 func JitSpawnerConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Int(prefix+".workers", DefaultJitSpawnerConfig.Workers, "number of concurrent validation threads")
+	f.StringToInt(prefix+".module-workers", DefaultJitSpawnerConfig.ModuleWorkers, "number of concurrent validation threads for specific wasm module roots, keyed by module root hex (roots not listed use workers)")
 	f.Bool(prefix+".cranelift", DefaultJitSpawnerConfig.Cranelift, "use Cranelift instead of LLVM when validating blocks using the jit-accelerated block validator")
+	f.Bool(prefix+".crosscheck", DefaultJitSpawnerConfig.CrossCheck, "validate every block under both Cranelift and LLVM, failing the validation if their results differ (for debugging determinism regressions, halves throughput)")
+	f.Bool(prefix+".preload-machines", DefaultJitSpawnerConfig.PreloadMachines, "load the machines of all available wasm module roots in the background on startup, instead of on their first validation")
 	f.Int(prefix+".wasm-memory-usage-limit", DefaultJitSpawnerConfig.WasmMemoryUsageLimit, "if memory used by a jit wasm exceeds this limit, a warning is logged")
-	f.Duration(prefix+".max-execution-time", DefaultJitSpawnerConfig.MaxExecutionTime, "if execution time used by a jit wasm exceeds this limit, a rpc error is returned")
+	f.Int(prefix+".wasm-memory-hard-limit", DefaultJitSpawnerConfig.WasmMemoryHardLimit, "if memory used by a jit validation exceeds this limit, it is killed and fails with an error (0 = disabled)")
+	f.Duration(prefix+".max-execution-time", DefaultJitSpawnerConfig.MaxExecutionTime, "if execution time used by a jit wasm exceeds this limit, the validation fails with a timeout error")
+	f.Duration(prefix+".stop-timeout", DefaultJitSpawnerConfig.StopTimeout, "maximum time to wait on stopping for validations in flight to complete, while refusing new ones")
+	f.String(prefix+".memory-free-limit", DefaultJitSpawnerConfig.MemoryFreeLimit, "minimum free-memory limit after reaching which the jit spawner defers starting new validations until memory is freed. Disabled by default, use e.g. 1GB to enable")
+	f.Int(prefix+".max-concurrent-machine-loads", DefaultJitSpawnerConfig.MaxConcurrentMachineLoads, "maximum number of jit machines for distinct module roots to load at once, excess loads are queued (0 = unlimited)")
+	f.Int(prefix+".circuit-breaker-failures", DefaultJitSpawnerConfig.CircuitBreakerFailures, "refuse validations against a module root for the circuit breaker cooldown after this many of them failed in a row (0 = disabled)")
+	f.Duration(prefix+".circuit-breaker-cooldown", DefaultJitSpawnerConfig.CircuitBreakerCooldown, "how long to refuse validations against a module root once its circuit breaker opens")
+	f.Bool(prefix+".tracing", DefaultJitSpawnerConfig.Tracing, "emit an OpenTelemetry span per validation through the globally registered tracer provider")
+	f.Bool(prefix+".otel-metrics", DefaultJitSpawnerConfig.OtelMetrics, "report the spawner's metrics through the globally registered OpenTelemetry meter provider instead of the default metrics registry")
 }
 
+const memoryPressurePollInterval = 100 * time.Millisecond
+const drainPollInterval = 50 * time.Millisecond
+
+const (
+	jitValidationsMetric        = "arb/validator/jit/validations/completed"
+	jitValidationFailuresMetric = "arb/validator/jit/validations/failed"
+	jitValidationDurationMetric = "arb/validator/jit/validations/duration"
+
+	jitValidationsLaunchedMetric = "arb/validator/jit/validations/launched"
+	jitValidationsRunningMetric  = "arb/validator/jit/validations/running"
+	// suffixed by the module root
+	jitModuleDurationMetricPrefix = "arb/validator/jit/validations/duration/"
+	jitModuleRunningMetricPrefix  = "arb/validator/jit/validations/running/"
+	// suffixed by the error class
+	jitFailureClassMetricPrefix = "arb/validator/jit/validations/failed/"
+)
+
+var errMachineUnavailable = errors.New("unable to get WASM machine")
+
+var ErrJitSpawnerDraining = errors.New("jit spawner is stopping")
+
+// ErrJitBackendMismatch is returned when cross-checking finds the compiler backends producing different global states.
+var ErrJitBackendMismatch = errors.New("jit compiler backends produced different global states")
+
+type JitSpawnerOption func(*JitSpawner)
+
 type JitSpawner struct {
 	stopwaiter.StopWaiter
-	count         atomic.Int32
 	locator       *server_common.MachineLocator
 	machineLoader *JitMachineLoader
 	config        JitSpawnerConfigFecher
+	metrics       metricsutil.Sink
+	// nil unless emitting a span per validation
+	tracer         trace.Tracer
+	tracerProvider trace.TracerProvider
+
+	// loads machines using the other compiler backend, if cross-checking
+	crossCheckLoader *JitMachineLoader
+
+	memoryFreeLimitChecker resourcemanager.LimitChecker
+
+	// inFlight counts the validations launched and not yet completed, while draining refuses new ones
+	inFlight atomic.Int32
+	draining atomic.Bool
+
+	// the validations in flight, by id
+	trackedMutex  sync.Mutex
+	tracked       map[uint64]validator.ValidationHandle
+	nextTrackedId uint64
+
+	workersMutex   sync.Mutex
+	running        map[common.Hash]int
+	runningTotal   int
+	workerReleased chan struct{}
+
+	// consecutive validation failures by module root, to refuse validations of broken ones
+	circuitsMutex sync.Mutex
+	circuits      map[common.Hash]*circuitBreaker
+}
+
+// WithMetricsSink makes the spawner and its machines report metrics to the
+// given sink instead of the default go-ethereum metrics registry.
+func WithMetricsSink(sink metricsutil.Sink) JitSpawnerOption {
+	return func(s *JitSpawner) {
+		s.metrics = sink
+	}
 }
 
-func NewJitSpawner(locator *server_common.MachineLocator, config JitSpawnerConfigFecher, fatalErrChan chan error) (*JitSpawner, error) {
-	// TODO - preload machines
+func NewJitSpawner(locator *server_common.MachineLocator, config JitSpawnerConfigFecher, fatalErrChan chan error, opts ...JitSpawnerOption) (*JitSpawner, error) {
 	machineConfig := DefaultJitMachineConfig
 	machineConfig.JitCranelift = config().Cranelift
 	machineConfig.WasmMemoryUsageLimit = config().WasmMemoryUsageLimit
+	machineConfig.MaxConcurrentLoads = config().MaxConcurrentMachineLoads
 	maxExecutionTime := config().MaxExecutionTime
-	loader, err := NewJitMachineLoader(&machineConfig, locator, maxExecutionTime, fatalErrChan)
+	spawner := &JitSpawner{
+		locator: locator,
+		config:  config,
+		metrics: metricsutil.DefaultSink,
+	}
+	if config().Tracing {
+		WithTracerProvider(otel.GetTracerProvider())(spawner)
+	}
+	if config().OtelMetrics {
+		WithMetricsSink(metricsutil.NewOtelSink(otel.GetMeterProvider(), jitInstrumentationScope))(spawner)
+	}
+	for _, opt := range opts {
+		opt(spawner)
+	}
+	loader, err := NewJitMachineLoader(&machineConfig, locator, maxExecutionTime, fatalErrChan, spawner.metrics)
 	if err != nil {
 		return nil, err
 	}
-	spawner := &JitSpawner{
-		locator:       locator,
-		machineLoader: loader,
-		config:        config,
+	spawner.machineLoader = loader
+	if config().CrossCheck {
+		crossCheckConfig := machineConfig
+		crossCheckConfig.JitCranelift = !machineConfig.JitCranelift
+		crossCheckLoader, err := NewJitMachineLoader(&crossCheckConfig, locator, maxExecutionTime, fatalErrChan, spawner.metrics)
+		if err != nil {
+			return nil, err
+		}
+		spawner.crossCheckLoader = crossCheckLoader
+		log.Warn("jit spawner cross-checking validations under both compiler backends", "backend", jitBackend(machineConfig.JitCranelift), "crossCheckBackend", jitBackend(crossCheckConfig.JitCranelift))
+	}
+	if config().MemoryFreeLimit != "" {
+		limit, err := resourcemanager.ParseMemLimit(config().MemoryFreeLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jit spawner config memory-free-limit string: %w", err)
+		}
+		limitChecker, err := resourcemanager.NewCgroupsMemoryLimitCheckerIfSupported(limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create jit spawner memory-free-limit checker: %w", err)
+		}
+		spawner.memoryFreeLimitChecker = limitChecker
 	}
 	return spawner, nil
 }
 
 func (v *JitSpawner) Start(ctx_in context.Context) error {
 	v.StopWaiter.Start(ctx_in, v)
+	if v.config().PreloadMachines {
+		v.preloadMachines(v.machineLoader)
+		if v.crossCheckLoader != nil {
+			v.preloadMachines(v.crossCheckLoader)
+		}
+	}
 	return nil
 }
 
+// preloadMachines loads the machines of all the locator's module roots in the background, so that the first
+// validation after startup doesn't wait for its machine to compile. Failures are only logged, as the machine
+// is loaded again on its first use.
+func (v *JitSpawner) preloadMachines(loader *JitMachineLoader) {
+	for _, moduleRoot := range v.locator.ModuleRoots() {
+		v.LaunchThread(func(ctx context.Context) {
+			start := time.Now()
+			if err := loader.Preload(ctx, moduleRoot); err != nil {
+				if ctx.Err() == nil {
+					log.Warn("failed to preload jit machine, loading it on first use", "moduleRoot", moduleRoot, "err", err)
+				}
+				return
+			}
+			log.Info("preloaded jit machine", "moduleRoot", moduleRoot, "elapsed", time.Since(start))
+		})
+	}
+}
+
 func (v *JitSpawner) WasmModuleRoots() ([]common.Hash, error) {
 	return v.locator.ModuleRoots(), nil
 }
 
+// ReadyWasmModuleRoots returns the subset of WasmModuleRoots for which a machine
+// is loaded or can be loaded, as opposed to every root the locator advertises.
+func (v *JitSpawner) ReadyWasmModuleRoots() ([]common.Hash, error) {
+	return v.machineLoader.ReadyModuleRoots(), nil
+}
+
 func (v *JitSpawner) StylusArchs() []rawdb.WasmTarget {
 	return []rawdb.WasmTarget{rawdb.LocalTarget()}
 }
 
+func (v *JitSpawner) isMemoryLimitExceeded() bool {
+	if v.memoryFreeLimitChecker == nil {
+		return false
+	}
+	exceeded, err := v.memoryFreeLimitChecker.IsLimitExceeded()
+	if err != nil {
+		log.Error("error checking if free-memory limit exceeded in jit spawner", "err", err)
+	}
+	return exceeded
+}
+
+// waitForMemory blocks until the free-memory limit is no longer exceeded or the context is done.
+func (v *JitSpawner) waitForMemory(ctx context.Context) error {
+	if !v.isMemoryLimitExceeded() {
+		return nil
+	}
+	log.Warn("jit spawner deferring validation due to running low on memory")
+	ticker := time.NewTicker(memoryPressurePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if !v.isMemoryLimitExceeded() {
+			return nil
+		}
+	}
+}
+
+// execute validates entry, also returning the resources used by the configured backend's validation.
 func (v *JitSpawner) execute(
 	ctx context.Context, entry *validator.ValidationInput, moduleRoot common.Hash,
-) (validator.GoGlobalState, error) {
-	machine, err := v.machineLoader.GetMachine(ctx, moduleRoot)
+) (validator.GoGlobalState, validator.ResourceUsage, error) {
+	state, usage, err := v.executeWith(ctx, v.machineLoader, entry, moduleRoot)
+	if err != nil || v.crossCheckLoader == nil {
+		return state, usage, err
+	}
+	state, err = crossCheckBackends(v.config().Cranelift, state, func() (validator.GoGlobalState, error) {
+		otherState, _, err := v.executeWith(ctx, v.crossCheckLoader, entry, moduleRoot)
+		return otherState, err
+	})
+	return state, usage, err
+}
+
+func (v *JitSpawner) executeWith(
+	ctx context.Context, loader *JitMachineLoader, entry *validator.ValidationInput, moduleRoot common.Hash,
+) (validator.GoGlobalState, validator.ResourceUsage, error) {
+	machine, err := loader.GetMachine(ctx, moduleRoot)
+	if err != nil {
+		return validator.GoGlobalState{}, validator.ResourceUsage{}, fmt.Errorf("%w: %w", errMachineUnavailable, err)
+	}
+
+	state, usage, err := machine.prove(ctx, entry, v.config().WasmMemoryHardLimit)
 	if err != nil {
-		return validator.GoGlobalState{}, fmt.Errorf("unable to get WASM machine: %w", err)
+		return state, usage, fmt.Errorf("error validating with jit machine of module root %v: %w", moduleRoot, err)
 	}
+	return state, usage, nil
+}
 
-	state, err := machine.prove(ctx, entry)
-	return state, err
+func jitBackend(cranelift bool) string {
+	if cranelift {
+		return "cranelift"
+	}
+	return "llvm"
+}
+
+// crossCheckBackends runs the validation under the other compiler backend, returning the state
+// produced by the configured backend if both agree, or else an ErrJitBackendMismatch error
+// reporting the state produced by each backend.
+func crossCheckBackends(
+	cranelift bool, state validator.GoGlobalState, executeOther func() (validator.GoGlobalState, error),
+) (validator.GoGlobalState, error) {
+	otherState, err := executeOther()
+	if err != nil {
+		return validator.GoGlobalState{}, fmt.Errorf("error cross-checking with %s backend: %w", jitBackend(!cranelift), err)
+	}
+	if otherState != state {
+		return validator.GoGlobalState{}, fmt.Errorf(
+			"%w: %s produced %v, %s produced %v",
+			ErrJitBackendMismatch, jitBackend(cranelift), state, jitBackend(!cranelift), otherState,
+		)
+	}
+	return state, nil
 }
 
 func (s *JitSpawner) Name() string {
@@ -101,23 +358,191 @@ func (s *JitSpawner) Name() string {
 }
 
 func (v *JitSpawner) Launch(entry *validator.ValidationInput, moduleRoot common.Hash) validator.ValidationRun {
-	v.count.Add(1)
+	return v.LaunchWithContext(context.Background(), entry, moduleRoot)
+}
+
+// LaunchWithContext launches a validation as Launch does, propagating the trace context of ctx to the
+// validation's span if tracing. The validation isn't cancelled along with ctx.
+func (v *JitSpawner) LaunchWithContext(traceCtx context.Context, entry *validator.ValidationInput, moduleRoot common.Hash) validator.ValidationRun {
+	parentSpan := trace.SpanContextFromContext(traceCtx)
+	v.inFlight.Add(1)
+	if v.draining.Load() {
+		v.inFlight.Add(-1)
+		return server_common.NewValRun(containers.NewReadyPromise(validator.GoGlobalState{}, ErrJitSpawnerDraining), moduleRoot, server_common.WithSpawnerName(v.Name()))
+	}
+	probe, err := v.checkCircuit(moduleRoot, time.Now())
+	if err != nil {
+		v.inFlight.Add(-1)
+		return server_common.NewValRun(containers.NewReadyPromise(validator.GoGlobalState{}, err), moduleRoot, server_common.WithSpawnerName(v.Name()))
+	}
+	v.metrics.IncCounter(jitValidationsLaunchedMetric, 1)
+	var usage atomic.Pointer[validator.ResourceUsage]
 	promise := stopwaiter.LaunchPromiseThread[validator.GoGlobalState](v, func(ctx context.Context) (validator.GoGlobalState, error) {
-		defer v.count.Add(-1)
-		return v.execute(ctx, entry, moduleRoot)
+		defer v.inFlight.Add(-1)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		defer v.untrackValidation(v.trackValidation(entry, moduleRoot, cancel))
+		// wait for memory first, so that deferred validations don't hold workers
+		if err := v.waitForMemory(ctx); err != nil {
+			return validator.GoGlobalState{}, err
+		}
+		if err := v.acquireWorker(ctx, moduleRoot); err != nil {
+			return validator.GoGlobalState{}, err
+		}
+		defer v.releaseWorker(moduleRoot)
+		ctx, span := v.startValidationSpan(ctx, parentSpan, entry, moduleRoot)
+		start := time.Now()
+		state, used, err := v.execute(ctx, entry, moduleRoot)
+		duration := time.Since(start)
+		v.recordValidation(entry.Id, moduleRoot, duration, err)
+		v.recordCircuitResult(moduleRoot, err, time.Now(), probe)
+		endValidationSpan(span, duration, err)
+		if err == nil {
+			usage.Store(&used)
+		}
+		return state, err
 	})
-	return server_common.NewValRun(promise, moduleRoot)
+	return server_common.NewValRun(promise, moduleRoot, server_common.WithSpawnerName(v.Name()), server_common.WithResourceUsage(&usage))
+}
+
+// validationErrorClass groups validation errors by cause, for failure metrics
+func validationErrorClass(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, ErrValidationTimeout), errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, ErrWasmMemoryHardLimit):
+		return "memory_limit"
+	case errors.Is(err, errMachineUnavailable):
+		return "machine_unavailable"
+	case errors.Is(err, ErrJitBackendMismatch):
+		return "backend_mismatch"
+	default:
+		return "execution"
+	}
 }
 
+// recordValidation reports the metrics of a completed validation
+func (v *JitSpawner) recordValidation(id uint64, moduleRoot common.Hash, duration time.Duration, err error) {
+	v.metrics.IncCounter(jitValidationsMetric, 1)
+	v.metrics.UpdateHistogram(jitValidationDurationMetric, duration.Milliseconds())
+	v.metrics.UpdateHistogram(jitModuleDurationMetricPrefix+moduleRoot.Hex(), duration.Milliseconds())
+	if err != nil {
+		v.metrics.IncCounter(jitValidationFailuresMetric, 1)
+		v.metrics.IncCounter(jitFailureClassMetricPrefix+validationErrorClass(err), 1)
+		log.Debug("jit validation failed", "id", id, "moduleRoot", moduleRoot, "duration", duration, "err", err)
+		return
+	}
+	log.Debug("jit validation completed", "id", id, "moduleRoot", moduleRoot, "duration", duration)
+}
+
+// workers returns the number of concurrent validations allowed across all module roots.
+func (v *JitSpawner) workers() int {
+	workers := v.config().Workers
+	if workers == 0 {
+		workers = util.GoMaxProcs()
+	}
+	return workers
+}
+
+// Room returns how many more validations can run right now, across all module roots.
 func (v *JitSpawner) Room() int {
-	avail := v.config().Workers
-	if avail == 0 {
-		avail = util.GoMaxProcs()
+	v.workersMutex.Lock()
+	defer v.workersMutex.Unlock()
+	return max(v.workers()-v.runningTotal, 0)
+}
+
+// moduleWorkers returns the number of concurrent validations allowed for moduleRoot,
+// falling back to the global worker count if the root has no limit of its own.
+// The config must have been validated.
+func (v *JitSpawner) moduleWorkers(moduleRoot common.Hash) int {
+	if workers, ok := v.config().moduleWorkers[moduleRoot]; ok {
+		return workers
 	}
-	return avail
+	return v.workers()
 }
 
+// RoomFor returns how many more validations against moduleRoot can run right now,
+// so that a burst against one root doesn't hide that another one is saturated.
+func (v *JitSpawner) RoomFor(moduleRoot common.Hash) int {
+	v.workersMutex.Lock()
+	defer v.workersMutex.Unlock()
+	return max(min(v.moduleWorkers(moduleRoot)-v.running[moduleRoot], v.workers()-v.runningTotal), 0)
+}
+
+// acquireWorker waits until a validation against moduleRoot can run within both its own limit and the global one.
+func (v *JitSpawner) acquireWorker(ctx context.Context, moduleRoot common.Hash) error {
+	for {
+		v.workersMutex.Lock()
+		if v.running == nil {
+			v.running = make(map[common.Hash]int)
+			v.workerReleased = make(chan struct{})
+		}
+		if v.running[moduleRoot] < v.moduleWorkers(moduleRoot) && v.runningTotal < v.workers() {
+			v.running[moduleRoot]++
+			v.runningTotal++
+			v.updateRunningMetrics(moduleRoot)
+			v.workersMutex.Unlock()
+			return nil
+		}
+		released := v.workerReleased
+		v.workersMutex.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// updateRunningMetrics reports the validations running in total and against moduleRoot.
+// Must be called with the workers mutex held.
+func (v *JitSpawner) updateRunningMetrics(moduleRoot common.Hash) {
+	v.metrics.UpdateGauge(jitValidationsRunningMetric, int64(v.runningTotal))
+	v.metrics.UpdateGauge(jitModuleRunningMetricPrefix+moduleRoot.Hex(), int64(v.running[moduleRoot]))
+}
+
+func (v *JitSpawner) releaseWorker(moduleRoot common.Hash) {
+	v.workersMutex.Lock()
+	defer v.workersMutex.Unlock()
+	v.running[moduleRoot]--
+	v.runningTotal--
+	v.updateRunningMetrics(moduleRoot)
+	if v.running[moduleRoot] <= 0 {
+		delete(v.running, moduleRoot)
+	}
+	// wake every waiter, since they may be waiting on different roots
+	close(v.workerReleased)
+	v.workerReleased = make(chan struct{})
+}
+
+// drain refuses new validations, and waits up to the stop timeout for those in flight to complete.
+func (v *JitSpawner) drain() {
+	v.draining.Store(true)
+	timeout := v.config().StopTimeout
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for inFlight := v.inFlight.Load(); inFlight > 0; inFlight = v.inFlight.Load() {
+		if !time.Now().Before(deadline) {
+			log.Warn("jit spawner stopping with validations still in flight", "inFlight", inFlight, "timeout", timeout)
+			return
+		}
+		<-ticker.C
+	}
+}
+
+// Stop drains the validations in flight, then cancels those still running after the stop timeout,
+// and waits for every validation thread to exit so that the metrics, logs and spans of recently
+// completed validations are emitted and flushed before the machines are torn down.
 func (v *JitSpawner) Stop() {
-	v.StopOnly()
+	v.drain()
+	v.StopAndWait()
+	metricsutil.Flush(v.metrics)
+	v.flushValidationSpans()
 	v.machineLoader.Stop()
+	if v.crossCheckLoader != nil {
+		v.crossCheckLoader.Stop()
+	}
 }
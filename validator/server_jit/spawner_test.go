@@ -0,0 +1,628 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/server_common"
+)
+
+type fakeLimitChecker struct {
+	exceeded atomic.Bool
+}
+
+func (c *fakeLimitChecker) IsLimitExceeded() (bool, error) {
+	return c.exceeded.Load(), nil
+}
+
+func (c *fakeLimitChecker) String() string { return "fake" }
+
+func TestJitSpawnerDefersUnderMemoryPressure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	moduleRoot := common.HexToHash("0x01")
+	dir := t.TempDir()
+	writeTestMachine(t, dir, moduleRoot, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loading := make(chan struct{}, 1)
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		loading <- struct{}{}
+		return nil, errors.New("failed to load machine")
+	}
+	config := DefaultJitSpawnerConfig
+	config.Workers = 2
+	config.PreloadMachines = false
+	checker := &fakeLimitChecker{}
+	checker.exceeded.Store(true)
+	spawner := &JitSpawner{
+		locator: locator,
+		machineLoader: &JitMachineLoader{
+			MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+			locator:       locator,
+			proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+		},
+		config:                 func() *JitSpawnerConfig { return &config },
+		metrics:                newBufferingSink(),
+		memoryFreeLimitChecker: checker,
+	}
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer spawner.Stop()
+
+	run := spawner.Launch(&validator.ValidationInput{Id: 1}, moduleRoot)
+	select {
+	case <-loading:
+		t.Fatal("validation started while memory limit exceeded")
+	case <-time.After(3 * memoryPressurePollInterval):
+	}
+	// a deferred validation doesn't hold a worker
+	if room := spawner.RoomFor(moduleRoot); room != 2 {
+		t.Fatal("expected room 2 while the validation is deferred, got", room)
+	}
+
+	checker.exceeded.Store(false)
+	select {
+	case <-loading:
+	case <-time.After(time.Second):
+		t.Fatal("validation still deferred after memory pressure dropped")
+	}
+	if _, err := run.Await(ctx); !errors.Is(err, errMachineUnavailable) {
+		t.Fatal("expected validation to fail without a machine, got", err)
+	}
+}
+
+func TestJitSpawnerMemoryWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	checker := &fakeLimitChecker{}
+	checker.exceeded.Store(true)
+	spawner := &JitSpawner{memoryFreeLimitChecker: checker}
+	cancel()
+	if err := spawner.waitForMemory(ctx); err == nil {
+		t.Fatal("expected error waiting for memory with cancelled context")
+	}
+}
+
+// bufferingSink holds counter updates until flushed, and keeps the latest gauge values
+type bufferingSink struct {
+	mutex    sync.Mutex
+	buffered map[string]int64
+	flushed  map[string]int64
+	gauges   map[string]int64
+}
+
+func newBufferingSink() *bufferingSink {
+	return &bufferingSink{buffered: make(map[string]int64), flushed: make(map[string]int64), gauges: make(map[string]int64)}
+}
+
+func (s *bufferingSink) UpdateGauge(name string, value int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.gauges[name] = value
+}
+
+func (s *bufferingSink) UpdateGaugeFloat64(string, float64) {}
+func (s *bufferingSink) UpdateHistogram(string, int64)      {}
+
+func (s *bufferingSink) IncCounter(name string, delta int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.buffered[name] += delta
+}
+
+func (s *bufferingSink) Flush() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for name, value := range s.buffered {
+		s.flushed[name] += value
+	}
+	s.buffered = make(map[string]int64)
+}
+
+func (s *bufferingSink) gauge(name string) int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.gauges[name]
+}
+
+func (s *bufferingSink) flushedCounter(name string) int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.flushed[name]
+}
+
+func TestJitSpawnerFlushesMetricsOnStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	moduleRoot := common.HexToHash("0x01")
+	dir := t.TempDir()
+	writeTestMachine(t, dir, moduleRoot, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		return nil, errors.New("failed to load machine")
+	}
+	sink := newBufferingSink()
+	spawner := &JitSpawner{
+		locator: locator,
+		machineLoader: &JitMachineLoader{
+			MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+			locator:       locator,
+			proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+		},
+		config:  func() *JitSpawnerConfig { return &DefaultJitSpawnerConfig },
+		metrics: sink,
+	}
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	run := spawner.Launch(&validator.ValidationInput{Id: 1}, moduleRoot)
+	if _, err := run.Await(ctx); err == nil {
+		t.Fatal("expected validation to fail without a machine")
+	}
+	if sink.flushedCounter(jitValidationsMetric) != 0 {
+		t.Fatal("expected validation metrics to be buffered before stopping")
+	}
+
+	spawner.Stop()
+	if validations := sink.flushedCounter(jitValidationsMetric); validations != 1 {
+		t.Fatalf("expected 1 validation reported after stop, got %d", validations)
+	}
+	if failures := sink.flushedCounter(jitValidationFailuresMetric); failures != 1 {
+		t.Fatalf("expected 1 failed validation reported after stop, got %d", failures)
+	}
+	if launched := sink.flushedCounter(jitValidationsLaunchedMetric); launched != 1 {
+		t.Fatalf("expected 1 launched validation reported after stop, got %d", launched)
+	}
+	if failures := sink.flushedCounter(jitFailureClassMetricPrefix + "machine_unavailable"); failures != 1 {
+		t.Fatalf("expected the failure to be classified as an unavailable machine, got %d", failures)
+	}
+}
+
+func TestValidationErrorClass(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected string
+	}{
+		{context.Canceled, "canceled"},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), "timeout"},
+		{os.ErrDeadlineExceeded, "timeout"},
+		{fmt.Errorf("error validating with jit machine: %w", ErrValidationTimeout), "timeout"},
+		{ErrWasmMemoryHardLimit, "memory_limit"},
+		{fmt.Errorf("%w: %w", errMachineUnavailable, errors.New("missing")), "machine_unavailable"},
+		{fmt.Errorf("%w: diverged", ErrJitBackendMismatch), "backend_mismatch"},
+		{errors.New("inter-process communication failure"), "execution"},
+	}
+	for _, c := range cases {
+		if class := validationErrorClass(c.err); class != c.expected {
+			t.Fatalf("error %v classified as %v, expected %v", c.err, class, c.expected)
+		}
+	}
+}
+
+func TestCrossCheckBackends(t *testing.T) {
+	state := validator.GoGlobalState{BlockHash: common.HexToHash("0x01"), Batch: 1}
+	agreeing := func() (validator.GoGlobalState, error) { return state, nil }
+	checked, err := crossCheckBackends(true, state, agreeing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checked != state {
+		t.Fatalf("expected cross-checked state %v, got %v", state, checked)
+	}
+
+	diverged := validator.GoGlobalState{BlockHash: common.HexToHash("0x02"), Batch: 1}
+	for _, cranelift := range []bool{true, false} {
+		_, err = crossCheckBackends(cranelift, state, func() (validator.GoGlobalState, error) { return diverged, nil })
+		if !errors.Is(err, ErrJitBackendMismatch) {
+			t.Fatal("expected backend mismatch, got", err)
+		}
+		// the error reports which backend produced which state
+		expected := fmt.Sprintf("%s produced %v, %s produced %v", jitBackend(cranelift), state, jitBackend(!cranelift), diverged)
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error %q to report %q", err, expected)
+		}
+	}
+
+	errOther := errors.New("llvm machine crashed")
+	_, err = crossCheckBackends(true, state, func() (validator.GoGlobalState, error) { return validator.GoGlobalState{}, errOther })
+	if !errors.Is(err, errOther) || errors.Is(err, ErrJitBackendMismatch) {
+		t.Fatal("expected cross-check execution error, got", err)
+	}
+}
+
+func TestJitSpawnerModuleWorkers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	limited := common.HexToHash("0x01")
+	other := common.HexToHash("0x02")
+	dir := t.TempDir()
+	writeTestMachine(t, dir, limited, true)
+	writeTestMachine(t, dir, other, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// machines load once released, failing the validations, so that they hold their workers until then
+	loading := make(chan common.Hash, 4)
+	release := make(chan struct{})
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		loading <- moduleRoot
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil, errors.New("failed to load machine")
+	}
+	config := DefaultJitSpawnerConfig
+	config.Workers = 2
+	config.ModuleWorkers = map[string]int{limited.Hex(): 1}
+	config.PreloadMachines = false
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	sink := newBufferingSink()
+	spawner := &JitSpawner{
+		locator: locator,
+		machineLoader: &JitMachineLoader{
+			MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+			locator:       locator,
+			proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+		},
+		config:  func() *JitSpawnerConfig { return &config },
+		metrics: sink,
+	}
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := []validator.ValidationRun{
+		spawner.Launch(&validator.ValidationInput{Id: 1}, limited),
+		spawner.Launch(&validator.ValidationInput{Id: 2}, limited),
+		spawner.Launch(&validator.ValidationInput{Id: 3}, other),
+	}
+	// a burst against the limited root doesn't starve the other one
+	for i := 0; i < 2; i++ {
+		select {
+		case <-loading:
+		case <-time.After(time.Second):
+			t.Fatal("validations didn't start")
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	if running := sink.gauge(jitModuleRunningMetricPrefix + limited.Hex()); running != 1 {
+		t.Fatal("expected 1 validation running against the limited root, got", running)
+	}
+	if running := sink.gauge(jitModuleRunningMetricPrefix + other.Hex()); running != 1 {
+		t.Fatal("expected 1 validation running against the other root, got", running)
+	}
+	if running := sink.gauge(jitValidationsRunningMetric); running != 2 {
+		t.Fatal("expected 2 validations running, got", running)
+	}
+	if room := spawner.RoomFor(limited); room != 0 {
+		t.Fatal("expected no room left for the limited root, got", room)
+	}
+	// roots without a limit of their own fall back to the global workers, which are all taken
+	if room := spawner.RoomFor(other); room != 0 {
+		t.Fatal("expected no room left for the other root, got", room)
+	}
+	if room := spawner.Room(); room != 0 {
+		t.Fatal("expected no room left, got", room)
+	}
+	// the other root is within its own limit, but waits for a global worker
+	runs = append(runs, spawner.Launch(&validator.ValidationInput{Id: 4}, other))
+	time.Sleep(100 * time.Millisecond)
+	if running := sink.gauge(jitValidationsRunningMetric); running != 2 {
+		t.Fatal("expected 2 validations running within the global workers, got", running)
+	}
+
+	close(release)
+	for _, run := range runs {
+		if _, err := run.Await(ctx); !errors.Is(err, errMachineUnavailable) {
+			t.Fatal("expected validation to fail without a machine, got", err)
+		}
+	}
+	if running := sink.gauge(jitModuleRunningMetricPrefix + limited.Hex()); running != 0 {
+		t.Fatal("expected no validation running against the limited root, got", running)
+	}
+	spawner.Stop()
+	if validations := sink.flushedCounter(jitValidationsMetric); validations != 4 {
+		t.Fatalf("expected 4 validations completed, got %d", validations)
+	}
+	if room := spawner.Room(); room != 2 {
+		t.Fatal("expected all workers free after the validations completed, got room", room)
+	}
+
+	// module roots must be given in full
+	config.ModuleWorkers = map[string]int{"0x01": 1}
+	if err := config.Validate(); err == nil {
+		t.Fatal("expected a module root that isn't 32 bytes of hex to be rejected")
+	}
+}
+
+func TestJitSpawnerDrainsOnStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	moduleRoot := common.HexToHash("0x01")
+	dir := t.TempDir()
+	writeTestMachine(t, dir, moduleRoot, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	errLoadReleased := errors.New("machine load released")
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		close(loading)
+		select {
+		case <-release:
+			return nil, errLoadReleased
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	config := DefaultJitSpawnerConfig
+	config.StopTimeout = 10 * time.Second
+	config.PreloadMachines = false
+	spawner := &JitSpawner{
+		locator: locator,
+		machineLoader: &JitMachineLoader{
+			MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+			locator:       locator,
+			proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+		},
+		config:  func() *JitSpawnerConfig { return &config },
+		metrics: newBufferingSink(),
+	}
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	inFlight := spawner.Launch(&validator.ValidationInput{Id: 1}, moduleRoot)
+	<-loading
+	stopped := make(chan struct{})
+	go func() {
+		spawner.Stop()
+		close(stopped)
+	}()
+	for !spawner.draining.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	refused := spawner.Launch(&validator.ValidationInput{Id: 2}, moduleRoot)
+	if _, err := refused.Await(ctx); !errors.Is(err, ErrJitSpawnerDraining) {
+		t.Fatal("expected validation launched while draining to be refused, got", err)
+	}
+	select {
+	case <-stopped:
+		t.Fatal("stopped with a validation in flight")
+	case <-time.After(3 * drainPollInterval):
+	}
+
+	close(release)
+	if _, err := inFlight.Await(ctx); !errors.Is(err, errLoadReleased) {
+		t.Fatal("expected in flight validation to complete rather than be cancelled, got", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("still stopping after validations in flight completed")
+	}
+}
+
+func TestJitSpawnerDrainTimeout(t *testing.T) {
+	config := DefaultJitSpawnerConfig
+	config.StopTimeout = 3 * drainPollInterval
+	spawner := &JitSpawner{config: func() *JitSpawnerConfig { return &config }}
+	spawner.inFlight.Add(1)
+
+	done := make(chan struct{})
+	go func() {
+		spawner.drain()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("drain didn't give up after the stop timeout")
+	}
+}
+
+func TestJitSpawnerPreloadsMachines(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	healthy := common.HexToHash("0x01")
+	flaky := common.HexToHash("0x02")
+	dir := t.TempDir()
+	writeTestMachine(t, dir, healthy, true)
+	writeTestMachine(t, dir, flaky, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loadsMutex sync.Mutex
+	loads := make(map[common.Hash]int)
+	loaded := make(chan common.Hash, 4)
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		loadsMutex.Lock()
+		loads[moduleRoot]++
+		attempt := loads[moduleRoot]
+		loadsMutex.Unlock()
+		defer func() { loaded <- moduleRoot }()
+		if moduleRoot == flaky && attempt == 1 {
+			return nil, errors.New("failed to load machine")
+		}
+		return &JitMachine{}, nil
+	}
+	newSpawner := func(preload bool) *JitSpawner {
+		config := DefaultJitSpawnerConfig
+		config.PreloadMachines = preload
+		return &JitSpawner{
+			locator: locator,
+			machineLoader: &JitMachineLoader{
+				MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+				locator:       locator,
+				proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+			},
+			config:  func() *JitSpawnerConfig { return &config },
+			metrics: newBufferingSink(),
+		}
+	}
+
+	disabled := newSpawner(false)
+	if err := disabled.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case moduleRoot := <-loaded:
+		t.Fatal("preloaded machine with preloading disabled, module root", moduleRoot)
+	case <-time.After(100 * time.Millisecond):
+	}
+	disabled.StopAndWait()
+
+	spawner := newSpawner(true)
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer spawner.StopAndWait()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-loaded:
+		case <-time.After(time.Second):
+			t.Fatal("machines not preloaded on start")
+		}
+	}
+	// a failed preload doesn't prevent loading the machine on first use, once the preload gave up on it
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := spawner.machineLoader.GetMachine(ctx, flaky)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("failed loading machine after failed preload:", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := spawner.machineLoader.GetMachine(ctx, healthy); err != nil {
+		t.Fatal(err)
+	}
+	loadsMutex.Lock()
+	defer loadsMutex.Unlock()
+	if loads[healthy] != 1 || loads[flaky] != 2 {
+		t.Fatalf("expected the healthy machine loaded once and the flaky one twice, got %d and %d", loads[healthy], loads[flaky])
+	}
+}
+
+func TestJitSpawnerInFlightValidations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	moduleRoot := common.HexToHash("0x01")
+	dir := t.TempDir()
+	writeTestMachine(t, dir, moduleRoot, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// validations are wedged waiting for their machine
+	release := make(chan struct{})
+	defer close(release)
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		<-release
+		return nil, errors.New("failed to load machine")
+	}
+	config := DefaultJitSpawnerConfig
+	config.PreloadMachines = false
+	spawner := &JitSpawner{
+		locator: locator,
+		machineLoader: &JitMachineLoader{
+			MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+			locator:       locator,
+			proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+		},
+		config:  func() *JitSpawnerConfig { return &config },
+		metrics: newBufferingSink(),
+	}
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer spawner.StopAndWait()
+
+	launched := time.Now()
+	first := spawner.Launch(&validator.ValidationInput{Id: 7}, moduleRoot)
+	second := spawner.Launch(&validator.ValidationInput{Id: 8}, moduleRoot)
+	var inFlight []validator.ValidationHandle
+	for deadline := time.Now().Add(time.Second); len(inFlight) < 2; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected 2 validations in flight, got", inFlight)
+		}
+		inFlight = spawner.InFlight()
+	}
+	for i, handle := range inFlight {
+		if handle.ModuleRoot != moduleRoot || handle.Block != uint64(7+i) || handle.Started.Before(launched) {
+			t.Fatalf("unexpected in-flight validation %+v", handle)
+		}
+	}
+
+	inFlight[0].Cancel()
+	awaitCtx, awaitCancel := context.WithTimeout(ctx, time.Second)
+	defer awaitCancel()
+	if _, err := first.Await(awaitCtx); !errors.Is(err, context.Canceled) {
+		t.Fatal("expected cancelled validation to fail with a cancellation error, got", err)
+	}
+	if second.Ready() {
+		t.Fatal("cancelling a validation completed another one")
+	}
+	for deadline := time.Now().Add(time.Second); len(spawner.InFlight()) != 1; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected only the other validation in flight, got", spawner.InFlight())
+		}
+	}
+	if remaining := spawner.InFlight()[0]; remaining.Block != 8 {
+		t.Fatal("unexpected validation left in flight", remaining)
+	}
+	second.Cancel()
+}
+
+func TestJitSpawnerRunsCarryBackend(t *testing.T) {
+	for _, cranelift := range []bool{true, false} {
+		config := DefaultJitSpawnerConfig
+		config.Cranelift = cranelift
+		spawner := &JitSpawner{config: func() *JitSpawnerConfig { return &config }}
+		// a draining spawner refuses the validation without launching it
+		spawner.draining.Store(true)
+		run := spawner.Launch(&validator.ValidationInput{Id: 1}, common.HexToHash("0x01"))
+		if _, err := run.Await(context.Background()); !errors.Is(err, ErrJitSpawnerDraining) {
+			t.Fatalf("expected draining error, got %v", err)
+		}
+		if name := validator.RunSpawnerName(run); name != spawner.Name() {
+			t.Fatalf("run attributed to %q, expected %q", name, spawner.Name())
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/validator"
+)
+
+const jitInstrumentationScope = "github.com/offchainlabs/nitro/validator/server_jit"
+
+const jitValidationSpanName = "jit validation"
+
+// WithTracerProvider makes the spawner emit an OpenTelemetry span per validation through the given provider,
+// e.g. for distributed tracing across the validation pipeline. It overrides the global provider used with
+// the tracing config, and by default no spans are emitted.
+func WithTracerProvider(provider trace.TracerProvider) JitSpawnerOption {
+	return func(s *JitSpawner) {
+		s.tracerProvider = provider
+		s.tracer = provider.Tracer(jitInstrumentationScope)
+	}
+}
+
+// spanFlusher is implemented by tracer providers exporting spans in batches, like the OpenTelemetry SDK's.
+type spanFlusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// flushValidationSpans exports the spans of completed validations still batched by the tracer provider,
+// waiting at most the stop timeout.
+func (v *JitSpawner) flushValidationSpans() {
+	flusher, ok := v.tracerProvider.(spanFlusher)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), v.config().StopTimeout)
+	defer cancel()
+	if err := flusher.ForceFlush(ctx); err != nil {
+		log.Warn("failed to flush jit validation spans on stop", "err", err)
+	}
+}
+
+// startValidationSpan starts the span of a validation as a child of parent if it's valid, returning
+// the context to run the validation with, and a nil span if the spawner isn't tracing.
+func (v *JitSpawner) startValidationSpan(
+	ctx context.Context, parent trace.SpanContext, entry *validator.ValidationInput, moduleRoot common.Hash,
+) (context.Context, trace.Span) {
+	if v.tracer == nil {
+		return ctx, nil
+	}
+	if parent.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, parent)
+	}
+	return v.tracer.Start(ctx, jitValidationSpanName, trace.WithAttributes(
+		attribute.String("validation.module_root", moduleRoot.Hex()),
+		// #nosec G115
+		attribute.Int64("validation.block", int64(entry.Id)),
+		attribute.String("validation.compiler", jitBackend(v.config().Cranelift)),
+	))
+}
+
+// endValidationSpan records the duration and outcome of a validation in its span, and ends it.
+func endValidationSpan(span trace.Span, duration time.Duration, err error) {
+	if span == nil {
+		return
+	}
+	outcome := "success"
+	if err != nil {
+		outcome = validationErrorClass(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(
+		attribute.Int64("validation.duration_ms", duration.Milliseconds()),
+		attribute.String("validation.outcome", outcome),
+	)
+	span.End()
+}
@@ -0,0 +1,166 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package server_jit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/util/metricsutil"
+	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/server_common"
+)
+
+func TestJitSpawnerValidationSpans(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	moduleRoot := common.HexToHash("0x01")
+	dir := t.TempDir()
+	writeTestMachine(t, dir, moduleRoot, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		return nil, errors.New("failed to load machine")
+	}
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer func() {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}()
+	config := DefaultJitSpawnerConfig
+	config.PreloadMachines = false
+	spawner := &JitSpawner{
+		locator: locator,
+		machineLoader: &JitMachineLoader{
+			MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+			locator:       locator,
+			proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+		},
+		config:  func() *JitSpawnerConfig { return &config },
+		metrics: newBufferingSink(),
+	}
+	WithTracerProvider(provider)(spawner)
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer spawner.StopAndWait()
+
+	// the caller's trace context is propagated to the validation's span
+	parentCtx, parent := provider.Tracer("test").Start(ctx, "validate request")
+	run := spawner.LaunchWithContext(parentCtx, &validator.ValidationInput{Id: 42}, moduleRoot)
+	if _, err := run.Await(ctx); err == nil {
+		t.Fatal("expected validation to fail without a machine")
+	}
+	parent.End()
+
+	var span *tracetest.SpanStub
+	for _, recorded := range exporter.GetSpans() {
+		if recorded.Name == jitValidationSpanName {
+			span = &recorded
+		}
+	}
+	if span == nil {
+		t.Fatal("no span recorded for the validation")
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() || span.SpanContext.TraceID() != parent.SpanContext().TraceID() {
+		t.Fatal("validation span isn't a child of the caller's span")
+	}
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	expected := map[attribute.Key]attribute.Value{
+		"validation.module_root": attribute.StringValue(moduleRoot.Hex()),
+		"validation.block":       attribute.Int64Value(42),
+		"validation.compiler":    attribute.StringValue("cranelift"),
+		"validation.outcome":     attribute.StringValue("machine_unavailable"),
+	}
+	for key, value := range expected {
+		if attributes[key] != value {
+			t.Fatalf("span attribute %v is %v, expected %v", key, attributes[key].Emit(), value.Emit())
+		}
+	}
+	if _, ok := attributes["validation.duration_ms"]; !ok {
+		t.Fatal("span is missing the validation duration")
+	}
+	if span.Status.Code != codes.Error {
+		t.Fatal("expected failed validation span to have an error status, got", span.Status.Code)
+	}
+}
+
+func TestJitSpawnerFlushesSpansOnStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	moduleRoot := common.HexToHash("0x01")
+	dir := t.TempDir()
+	writeTestMachine(t, dir, moduleRoot, true)
+	locator, err := server_common.NewMachineLocator(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createMachine := func(ctx context.Context, moduleRoot common.Hash) (*JitMachine, error) {
+		return nil, errors.New("failed to load machine")
+	}
+	// spans are only exported in batches, which won't fill or time out during the test
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(time.Hour)))
+	defer func() {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}()
+	registry := metrics.NewRegistry()
+	config := DefaultJitSpawnerConfig
+	config.PreloadMachines = false
+	spawner := &JitSpawner{
+		locator: locator,
+		machineLoader: &JitMachineLoader{
+			MachineLoader: *server_common.NewMachineLoader[JitMachine](locator, createMachine),
+			locator:       locator,
+			proverBinPath: DefaultJitMachineConfig.ProverBinPath,
+		},
+		config:  func() *JitSpawnerConfig { return &config },
+		metrics: metricsutil.NewRegistrySink(registry),
+	}
+	WithTracerProvider(provider)(spawner)
+	if err := spawner.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	run := spawner.Launch(&validator.ValidationInput{Id: 7}, moduleRoot)
+	if _, err := run.Await(ctx); err == nil {
+		t.Fatal("expected validation to fail without a machine")
+	}
+	if spans := exporter.GetSpans(); len(spans) != 0 {
+		t.Fatalf("expected the validation span to be batched before stopping, got %d exported", len(spans))
+	}
+
+	spawner.Stop()
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != jitValidationSpanName {
+		t.Fatalf("expected the validation span to be exported on stop, got %v", spans)
+	}
+	if validations := metrics.GetOrRegisterCounter(jitValidationsMetric, registry).Snapshot().Count(); validations != 1 {
+		t.Fatalf("expected 1 validation in the registry after stop, got %d", validations)
+	}
+	if failures := metrics.GetOrRegisterCounter(jitValidationFailuresMetric, registry).Snapshot().Count(); failures != 1 {
+		t.Fatalf("expected 1 failed validation in the registry after stop, got %d", failures)
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/offchainlabs/nitro/util/stopwaiter"
 	"github.com/offchainlabs/nitro/validator"
@@ -21,7 +23,20 @@ import (
 )
 
 type ValidationServerAPI struct {
-	spawner validator.ValidationSpawner
+	spawner             validator.ValidationSpawner
+	validateInputConfig ValidateInputConfigFetcher
+}
+
+// contextLauncher is implemented by spawners propagating the trace context of the request to its validation.
+type contextLauncher interface {
+	LaunchWithContext(ctx context.Context, input *validator.ValidationInput, moduleRoot common.Hash) validator.ValidationRun
+}
+
+func (a *ValidationServerAPI) launch(ctx context.Context, input *validator.ValidationInput, moduleRoot common.Hash) validator.ValidationRun {
+	if launcher, ok := a.spawner.(contextLauncher); ok {
+		return launcher.LaunchWithContext(ctx, input, moduleRoot)
+	}
+	return a.spawner.Launch(input, moduleRoot)
 }
 
 func (a *ValidationServerAPI) Name() string {
@@ -37,20 +52,128 @@ func (a *ValidationServerAPI) Validate(ctx context.Context, entry *server_api.In
 	if err != nil {
 		return validator.GoGlobalState{}, err
 	}
-	valRun := a.spawner.Launch(valInput, moduleRoot)
+	valRun := a.launch(ctx, valInput, moduleRoot)
 	return valRun.Await(ctx)
 }
 
+// ValidateInput validates an arbitrary input against moduleRoot, enforcing the configured input
+// size and timeout limits. Failures due to these limits or to an unsupported module root are
+// returned as a server_api.ValidationError with the matching error code.
+func (a *ValidationServerAPI) ValidateInput(ctx context.Context, entry *server_api.InputJSON, moduleRoot common.Hash) (validator.GoGlobalState, error) {
+	config := a.validateInputConfig()
+	if !validator.SpawnerSupportsModule(a.spawner, moduleRoot) {
+		return validator.GoGlobalState{}, &server_api.ValidationError{
+			Code:    server_api.UnknownModuleRootErrorCode,
+			Message: fmt.Sprintf("unknown wasm module root %v", moduleRoot),
+		}
+	}
+	valInput, err := server_api.ValidationInputFromJson(entry)
+	if err != nil {
+		return validator.GoGlobalState{}, err
+	}
+	if size := validationInputSize(valInput); config.MaxInputSize > 0 && size > config.MaxInputSize {
+		return validator.GoGlobalState{}, &server_api.ValidationError{
+			Code:    server_api.InputTooLargeErrorCode,
+			Message: fmt.Sprintf("validation input size %d exceeds limit %d", size, config.MaxInputSize),
+		}
+	}
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+	valRun := a.launch(ctx, valInput, moduleRoot)
+	defer valRun.Cancel()
+	gs, err := valRun.Await(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return validator.GoGlobalState{}, &server_api.ValidationError{
+			Code:    server_api.ValidationTimeoutErrorCode,
+			Message: fmt.Sprintf("validation timed out after %v", config.Timeout),
+		}
+	}
+	return gs, err
+}
+
+// validationInputSize returns the total size of the data carried by a validation input
+func validationInputSize(input *validator.ValidationInput) uint64 {
+	var size int
+	for _, preimages := range input.Preimages {
+		for _, preimage := range preimages {
+			size += len(preimage)
+		}
+	}
+	for _, wasms := range input.UserWasms {
+		for _, wasm := range wasms {
+			size += len(wasm)
+		}
+	}
+	for _, batch := range input.BatchInfo {
+		size += len(batch.Data)
+	}
+	size += len(input.DelayedMsg)
+	return uint64(size) // #nosec G115
+}
+
 func (a *ValidationServerAPI) WasmModuleRoots() ([]common.Hash, error) {
 	return a.spawner.WasmModuleRoots()
 }
 
+type readyModuleRootsSpawner interface {
+	ReadyWasmModuleRoots() ([]common.Hash, error)
+}
+
+// ReadyWasmModuleRoots returns the module roots the spawner can validate right now.
+// Spawners that don't distinguish ready roots report their advertised roots.
+func (a *ValidationServerAPI) ReadyWasmModuleRoots() ([]common.Hash, error) {
+	if spawner, ok := a.spawner.(readyModuleRootsSpawner); ok {
+		return spawner.ReadyWasmModuleRoots()
+	}
+	return a.spawner.WasmModuleRoots()
+}
+
+var errInFlightNotListed = errors.New("validation spawner doesn't list in-flight validations")
+
+// InFlightValidations returns the validations in flight on the spawner, e.g. to find a wedged one.
+func (a *ValidationServerAPI) InFlightValidations() ([]validator.ValidationHandle, error) {
+	lister, ok := a.spawner.(validator.InFlightLister)
+	if !ok {
+		return nil, errInFlightNotListed
+	}
+	return lister.InFlight(), nil
+}
+
 func (a *ValidationServerAPI) StylusArchs() ([]rawdb.WasmTarget, error) {
 	return a.spawner.StylusArchs(), nil
 }
 
-func NewValidationServerAPI(spawner validator.ValidationSpawner) *ValidationServerAPI {
-	return &ValidationServerAPI{spawner}
+func NewValidationServerAPI(spawner validator.ValidationSpawner, validateInputConfig ValidateInputConfigFetcher) *ValidationServerAPI {
+	return &ValidationServerAPI{spawner, validateInputConfig}
+}
+
+// ValidationAdminAPI holds the validation methods able to disrupt the server, which are only
+// registered on the authenticated endpoint.
+type ValidationAdminAPI struct {
+	spawner validator.ValidationSpawner
+}
+
+func NewValidationAdminAPI(spawner validator.ValidationSpawner) *ValidationAdminAPI {
+	return &ValidationAdminAPI{spawner}
+}
+
+// CancelValidation aborts the validation in flight with the given id, as listed by InFlightValidations.
+func (a *ValidationAdminAPI) CancelValidation(id uint64) error {
+	lister, ok := a.spawner.(validator.InFlightLister)
+	if !ok {
+		return errInFlightNotListed
+	}
+	for _, handle := range lister.InFlight() {
+		if handle.Id == id {
+			log.Warn("cancelling in-flight validation", "id", id, "moduleRoot", handle.ModuleRoot, "block", handle.Block, "started", handle.Started)
+			handle.Cancel()
+			return nil
+		}
+	}
+	return fmt.Errorf("no validation with id %d in flight", id)
 }
 
 type execRunEntry struct {
@@ -70,9 +193,9 @@ type ExecServerAPI struct {
 	runs      map[uint64]*execRunEntry
 }
 
-func NewExecutionServerAPI(valSpawner validator.ValidationSpawner, execution validator.ExecutionSpawner, config server_arb.ArbitratorSpawnerConfigFecher) *ExecServerAPI {
+func NewExecutionServerAPI(valSpawner validator.ValidationSpawner, execution validator.ExecutionSpawner, config server_arb.ArbitratorSpawnerConfigFecher, validateInputConfig ValidateInputConfigFetcher) *ExecServerAPI {
 	return &ExecServerAPI{
-		ValidationServerAPI: *NewValidationServerAPI(valSpawner),
+		ValidationServerAPI: *NewValidationServerAPI(valSpawner, validateInputConfig),
 		execSpawner:         execution,
 		nextId:              rand.Uint64(), // good-enough to aver reusing ids after reboot
 		runs:                make(map[uint64]*execRunEntry),
@@ -0,0 +1,183 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro/blob/master/LICENSE.md
+
+package valnode
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/daprovider"
+	"github.com/offchainlabs/nitro/util/containers"
+	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/server_api"
+)
+
+var testModuleRoot = common.HexToHash("0xa5a5a5")
+
+// fakeSpawner returns the start state with the batch advanced, or never completes if hang is set
+type fakeSpawner struct {
+	hang bool
+}
+
+type fakeValRun struct {
+	containers.Promise[validator.GoGlobalState]
+	root common.Hash
+}
+
+func (r *fakeValRun) WasmModuleRoot() common.Hash { return r.root }
+
+func (s *fakeSpawner) Launch(entry *validator.ValidationInput, moduleRoot common.Hash) validator.ValidationRun {
+	run := &fakeValRun{
+		Promise: containers.NewPromise[validator.GoGlobalState](nil),
+		root:    moduleRoot,
+	}
+	if !s.hang {
+		end := entry.StartState
+		end.Batch++
+		run.Produce(end)
+	}
+	return run
+}
+
+func (s *fakeSpawner) WasmModuleRoots() ([]common.Hash, error) {
+	return []common.Hash{testModuleRoot}, nil
+}
+func (s *fakeSpawner) Start(context.Context) error     { return nil }
+func (s *fakeSpawner) Stop()                           {}
+func (s *fakeSpawner) Name() string                    { return "fake" }
+func (s *fakeSpawner) StylusArchs() []rawdb.WasmTarget { return []rawdb.WasmTarget{rawdb.TargetWavm} }
+func (s *fakeSpawner) Room() int                       { return 1 }
+
+func requireValidationErrorCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected rpc error with code %d, got %v", code, err)
+	}
+	if rpcErr.ErrorCode() != code {
+		t.Fatalf("expected error code %d, got %d (%v)", code, rpcErr.ErrorCode(), err)
+	}
+}
+
+func TestValidateInputOverRPC(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	spawner := &fakeSpawner{}
+	config := ValidateInputConfig{
+		MaxInputSize: 64,
+		Timeout:      100 * time.Millisecond,
+	}
+	api := NewValidationServerAPI(spawner, func() *ValidateInputConfig { return &config })
+	server := rpc.NewServer()
+	if err := server.RegisterName(server_api.Namespace, api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	startState := validator.GoGlobalState{
+		BlockHash:  common.HexToHash("0x1234"),
+		SendRoot:   common.HexToHash("0x5678"),
+		Batch:      7,
+		PosInBatch: 0,
+	}
+	input := server_api.ValidationInputToJson(&validator.ValidationInput{
+		StartState: startState,
+		Preimages: daprovider.PreimagesMap{
+			arbutil.Keccak256PreimageType: {common.HexToHash("0x01"): []byte("small preimage")},
+		},
+	})
+	method := server_api.Namespace + "_validateInput"
+
+	var result validator.GoGlobalState
+	if err := client.CallContext(ctx, &result, method, input, testModuleRoot); err != nil {
+		t.Fatal(err)
+	}
+	expected := startState
+	expected.Batch++
+	if result != expected {
+		t.Fatalf("unexpected validation result %v, expected %v", result, expected)
+	}
+
+	err := client.CallContext(ctx, &result, method, input, common.HexToHash("0xdead"))
+	requireValidationErrorCode(t, err, server_api.UnknownModuleRootErrorCode)
+
+	largeInput := server_api.ValidationInputToJson(&validator.ValidationInput{
+		StartState: startState,
+		Preimages: daprovider.PreimagesMap{
+			arbutil.Keccak256PreimageType: {common.HexToHash("0x01"): make([]byte, config.MaxInputSize+1)},
+		},
+	})
+	err = client.CallContext(ctx, &result, method, largeInput, testModuleRoot)
+	requireValidationErrorCode(t, err, server_api.InputTooLargeErrorCode)
+
+	spawner.hang = true
+	err = client.CallContext(ctx, &result, method, input, testModuleRoot)
+	requireValidationErrorCode(t, err, server_api.ValidationTimeoutErrorCode)
+}
+
+// listingSpawner lists a single validation in flight, recording its cancellation
+type listingSpawner struct {
+	fakeSpawner
+	cancelled bool
+}
+
+func (s *listingSpawner) InFlight() []validator.ValidationHandle {
+	return []validator.ValidationHandle{
+		validator.NewValidationHandle(3, testModuleRoot, 10, time.Now(), func() { s.cancelled = true }),
+	}
+}
+
+func TestCancelValidationOnlyOnAdminAPI(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	spawner := &listingSpawner{}
+	config := DefaultValidateInputConfig
+	publicServer := rpc.NewServer()
+	if err := publicServer.RegisterName(server_api.Namespace, NewValidationServerAPI(spawner, func() *ValidateInputConfig { return &config })); err != nil {
+		t.Fatal(err)
+	}
+	publicClient := rpc.DialInProc(publicServer)
+	defer publicClient.Close()
+	method := server_api.Namespace + "_cancelValidation"
+
+	var handles []validator.ValidationHandle
+	if err := publicClient.CallContext(ctx, &handles, server_api.Namespace+"_inFlightValidations"); err != nil {
+		t.Fatal(err)
+	}
+	if len(handles) != 1 || handles[0].Id != 3 {
+		t.Fatalf("unexpected in-flight validations %v", handles)
+	}
+	if err := publicClient.CallContext(ctx, nil, method, uint64(3)); err == nil {
+		t.Fatal("cancelValidation is exposed by the public API")
+	}
+	if spawner.cancelled {
+		t.Fatal("validation cancelled through the public API")
+	}
+
+	adminServer := rpc.NewServer()
+	if err := adminServer.RegisterName(server_api.Namespace, NewValidationAdminAPI(spawner)); err != nil {
+		t.Fatal(err)
+	}
+	adminClient := rpc.DialInProc(adminServer)
+	defer adminClient.Close()
+	if err := adminClient.CallContext(ctx, nil, method, uint64(4)); err == nil {
+		t.Fatal("expected cancelling an unknown validation to fail")
+	}
+	if err := adminClient.CallContext(ctx, nil, method, uint64(3)); err != nil {
+		t.Fatal(err)
+	}
+	if !spawner.cancelled {
+		t.Fatal("validation not cancelled through the admin API")
+	}
+}
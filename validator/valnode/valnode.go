@@ -2,6 +2,8 @@ package valnode
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -35,33 +37,53 @@ var DefaultWasmConfig = WasmConfig{
 	AllowedWasmModuleRoots: []string{},
 }
 
+type ValidateInputConfig struct {
+	MaxInputSize uint64        `koanf:"max-input-size"`
+	Timeout      time.Duration `koanf:"timeout"`
+}
+
+type ValidateInputConfigFetcher func() *ValidateInputConfig
+
+func ValidateInputConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Uint64(prefix+".max-input-size", DefaultValidateInputConfig.MaxInputSize, "maximum total size in bytes of the data in an input submitted to validateInput (0 for no limit)")
+	f.Duration(prefix+".timeout", DefaultValidateInputConfig.Timeout, "maximum time to validate an input submitted to validateInput (0 for no limit)")
+}
+
+var DefaultValidateInputConfig = ValidateInputConfig{
+	MaxInputSize: 512 * 1024 * 1024,
+	Timeout:      15 * time.Minute,
+}
+
 type Config struct {
-	UseJit     bool                               `koanf:"use-jit"`
-	ApiAuth    bool                               `koanf:"api-auth"`
-	ApiPublic  bool                               `koanf:"api-public"`
-	Arbitrator server_arb.ArbitratorSpawnerConfig `koanf:"arbitrator" reload:"hot"`
-	Jit        server_jit.JitSpawnerConfig        `koanf:"jit" reload:"hot"`
-	Wasm       WasmConfig                         `koanf:"wasm"`
+	UseJit        bool                               `koanf:"use-jit"`
+	ApiAuth       bool                               `koanf:"api-auth"`
+	ApiPublic     bool                               `koanf:"api-public"`
+	Arbitrator    server_arb.ArbitratorSpawnerConfig `koanf:"arbitrator" reload:"hot"`
+	Jit           server_jit.JitSpawnerConfig        `koanf:"jit" reload:"hot"`
+	Wasm          WasmConfig                         `koanf:"wasm"`
+	ValidateInput ValidateInputConfig                `koanf:"validate-input" reload:"hot"`
 }
 
 type ValidationConfigFetcher func() *Config
 
 var DefaultValidationConfig = Config{
-	UseJit:     true,
-	Jit:        server_jit.DefaultJitSpawnerConfig,
-	ApiAuth:    true,
-	ApiPublic:  false,
-	Arbitrator: server_arb.DefaultArbitratorSpawnerConfig,
-	Wasm:       DefaultWasmConfig,
+	UseJit:        true,
+	Jit:           server_jit.DefaultJitSpawnerConfig,
+	ApiAuth:       true,
+	ApiPublic:     false,
+	Arbitrator:    server_arb.DefaultArbitratorSpawnerConfig,
+	Wasm:          DefaultWasmConfig,
+	ValidateInput: DefaultValidateInputConfig,
 }
 
 var TestValidationConfig = Config{
-	UseJit:     true,
-	Jit:        server_jit.DefaultJitSpawnerConfig,
-	ApiAuth:    false,
-	ApiPublic:  true,
-	Arbitrator: server_arb.DefaultArbitratorSpawnerConfig,
-	Wasm:       DefaultWasmConfig,
+	UseJit:        true,
+	Jit:           server_jit.DefaultJitSpawnerConfig,
+	ApiAuth:       false,
+	ApiPublic:     true,
+	Arbitrator:    server_arb.DefaultArbitratorSpawnerConfig,
+	Wasm:          DefaultWasmConfig,
+	ValidateInput: DefaultValidateInputConfig,
 }
 
 func ValidationConfigAddOptions(prefix string, f *pflag.FlagSet) {
@@ -71,6 +93,14 @@ func ValidationConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	server_arb.ArbitratorSpawnerConfigAddOptions(prefix+".arbitrator", f)
 	server_jit.JitSpawnerConfigAddOptions(prefix+".jit", f)
 	WasmConfigAddOptions(prefix+".wasm", f)
+	ValidateInputConfigAddOptions(prefix+".validate-input", f)
+}
+
+func (c *Config) Validate() error {
+	if err := c.Jit.Validate(); err != nil {
+		return fmt.Errorf("failed to validate jit config: %w", err)
+	}
+	return nil
 }
 
 type ValidationNode struct {
@@ -108,6 +138,7 @@ func CreateValidationNode(configFetcher ValidationConfigFetcher, stack *node.Nod
 	if err != nil {
 		return nil, err
 	}
+	validateInputConfigFetcher := func() *ValidateInputConfig { return &configFetcher().ValidateInput }
 	var serverAPI *ExecServerAPI
 	var jitSpawner *server_jit.JitSpawner
 	if config.UseJit {
@@ -117,9 +148,9 @@ func CreateValidationNode(configFetcher ValidationConfigFetcher, stack *node.Nod
 		if err != nil {
 			return nil, err
 		}
-		serverAPI = NewExecutionServerAPI(jitSpawner, arbSpawner, arbConfigFetcher)
+		serverAPI = NewExecutionServerAPI(jitSpawner, arbSpawner, arbConfigFetcher, validateInputConfigFetcher)
 	} else {
-		serverAPI = NewExecutionServerAPI(arbSpawner, arbSpawner, arbConfigFetcher)
+		serverAPI = NewExecutionServerAPI(arbSpawner, arbSpawner, arbConfigFetcher, validateInputConfigFetcher)
 	}
 	var redisConsumer *redis.ValidationServer
 	redisValidationConfig := arbConfigFetcher().RedisValidationServerConfig
@@ -135,6 +166,12 @@ func CreateValidationNode(configFetcher ValidationConfigFetcher, stack *node.Nod
 		Service:       serverAPI,
 		Public:        config.ApiPublic,
 		Authenticated: config.ApiAuth,
+	}, {
+		Namespace:     server_api.Namespace,
+		Version:       "1.0",
+		Service:       NewValidationAdminAPI(serverAPI.spawner),
+		Public:        false,
+		Authenticated: true,
 	}}
 	stack.RegisterAPIs(valAPIs)
 